@@ -0,0 +1,41 @@
+// Command rebuild-stats recomputes the denormalized user and skill stats from
+// sessions, reviews and transactions.
+//
+// Stats are kept up to date as sessions complete and reviews change; run this once
+// to backfill existing data, or again after editing those tables by hand.
+//
+//	go run ./cmd/rebuild-stats
+package main
+
+import (
+	"log"
+
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/database"
+	"github.com/timebankingskill/backend/internal/repository"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	if err := database.Connect(&cfg.Database); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	log.Println("🔄 Rebuilding user and skill stats...")
+	result, err := repository.NewStatsRepository(database.DB).RebuildAll()
+	if err != nil {
+		log.Fatalf("❌ Failed to rebuild stats: %v", err)
+	}
+	log.Printf("✅ Rebuilt stats for %d users, %d user skills and %d skills",
+		result.Users, result.UserSkills, result.Skills)
+
+	// Leaderboard and skill views read these stats
+	if err := database.RefreshMaterializedViews(database.DB); err != nil {
+		log.Printf("⚠️  Warning: Failed to refresh materialized views: %v", err)
+	}
+}
@@ -6,10 +6,13 @@ import (
   "time"
 
   "github.com/gin-gonic/gin"
+  "github.com/timebankingskill/backend/internal/broadcast"
   "github.com/timebankingskill/backend/internal/config"
   "github.com/timebankingskill/backend/internal/database"
   "github.com/timebankingskill/backend/internal/middleware"
+  "github.com/timebankingskill/backend/internal/repository"
   "github.com/timebankingskill/backend/internal/routes"
+  "github.com/timebankingskill/backend/internal/utils"
 )
 
 // @title Wibi Time Banking Skill API
@@ -71,10 +74,99 @@ func main() {
     log.Println("⏭️ Skipping initial data seeding (SEED_DATA=false)")
   }
 
+  // Fan notifications out across replicas (before any service publishes)
+  if cfg.Realtime.Broadcaster == "redis" {
+    broadcaster, err := broadcast.NewRedis(utils.GetRedisConfigFromEnv())
+    if err != nil {
+      log.Printf("⚠️  Warning: Redis broadcaster unavailable, notifications reach this instance only: %v", err)
+    } else {
+      broadcast.SetDefault(broadcaster)
+      defer broadcaster.Close()
+      log.Println("📡 Notifications fanned out over Redis pub/sub")
+    }
+  }
+
   // Start materialized view auto-refresher (every 10 minutes)
   stopRefresher := database.StartMaterializedViewRefresher(database.DB, 10*time.Minute)
   defer close(stopRefresher)
 
+  // Cap balances on every ledger posting
+  if cfg.CreditPolicy.Enabled {
+    repository.SetBalanceCap(cfg.CreditPolicy.MaxBalance)
+  }
+
+  // Start credit policy job (balance decay & bonus expiry)
+  if cfg.CreditPolicy.Enabled {
+    stopCreditPolicy := routes.InitializeCreditPolicyService(database.DB, cfg).StartScheduler()
+    defer close(stopCreditPolicy)
+  } else {
+    log.Println("⏭️ Skipping credit policy job (CREDIT_POLICY_ENABLED=false)")
+  }
+
+  // Start community pool automatic grants
+  if cfg.CommunityPool.AutoGrantEnabled {
+    stopAutoGrants := routes.InitializeCommunityPoolService(database.DB, cfg).StartScheduler()
+    defer close(stopAutoGrants)
+  } else {
+    log.Println("⏭️ Skipping community pool auto grants (POOL_AUTO_GRANT_ENABLED=false)")
+  }
+
+  // Start fraud detection job (session farming & transfer cycles)
+  if cfg.Fraud.Enabled {
+    stopFraudDetection := routes.InitializeFraudDetectionService(database.DB, cfg).StartScheduler()
+    defer close(stopFraudDetection)
+  } else {
+    log.Println("⏭️ Skipping fraud detection job (FRAUD_DETECTION_ENABLED=false)")
+  }
+
+  // Start event-driven badge evaluation
+  if cfg.Badges.EventsEnabled {
+    stopBadgeEvents := routes.InitializeBadgeEventProcessor(database.DB, cfg).StartScheduler()
+    defer close(stopBadgeEvents)
+  } else {
+    log.Println("⏭️ Skipping event-driven badge evaluation (BADGE_EVENTS_ENABLED=false)")
+  }
+
+  // Start challenge progress evaluation
+  if cfg.Challenges.Enabled {
+    stopChallenges := routes.InitializeChallengeService(database.DB, cfg).StartScheduler()
+    defer close(stopChallenges)
+  } else {
+    log.Println("⏭️ Skipping challenge progress evaluation (CHALLENGES_ENABLED=false)")
+  }
+
+  // Start double-blind review reveals (always on: sealed reviews depend on it)
+  stopReviewReveals := routes.InitializeReviewService(database.DB, cfg).StartScheduler()
+  defer close(stopReviewReveals)
+
+  // Start notification email delivery
+  if cfg.Email.Enabled {
+    stopEmails := routes.InitializeNotificationEmailDispatcher(database.DB, cfg).StartScheduler()
+    defer close(stopEmails)
+  } else {
+    log.Println("⏭️ Skipping notification emails (EMAIL_NOTIFICATIONS_ENABLED=false)")
+  }
+
+  // Start notification push delivery (needs VAPID keys)
+  if cfg.Push.Enabled {
+    if pushDispatcher, err := routes.InitializeNotificationPushDispatcher(database.DB, cfg); err != nil {
+      log.Printf("⚠️  Skipping notification pushes: %v", err)
+    } else {
+      stopPushes := pushDispatcher.StartScheduler()
+      defer close(stopPushes)
+    }
+  } else {
+    log.Println("⏭️ Skipping notification pushes (PUSH_NOTIFICATIONS_ENABLED=false)")
+  }
+
+  // Start weekly digest emails
+  if cfg.Digest.Enabled {
+    stopDigests := routes.InitializeDigestService(database.DB, cfg).StartScheduler()
+    defer close(stopDigests)
+  } else {
+    log.Println("⏭️ Skipping weekly digest emails (DIGEST_ENABLED=false)")
+  }
+
   // Initialize Gin router
   router := gin.New()
 
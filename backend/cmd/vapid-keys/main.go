@@ -0,0 +1,23 @@
+// Command vapid-keys generates a VAPID key pair for Web Push notifications.
+//
+// Generate the pair once per environment and keep it: browser push subscriptions are
+// bound to the public key, so changing it silently breaks every subscribed device.
+//
+//	go run ./cmd/vapid-keys
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/timebankingskill/backend/internal/webpush"
+)
+
+func main() {
+	publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		log.Fatalf("❌ Failed to generate VAPID keys: %v", err)
+	}
+	fmt.Printf("VAPID_PUBLIC_KEY=%s\n", publicKey)
+	fmt.Printf("VAPID_PRIVATE_KEY=%s\n", privateKey)
+}
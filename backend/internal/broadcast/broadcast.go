@@ -0,0 +1,210 @@
+// Package broadcast fans notifications out to the live connections of their users
+//
+// Each instance keeps the channels of its own connected clients (WebSocket today). A
+// Broadcaster decides how a published notification reaches the instance a user is
+// connected to: Memory delivers within the process, Redis relays through pub/sub so every
+// replica delivers to its local clients. Services publish through Default(), which main
+// swaps for the Redis broadcaster when configured.
+//
+// Broadcasters also track presence: whether a user has an open subscription on any
+// instance, which the push dispatcher uses to skip users who are online.
+package broadcast
+
+import (
+	"log"
+	"sync"
+
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+// Broadcaster delivers notifications to the connected clients of a user
+type Broadcaster interface {
+	// Publish sends a notification to every connection of the user, on any instance
+	Publish(userID uint, notification *models.Notification)
+
+	// Subscribe registers a local client channel of the user
+	Subscribe(userID uint, ch chan *models.Notification)
+
+	// Unsubscribe removes a local client channel and closes it
+	Unsubscribe(userID uint, ch chan *models.Notification)
+
+	// Connected reports whether the user has an open subscription on any instance
+	Connected(userID uint) bool
+
+	// Close releases the broadcaster's resources
+	Close() error
+}
+
+var (
+	defaultMu          sync.RWMutex
+	defaultBroadcaster Broadcaster = NewMemory()
+)
+
+// Default returns the process-wide broadcaster
+func Default() Broadcaster {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultBroadcaster
+}
+
+// SetDefault replaces the process-wide broadcaster
+// Call it at startup, before clients connect: clients subscribed to the previous
+// broadcaster stay with it.
+func SetDefault(b Broadcaster) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultBroadcaster = b
+}
+
+// hub holds the client channels connected to this instance
+type hub struct {
+	mutex   sync.RWMutex
+	clients map[uint][]*client
+}
+
+func newHub() *hub {
+	return &hub{clients: make(map[uint][]*client)}
+}
+
+// client is the channel of one connection
+// Its own mutex orders sends against the close, so a send never hits a closed channel
+// and the hub lock is never held while sending.
+type client struct {
+	mu     sync.Mutex
+	ch     chan *models.Notification
+	closed bool
+}
+
+// trySend queues a notification without blocking
+// A client whose buffer is full is too slow to keep up: its channel is closed, so the
+// connection ends and the client reconnects and replays what it missed.
+func (c *client) trySend(notification *models.Notification) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return true
+	}
+	select {
+	case c.ch <- notification:
+		return true
+	default:
+		c.closed = true
+		close(c.ch)
+		return false
+	}
+}
+
+// close closes the client channel once
+func (c *client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.ch)
+	}
+}
+
+// Subscribe registers a client channel of a user
+func (h *hub) Subscribe(userID uint, ch chan *models.Notification) {
+	h.add(userID, ch)
+}
+
+// Unsubscribe removes a client channel of a user and closes it
+func (h *hub) Unsubscribe(userID uint, ch chan *models.Notification) {
+	h.remove(userID, ch)
+}
+
+// add registers a client channel and reports whether it is the user's first on this instance
+func (h *hub) add(userID uint, ch chan *models.Notification) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.clients[userID] = append(h.clients[userID], &client{ch: ch})
+	return len(h.clients[userID]) == 1
+}
+
+// remove unregisters and closes a client channel, and reports whether the user has no
+// channel left on this instance
+func (h *hub) remove(userID uint, ch chan *models.Notification) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	clients := h.clients[userID]
+	for i, c := range clients {
+		if c.ch == ch {
+			h.clients[userID] = append(clients[:i], clients[i+1:]...)
+			c.close()
+			break
+		}
+	}
+
+	// Clean up empty user entry
+	if len(h.clients[userID]) == 0 {
+		delete(h.clients, userID)
+		return true
+	}
+	return false
+}
+
+// connectedLocally reports whether the user has a client channel on this instance
+func (h *hub) connectedLocally(userID uint) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.clients[userID]) > 0
+}
+
+// localUsers returns the users with a client channel on this instance
+func (h *hub) localUsers() []uint {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	users := make([]uint, 0, len(h.clients))
+	for userID := range h.clients {
+		users = append(users, userID)
+	}
+	return users
+}
+
+// deliver sends a notification to the local clients of a user without blocking
+// The clients are copied under the read lock and sent to after it is released, so a
+// slow client delays neither other users nor (un)subscribes.
+func (h *hub) deliver(userID uint, notification *models.Notification) {
+	h.mutex.RLock()
+	clients := append([]*client(nil), h.clients[userID]...)
+	h.mutex.RUnlock()
+
+	for _, c := range clients {
+		if !c.trySend(notification) {
+			log.Printf("⚠️  Notification buffer of user %d is full, closing the slow connection", userID)
+		}
+	}
+}
+
+// Memory is a Broadcaster delivering within the process
+// Enough for a single instance; with several replicas a user only receives the
+// notifications created on the instance they are connected to.
+type Memory struct {
+	*hub
+}
+
+// NewMemory creates an in-process broadcaster
+func NewMemory() *Memory {
+	return &Memory{hub: newHub()}
+}
+
+// Publish delivers a notification to the user's clients on this instance
+func (m *Memory) Publish(userID uint, notification *models.Notification) {
+	m.deliver(userID, notification)
+}
+
+// Connected reports whether the user has a client on this instance
+func (m *Memory) Connected(userID uint) bool {
+	return m.connectedLocally(userID)
+}
+
+// Close does nothing: the in-process broadcaster holds no resources
+func (m *Memory) Close() error {
+	return nil
+}
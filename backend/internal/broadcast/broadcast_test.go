@@ -0,0 +1,205 @@
+package broadcast
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/redistest"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// receive waits for a notification on a client channel
+func receive(t *testing.T, ch chan *models.Notification) *models.Notification {
+	t.Helper()
+	select {
+	case notification := <-ch:
+		return notification
+	case <-time.After(2 * time.Second):
+		t.Fatal("no notification received")
+		return nil
+	}
+}
+
+// assertNothing checks that a client channel receives nothing
+func assertNothing(t *testing.T, ch chan *models.Notification) {
+	t.Helper()
+	select {
+	case notification := <-ch:
+		t.Fatalf("unexpected notification %d", notification.ID)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func testNotification(id, userID uint) *models.Notification {
+	return &models.Notification{
+		ID:        id,
+		UserID:    userID,
+		Type:      models.NotificationTypeSession,
+		Title:     "New Session Request",
+		Message:   "Budi wants to learn React.js",
+		Data:      json.RawMessage(`{"sessionID":123}`),
+		CreatedAt: time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC),
+	}
+}
+
+// newRedisInstance starts a Redis broadcaster, standing for one backend replica
+func newRedisInstance(t *testing.T, srv *redistest.Server) *Redis {
+	t.Helper()
+	b, err := NewRedis(utils.RedisConfig{URL: srv.Addr, Prefix: "wibi:"})
+	require.NoError(t, err)
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestMemoryDeliversToUserClients(t *testing.T) {
+	b := NewMemory()
+	phone := make(chan *models.Notification, 1)
+	laptop := make(chan *models.Notification, 1)
+	other := make(chan *models.Notification, 1)
+	b.Subscribe(7, phone)
+	b.Subscribe(7, laptop)
+	b.Subscribe(8, other)
+
+	b.Publish(7, testNotification(1, 7))
+
+	assert.Equal(t, uint(1), receive(t, phone).ID)
+	assert.Equal(t, uint(1), receive(t, laptop).ID)
+	assertNothing(t, other)
+}
+
+func TestMemoryUnsubscribeClosesChannel(t *testing.T) {
+	b := NewMemory()
+	ch := make(chan *models.Notification, 1)
+	b.Subscribe(7, ch)
+	b.Unsubscribe(7, ch)
+
+	_, open := <-ch
+	assert.False(t, open)
+
+	// Publishing to a user without clients is a no-op
+	b.Publish(7, testNotification(1, 7))
+	assert.Empty(t, b.clients)
+}
+
+func TestMemoryClosesSlowClientWithoutBlocking(t *testing.T) {
+	b := NewMemory()
+	slow := make(chan *models.Notification) // Never read: the buffer is always full
+	fast := make(chan *models.Notification, 1)
+	b.Subscribe(7, slow)
+	b.Subscribe(7, fast)
+
+	start := time.Now()
+	b.Publish(7, testNotification(1, 7))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	assert.Equal(t, uint(1), receive(t, fast).ID)
+	_, open := <-slow
+	assert.False(t, open, "the slow connection is closed so it reconnects and replays")
+
+	// The connection unsubscribes when it sees the closed channel
+	b.Unsubscribe(7, slow)
+	assert.True(t, b.Connected(7))
+}
+
+func TestMemoryConnected(t *testing.T) {
+	b := NewMemory()
+	phone := make(chan *models.Notification, 1)
+	laptop := make(chan *models.Notification, 1)
+	b.Subscribe(7, phone)
+	b.Subscribe(7, laptop)
+	assert.True(t, b.Connected(7))
+	assert.False(t, b.Connected(8))
+
+	b.Unsubscribe(7, phone)
+	assert.True(t, b.Connected(7), "the laptop is still connected")
+	b.Unsubscribe(7, laptop)
+	assert.False(t, b.Connected(7))
+}
+
+func TestRedisConnectedAcrossInstances(t *testing.T) {
+	srv, err := redistest.NewServer()
+	require.NoError(t, err)
+	defer srv.Close()
+
+	instanceA := newRedisInstance(t, srv)
+	instanceB := newRedisInstance(t, srv)
+
+	phone := make(chan *models.Notification, 1)
+	laptop := make(chan *models.Notification, 1)
+	instanceA.Subscribe(7, phone)
+	instanceA.Subscribe(7, laptop)
+
+	assert.True(t, instanceA.Connected(7))
+	assert.True(t, instanceB.Connected(7), "connected to another instance")
+	assert.False(t, instanceB.Connected(8))
+
+	instanceA.Unsubscribe(7, phone)
+	assert.True(t, instanceB.Connected(7), "the laptop is still connected")
+	instanceA.Unsubscribe(7, laptop)
+	assert.False(t, instanceB.Connected(7))
+
+	// Closing an instance withdraws the presence of its users
+	instanceA.Subscribe(8, make(chan *models.Notification, 1))
+	assert.True(t, instanceB.Connected(8))
+	require.NoError(t, instanceA.Close())
+	assert.False(t, instanceB.Connected(8))
+}
+
+func TestRedisFansOutAcrossInstances(t *testing.T) {
+	srv, err := redistest.NewServer()
+	require.NoError(t, err)
+	defer srv.Close()
+
+	instanceA := newRedisInstance(t, srv)
+	instanceB := newRedisInstance(t, srv)
+	assert.Equal(t, 2, srv.Subscribers("wibi:notifications"))
+
+	onA := make(chan *models.Notification, 1)
+	onB := make(chan *models.Notification, 1)
+	otherOnB := make(chan *models.Notification, 1)
+	instanceA.Subscribe(7, onA)
+	instanceB.Subscribe(7, onB)
+	instanceB.Subscribe(8, otherOnB)
+
+	// Created on instance A, the notification reaches the user's sockets on both instances
+	instanceA.Publish(7, testNotification(42, 7))
+
+	got := receive(t, onB)
+	assert.Equal(t, uint(42), got.ID)
+	assert.Equal(t, uint(7), got.UserID)
+	assert.Equal(t, models.NotificationTypeSession, got.Type)
+	assert.Equal(t, "Budi wants to learn React.js", got.Message)
+	assert.JSONEq(t, `{"sessionID":123}`, string(got.Data))
+	assert.True(t, got.CreatedAt.Equal(time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, uint(42), receive(t, onA).ID)
+	assertNothing(t, otherOnB)
+}
+
+func TestRedisPublishDeliversLocallyWhenRedisIsDown(t *testing.T) {
+	srv, err := redistest.NewServer()
+	require.NoError(t, err)
+
+	instance := newRedisInstance(t, srv)
+	ch := make(chan *models.Notification, 1)
+	instance.Subscribe(7, ch)
+
+	srv.Close()
+	instance.Publish(7, testNotification(5, 7))
+
+	assert.Equal(t, uint(5), receive(t, ch).ID)
+}
+
+func TestNewRedisFailsWithoutServer(t *testing.T) {
+	srv, err := redistest.NewServer()
+	require.NoError(t, err)
+	addr := srv.Addr
+	srv.Close()
+
+	_, err = NewRedis(utils.RedisConfig{URL: addr})
+	assert.Error(t, err)
+}
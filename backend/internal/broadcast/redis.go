@@ -0,0 +1,251 @@
+package broadcast
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// redisChannel is the pub/sub channel notifications are relayed on, after the key prefix
+const redisChannel = "notifications"
+
+// redisPresenceKey is the key prefix of the per-user presence sets, after the key prefix
+const redisPresenceKey = "presence:"
+
+// presenceTTL is how long an instance's presence entry lives without a heartbeat, so the
+// users of a crashed instance stop counting as connected
+const presenceTTL = time.Minute
+
+// presenceCloseTimeout bounds how long Close waits to withdraw presence; if Redis is down
+// the entries expire after presenceTTL anyway
+const presenceCloseTimeout = time.Second
+
+// redisEnvelope is a notification relayed between instances
+type redisEnvelope struct {
+	UserID       uint                 `json:"user_id"`
+	Notification *models.Notification `json:"notification"`
+}
+
+// Redis is a Broadcaster relaying notifications through Redis pub/sub
+// Every instance subscribes to one channel and delivers the notifications of the users
+// connected to it, including the ones it published itself. If publishing fails the
+// notification is still delivered locally, so users on the publishing instance get it.
+//
+// Presence is a sorted set per user holding the IDs of the instances the user is connected
+// to, scored by when the entry expires. Each instance refreshes the entries of its users
+// every presenceTTL/3 and removes an entry when the user's last local client leaves.
+type Redis struct {
+	*hub
+	client     *redis.Client
+	pubsub     *redis.PubSub
+	channel    string
+	prefix     string
+	instanceID string
+	done       chan struct{}
+	stop       chan struct{}
+	heartbeat  chan struct{}
+	closeOnce  sync.Once
+	closeErr   error
+}
+
+// NewRedis connects to Redis and starts relaying notifications
+//
+// Parameters:
+//   - config: Redis connection configuration (see utils.GetRedisConfigFromEnv)
+//
+// Returns:
+//   - *Redis: Broadcaster, subscribed before it is returned
+//   - error: If Redis is unavailable
+func NewRedis(config utils.RedisConfig) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.URL,
+		Password: config.Password,
+		DB:       config.DB,
+	})
+
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("redis connection failed: %w", err)
+	}
+
+	channel := config.Prefix + redisChannel
+	pubsub := client.Subscribe(ctx, channel)
+	// Wait for the subscription, so nothing published after NewRedis returns is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		client.Close()
+		return nil, fmt.Errorf("redis subscribe failed: %w", err)
+	}
+
+	instanceID := make([]byte, 8)
+	if _, err := rand.Read(instanceID); err != nil {
+		pubsub.Close()
+		client.Close()
+		return nil, fmt.Errorf("failed to generate instance id: %w", err)
+	}
+
+	r := &Redis{
+		hub:        newHub(),
+		client:     client,
+		pubsub:     pubsub,
+		channel:    channel,
+		prefix:     config.Prefix,
+		instanceID: hex.EncodeToString(instanceID),
+		done:       make(chan struct{}),
+		stop:       make(chan struct{}),
+		heartbeat:  make(chan struct{}),
+	}
+	go r.listen()
+	go r.refreshPresence()
+	return r, nil
+}
+
+// Subscribe registers a local client channel and marks the user present on this instance
+func (r *Redis) Subscribe(userID uint, ch chan *models.Notification) {
+	if r.add(userID, ch) {
+		r.markPresent(userID)
+	}
+}
+
+// Unsubscribe removes a local client channel and, for the user's last local client,
+// removes this instance from the user's presence
+func (r *Redis) Unsubscribe(userID uint, ch chan *models.Notification) {
+	if r.remove(userID, ch) {
+		r.markAbsent(userID)
+	}
+}
+
+// Connected reports whether the user has a client on any instance
+// If Redis cannot be reached only local clients count, so a push is sent rather than lost.
+func (r *Redis) Connected(userID uint) bool {
+	if r.connectedLocally(userID) {
+		return true
+	}
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	count, err := r.client.ZCount(context.Background(), r.presenceKey(userID), "("+now, "+inf").Result()
+	if err != nil {
+		log.Printf("⚠️  Failed to read presence of user %d from Redis: %v", userID, err)
+		return false
+	}
+	return count > 0
+}
+
+// Publish relays a notification to every instance
+func (r *Redis) Publish(userID uint, notification *models.Notification) {
+	payload, err := json.Marshal(redisEnvelope{UserID: userID, Notification: notification})
+	if err == nil {
+		err = r.client.Publish(context.Background(), r.channel, payload).Err()
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to publish notification to Redis, delivering locally: %v", err)
+		r.deliver(userID, notification)
+	}
+}
+
+// Close stops relaying, withdraws this instance's presence and disconnects from Redis
+// Calling it again returns the result of the first call.
+func (r *Redis) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.stop)
+		<-r.heartbeat
+		r.withdrawPresence()
+
+		err := r.pubsub.Close()
+		<-r.done
+		if closeErr := r.client.Close(); err == nil {
+			err = closeErr
+		}
+		r.closeErr = err
+	})
+	return r.closeErr
+}
+
+// listen delivers relayed notifications to local clients until the subscription is closed
+// go-redis resubscribes by itself after a dropped connection.
+func (r *Redis) listen() {
+	defer close(r.done)
+	for msg := range r.pubsub.Channel() {
+		var envelope redisEnvelope
+		if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil || envelope.Notification == nil {
+			log.Printf("⚠️  Ignoring malformed notification from Redis: %v", err)
+			continue
+		}
+		r.deliver(envelope.UserID, envelope.Notification)
+	}
+}
+
+// refreshPresence renews the presence entries of the local users until Close
+func (r *Redis) refreshPresence() {
+	defer close(r.heartbeat)
+	ticker := time.NewTicker(presenceTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, userID := range r.localUsers() {
+				r.markPresent(userID)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// markPresent records that the user is connected to this instance for presenceTTL
+func (r *Redis) markPresent(userID uint) {
+	ctx := context.Background()
+	key := r.presenceKey(userID)
+	expiresAt := time.Now().Add(presenceTTL).UnixMilli()
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(expiresAt), Member: r.instanceID})
+		pipe.PExpire(ctx, key, presenceTTL)
+		return nil
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to record presence of user %d in Redis: %v", userID, err)
+	}
+}
+
+// markAbsent removes this instance from the user's presence
+func (r *Redis) markAbsent(userID uint) {
+	if err := r.client.ZRem(context.Background(), r.presenceKey(userID), r.instanceID).Err(); err != nil {
+		log.Printf("⚠️  Failed to clear presence of user %d in Redis: %v", userID, err)
+	}
+}
+
+// withdrawPresence removes this instance from the presence of all its local users
+func (r *Redis) withdrawPresence() {
+	users := r.localUsers()
+	if len(users) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), presenceCloseTimeout)
+	defer cancel()
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, userID := range users {
+			pipe.ZRem(ctx, r.presenceKey(userID), r.instanceID)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to withdraw presence from Redis: %v", err)
+	}
+}
+
+// presenceKey returns the presence set key of a user
+func (r *Redis) presenceKey(userID uint) string {
+	return r.prefix + redisPresenceKey + strconv.FormatUint(uint64(userID), 10)
+}
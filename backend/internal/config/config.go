@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,11 +12,21 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	CORS     CORSConfig
-	Supabase SupabaseConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	JWT           JWTConfig
+	CORS          CORSConfig
+	Supabase      SupabaseConfig
+	CreditPolicy  CreditPolicyConfig
+	CommunityPool CommunityPoolConfig
+	Fraud         FraudConfig
+	Badges        BadgeConfig
+	Challenges    ChallengeConfig
+	Reviews       ReviewConfig
+	Email         EmailConfig
+	Digest        DigestConfig
+	Realtime      RealtimeConfig
+	Push          PushConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -54,6 +65,108 @@ type SupabaseConfig struct {
 	Key string
 }
 
+// CreditPolicyConfig holds the anti-hoarding rules for credit balances
+// Enabled=false (the default, so deploying changes no balance until opted in) turns every
+// rule off; a zero value for MaxBalance, InactivityMonths or BonusExpiryDays disables that
+// rule only
+type CreditPolicyConfig struct {
+	Enabled          bool          // Whether the policy rules (cap and periodic job) apply
+	MaxBalance       float64       // Balance cap; earnings above it go to the community pool
+	InactivityMonths int           // Months without activity before decay starts
+	DecayRate        float64       // Fraction of the balance above DecayFloor removed per decay run
+	DecayFloor       float64       // Decay never takes a balance below this amount
+	BonusExpiryDays  int           // Days after which unused bonus credits expire
+	NoticeDays       int           // Days between the announcement and the adjustment
+	RunInterval      time.Duration // How often the policy job runs
+}
+
+// CommunityPoolConfig holds the automatic grant rule of the community pool
+type CommunityPoolConfig struct {
+	AutoGrantEnabled      bool          // Whether the automatic grant job runs
+	AutoGrantThreshold    float64       // Students below this available balance are eligible
+	AutoGrantAmount       float64       // Credits granted per automatic grant
+	AutoGrantCooldownDays int           // Minimum days between two grants to the same user
+	AutoGrantBatchSize    int           // Maximum grants per run
+	AutoGrantInterval     time.Duration // How often the automatic grant job runs
+}
+
+// FraudConfig holds the thresholds of the session-farming and transfer-cycle detection job
+type FraudConfig struct {
+	Enabled               bool          // Whether the detection job runs
+	FreezePayouts         bool          // Freeze payouts of flagged users until an admin reviews the report
+	LookbackDays          int           // How far back sessions and transfers are scanned
+	ReciprocalMinSessions int           // Completed sessions between two users (both directions) to flag a pair
+	MinSessionMinutes     int           // Check-in to completion faster than this counts as an instant session
+	InstantMinSessions    int           // Instant sessions by one teacher to flag them
+	CycleMaxLength        int           // Longest transfer cycle searched (A → B → ... → A)
+	CycleMinAmount        float64       // Smallest total per transfer edge considered in a cycle
+	NewAccountDays        int           // Accounts younger than this count as new for clustering
+	ClusterMinSize        int           // New accounts trading only among themselves to flag a cluster
+	RunInterval           time.Duration // How often the detection job runs
+}
+
+// BadgeConfig holds the settings of event-driven badge evaluation
+type BadgeConfig struct {
+	EventsEnabled      bool          // Whether badges are awarded automatically from domain events
+	EvaluationInterval time.Duration // How often recorded events are evaluated (bursts are batched per user)
+}
+
+// ReviewConfig holds the settings of double-blind reviews
+type ReviewConfig struct {
+	RevealWindow   time.Duration // After session completion: reviews can be written until then, when sealed ones are revealed
+	RevealInterval time.Duration // How often sealed reviews whose window closed are revealed
+}
+
+// EmailConfig holds the settings of the notification email channel
+// SMTP credentials are read by utils from SMTP_HOST, SMTP_PORT, SMTP_USER, SMTP_PASS and EMAIL_FROM.
+type EmailConfig struct {
+	Enabled          bool           // Whether queued notification emails are sent
+	DispatchInterval time.Duration  // How often due emails are sent
+	BatchSize        int            // Emails sent per run at most
+	MaxAttempts      int            // SMTP attempts before an email is given up
+	RetryDelay       time.Duration  // Wait after a failed attempt, multiplied by the attempt number
+	MaxAge           time.Duration  // Emails still unsent this long after the notification was created are dropped
+	Location         *time.Location // Time zone quiet hours are read in
+}
+
+// RealtimeConfig holds the settings of real-time notification delivery
+// Redis connection settings are read by utils from REDIS_URL, REDIS_PASSWORD, REDIS_DB and REDIS_PREFIX.
+type RealtimeConfig struct {
+	Broadcaster string // "memory" (single instance) or "redis" (fan-out across replicas)
+}
+
+// PushConfig holds the settings of the Web Push notification channel
+// Generate the VAPID key pair once with `go run ./cmd/vapid-keys` and keep it: browser
+// subscriptions are bound to the public key. Quiet hours are read in the email time zone.
+type PushConfig struct {
+	Enabled          bool          // Whether queued notification pushes are sent
+	VAPIDPublicKey   string        // Uncompressed P-256 public key, base64url (given to browsers)
+	VAPIDPrivateKey  string        // P-256 private key, base64url
+	VAPIDSubject     string        // Contact for push service operators, mailto: or https: URL
+	LiveGrace        time.Duration // How long to wait for a live acknowledgement before checking presence and pushing
+	DispatchInterval time.Duration // How often due pushes are sent
+	BatchSize        int           // Pushes sent per run at most
+	MaxAttempts      int           // Push service attempts before a push is given up
+	RetryDelay       time.Duration // Wait after a failed attempt, multiplied by the attempt number
+	MaxAge           time.Duration // Pushes still unsent this long after they were due are dropped
+	TTL              time.Duration // How long push services keep a message for an offline device
+	AllowedHosts     []string      // Push service hosts subscriptions may point to; a leading dot allows any subdomain
+}
+
+// DigestConfig holds the settings of the weekly digest email
+// Digests are sent in the email time zone (EMAIL_TIMEZONE).
+type DigestConfig struct {
+	Enabled   bool          // Whether weekly digests are sent
+	Interval  time.Duration // How often users due a digest are looked up
+	SendHour  int           // Hour of the user's digest weekday from which the digest is sent
+	BatchSize int           // Digests sent per run at most
+}
+
+// ChallengeConfig holds the settings of challenge progress evaluation
+type ChallengeConfig struct {
+	Enabled            bool          // Whether challenge progress is updated from domain events
+	EvaluationInterval time.Duration // How often recorded events are evaluated
+}
 
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
@@ -95,11 +208,159 @@ func Load() (*Config, error) {
 		},
 	}
 
+	// Parse credit policy run interval
+	policyInterval, err := time.ParseDuration(getEnv("CREDIT_POLICY_INTERVAL", "24h"))
+	if err != nil {
+		policyInterval = 24 * time.Hour
+	}
+	config.CreditPolicy = CreditPolicyConfig{
+		Enabled:          getEnvAsBool("CREDIT_POLICY_ENABLED", false),
+		MaxBalance:       getEnvAsFloat("CREDIT_MAX_BALANCE", 100),
+		InactivityMonths: getEnvAsInt("CREDIT_DECAY_INACTIVITY_MONTHS", 6),
+		DecayRate:        getEnvAsFloat("CREDIT_DECAY_RATE", 0.1),
+		DecayFloor:       getEnvAsFloat("CREDIT_DECAY_FLOOR", 3.0),
+		BonusExpiryDays:  getEnvAsInt("CREDIT_BONUS_EXPIRY_DAYS", 180),
+		NoticeDays:       getEnvAsInt("CREDIT_POLICY_NOTICE_DAYS", 7),
+		RunInterval:      policyInterval,
+	}
+
+	// Parse community pool auto grant interval
+	autoGrantInterval, err := time.ParseDuration(getEnv("POOL_AUTO_GRANT_INTERVAL", "24h"))
+	if err != nil {
+		autoGrantInterval = 24 * time.Hour
+	}
+	config.CommunityPool = CommunityPoolConfig{
+		AutoGrantEnabled:      getEnvAsBool("POOL_AUTO_GRANT_ENABLED", true),
+		AutoGrantThreshold:    getEnvAsFloat("POOL_AUTO_GRANT_THRESHOLD", 1.0),
+		AutoGrantAmount:       getEnvAsFloat("POOL_AUTO_GRANT_AMOUNT", 2.0),
+		AutoGrantCooldownDays: getEnvAsInt("POOL_AUTO_GRANT_COOLDOWN_DAYS", 30),
+		AutoGrantBatchSize:    getEnvAsInt("POOL_AUTO_GRANT_BATCH_SIZE", 50),
+		AutoGrantInterval:     autoGrantInterval,
+	}
+
+	// Parse fraud detection interval
+	fraudInterval, err := time.ParseDuration(getEnv("FRAUD_DETECTION_INTERVAL", "6h"))
+	if err != nil {
+		fraudInterval = 6 * time.Hour
+	}
+	config.Fraud = FraudConfig{
+		Enabled:               getEnvAsBool("FRAUD_DETECTION_ENABLED", true),
+		FreezePayouts:         getEnvAsBool("FRAUD_FREEZE_PAYOUTS", false),
+		LookbackDays:          getEnvAsInt("FRAUD_LOOKBACK_DAYS", 30),
+		ReciprocalMinSessions: getEnvAsInt("FRAUD_RECIPROCAL_MIN_SESSIONS", 4),
+		MinSessionMinutes:     getEnvAsInt("FRAUD_MIN_SESSION_MINUTES", 10),
+		InstantMinSessions:    getEnvAsInt("FRAUD_INSTANT_MIN_SESSIONS", 3),
+		CycleMaxLength:        getEnvAsInt("FRAUD_CYCLE_MAX_LENGTH", 4),
+		CycleMinAmount:        getEnvAsFloat("FRAUD_CYCLE_MIN_AMOUNT", 1.0),
+		NewAccountDays:        getEnvAsInt("FRAUD_NEW_ACCOUNT_DAYS", 14),
+		ClusterMinSize:        getEnvAsInt("FRAUD_CLUSTER_MIN_SIZE", 5),
+		RunInterval:           fraudInterval,
+	}
+
+	badgeInterval, err := time.ParseDuration(getEnv("BADGE_EVALUATION_INTERVAL", "5s"))
+	if err != nil {
+		badgeInterval = 5 * time.Second
+	}
+	config.Badges = BadgeConfig{
+		EventsEnabled:      getEnvAsBool("BADGE_EVENTS_ENABLED", true),
+		EvaluationInterval: badgeInterval,
+	}
+
+	challengeInterval, err := time.ParseDuration(getEnv("CHALLENGE_EVALUATION_INTERVAL", "10s"))
+	if err != nil {
+		challengeInterval = 10 * time.Second
+	}
+	config.Challenges = ChallengeConfig{
+		Enabled:            getEnvAsBool("CHALLENGES_ENABLED", true),
+		EvaluationInterval: challengeInterval,
+	}
+
+	revealInterval, err := time.ParseDuration(getEnv("REVIEW_REVEAL_INTERVAL", "15m"))
+	if err != nil {
+		revealInterval = 15 * time.Minute
+	}
+	config.Reviews = ReviewConfig{
+		RevealWindow:   time.Duration(getEnvAsInt("REVIEW_REVEAL_WINDOW_DAYS", 14)) * 24 * time.Hour,
+		RevealInterval: revealInterval,
+	}
+
+	emailInterval, err := time.ParseDuration(getEnv("EMAIL_DISPATCH_INTERVAL", "1m"))
+	if err != nil {
+		emailInterval = time.Minute
+	}
+	emailRetryDelay, err := time.ParseDuration(getEnv("EMAIL_RETRY_DELAY", "5m"))
+	if err != nil {
+		emailRetryDelay = 5 * time.Minute
+	}
+	emailLocation, err := time.LoadLocation(getEnv("EMAIL_TIMEZONE", "Asia/Jakarta"))
+	if err != nil {
+		emailLocation = time.UTC
+	}
+	config.Email = EmailConfig{
+		Enabled:          getEnvAsBool("EMAIL_NOTIFICATIONS_ENABLED", true),
+		DispatchInterval: emailInterval,
+		BatchSize:        getEnvAsInt("EMAIL_BATCH_SIZE", 50),
+		MaxAttempts:      getEnvAsInt("EMAIL_MAX_ATTEMPTS", 5),
+		RetryDelay:       emailRetryDelay,
+		MaxAge:           time.Duration(getEnvAsInt("EMAIL_MAX_AGE_HOURS", 24)) * time.Hour,
+		Location:         emailLocation,
+	}
+
+	digestInterval, err := time.ParseDuration(getEnv("DIGEST_INTERVAL", "1h"))
+	if err != nil {
+		digestInterval = time.Hour
+	}
+	config.Realtime = RealtimeConfig{
+		Broadcaster: getEnv("NOTIFICATION_BROADCASTER", "memory"),
+	}
+
+	pushGrace, err := time.ParseDuration(getEnv("PUSH_LIVE_GRACE", "30s"))
+	if err != nil {
+		pushGrace = 30 * time.Second
+	}
+	pushInterval, err := time.ParseDuration(getEnv("PUSH_DISPATCH_INTERVAL", "15s"))
+	if err != nil {
+		pushInterval = 15 * time.Second
+	}
+	pushRetryDelay, err := time.ParseDuration(getEnv("PUSH_RETRY_DELAY", "1m"))
+	if err != nil {
+		pushRetryDelay = time.Minute
+	}
+	pushMaxAge, err := time.ParseDuration(getEnv("PUSH_MAX_AGE", "6h"))
+	if err != nil {
+		pushMaxAge = 6 * time.Hour
+	}
+	pushTTL, err := time.ParseDuration(getEnv("PUSH_TTL", "24h"))
+	if err != nil {
+		pushTTL = 24 * time.Hour
+	}
+	config.Push = PushConfig{
+		Enabled:          getEnvAsBool("PUSH_NOTIFICATIONS_ENABLED", true),
+		VAPIDPublicKey:   getEnv("VAPID_PUBLIC_KEY", ""),
+		VAPIDPrivateKey:  getEnv("VAPID_PRIVATE_KEY", ""),
+		VAPIDSubject:     getEnv("VAPID_SUBJECT", "mailto:admin@wibi.id"),
+		LiveGrace:        pushGrace,
+		DispatchInterval: pushInterval,
+		BatchSize:        getEnvAsInt("PUSH_BATCH_SIZE", 100),
+		MaxAttempts:      getEnvAsInt("PUSH_MAX_ATTEMPTS", 5),
+		RetryDelay:       pushRetryDelay,
+		MaxAge:           pushMaxAge,
+		TTL:              pushTTL,
+		AllowedHosts:     parsePushHosts(getEnv("PUSH_ALLOWED_HOSTS", defaultPushHosts)),
+	}
+
+	config.Digest = DigestConfig{
+		Enabled:   getEnvAsBool("DIGEST_ENABLED", true),
+		Interval:  digestInterval,
+		SendHour:  getEnvAsInt("DIGEST_SEND_HOUR", 8),
+		BatchSize: getEnvAsInt("DIGEST_BATCH_SIZE", 100),
+	}
+
 	// Validate required fields
 	if config.Database.Password == "" {
 		return nil, fmt.Errorf("DB_PASSWORD is required")
 	}
-	
+
 	// Enforce JWT_SECRET validation - must be set and not default
 	if config.JWT.Secret == "" {
 		return nil, fmt.Errorf("JWT_SECRET is required")
@@ -129,6 +390,24 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return strings.ToLower(val) == "true" || val == "1"
 }
 
+// getEnvAsInt gets environment variable as an integer with fallback
+func getEnvAsInt(key string, defaultValue int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return val
+}
+
+// getEnvAsFloat gets environment variable as a float with fallback
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	val, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return val
+}
+
 // parseAllowedOrigins parses comma-separated origins from environment variable
 func parseAllowedOrigins(originsStr string) []string {
 	if originsStr == "" {
@@ -151,6 +430,21 @@ func parseAllowedOrigins(originsStr string) []string {
 	return parsed
 }
 
+// defaultPushHosts are the push services of Chrome, Firefox, Edge and Safari
+const defaultPushHosts = "fcm.googleapis.com,.push.services.mozilla.com,.notify.windows.com,.push.apple.com"
+
+// parsePushHosts parses comma-separated push service hosts from environment variable
+func parsePushHosts(hostsStr string) []string {
+	var parsed []string
+	for _, host := range strings.Split(hostsStr, ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			parsed = append(parsed, host)
+		}
+	}
+	return parsed
+}
+
 // GetDSN returns database connection string
 func (c *DatabaseConfig) GetDSN() string {
 	return fmt.Sprintf(
@@ -106,6 +106,11 @@ func SeedInitialData() error {
     log.Printf("⚠️  Warning: Failed to seed badges: %v", err)
   }
 
+  // Seed review tags (has its own duplicate check)
+  if err := seedReviewTags(); err != nil {
+    log.Printf("⚠️  Warning: Failed to seed review tags: %v", err)
+  }
+
   log.Println("Initial data seeding completed")
   return nil
 }
@@ -236,6 +241,42 @@ func seedBadges() error {
   return nil
 }
 
+// seedReviewTags creates the initial review tag vocabulary
+func seedReviewTags() error {
+  tags := []models.ReviewTag{
+    // Teacher tags (given by students)
+    {Slug: "explains-clearly", Label: "Explains clearly", Type: models.ReviewTypeTeacher, SortOrder: 1},
+    {Slug: "patient", Label: "Patient", Type: models.ReviewTypeTeacher, SortOrder: 2},
+    {Slug: "knowledgeable", Label: "Knowledgeable", Type: models.ReviewTypeTeacher, SortOrder: 3},
+    {Slug: "well-prepared", Label: "Well prepared", Type: models.ReviewTypeTeacher, SortOrder: 4},
+    {Slug: "punctual", Label: "Punctual", Type: models.ReviewTypeTeacher, SortOrder: 5},
+    {Slug: "encouraging", Label: "Encouraging", Type: models.ReviewTypeTeacher, SortOrder: 6},
+    {Slug: "practical-examples", Label: "Practical examples", Type: models.ReviewTypeTeacher, SortOrder: 7},
+
+    // Student tags (given by teachers)
+    {Slug: "eager-to-learn", Label: "Eager to learn", Type: models.ReviewTypeStudent, SortOrder: 1},
+    {Slug: "student-well-prepared", Label: "Well prepared", Type: models.ReviewTypeStudent, SortOrder: 2},
+    {Slug: "student-punctual", Label: "Punctual", Type: models.ReviewTypeStudent, SortOrder: 3},
+    {Slug: "asks-good-questions", Label: "Asks good questions", Type: models.ReviewTypeStudent, SortOrder: 4},
+    {Slug: "respectful", Label: "Respectful", Type: models.ReviewTypeStudent, SortOrder: 5},
+    {Slug: "does-the-practice", Label: "Does the practice", Type: models.ReviewTypeStudent, SortOrder: 6},
+  }
+
+  for _, tag := range tags {
+    // Check if tag already exists (including deleted ones, which admins removed on purpose)
+    var existing models.ReviewTag
+    result := DB.Unscoped().Where("slug = ?", tag.Slug).First(&existing)
+    if result.Error == gorm.ErrRecordNotFound {
+      // Create new tag
+      if err := DB.Create(&tag).Error; err != nil {
+        return fmt.Errorf("failed to seed review tag %s: %w", tag.Slug, err)
+      }
+    }
+  }
+
+  return nil
+}
+
 // seedAdmin creates initial admin user
 func seedAdmin() error {
   // Check if admin already exists
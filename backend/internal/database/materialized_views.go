@@ -23,9 +23,12 @@ import (
 func CreateMaterializedViews(db *gorm.DB) error {
 	fmt.Println("Creating materialized views for performance optimization...")
 
+	// marker is text the current definition contains; an existing view without it
+	// predates a definition change and is recreated
 	views := []struct {
-		name string
-		sql  string
+		name   string
+		sql    string
+		marker string
 	}{
 		{
 			name: "leaderboard_badges",
@@ -93,7 +96,8 @@ func CreateMaterializedViews(db *gorm.DB) error {
 					COALESCE(AVG(r.rating), 0) AS calculated_avg_rating
 				FROM users u
 				LEFT JOIN sessions s ON s.teacher_id = u.id AND s.status = 'completed'
-				LEFT JOIN reviews r ON r.session_id = s.id
+				LEFT JOIN reviews r ON r.session_id = s.id AND r.reviewee_id = u.id
+					AND r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL
 				WHERE u.is_active = true 
 					AND u.deleted_at IS NULL
 					AND u.total_sessions_as_teacher > 0
@@ -102,6 +106,7 @@ func CreateMaterializedViews(db *gorm.DB) error {
 				HAVING COUNT(r.id) >= 3
 				ORDER BY calculated_avg_rating DESC, review_count DESC
 			`,
+			marker: "is_sealed",
 		},
 		{
 			name: "leaderboard_credits",
@@ -139,16 +144,21 @@ func CreateMaterializedViews(db *gorm.DB) error {
 				LEFT JOIN user_skills us ON us.skill_id = s.id AND us.is_available = true
 				LEFT JOIN learning_skills ls ON ls.skill_id = s.id
 				LEFT JOIN sessions sess ON sess.user_skill_id = us.id AND sess.status = 'completed'
-				LEFT JOIN reviews r ON r.session_id = sess.id
+				LEFT JOIN reviews r ON r.session_id = sess.id AND r.type = 'teacher'
+					AND r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL
 				WHERE s.deleted_at IS NULL
 				GROUP BY s.id, s.name, s.category
 				ORDER BY session_count DESC
 			`,
+			marker: "is_sealed",
 		},
 	}
 
 	// Create each materialized view
 	for _, view := range views {
+		if view.marker != "" {
+			dropOutdatedView(db, view.name, view.marker)
+		}
 		if err := db.Exec(view.sql).Error; err != nil {
 			fmt.Printf("⚠️  Warning: Could not create materialized view %s: %v\n", view.name, err)
 			// Continue with other views, don't fail completely
@@ -178,6 +188,20 @@ func CreateMaterializedViews(db *gorm.DB) error {
 	return nil
 }
 
+// dropOutdatedView drops a materialized view whose stored definition lacks marker,
+// so CREATE ... IF NOT EXISTS recreates it with the current definition
+func dropOutdatedView(db *gorm.DB, name, marker string) {
+	var outdated int64
+	db.Raw("SELECT COUNT(*) FROM pg_matviews WHERE matviewname = ? AND definition NOT LIKE ?",
+		name, "%"+marker+"%").Scan(&outdated)
+	if outdated == 0 {
+		return
+	}
+	if err := db.Exec(fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s", name)).Error; err != nil {
+		fmt.Printf("⚠️  Warning: Could not drop outdated materialized view %s: %v\n", name, err)
+	}
+}
+
 // RefreshMaterializedViews refreshes all materialized views
 // Should be called periodically (e.g., every 5-15 minutes via cron/scheduler)
 //
@@ -30,6 +30,11 @@ func RunMigrations(db *gorm.DB) error {
 		return fmt.Errorf("failed to add missing columns: %w", err)
 	}
 
+	// Enforce one active award per user and badge
+	if err := ensureUniqueUserBadges(db); err != nil {
+		return fmt.Errorf("failed to enforce unique user badges: %w", err)
+	}
+
 	// Add performance indexes
 	if err := createPerformanceIndexes(db); err != nil {
 		return fmt.Errorf("failed to create indexes: %w", err)
@@ -38,6 +43,30 @@ func RunMigrations(db *gorm.DB) error {
 	return nil
 }
 
+// ensureUniqueUserBadges creates the partial unique index on user_badges(user_id, badge_id)
+// AutoMigrate cannot create it while duplicate awards exist, so duplicates are soft deleted
+// first, keeping the earliest award of each badge.
+func ensureUniqueUserBadges(db *gorm.DB) error {
+	if db.Migrator().HasIndex("user_badges", "idx_user_badges_active") {
+		return nil
+	}
+
+	result := db.Exec(`
+		UPDATE user_badges SET deleted_at = NOW()
+		WHERE deleted_at IS NULL AND id NOT IN (
+			SELECT MIN(id) FROM user_badges WHERE deleted_at IS NULL GROUP BY user_id, badge_id
+		)`)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove duplicate user badges: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		fmt.Printf("  ✓ Removed %d duplicate user badges\n", result.RowsAffected)
+	}
+
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_user_badges_active
+		ON user_badges(user_id, badge_id) WHERE deleted_at IS NULL`).Error
+}
+
 // addMissingColumns adds columns to existing tables that may be missing
 // This handles schema evolution when new fields are added to models
 func addMissingColumns(db *gorm.DB) error {
@@ -278,7 +307,7 @@ func DropPerformanceIndexes(db *gorm.DB) error {
 
 	for _, idx := range indexes {
 		if db.Migrator().HasIndex(idx.table, idx.name) {
-			if err := db.Migrator().DropIndex(idx.table, idx.name).Error; err != nil {
+			if err := db.Migrator().DropIndex(idx.table, idx.name); err != nil {
 				return fmt.Errorf("failed to drop index %s.%s: %w", idx.table, idx.name, err)
 			}
 		}
@@ -44,3 +44,29 @@ type AdminChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required,min=8"`
 }
+
+// CreateCreditAdjustmentRequest represents an admin proposing a balance correction
+type CreateCreditAdjustmentRequest struct {
+	UserID uint    `json:"user_id" binding:"required"`
+	Amount float64 `json:"amount" binding:"required"` // Positive to credit, negative to debit
+	Reason string  `json:"reason" binding:"required,min=10,max=500"`
+}
+
+// ReviewCreditAdjustmentRequest represents a second admin approving or rejecting an adjustment
+type ReviewCreditAdjustmentRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// FraudScanResult reports how many new fraud reports a detection run raised per signal
+type FraudScanResult struct {
+	ReciprocalPairs int  `json:"reciprocal_pairs"`
+	InstantSessions int  `json:"instant_sessions"`
+	TransferCycles  int  `json:"transfer_cycles"`
+	NewAccountRings int  `json:"new_account_rings"`
+	FreezePayouts   bool `json:"freeze_payouts"` // Whether flagged users' payouts were frozen
+}
+
+// Total returns the number of reports raised
+func (r *FraudScanResult) Total() int {
+	return r.ReciprocalPairs + r.InstantSessions + r.TransferCycles + r.NewAccountRings
+}
@@ -1,40 +1,42 @@
 package dto
 
 import (
+	"encoding/json"
+
 	"github.com/timebankingskill/backend/internal/models"
 )
 
 // BadgeResponse represents a badge in API responses
 type BadgeResponse struct {
-	ID           uint   `json:"id"`
-	Name         string `json:"name"`
-	Description  string `json:"description"`
-	Icon         string `json:"icon"`
-	Type         string `json:"type"`
-	Requirements string `json:"requirements"`
+	ID           uint    `json:"id"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Icon         string  `json:"icon"`
+	Type         string  `json:"type"`
+	Requirements string  `json:"requirements"`
 	BonusCredits float64 `json:"bonus_credits"`
-	Rarity       int    `json:"rarity"`
-	TotalAwarded int    `json:"total_awarded"`
-	TotalEarned  int    `json:"total_earned"`
-	Color        string `json:"color"`
-	IsActive     bool   `json:"is_active"`
-	DisplayOrder int    `json:"display_order"`
-	CreatedAt    string `json:"created_at"`
-	UpdatedAt    string `json:"updated_at"`
+	Rarity       int     `json:"rarity"`
+	TotalAwarded int     `json:"total_awarded"`
+	TotalEarned  int     `json:"total_earned"`
+	Color        string  `json:"color"`
+	IsActive     bool    `json:"is_active"`
+	DisplayOrder int     `json:"display_order"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
 }
 
 // UserBadgeResponse represents a badge earned by a user
 type UserBadgeResponse struct {
-	ID             uint           `json:"id"`
-	UserID         uint           `json:"user_id"`
-	BadgeID        uint           `json:"badge_id"`
-	Badge          *BadgeResponse `json:"badge,omitempty"`
-	EarnedAt       string         `json:"earned_at"`
-	Progress       int            `json:"progress"`
-	ProgressGoal   int            `json:"progress_goal"`
-	ProgressPercent float64       `json:"progress_percent"`
-	IsPinned       bool           `json:"is_pinned"`
-	IsCompleted    bool           `json:"is_completed"`
+	ID              uint           `json:"id"`
+	UserID          uint           `json:"user_id"`
+	BadgeID         uint           `json:"badge_id"`
+	Badge           *BadgeResponse `json:"badge,omitempty"`
+	EarnedAt        string         `json:"earned_at"`
+	Progress        int            `json:"progress"`
+	ProgressGoal    int            `json:"progress_goal"`
+	ProgressPercent float64        `json:"progress_percent"`
+	IsPinned        bool           `json:"is_pinned"`
+	IsCompleted     bool           `json:"is_completed"`
 }
 
 // LeaderboardEntry represents a user in leaderboard
@@ -44,16 +46,38 @@ type LeaderboardEntry struct {
 	FullName  string `json:"full_name"`
 	Avatar    string `json:"avatar"`
 	Score     int    `json:"score"`
-	ScoreType string `json:"score_type"` // badges, rarity, sessions, rating, credits
+	RawScore  *int   `json:"raw_score,omitempty"` // rating: plain average x100, Score being the adjusted one
+	ScoreType string `json:"score_type"`          // badges, rarity, sessions, rating, credits
 	Rank      int    `json:"rank,omitempty"`
 }
 
 // LeaderboardResponse represents leaderboard data
 type LeaderboardResponse struct {
+	Type      string             `json:"type"`
+	Entries   []LeaderboardEntry `json:"entries"`
+	Total     int                `json:"total"`
+	UpdatedAt string             `json:"updated_at"`
+}
+
+// LeaderboardQuery selects a leaderboard's period and scope
+// Period is all (default), week, month or semester; at most one of School, SkillID
+// and Category may be set, otherwise the leaderboard is global.
+type LeaderboardQuery struct {
+	Period   string `form:"period"`
+	School   string `form:"school"`
+	SkillID  uint   `form:"skill_id"`
+	Category string `form:"category"`
+}
+
+// MyRankResponse is the caller's position on a leaderboard with their neighbours
+// Rank and Score are 0 when the caller has no score on the leaderboard.
+type MyRankResponse struct {
 	Type       string             `json:"type"`
-	Entries    []LeaderboardEntry `json:"entries"`
-	Total      int                `json:"total"`
-	UpdatedAt  string             `json:"updated_at"`
+	Period     string             `json:"period"`
+	Rank       int                `json:"rank"`
+	Score      int                `json:"score"`
+	Total      int64              `json:"total"`
+	Neighbours []LeaderboardEntry `json:"neighbours"`
 }
 
 // MapBadgeToResponse maps a Badge model to BadgeResponse
@@ -90,15 +114,15 @@ func MapBadgesToResponse(badges []models.Badge) []BadgeResponse {
 // MapUserBadgeToResponse maps a UserBadge model to UserBadgeResponse
 func MapUserBadgeToResponse(userBadge *models.UserBadge) *UserBadgeResponse {
 	resp := &UserBadgeResponse{
-		ID:             userBadge.ID,
-		UserID:         userBadge.UserID,
-		BadgeID:        userBadge.BadgeID,
-		EarnedAt:       userBadge.EarnedAt.Format("2006-01-02T15:04:05Z07:00"),
-		Progress:       userBadge.Progress,
-		ProgressGoal:   userBadge.ProgressGoal,
+		ID:              userBadge.ID,
+		UserID:          userBadge.UserID,
+		BadgeID:         userBadge.BadgeID,
+		EarnedAt:        userBadge.EarnedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Progress:        userBadge.Progress,
+		ProgressGoal:    userBadge.ProgressGoal,
 		ProgressPercent: userBadge.ProgressPercentage(),
-		IsPinned:       userBadge.IsPinned,
-		IsCompleted:    userBadge.IsCompleted(),
+		IsPinned:        userBadge.IsPinned,
+		IsCompleted:     userBadge.IsCompleted(),
 	}
 
 	if userBadge.Badge.ID > 0 {
@@ -117,3 +141,99 @@ func MapUserBadgesToResponse(userBadges []models.UserBadge) []UserBadgeResponse
 	}
 	return responses
 }
+
+// CreateBadgeRequest represents an admin request to create a badge
+// Requirements is a badge rule (see service.BadgeRule) and is validated before saving.
+type CreateBadgeRequest struct {
+	Name         string          `json:"name" binding:"required,max=100"`
+	Description  string          `json:"description"`
+	Icon         string          `json:"icon"`
+	Type         string          `json:"type" binding:"required,oneof=achievement milestone quality special"`
+	Requirements json.RawMessage `json:"requirements" binding:"required"`
+	BonusCredits float64         `json:"bonus_credits" binding:"min=0,max=100"`
+	Rarity       int             `json:"rarity" binding:"omitempty,min=1,max=5"`
+	Color        string          `json:"color"`
+	IsActive     *bool           `json:"is_active"`
+	DisplayOrder int             `json:"display_order"`
+	Backfill     bool            `json:"backfill"` // Start a background job awarding the badge to everyone already qualifying
+}
+
+// UpdateBadgeRequest represents an admin request to update a badge (omitted fields are unchanged)
+type UpdateBadgeRequest struct {
+	Name         *string         `json:"name" binding:"omitempty,max=100"`
+	Description  *string         `json:"description"`
+	Icon         *string         `json:"icon"`
+	Type         *string         `json:"type" binding:"omitempty,oneof=achievement milestone quality special"`
+	Requirements json.RawMessage `json:"requirements"`
+	BonusCredits *float64        `json:"bonus_credits" binding:"omitempty,min=0,max=100"`
+	Rarity       *int            `json:"rarity" binding:"omitempty,min=1,max=5"`
+	Color        *string         `json:"color"`
+	IsActive     *bool           `json:"is_active"`
+	DisplayOrder *int            `json:"display_order"`
+	Backfill     bool            `json:"backfill"` // Start a background job awarding the badge to everyone already qualifying
+}
+
+// BadgeDryRunRequest represents a request to preview who qualifies for badge requirements
+// With BadgeID set, users who already hold that badge are counted separately.
+type BadgeDryRunRequest struct {
+	Requirements json.RawMessage `json:"requirements" binding:"required"`
+	BadgeID      uint            `json:"badge_id"`
+	SampleSize   int             `json:"sample_size" binding:"omitempty,min=1,max=50"`
+}
+
+// BadgeQualifier is a user in a dry-run sample
+type BadgeQualifier struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+	Avatar   string `json:"avatar"`
+}
+
+// BadgeDryRunResponse reports how many users qualify for badge requirements today
+type BadgeDryRunResponse struct {
+	UsersChecked   int              `json:"users_checked"`
+	Qualifying     int              `json:"qualifying"`
+	AlreadyAwarded int              `json:"already_awarded"` // Qualifying users who already hold the badge
+	WouldBeAwarded int              `json:"would_be_awarded"`
+	Sample         []BadgeQualifier `json:"sample"`
+}
+
+// AdminBadgeResponse is a created or updated badge with the backfill job, if one was requested
+// The backfill runs in the background; poll GET /api/v1/admin/badges/backfills/:id for its outcome.
+type AdminBadgeResponse struct {
+	Badge    *BadgeResponse        `json:"badge"`
+	Backfill *models.BadgeBackfill `json:"backfill,omitempty"`
+}
+
+// BadgeConditionProgress is one condition of a badge rule with the user's current value
+type BadgeConditionProgress struct {
+	Metric      string  `json:"metric"`
+	Description string  `json:"description"`
+	Op          string  `json:"op"`
+	Target      float64 `json:"target"`
+	Actual      float64 `json:"actual"`
+	WindowDays  int     `json:"window_days,omitempty"`
+	Met         bool    `json:"met"`
+	Percent     int     `json:"percent"`
+}
+
+// BadgeProgressResponse is a user's progress toward a badge they have not earned yet
+// Progress and ProgressGoal describe the condition furthest from being met, e.g. 7 of 10 sessions.
+type BadgeProgressResponse struct {
+	Badge        *BadgeResponse           `json:"badge"`
+	Percent      int                      `json:"percent"`
+	Metric       string                   `json:"metric"`
+	Progress     float64                  `json:"progress"`
+	ProgressGoal float64                  `json:"progress_goal"`
+	Conditions   []BadgeConditionProgress `json:"conditions"`
+}
+
+// RevokeBadgeRequest represents an admin request to take a badge back from a user
+// The remaining badge bonus is clawed back unless KeepBonus is set.
+type RevokeBadgeRequest struct {
+	UserID       uint   `json:"user_id" binding:"required"`
+	Reason       string `json:"reason" binding:"required,max=500"`
+	KeepBonus    bool   `json:"keep_bonus"`
+	Override     bool   `json:"override"`      // Claw back the full bonus even if the balance goes below zero
+	AllowReaward bool   `json:"allow_reaward"` // Let the user earn the badge again when they qualify
+}
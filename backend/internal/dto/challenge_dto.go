@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+// CreateChallengeRequest represents an admin request to create a challenge
+// Goal uses the badge requirements format; conditions count activity from StartsAt,
+// so window_days is not allowed.
+type CreateChallengeRequest struct {
+	Title           string          `json:"title" binding:"required,max=200"`
+	Description     string          `json:"description"`
+	Icon            string          `json:"icon"`
+	Season          string          `json:"season" binding:"max=100"`
+	Goal            json.RawMessage `json:"goal" binding:"required"`
+	StartsAt        time.Time       `json:"starts_at" binding:"required"`
+	EndsAt          time.Time       `json:"ends_at" binding:"required"`
+	RewardCredits   float64         `json:"reward_credits" binding:"min=0"`
+	RewardBadgeID   *uint           `json:"reward_badge_id"`
+	MaxParticipants int             `json:"max_participants" binding:"min=0"`
+	IsActive        *bool           `json:"is_active"`
+}
+
+// UpdateChallengeRequest represents an admin request to update a challenge
+// Omitted fields are left unchanged; RewardBadgeID 0 removes the badge reward.
+type UpdateChallengeRequest struct {
+	Title           *string         `json:"title" binding:"omitempty,max=200"`
+	Description     *string         `json:"description"`
+	Icon            *string         `json:"icon"`
+	Season          *string         `json:"season" binding:"omitempty,max=100"`
+	Goal            json.RawMessage `json:"goal"`
+	StartsAt        *time.Time      `json:"starts_at"`
+	EndsAt          *time.Time      `json:"ends_at"`
+	RewardCredits   *float64        `json:"reward_credits" binding:"omitempty,min=0"`
+	RewardBadgeID   *uint           `json:"reward_badge_id"`
+	MaxParticipants *int            `json:"max_participants" binding:"omitempty,min=0"`
+	IsActive        *bool           `json:"is_active"`
+}
+
+// ChallengeProgress is a participant's progress in a challenge
+type ChallengeProgress struct {
+	Percent     int                      `json:"percent"`
+	Completed   bool                     `json:"completed"`
+	CompletedAt *time.Time               `json:"completed_at,omitempty"`
+	Rank        int                      `json:"rank"`
+	Conditions  []BadgeConditionProgress `json:"conditions"`
+}
+
+// ChallengeResponse represents a challenge in API responses
+// Enrolled and Progress describe the requesting user.
+type ChallengeResponse struct {
+	ID               uint               `json:"id"`
+	Title            string             `json:"title"`
+	Description      string             `json:"description"`
+	Icon             string             `json:"icon"`
+	Season           string             `json:"season"`
+	Goal             json.RawMessage    `json:"goal"`
+	StartsAt         time.Time          `json:"starts_at"`
+	EndsAt           time.Time          `json:"ends_at"`
+	Status           string             `json:"status"`
+	RewardCredits    float64            `json:"reward_credits"`
+	RewardBadge      *BadgeResponse     `json:"reward_badge,omitempty"`
+	MaxParticipants  int                `json:"max_participants"`
+	ParticipantCount int                `json:"participant_count"`
+	CompletedCount   int                `json:"completed_count"`
+	IsActive         bool               `json:"is_active"`
+	Enrolled         bool               `json:"enrolled"`
+	Progress         *ChallengeProgress `json:"progress,omitempty"`
+}
+
+// ChallengeLeaderboardEntry is a participant in a challenge leaderboard
+type ChallengeLeaderboardEntry struct {
+	Rank        int        `json:"rank"`
+	UserID      uint       `json:"user_id"`
+	Username    string     `json:"username"`
+	FullName    string     `json:"full_name"`
+	Avatar      string     `json:"avatar"`
+	Percent     int        `json:"percent"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// MapChallengeToResponse converts a challenge to its response (without user progress)
+func MapChallengeToResponse(challenge *models.Challenge, now time.Time) *ChallengeResponse {
+	response := &ChallengeResponse{
+		ID:               challenge.ID,
+		Title:            challenge.Title,
+		Description:      challenge.Description,
+		Icon:             challenge.Icon,
+		Season:           challenge.Season,
+		Goal:             json.RawMessage(challenge.Goal),
+		StartsAt:         challenge.StartsAt,
+		EndsAt:           challenge.EndsAt,
+		Status:           string(challenge.StatusAt(now)),
+		RewardCredits:    challenge.RewardCredits,
+		MaxParticipants:  challenge.MaxParticipants,
+		ParticipantCount: challenge.ParticipantCount,
+		CompletedCount:   challenge.CompletedCount,
+		IsActive:         challenge.IsActive,
+	}
+	if challenge.RewardBadge != nil {
+		response.RewardBadge = MapBadgeToResponse(challenge.RewardBadge)
+	}
+	return response
+}
@@ -156,3 +156,22 @@ type EndorsementResponse struct {
 	CreatedAt   string               `json:"created_at"`
 	UpdatedAt   string               `json:"updated_at"`
 }
+
+// ===== COMMUNITY POOL DTOs =====
+
+// CommunityPoolStatsResponse is the public summary of the community pool
+type CommunityPoolStatsResponse struct {
+	Balance            float64 `json:"balance"`
+	TotalDonated       float64 `json:"total_donated"`
+	TotalDistributed   float64 `json:"total_distributed"`
+	TotalRedistributed float64 `json:"total_redistributed"` // From balance cap, decay and bonus expiry
+	DonorCount         int64   `json:"donor_count"`
+	RecipientCount     int64   `json:"recipient_count"`
+}
+
+// CommunityPoolGrantRequest is the request for an admin grant from the pool
+type CommunityPoolGrantRequest struct {
+	UserID uint    `json:"user_id" binding:"required"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Reason string  `json:"reason" binding:"required,max=500"`
+}
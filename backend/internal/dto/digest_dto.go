@@ -0,0 +1,79 @@
+package dto
+
+import "time"
+
+// WeeklyDigestResponse is a user's weekly digest, as emailed and previewed
+type WeeklyDigestResponse struct {
+	PeriodStart        time.Time             `json:"period_start"`
+	PeriodEnd          time.Time             `json:"period_end"`
+	UpcomingSessions   []DigestSession       `json:"upcoming_sessions"`
+	PendingRequests    []DigestRequest       `json:"pending_requests"` // Session requests awaiting the user's approval
+	NewTeachers        []DigestTeacher       `json:"new_teachers"`     // New teachers for skills on the user's wishlist
+	ThreadReplies      []DigestThreadReplies `json:"thread_replies"`   // New replies in threads the user authored
+	Credits            DigestCredits         `json:"credits"`
+	NearlyEarnedBadges []DigestBadge         `json:"nearly_earned_badges"` // Badges close to completion
+}
+
+// DigestSession is an upcoming session in the weekly digest
+type DigestSession struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Duration    float64   `json:"duration"`
+	Mode        string    `json:"mode"`
+	Role        string    `json:"role"` // teacher or student
+	PartnerName string    `json:"partner_name"`
+}
+
+// DigestRequest is a pending session request in the weekly digest
+type DigestRequest struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	StudentName string     `json:"student_name"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	RequestedAt time.Time  `json:"requested_at"`
+}
+
+// DigestTeacher is a new teacher of a wishlist skill in the weekly digest
+type DigestTeacher struct {
+	UserSkillID uint   `json:"user_skill_id"`
+	SkillName   string `json:"skill_name"`
+	TeacherID   uint   `json:"teacher_id"`
+	TeacherName string `json:"teacher_name"`
+	Level       string `json:"level"`
+}
+
+// DigestThreadReplies counts new replies to one of the user's threads in the weekly digest
+type DigestThreadReplies struct {
+	ThreadID    uint      `json:"thread_id"`
+	Title       string    `json:"title"`
+	NewReplies  int       `json:"new_replies"`
+	LastReplyAt time.Time `json:"last_reply_at"`
+}
+
+// DigestCredits summarizes the user's credit balance changes in the weekly digest
+type DigestCredits struct {
+	Balance      float64 `json:"balance"`
+	Earned       float64 `json:"earned"` // Balance increases
+	Spent        float64 `json:"spent"`  // Balance decreases; escrow holds are not spent yet
+	Net          float64 `json:"net"`
+	Transactions int     `json:"transactions"` // Transactions that changed the balance
+}
+
+// DigestBadge is a badge close to completion in the weekly digest
+type DigestBadge struct {
+	BadgeID uint   `json:"badge_id"`
+	Name    string `json:"name"`
+	Icon    string `json:"icon"`
+	Percent int    `json:"percent"`
+}
+
+// IsEmpty reports whether the digest has nothing to tell the user
+func (d *WeeklyDigestResponse) IsEmpty() bool {
+	return len(d.UpcomingSessions) == 0 &&
+		len(d.PendingRequests) == 0 &&
+		len(d.NewTeachers) == 0 &&
+		len(d.ThreadReplies) == 0 &&
+		d.Credits.Transactions == 0 &&
+		len(d.NearlyEarnedBadges) == 0
+}
@@ -57,6 +57,8 @@ type UpdateNotificationPreferencesRequest struct {
 	QuietHours               bool   `json:"quietHours"`
 	QuietHoursStart          string `json:"quietHoursStart"`
 	QuietHoursEnd            string `json:"quietHoursEnd"`
+	WeeklyDigest             *bool  `json:"weeklyDigest"`                                  // Unchanged if omitted
+	DigestWeekday            *int   `json:"digestWeekday" binding:"omitempty,min=0,max=6"` // 0 = Sunday; unchanged if omitted
 }
 
 // NotificationPreferencesResponse represents notification preferences in API responses
@@ -72,6 +74,8 @@ type NotificationPreferencesResponse struct {
 	QuietHours               bool   `json:"quietHours"`
 	QuietHoursStart          string `json:"quietHoursStart"`
 	QuietHoursEnd            string `json:"quietHoursEnd"`
+	WeeklyDigest             bool   `json:"weeklyDigest"`
+	DigestWeekday            int    `json:"digestWeekday"`
 }
 
 // MapPreferencesToResponse converts a NotificationPreference model to response DTO
@@ -88,5 +92,7 @@ func MapPreferencesToResponse(pref *models.NotificationPreference) NotificationP
 		QuietHours:               pref.QuietHours,
 		QuietHoursStart:          pref.QuietHoursStart,
 		QuietHoursEnd:            pref.QuietHoursEnd,
+		WeeklyDigest:             pref.WeeklyDigest,
+		DigestWeekday:            pref.DigestWeekday,
 	}
 }
@@ -0,0 +1,16 @@
+package dto
+
+// PushSubscriptionRequest registers a browser push subscription
+// It is the JSON of the browser's PushSubscription (subscription.toJSON()).
+type PushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required,url"`
+	Keys     struct {
+		P256dh string `json:"p256dh" binding:"required"`
+		Auth   string `json:"auth" binding:"required"`
+	} `json:"keys" binding:"required"`
+}
+
+// DeletePushSubscriptionRequest removes a browser push subscription
+type DeletePushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+}
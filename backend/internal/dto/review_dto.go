@@ -9,7 +9,7 @@ type CreateReviewRequest struct {
 	SessionID           uint    `json:"session_id" binding:"required"`
 	Rating              int     `json:"rating" binding:"required,min=1,max=5"`
 	Comment             string  `json:"comment" binding:"omitempty,max=1000"`
-	Tags                string  `json:"tags" binding:"omitempty,max=200"` // Comma-separated or JSON
+	Tags                string  `json:"tags" binding:"omitempty,max=200"` // Comma-separated review tag slugs or labels
 	CommunicationRating *int    `json:"communication_rating" binding:"omitempty,min=1,max=5"`
 	PunctualityRating   *int    `json:"punctuality_rating" binding:"omitempty,min=1,max=5"`
 	KnowledgeRating     *int    `json:"knowledge_rating" binding:"omitempty,min=1,max=5"`
@@ -39,8 +39,12 @@ type ReviewResponse struct {
 	PunctualityRating   *int                `json:"punctuality_rating"`
 	KnowledgeRating     *int                `json:"knowledge_rating"`
 	HelpfulCount        int                 `json:"helpful_count"`
+	Reply               string              `json:"reply,omitempty"`
+	RepliedAt           *string             `json:"replied_at,omitempty"`
 	IsReported          bool                `json:"is_reported"`
 	IsHidden            bool                `json:"is_hidden"`
+	IsSealed            bool                `json:"is_sealed"`             // Not yet visible to the reviewee
+	RevealedAt          *string             `json:"revealed_at,omitempty"` // When the reviewee could first see it
 	Reviewer            *UserProfileResponse `json:"reviewer,omitempty"`
 	Reviewee            *UserProfileResponse `json:"reviewee,omitempty"`
 	CreatedAt           string              `json:"created_at"`
@@ -55,6 +59,57 @@ type ReviewListResponse struct {
 	Offset  int              `json:"offset"`
 }
 
+// ReplyReviewRequest represents the reviewee's public reply to a review
+type ReplyReviewRequest struct {
+	Reply string `json:"reply" binding:"required,max=1000"`
+}
+
+// ReportReviewRequest represents a user reporting a review for moderation
+// Reason is one of models.ReviewReportReasons.
+type ReportReviewRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Details string `json:"details" binding:"omitempty,max=1000"`
+}
+
+// ModerateReviewRequest represents an admin hiding, unhiding or dismissing reports on a review
+type ModerateReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// CreateReviewTagRequest represents an admin adding a tag to the review vocabulary
+// Type is the review type the tag can be given in: teacher or student.
+type CreateReviewTagRequest struct {
+	Slug      string `json:"slug" binding:"required,max=50"`
+	Label     string `json:"label" binding:"required,max=100"`
+	Type      string `json:"type" binding:"required,oneof=teacher student"`
+	IsActive  *bool  `json:"is_active"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// UpdateReviewTagRequest represents an admin updating a review tag (omitted fields are unchanged)
+type UpdateReviewTagRequest struct {
+	Label     *string `json:"label" binding:"omitempty,min=1,max=100"`
+	IsActive  *bool   `json:"is_active"`
+	SortOrder *int    `json:"sort_order"`
+}
+
+// ReviewModerationItem is a review in the moderation queue with its pending reports
+type ReviewModerationItem struct {
+	Review      ReviewResponse `json:"review"`
+	ReportCount int            `json:"report_count"`
+	Reasons     map[string]int `json:"reasons"` // Pending reports per reason
+	Reports     []ReportDetail `json:"reports"`
+}
+
+// ReportDetail is a pending report shown in the moderation queue
+type ReportDetail struct {
+	ID         uint   `json:"id"`
+	ReportedBy *uint  `json:"reported_by"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
 // MapReviewToResponse maps a Review model to ReviewResponse
 func MapReviewToResponse(review *models.Review) *ReviewResponse {
 	resp := &ReviewResponse{
@@ -72,10 +127,25 @@ func MapReviewToResponse(review *models.Review) *ReviewResponse {
 		HelpfulCount:        review.HelpfulCount,
 		IsReported:          review.IsReported,
 		IsHidden:            review.IsHidden,
+		IsSealed:            review.IsSealed,
 		CreatedAt:           review.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:           review.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
+	if review.RevealedAt != nil {
+		revealedAt := review.RevealedAt.Format("2006-01-02T15:04:05Z07:00")
+		resp.RevealedAt = &revealedAt
+	}
+
+	// Map the reviewee's reply
+	if review.Reply != "" {
+		resp.Reply = review.Reply
+		if review.RepliedAt != nil {
+			repliedAt := review.RepliedAt.Format("2006-01-02T15:04:05Z07:00")
+			resp.RepliedAt = &repliedAt
+		}
+	}
+
 	// Map reviewer
 	if review.Reviewer.ID > 0 {
 		resp.Reviewer = &UserProfileResponse{
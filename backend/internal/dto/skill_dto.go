@@ -32,7 +32,9 @@ type SkillResponse struct {
 	MinRate          float64   `json:"min_rate"`
 	MaxRate          float64   `json:"max_rate"`
 	MaxTeacherRating float64   `json:"max_teacher_rating"`
-	CreatedAt        time.Time `json:"created_at"`
+	// Best Bayesian, recency-weighted teacher rating; only on recommendations
+	MaxTeacherAdjustedRating float64   `json:"max_teacher_adjusted_rating,omitempty"`
+	CreatedAt                time.Time `json:"created_at"`
 }
 
 type SkillListResponse struct {
@@ -87,6 +89,8 @@ type UserSkillResponse struct {
 	TotalSessions     int           `json:"total_sessions"`
 	AverageRating     float64       `json:"average_rating"`
 	TotalReviews      int           `json:"total_reviews"`
+	Rating            *models.RatingScore `json:"rating,omitempty"` // Raw and adjusted rating; only on teacher lists
+	Tags              []models.ReviewTagCount `json:"tags,omitempty"` // Review tag cloud for the skill; only on teacher lists
 	CreatedAt         time.Time     `json:"created_at"`
 	UpdatedAt         time.Time     `json:"updated_at"`
 }
@@ -125,6 +129,7 @@ func ToSkillResponse(skill *models.Skill) SkillResponse {
 		MinRate:          skill.MinRate,
 		MaxRate:          skill.MaxRate,
 		MaxTeacherRating: skill.MaxTeacherRating,
+		MaxTeacherAdjustedRating: skill.MaxTeacherAdjustedRating,
 		CreatedAt:        skill.CreatedAt,
 	}
 }
@@ -146,6 +151,8 @@ func ToUserSkillResponse(userSkill *models.UserSkill) UserSkillResponse {
 		TotalSessions:     userSkill.TotalSessions,
 		AverageRating:     userSkill.AverageRating,
 		TotalReviews:      userSkill.TotalReviews,
+		Rating:            userSkill.Rating,
+		Tags:              userSkill.Tags,
 		CreatedAt:         userSkill.CreatedAt,
 		UpdatedAt:         userSkill.UpdatedAt,
 	}
@@ -1,4 +1,6 @@
-package dto 
+package dto
+
+import "time"
 
 // TransferCreditsRequest represents a peer-to-peer credit transfer request
 type TransferCreditsRequest struct {
@@ -14,4 +16,61 @@ type TransferCreditsResponse struct {
 	Amount      float64 `json:"amount"`
 	NewBalance  float64 `json:"new_balance"`
 	Message     string  `json:"message"`
-}
\ No newline at end of file
+}
+
+// CreateCreditRequestRequest represents a request asking another user to send credits
+type CreateCreditRequestRequest struct {
+	PayerID        uint    `json:"payer_id" binding:"required"`
+	Amount         float64 `json:"amount" binding:"required,gt=0"`
+	Message        string  `json:"message" binding:"max=500"`
+	ExpiresInHours int     `json:"expires_in_hours" binding:"omitempty,min=1,max=168"` // Defaults to 72
+}
+
+// DeclineCreditRequestRequest represents the payer declining a credit request
+type DeclineCreditRequestRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// CreditRequestResponse represents a credit request with both parties
+type CreditRequestResponse struct {
+	ID            uint               `json:"id"`
+	Requester     *UserPublicProfile `json:"requester"`
+	Payer         *UserPublicProfile `json:"payer"`
+	Amount        float64            `json:"amount"`
+	Message       string             `json:"message"`
+	Status        string             `json:"status"`
+	DeclineReason string             `json:"decline_reason,omitempty"`
+	TransactionID *uint              `json:"transaction_id,omitempty"`
+	ExpiresAt     time.Time          `json:"expires_at"`
+	RespondedAt   *time.Time         `json:"responded_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// StatementLine is one ledger line of a monthly statement
+type StatementLine struct {
+	Date         time.Time `json:"date"`
+	Type         string    `json:"type"`
+	Description  string    `json:"description"`
+	SessionTitle string    `json:"session_title,omitempty"`
+	Amount       float64   `json:"amount"`
+	BalanceAfter float64   `json:"balance_after"`
+}
+
+// MonthlyStatement is a user's account statement for one calendar month
+type MonthlyStatement struct {
+	UserID          uint            `json:"user_id"`
+	FullName        string          `json:"full_name"`
+	Email           string          `json:"email"`
+	Month           string          `json:"month"` // yyyy-mm
+	PeriodStart     time.Time       `json:"period_start"`
+	PeriodEnd       time.Time       `json:"period_end"` // Exclusive
+	OpeningBalance  float64         `json:"opening_balance"`
+	ClosingBalance  float64         `json:"closing_balance"`
+	TotalCredited   float64         `json:"total_credited"` // Balance increases; escrow holds and releases are excluded
+	TotalDebited    float64         `json:"total_debited"`  // Balance decreases
+	HoursTaught     float64         `json:"hours_taught"`
+	HoursLearned    float64         `json:"hours_learned"`
+	SessionsTaught  int             `json:"sessions_taught"`
+	SessionsLearned int             `json:"sessions_learned"`
+	Lines           []StatementLine `json:"lines"`
+}
@@ -46,19 +46,21 @@ type UserProfileResponse struct {
 }
 
 type PublicProfileResponse struct {
-	ID                     uint      `json:"id"`
-	FullName               string    `json:"full_name"`
-	Username               string    `json:"username"`
-	School                 string    `json:"school"`
-	Grade                  string    `json:"grade"`
-	Major                  string    `json:"major"`
-	Bio                    string    `json:"bio"`
-	Avatar                 string    `json:"avatar"`
-	Location               string    `json:"location"`
-	TotalSessionsAsTeacher int       `json:"total_sessions_as_teacher"`
-	AverageRatingAsTeacher float64   `json:"average_rating_as_teacher"`
-	TotalTeachingHours     float64   `json:"total_teaching_hours"`
-	CreatedAt              time.Time `json:"created_at"`
+	ID                     uint                    `json:"id"`
+	FullName               string                  `json:"full_name"`
+	Username               string                  `json:"username"`
+	School                 string                  `json:"school"`
+	Grade                  string                  `json:"grade"`
+	Major                  string                  `json:"major"`
+	Bio                    string                  `json:"bio"`
+	Avatar                 string                  `json:"avatar"`
+	Location               string                  `json:"location"`
+	TotalSessionsAsTeacher int                     `json:"total_sessions_as_teacher"`
+	AverageRatingAsTeacher float64                 `json:"average_rating_as_teacher"`
+	TotalTeachingHours     float64                 `json:"total_teaching_hours"`
+	TeacherTags            []models.ReviewTagCount `json:"teacher_tags"` // Review tags received as teacher, most given first
+	StudentTags            []models.ReviewTagCount `json:"student_tags"` // Review tags received as student, most given first
+	CreatedAt              time.Time               `json:"created_at"`
 }
 
 // UserPublicProfile is a minimal user profile for embedding in other responses
@@ -0,0 +1,113 @@
+// Package events is an in-process domain event bus
+//
+// Services publish what happened (a session completed, a review was written, ...)
+// without knowing who reacts to it; subscribers such as badge evaluation register
+// at startup. Handlers run synchronously inside Publish, so they must be cheap and
+// must not block: record the event and do the work elsewhere.
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Type identifies a domain event
+type Type string
+
+const (
+	SessionCompleted   Type = "session.completed"         // Teacher and student of a completed session
+	ReviewCreated      Type = "review.created"            // Reviewer and reviewee of a new or edited review
+	ReviewDeleted      Type = "review.deleted"            // Reviewer and reviewee of a deleted review
+	TransferMade       Type = "transfer.made"             // Sender and recipient of a peer credit transfer
+	ForumThreadCreated Type = "forum.thread_created"      // Author of a new forum thread
+	ForumReplyCreated  Type = "forum.reply_created"       // Author of a new forum reply
+	ForumReplyDeleted  Type = "forum.reply_deleted"       // Author of a deleted forum reply
+	DonationMade       Type = "donation.made"             // Donor to the community pool
+	BadgeAwarded       Type = "badge.awarded"             // User who earned a badge
+	BadgeRevoked       Type = "badge.revoked"             // User who lost a badge
+	BadgesChanged      Type = "badge.definitions_changed" // An admin created, edited or deleted a badge (no users)
+)
+
+// Event is something that happened to one or more users
+type Event struct {
+	Type       Type
+	UserIDs    []uint
+	Data       map[string]interface{}
+	OccurredAt time.Time
+}
+
+// Handler reacts to an event
+type Handler func(Event)
+
+// Bus dispatches events to the handlers subscribed to their type
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[Type][]Handler
+	all      []Handler
+}
+
+// NewBus creates an empty event bus
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[Type][]Handler)}
+}
+
+// Default is the process-wide bus used by Publish and Subscribe
+var Default = NewBus()
+
+// Subscribe registers a handler for the given event types, or for every event if none are given
+func (b *Bus) Subscribe(handler Handler, types ...Type) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(types) == 0 {
+		b.all = append(b.all, handler)
+		return
+	}
+	for _, t := range types {
+		b.handlers[t] = append(b.handlers[t], handler)
+	}
+}
+
+// Publish delivers an event to its subscribers
+// A panicking handler is logged and does not affect the publisher or other handlers.
+func (b *Bus) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[event.Type])+len(b.all))
+	handlers = append(handlers, b.handlers[event.Type]...)
+	handlers = append(handlers, b.all...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		dispatch(handler, event)
+	}
+}
+
+// dispatch runs one handler, recovering from panics
+func dispatch(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("⚠️  Event handler for %s panicked: %v", event.Type, r)
+		}
+	}()
+	handler(event)
+}
+
+// Publish publishes an event on the Default bus
+func Publish(eventType Type, userIDs ...uint) {
+	Default.Publish(Event{Type: eventType, UserIDs: userIDs})
+}
+
+// PublishWithData publishes an event with extra data on the Default bus
+func PublishWithData(eventType Type, data map[string]interface{}, userIDs ...uint) {
+	Default.Publish(Event{Type: eventType, UserIDs: userIDs, Data: data})
+}
+
+// Subscribe registers a handler on the Default bus
+func Subscribe(handler Handler, types ...Type) {
+	Default.Subscribe(handler, types...)
+}
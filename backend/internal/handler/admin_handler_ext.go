@@ -53,14 +53,15 @@ func (h *AdminHandler) GetAllSessions(c *gin.Context) {
 }
 
 // GetAllTransactions gets all transactions (admin only)
-// GET /api/v1/admin/transactions
+// GET /api/v1/admin/transactions?type=adjustment&user_id=1
 func (h *AdminHandler) GetAllTransactions(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	search := c.Query("search")
 	typeFilter := c.Query("type")
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
 
-	transactions, total, err := h.adminService.GetAllTransactions(page, limit, typeFilter, search)
+	transactions, total, err := h.adminService.GetAllTransactions(page, limit, typeFilter, search, uint(userID))
 	if err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch transactions", err)
 		return
@@ -175,6 +176,25 @@ func (h *AdminHandler) ActivateUser(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "User activated successfully", nil)
 }
 
+// ReleasePayouts lifts a user's payout freeze set by fraud detection
+// POST /api/v1/admin/users/:id/release-payouts
+func (h *AdminHandler) ReleasePayouts(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	user, err := h.adminService.ReleasePayouts(uint(id))
+	if err != nil {
+		utils.SendError(c, utils.MapErrorToStatus(err), "Failed to release payouts", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Payouts released successfully", user)
+}
+
 // ResolveReport resolves a report
 // POST /api/v1/admin/reports/:id/resolve
 func (h *AdminHandler) ResolveReport(c *gin.Context) {
@@ -185,7 +205,7 @@ func (h *AdminHandler) ResolveReport(c *gin.Context) {
 		return
 	}
 
-	adminID := c.GetUint("user_id")
+	adminID := c.GetUint("admin_id")
 
 	if err := h.adminService.ResolveReport(uint(id), adminID, "Resolved by admin"); err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Failed to resolve report", err)
@@ -205,7 +225,7 @@ func (h *AdminHandler) DismissReport(c *gin.Context) {
 		return
 	}
 
-	adminID := c.GetUint("user_id")
+	adminID := c.GetUint("admin_id")
 
 	if err := h.adminService.DismissReport(uint(id), adminID); err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Failed to dismiss report", err)
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/service"
 	"github.com/timebankingskill/backend/internal/utils"
 )
@@ -106,6 +108,27 @@ func (h *BadgeHandler) GetUserBadgesByType(c *gin.Context) {
 	})
 }
 
+// GetBadgeProgress retrieves the current user's progress toward badges they have not earned yet
+// GET /api/v1/user/badges/progress
+func (h *BadgeHandler) GetBadgeProgress(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	progress, err := h.badgeService.GetBadgeProgress(userID)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch badge progress", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Badge progress retrieved successfully", gin.H{
+		"progress": progress,
+		"total":    len(progress),
+	})
+}
+
 // CheckAndAwardBadges checks if user qualifies for any badges and awards them
 // POST /api/v1/user/badges/check
 func (h *BadgeHandler) CheckAndAwardBadges(c *gin.Context) {
@@ -178,219 +201,322 @@ func (h *BadgeHandler) PinBadge(c *gin.Context) {
 	})
 }
 
+// GetBadgeLeaderboard ranks users by badge count
+// Query: limit, period=all|week|month|semester, and one of school, skill_id, category
+// GET /api/v1/leaderboard/badges
 func (h *BadgeHandler) GetBadgeLeaderboard(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10
+	h.getLeaderboard(c, models.LeaderboardBadges)
+}
+
+// GetRarityLeaderboard ranks users by badge rarity
+// GET /api/v1/leaderboard/rarity
+func (h *BadgeHandler) GetRarityLeaderboard(c *gin.Context) {
+	h.getLeaderboard(c, models.LeaderboardRarity)
+}
+
+// GetSessionLeaderboard ranks users by completed sessions
+// GET /api/v1/leaderboard/sessions
+func (h *BadgeHandler) GetSessionLeaderboard(c *gin.Context) {
+	h.getLeaderboard(c, models.LeaderboardSessions)
+}
+
+// GetRatingLeaderboard ranks users by average rating
+// GET /api/v1/leaderboard/rating
+func (h *BadgeHandler) GetRatingLeaderboard(c *gin.Context) {
+	h.getLeaderboard(c, models.LeaderboardRating)
+}
+
+// GetCreditLeaderboard ranks users by credits earned
+// GET /api/v1/leaderboard/credits
+func (h *BadgeHandler) GetCreditLeaderboard(c *gin.Context) {
+	h.getLeaderboard(c, models.LeaderboardCredits)
+}
+
+// GetMyRank gets the caller's position on a leaderboard with their neighbours
+// Query: type (default badges), around (default 2, max 10), and the leaderboard filters
+// GET /api/v1/leaderboard/me
+func (h *BadgeHandler) GetMyRank(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	board := models.LeaderboardType(c.DefaultQuery("type", string(models.LeaderboardBadges)))
+	if _, ok := leaderboardCacheKeys[board]; !ok {
+		utils.SendError(c, http.StatusBadRequest, "Invalid type, expected badges, rarity, sessions, rating or credits", nil)
+		return
+	}
+	var query dto.LeaderboardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	around, err := strconv.Atoi(c.DefaultQuery("around", "2"))
+	if err != nil {
+		around = 2
 	}
 
 	// Try to get from cache
-	cacheKey := fmt.Sprintf("%s:%d", utils.CacheKeyLeaderboardBadge, limit)
-	var cachedLeaderboard []dto.LeaderboardEntry
-	if utils.GetCache().GetJSON(cacheKey, &cachedLeaderboard) {
-		utils.SendSuccess(c, http.StatusOK, "Badge leaderboard retrieved from cache", gin.H{
-			"type":    "badges",
-			"entries": cachedLeaderboard,
-			"total":   len(cachedLeaderboard),
-		})
+	cacheKey := fmt.Sprintf("%s:me:%d:%d", leaderboardCacheKey(board, query), around, userID)
+	var cachedRank dto.MyRankResponse
+	if utils.GetCache().GetJSON(cacheKey, &cachedRank) {
+		utils.SendSuccess(c, http.StatusOK, "Leaderboard rank retrieved from cache", cachedRank)
 		return
 	}
 
-	leaderboard, err := h.badgeService.GetBadgeLeaderboard(limit)
+	rank, err := h.badgeService.GetMyRank(userID, board, query, around)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch leaderboard", err)
+		sendLeaderboardError(c, err)
 		return
 	}
 
-	// Add rank
-	for i := range leaderboard {
-		leaderboard[i].Rank = i + 1
-	}
+	utils.GetCache().SetWithTTL(cacheKey, rank, 5*time.Minute)
 
-	// Save to cache (TTL: 5 minutes)
-	utils.GetCache().SetWithTTL(cacheKey, leaderboard, 5*time.Minute)
+	utils.SendSuccess(c, http.StatusOK, "Leaderboard rank retrieved successfully", rank)
+}
 
-	utils.SendSuccess(c, http.StatusOK, "Badge leaderboard retrieved successfully", gin.H{
-		"type":    "badges",
-		"entries": leaderboard,
-		"total":   len(leaderboard),
-	})
+// leaderboardCacheKeys maps each leaderboard to its cache key prefix
+var leaderboardCacheKeys = map[models.LeaderboardType]string{
+	models.LeaderboardBadges:   utils.CacheKeyLeaderboardBadge,
+	models.LeaderboardRarity:   utils.CacheKeyLeaderboardRarity,
+	models.LeaderboardSessions: utils.CacheKeyLeaderboardSession,
+	models.LeaderboardRating:   utils.CacheKeyLeaderboardRating,
+	models.LeaderboardCredits:  utils.CacheKeyLeaderboardCredit,
 }
 
-func (h *BadgeHandler) GetRarityLeaderboard(c *gin.Context) {
+// leaderboardCacheKey builds the cache key of a leaderboard period and scope
+func leaderboardCacheKey(board models.LeaderboardType, query dto.LeaderboardQuery) string {
+	scope := "global"
+	switch {
+	case query.School != "":
+		scope = "school=" + query.School
+	case query.SkillID != 0:
+		scope = fmt.Sprintf("skill=%d", query.SkillID)
+	case query.Category != "":
+		scope = "category=" + query.Category
+	}
+	period := query.Period
+	if period == "" {
+		period = string(models.PeriodAllTime)
+	}
+	return fmt.Sprintf("%s:%s:%s", leaderboardCacheKeys[board], period, scope)
+}
+
+// getLeaderboard serves a leaderboard, cached for 5 minutes per period, scope and limit
+func (h *BadgeHandler) getLeaderboard(c *gin.Context, board models.LeaderboardType) {
 	limitStr := c.DefaultQuery("limit", "10")
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 || limit > 100 {
 		limit = 10
 	}
+	var query dto.LeaderboardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
 
 	// Try to get from cache
-	cacheKey := fmt.Sprintf("%s:%d", utils.CacheKeyLeaderboardRarity, limit)
+	cacheKey := fmt.Sprintf("%s:%d", leaderboardCacheKey(board, query), limit)
 	var cachedLeaderboard []dto.LeaderboardEntry
 	if utils.GetCache().GetJSON(cacheKey, &cachedLeaderboard) {
-		utils.SendSuccess(c, http.StatusOK, "Rarity leaderboard retrieved from cache", gin.H{
-			"type":    "rarity",
+		utils.SendSuccess(c, http.StatusOK, "Leaderboard retrieved from cache", gin.H{
+			"type":    board,
 			"entries": cachedLeaderboard,
 			"total":   len(cachedLeaderboard),
 		})
 		return
 	}
 
-	leaderboard, err := h.badgeService.GetRarityLeaderboard(limit)
+	leaderboard, err := h.badgeService.GetLeaderboard(board, query, limit)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch leaderboard", err)
+		sendLeaderboardError(c, err)
 		return
 	}
 
-	// Add rank
-	for i := range leaderboard {
-		leaderboard[i].Rank = i + 1
-	}
-
-	// Save to cache
+	// Save to cache (TTL: 5 minutes)
 	utils.GetCache().SetWithTTL(cacheKey, leaderboard, 5*time.Minute)
 
-	utils.SendSuccess(c, http.StatusOK, "Rarity leaderboard retrieved successfully", gin.H{
-		"type":    "rarity",
+	utils.SendSuccess(c, http.StatusOK, "Leaderboard retrieved successfully", gin.H{
+		"type":    board,
 		"entries": leaderboard,
 		"total":   len(leaderboard),
 	})
 }
 
-func (h *BadgeHandler) GetSessionLeaderboard(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10
-	}
-
-	// Try to get from cache
-	cacheKey := fmt.Sprintf("%s:%d", utils.CacheKeyLeaderboardSession, limit)
-	var cachedLeaderboard []dto.LeaderboardEntry
-	if utils.GetCache().GetJSON(cacheKey, &cachedLeaderboard) {
-		utils.SendSuccess(c, http.StatusOK, "Session leaderboard retrieved from cache", gin.H{
-			"type":    "sessions",
-			"entries": cachedLeaderboard,
-			"total":   len(cachedLeaderboard),
-		})
+// sendLeaderboardError maps leaderboard service errors to HTTP responses
+func sendLeaderboardError(c *gin.Context, err error) {
+	if errors.Is(err, utils.ErrInvalidLeaderboard) {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
+	utils.SendError(c, http.StatusInternalServerError, "Failed to fetch leaderboard", err)
+}
 
-	leaderboard, err := h.badgeService.GetSessionLeaderboard(limit)
+// DeleteBadge deletes a badge by ID, revoking it from its holders (admin only)
+// Pass ?clawback=true to also take back the holders' remaining badge bonus.
+// DELETE /api/v1/admin/badges/:id
+func (h *BadgeHandler) DeleteBadge(c *gin.Context) {
+	badgeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch leaderboard", err)
+		utils.SendError(c, http.StatusBadRequest, "Invalid badge ID", err)
 		return
 	}
 
-	// Add rank
-	for i := range leaderboard {
-		leaderboard[i].Rank = i + 1
+	revoked, err := h.badgeService.DeleteBadge(c.GetUint("admin_id"), uint(badgeID), c.Query("clawback") == "true")
+	if err != nil {
+		h.sendAdminError(c, err, "Failed to delete badge")
+		return
 	}
 
-	// Save to cache
-	utils.GetCache().SetWithTTL(cacheKey, leaderboard, 5*time.Minute)
-
-	utils.SendSuccess(c, http.StatusOK, "Session leaderboard retrieved successfully", gin.H{
-		"type":    "sessions",
-		"entries": leaderboard,
-		"total":   len(leaderboard),
-	})
+	utils.SendSuccess(c, http.StatusOK, "Badge deleted successfully", gin.H{"revoked": revoked})
 }
 
-func (h *BadgeHandler) GetRatingLeaderboard(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10
+// RevokeBadge takes a badge back from a user and claws back its bonus (admin only)
+// POST /api/v1/admin/badges/:id/revoke
+func (h *BadgeHandler) RevokeBadge(c *gin.Context) {
+	badgeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid badge ID", err)
+		return
 	}
 
-	// Try to get from cache
-	cacheKey := fmt.Sprintf("%s:%d", utils.CacheKeyLeaderboardRating, limit)
-	var cachedLeaderboard []dto.LeaderboardEntry
-	if utils.GetCache().GetJSON(cacheKey, &cachedLeaderboard) {
-		utils.SendSuccess(c, http.StatusOK, "Rating leaderboard retrieved from cache", gin.H{
-			"type":    "rating",
-			"entries": cachedLeaderboard,
-			"total":   len(cachedLeaderboard),
-		})
+	var req dto.RevokeBadgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
 		return
 	}
 
-	leaderboard, err := h.badgeService.GetRatingLeaderboard(limit)
+	revocation, err := h.badgeService.RevokeBadge(c.GetUint("admin_id"), uint(badgeID), &req)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch leaderboard", err)
+		h.sendAdminError(c, err, "Failed to revoke badge")
 		return
 	}
 
-	// Add rank
-	for i := range leaderboard {
-		leaderboard[i].Rank = i + 1
+	utils.SendSuccess(c, http.StatusOK, "Badge revoked successfully", revocation)
+}
+
+// GetRevocations lists badge revocations, filtered by ?user_id= and ?badge_id= (admin only)
+// GET /api/v1/admin/badges/revocations
+func (h *BadgeHandler) GetRevocations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	badgeID, _ := strconv.ParseUint(c.Query("badge_id"), 10, 32)
+
+	revocations, total, err := h.badgeService.GetRevocations(uint(userID), uint(badgeID), page, limit)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch badge revocations", err)
+		return
 	}
 
-	// Save to cache
-	utils.GetCache().SetWithTTL(cacheKey, leaderboard, 5*time.Minute)
+	utils.SendSuccess(c, http.StatusOK, "Badge revocations retrieved successfully", gin.H{
+		"data":  revocations,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
 
-	utils.SendSuccess(c, http.StatusOK, "Rating leaderboard retrieved successfully", gin.H{
-		"type":    "rating",
-		"entries": leaderboard,
-		"total":   len(leaderboard),
+// GetBadgeMetrics lists the metrics badge requirements can use (admin only)
+// GET /api/v1/admin/badges/metrics
+func (h *BadgeHandler) GetBadgeMetrics(c *gin.Context) {
+	utils.SendSuccess(c, http.StatusOK, "Badge metrics retrieved successfully", gin.H{
+		"metrics":   service.ListBadgeMetrics(),
+		"operators": []string{">=", ">", "<=", "<", "==", "!="},
 	})
 }
 
-func (h *BadgeHandler) GetCreditLeaderboard(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10
+// CreateBadge creates a badge, optionally starting a backfill job awarding it to everyone already qualifying (admin only)
+// POST /api/v1/admin/badges
+func (h *BadgeHandler) CreateBadge(c *gin.Context) {
+	var req dto.CreateBadgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
 	}
 
-	// Try to get from cache
-	cacheKey := fmt.Sprintf("%s:%d", utils.CacheKeyLeaderboardCredit, limit)
-	var cachedLeaderboard []dto.LeaderboardEntry
-	if utils.GetCache().GetJSON(cacheKey, &cachedLeaderboard) {
-		utils.SendSuccess(c, http.StatusOK, "Credit leaderboard retrieved from cache", gin.H{
-			"type":    "credits",
-			"entries": cachedLeaderboard,
-			"total":   len(cachedLeaderboard),
-		})
+	badge, err := h.badgeService.CreateBadge(&req)
+	if err != nil {
+		h.sendAdminError(c, err, "Failed to create badge")
 		return
 	}
 
-	leaderboard, err := h.badgeService.GetCreditLeaderboard(limit)
+	utils.SendSuccess(c, http.StatusCreated, "Badge created successfully", badge)
+}
+
+// UpdateBadge updates a badge, optionally starting a backfill job awarding it to everyone already qualifying (admin only)
+// PUT /api/v1/admin/badges/:id
+func (h *BadgeHandler) UpdateBadge(c *gin.Context) {
+	badgeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch leaderboard", err)
+		utils.SendError(c, http.StatusBadRequest, "Invalid badge ID", err)
 		return
 	}
 
-	// Add rank
-	for i := range leaderboard {
-		leaderboard[i].Rank = i + 1
+	var req dto.UpdateBadgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
 	}
 
-	// Save to cache
-	utils.GetCache().SetWithTTL(cacheKey, leaderboard, 5*time.Minute)
+	badge, err := h.badgeService.UpdateBadge(uint(badgeID), &req)
+	if err != nil {
+		h.sendAdminError(c, err, "Failed to update badge")
+		return
+	}
 
-	utils.SendSuccess(c, http.StatusOK, "Credit leaderboard retrieved successfully", gin.H{
-		"type":    "credits",
-		"entries": leaderboard,
-		"total":   len(leaderboard),
-	})
+	utils.SendSuccess(c, http.StatusOK, "Badge updated successfully", badge)
 }
 
-// DeleteBadge deletes a badge by ID (admin only)
-// DELETE /api/v1/admin/badges/:id
-func (h *BadgeHandler) DeleteBadge(c *gin.Context) {
-	badgeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+// DryRunBadge previews how many users qualify for badge requirements today (admin only)
+// POST /api/v1/admin/badges/dry-run
+func (h *BadgeHandler) DryRunBadge(c *gin.Context) {
+	var req dto.BadgeDryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	result, err := h.badgeService.DryRunBadge(&req)
 	if err != nil {
-		utils.SendError(c, http.StatusBadRequest, "Invalid badge ID", err)
+		h.sendAdminError(c, err, "Failed to run badge dry run")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Badge dry run completed", result)
+}
+
+// GetBackfill returns the progress or outcome of a badge backfill job (admin only)
+// GET /api/v1/admin/badges/backfills/:id
+func (h *BadgeHandler) GetBackfill(c *gin.Context) {
+	backfillID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid backfill ID", err)
 		return
 	}
 
-	if err := h.badgeService.DeleteBadge(uint(badgeID)); err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed to delete badge", err)
+	backfill, err := h.badgeService.GetBackfill(uint(backfillID))
+	if err != nil {
+		h.sendAdminError(c, err, "Failed to fetch badge backfill")
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Badge deleted successfully", nil)
+	utils.SendSuccess(c, http.StatusOK, "Badge backfill retrieved successfully", backfill)
+}
+
+// sendAdminError maps badge authoring errors to HTTP status codes
+func (h *BadgeHandler) sendAdminError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidBadgeRule), errors.Is(err, utils.ErrInvalidBadge),
+		errors.Is(err, utils.ErrInvalidRevocation):
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, utils.ErrBadgeNotFound), errors.Is(err, utils.ErrBadgeNotHeld),
+		errors.Is(err, utils.ErrBackfillNotFound):
+		utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, utils.ErrBadgeNameTaken):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, fallback, err)
+	}
 }
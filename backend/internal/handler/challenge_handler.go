@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// ChallengeHandler handles challenge-related HTTP requests
+type ChallengeHandler struct {
+	challengeService *service.ChallengeService
+}
+
+// NewChallengeHandler creates a new challenge handler
+func NewChallengeHandler(challengeService *service.ChallengeService) *ChallengeHandler {
+	return &ChallengeHandler{challengeService: challengeService}
+}
+
+// GetChallenges lists published challenges with the caller's enrollment
+// Query: status=upcoming|active|ended (default: upcoming and active)
+// GET /api/v1/challenges
+func (h *ChallengeHandler) GetChallenges(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	status := c.Query("status")
+	if !validChallengeStatus(status) {
+		utils.SendError(c, http.StatusBadRequest, "Invalid status, expected upcoming, active or ended", nil)
+		return
+	}
+
+	challenges, err := h.challengeService.GetChallenges(userID, status)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch challenges", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Challenges retrieved successfully", gin.H{
+		"challenges": challenges,
+		"total":      len(challenges),
+	})
+}
+
+// GetChallenge gets a challenge with the caller's live progress
+// GET /api/v1/challenges/:id
+func (h *ChallengeHandler) GetChallenge(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	challengeID, ok := parseChallengeID(c)
+	if !ok {
+		return
+	}
+
+	challenge, err := h.challengeService.GetChallenge(userID, challengeID)
+	if err != nil {
+		h.sendError(c, err, "Failed to fetch challenge")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Challenge retrieved successfully", challenge)
+}
+
+// JoinChallenge enrolls the caller in a challenge
+// POST /api/v1/challenges/:id/join
+func (h *ChallengeHandler) JoinChallenge(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	challengeID, ok := parseChallengeID(c)
+	if !ok {
+		return
+	}
+
+	challenge, err := h.challengeService.JoinChallenge(userID, challengeID)
+	if err != nil {
+		h.sendError(c, err, "Failed to join challenge")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Joined challenge successfully", challenge)
+}
+
+// LeaveChallenge withdraws the caller from a challenge they have not completed
+// DELETE /api/v1/challenges/:id/join
+func (h *ChallengeHandler) LeaveChallenge(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	challengeID, ok := parseChallengeID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.challengeService.LeaveChallenge(userID, challengeID); err != nil {
+		h.sendError(c, err, "Failed to leave challenge")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Left challenge successfully", nil)
+}
+
+// GetLeaderboard ranks a challenge's participants
+// GET /api/v1/challenges/:id/leaderboard?limit=20
+func (h *ChallengeHandler) GetLeaderboard(c *gin.Context) {
+	challengeID, ok := parseChallengeID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	entries, err := h.challengeService.GetLeaderboard(challengeID, limit)
+	if err != nil {
+		h.sendError(c, err, "Failed to fetch challenge leaderboard")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Challenge leaderboard retrieved successfully", gin.H{
+		"entries": entries,
+		"total":   len(entries),
+	})
+}
+
+// GetAllChallenges lists every challenge, including unpublished ones (admin only)
+// GET /api/v1/admin/challenges
+func (h *ChallengeHandler) GetAllChallenges(c *gin.Context) {
+	status := c.Query("status")
+	if !validChallengeStatus(status) {
+		utils.SendError(c, http.StatusBadRequest, "Invalid status, expected upcoming, active or ended", nil)
+		return
+	}
+
+	challenges, err := h.challengeService.GetAllChallenges(status)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch challenges", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Challenges retrieved successfully", gin.H{
+		"challenges": challenges,
+		"total":      len(challenges),
+	})
+}
+
+// CreateChallenge creates a challenge (admin only)
+// POST /api/v1/admin/challenges
+func (h *ChallengeHandler) CreateChallenge(c *gin.Context) {
+	var req dto.CreateChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	challenge, err := h.challengeService.CreateChallenge(&req)
+	if err != nil {
+		h.sendError(c, err, "Failed to create challenge")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Challenge created successfully", challenge)
+}
+
+// UpdateChallenge updates a challenge (admin only)
+// PUT /api/v1/admin/challenges/:id
+func (h *ChallengeHandler) UpdateChallenge(c *gin.Context) {
+	challengeID, ok := parseChallengeID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	challenge, err := h.challengeService.UpdateChallenge(challengeID, &req)
+	if err != nil {
+		h.sendError(c, err, "Failed to update challenge")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Challenge updated successfully", challenge)
+}
+
+// DeleteChallenge deletes a challenge (admin only)
+// DELETE /api/v1/admin/challenges/:id
+func (h *ChallengeHandler) DeleteChallenge(c *gin.Context) {
+	challengeID, ok := parseChallengeID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.challengeService.DeleteChallenge(challengeID); err != nil {
+		h.sendError(c, err, "Failed to delete challenge")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Challenge deleted successfully", nil)
+}
+
+// parseChallengeID reads the :id parameter, sending a 400 if it is invalid
+func parseChallengeID(c *gin.Context) (uint, bool) {
+	challengeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid challenge ID", err)
+		return 0, false
+	}
+	return uint(challengeID), true
+}
+
+// validChallengeStatus reports whether a status filter is supported
+func validChallengeStatus(status string) bool {
+	switch status {
+	case "", "upcoming", "active", "ended":
+		return true
+	}
+	return false
+}
+
+// sendError maps challenge service errors to HTTP responses
+func (h *ChallengeHandler) sendError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidChallenge), errors.Is(err, utils.ErrInvalidBadgeRule),
+		errors.Is(err, utils.ErrChallengeStarted):
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, utils.ErrChallengeNotFound), errors.Is(err, utils.ErrBadgeNotFound),
+		errors.Is(err, utils.ErrNotEnrolled):
+		utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, utils.ErrChallengeClosed), errors.Is(err, utils.ErrChallengeFull),
+		errors.Is(err, utils.ErrAlreadyEnrolled), errors.Is(err, utils.ErrChallengeCompleted):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, fallback, err)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// CommunityPoolHandler handles community pool HTTP requests
+type CommunityPoolHandler struct {
+	poolService *service.CommunityPoolService
+}
+
+// NewCommunityPoolHandler creates a new community pool handler
+func NewCommunityPoolHandler(poolService *service.CommunityPoolService) *CommunityPoolHandler {
+	return &CommunityPoolHandler{poolService: poolService}
+}
+
+// GetStats returns public community pool statistics (no auth required)
+// GET /api/v1/community-pool/stats
+func (h *CommunityPoolHandler) GetStats(c *gin.Context) {
+	stats, err := h.poolService.GetStats()
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch community pool stats", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Community pool stats retrieved successfully", stats)
+}
+
+// Grant grants credits from the pool to a user (admin only)
+// POST /api/v1/admin/community-pool/grants
+func (h *CommunityPoolHandler) Grant(c *gin.Context) {
+	adminID := c.GetUint("admin_id")
+	if adminID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req dto.CommunityPoolGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	transaction, err := h.poolService.GrantByAdmin(adminID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrUserNotFound):
+			utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+		case errors.Is(err, utils.ErrPoolInsufficient):
+			utils.SendError(c, http.StatusConflict, err.Error(), nil)
+		case errors.Is(err, utils.ErrUserInactive):
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utils.SendError(c, http.StatusInternalServerError, "Failed to grant credits", err)
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Credits granted successfully", transaction)
+}
+
+// RunAutoGrants runs the automatic grant rules immediately (admin only)
+// POST /api/v1/admin/community-pool/auto-grants/run
+func (h *CommunityPoolHandler) RunAutoGrants(c *gin.Context) {
+	granted, err := h.poolService.RunAutoGrants()
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to run automatic grants", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Automatic grants completed", gin.H{
+		"granted": granted,
+	})
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// CreditAdjustmentHandler handles admin credit adjustment HTTP requests
+type CreditAdjustmentHandler struct {
+	adjustmentService *service.CreditAdjustmentService
+}
+
+// NewCreditAdjustmentHandler creates a new credit adjustment handler
+func NewCreditAdjustmentHandler(adjustmentService *service.CreditAdjustmentService) *CreditAdjustmentHandler {
+	return &CreditAdjustmentHandler{adjustmentService: adjustmentService}
+}
+
+// GetAdjustments lists credit adjustments (admin only)
+// GET /api/v1/admin/credit-adjustments?status=pending&user_id=1&page=1&limit=10
+func (h *CreditAdjustmentHandler) GetAdjustments(c *gin.Context) {
+	adminID := c.GetUint("admin_id")
+	if adminID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
+
+	adjustments, total, err := h.adjustmentService.GetAdjustments(adminID, c.GetString("email"), c.Query("status"), uint(userID), page, limit)
+	if err != nil {
+		h.sendError(c, err, "Failed to fetch credit adjustments")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Credit adjustments retrieved successfully", gin.H{
+		"data":  adjustments,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ProposeAdjustment proposes a balance correction for a user (admin only)
+// POST /api/v1/admin/credit-adjustments
+func (h *CreditAdjustmentHandler) ProposeAdjustment(c *gin.Context) {
+	adminID := c.GetUint("admin_id")
+	if adminID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req dto.CreateCreditAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	adjustment, err := h.adjustmentService.ProposeAdjustment(adminID, c.GetString("email"), &req)
+	if err != nil {
+		h.sendError(c, err, "Failed to propose credit adjustment")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Credit adjustment proposed, waiting for approval", adjustment)
+}
+
+// ApproveAdjustment approves and posts a pending adjustment (second admin with manage_finance)
+// POST /api/v1/admin/credit-adjustments/:id/approve
+func (h *CreditAdjustmentHandler) ApproveAdjustment(c *gin.Context) {
+	h.review(c, true)
+}
+
+// RejectAdjustment rejects a pending adjustment (second admin with manage_finance)
+// POST /api/v1/admin/credit-adjustments/:id/reject
+func (h *CreditAdjustmentHandler) RejectAdjustment(c *gin.Context) {
+	h.review(c, false)
+}
+
+// review handles approve and reject, which share input and error handling
+func (h *CreditAdjustmentHandler) review(c *gin.Context, approve bool) {
+	adminID := c.GetUint("admin_id")
+	if adminID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid adjustment ID", err)
+		return
+	}
+
+	var req dto.ReviewCreditAdjustmentRequest
+	// Body is optional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	if approve {
+		adjustment, err := h.adjustmentService.ApproveAdjustment(adminID, c.GetString("email"), uint(id), req.Note)
+		if err != nil {
+			h.sendError(c, err, "Failed to approve credit adjustment")
+			return
+		}
+		utils.SendSuccess(c, http.StatusOK, "Credit adjustment approved and posted", adjustment)
+		return
+	}
+
+	adjustment, err := h.adjustmentService.RejectAdjustment(adminID, c.GetString("email"), uint(id), req.Note)
+	if err != nil {
+		h.sendError(c, err, "Failed to reject credit adjustment")
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Credit adjustment rejected", adjustment)
+}
+
+// sendError maps credit adjustment errors to HTTP status codes
+func (h *CreditAdjustmentHandler) sendError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrNotAuthorized), errors.Is(err, utils.ErrSelfApproval):
+		utils.SendError(c, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, utils.ErrAdjustmentNotFound), errors.Is(err, utils.ErrUserNotFound):
+		utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, utils.ErrAdjustmentNotPending), errors.Is(err, utils.ErrInsufficientCredits):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, utils.ErrInvalidAdjustmentAmount):
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, fallback, err)
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// CreditPolicyHandler handles credit policy and community pool HTTP requests
+type CreditPolicyHandler struct {
+	creditPolicyService *service.CreditPolicyService
+}
+
+// NewCreditPolicyHandler creates a new credit policy handler
+func NewCreditPolicyHandler(creditPolicyService *service.CreditPolicyService) *CreditPolicyHandler {
+	return &CreditPolicyHandler{
+		creditPolicyService: creditPolicyService,
+	}
+}
+
+// GetPolicy returns the active credit policy and the community pool balance
+// GET /api/v1/admin/credit-policy
+func (h *CreditPolicyHandler) GetPolicy(c *gin.Context) {
+	pool, err := h.creditPolicyService.GetPool()
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch community pool", err)
+		return
+	}
+
+	policy := h.creditPolicyService.GetPolicy()
+	utils.SendSuccess(c, http.StatusOK, "Credit policy retrieved successfully", gin.H{
+		"policy": gin.H{
+			"enabled":           policy.Enabled,
+			"max_balance":       policy.MaxBalance,
+			"inactivity_months": policy.InactivityMonths,
+			"decay_rate":        policy.DecayRate,
+			"decay_floor":       policy.DecayFloor,
+			"bonus_expiry_days": policy.BonusExpiryDays,
+			"notice_days":       policy.NoticeDays,
+			"run_interval":      policy.RunInterval.String(),
+		},
+		"pool": pool,
+	})
+}
+
+// RunPolicies runs the credit policy job immediately
+// POST /api/v1/admin/credit-policy/run
+func (h *CreditPolicyHandler) RunPolicies(c *gin.Context) {
+	if err := h.creditPolicyService.RunPolicies(); err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to run credit policy", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Credit policy run completed", nil)
+}
+
+// GetPoolEntries returns the community pool ledger
+// GET /api/v1/admin/community-pool/entries?type=overflow&limit=20&offset=0
+func (h *CreditPolicyHandler) GetPoolEntries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	entries, total, err := h.creditPolicyService.GetPoolEntries(limit, offset, c.Query("type"))
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch pool entries", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Pool entries retrieved successfully", gin.H{
+		"entries": entries,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetMyNotices returns announced and applied credit adjustments for the authenticated user
+// GET /api/v1/user/credit-notices
+func (h *CreditPolicyHandler) GetMyNotices(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	notices, err := h.creditPolicyService.GetUserNotices(userID.(uint))
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch credit notices", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Credit notices retrieved successfully", notices)
+}
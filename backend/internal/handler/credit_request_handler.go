@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// CreditRequestHandler handles credit request HTTP requests
+type CreditRequestHandler struct {
+	requestService *service.CreditRequestService
+}
+
+// NewCreditRequestHandler creates a new credit request handler
+func NewCreditRequestHandler(requestService *service.CreditRequestService) *CreditRequestHandler {
+	return &CreditRequestHandler{requestService: requestService}
+}
+
+// CreateRequest asks another user to send credits
+// POST /api/v1/user/credit-requests
+func (h *CreditRequestHandler) CreateRequest(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req dto.CreateCreditRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	request, err := h.requestService.CreateRequest(userID.(uint), &req)
+	if err != nil {
+		h.sendError(c, err, "Failed to create credit request")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Credit request sent successfully", request)
+}
+
+// GetIncoming lists requests the user has been asked to pay
+// GET /api/v1/user/credit-requests/incoming?status=pending&limit=10&offset=0
+func (h *CreditRequestHandler) GetIncoming(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	limit, offset := parseCreditRequestPagination(c)
+	requests, total, err := h.requestService.GetIncoming(userID.(uint), c.Query("status"), limit, offset)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch credit requests", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Credit requests retrieved successfully", gin.H{
+		"requests": requests,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// GetOutgoing lists requests the user has created
+// GET /api/v1/user/credit-requests/outgoing?status=pending&limit=10&offset=0
+func (h *CreditRequestHandler) GetOutgoing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	limit, offset := parseCreditRequestPagination(c)
+	requests, total, err := h.requestService.GetOutgoing(userID.(uint), c.Query("status"), limit, offset)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch credit requests", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Credit requests retrieved successfully", gin.H{
+		"requests": requests,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// AcceptRequest pays a credit request
+// POST /api/v1/user/credit-requests/:id/accept
+func (h *CreditRequestHandler) AcceptRequest(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid credit request ID", err)
+		return
+	}
+
+	request, err := h.requestService.AcceptRequest(userID.(uint), uint(id))
+	if err != nil {
+		h.sendError(c, err, "Failed to accept credit request")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Credit request accepted successfully", request)
+}
+
+// DeclineRequest declines a credit request with an optional reason
+// POST /api/v1/user/credit-requests/:id/decline
+func (h *CreditRequestHandler) DeclineRequest(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid credit request ID", err)
+		return
+	}
+
+	var req dto.DeclineCreditRequestRequest
+	// Body is optional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	request, err := h.requestService.DeclineRequest(userID.(uint), uint(id), req.Reason)
+	if err != nil {
+		h.sendError(c, err, "Failed to decline credit request")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Credit request declined", request)
+}
+
+// CancelRequest withdraws a credit request the user created
+// POST /api/v1/user/credit-requests/:id/cancel
+func (h *CreditRequestHandler) CancelRequest(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid credit request ID", err)
+		return
+	}
+
+	request, err := h.requestService.CancelRequest(userID.(uint), uint(id))
+	if err != nil {
+		h.sendError(c, err, "Failed to cancel credit request")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Credit request cancelled", request)
+}
+
+// sendError maps credit request errors to HTTP status codes
+func (h *CreditRequestHandler) sendError(c *gin.Context, err error, fallback string) {
+	errMsg := err.Error()
+	switch {
+	case errors.Is(err, utils.ErrCreditRequestNotFound):
+		utils.SendError(c, http.StatusNotFound, errMsg, nil)
+	case errors.Is(err, utils.ErrNotAuthorized):
+		utils.SendError(c, http.StatusForbidden, errMsg, nil)
+	case errors.Is(err, utils.ErrCreditRequestNotPending):
+		utils.SendError(c, http.StatusConflict, errMsg, nil)
+	case errors.Is(err, utils.ErrCreditRequestLimit):
+		utils.SendError(c, http.StatusTooManyRequests, errMsg, nil)
+	case errMsg == "payer not found" || errMsg == "payer account is not active":
+		utils.SendError(c, http.StatusNotFound, errMsg, nil)
+	case strings.HasPrefix(errMsg, "insufficient credits"),
+		strings.HasPrefix(errMsg, "request amount"),
+		errMsg == "cannot request credits from yourself":
+		utils.SendError(c, http.StatusBadRequest, errMsg, nil)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, fallback, err)
+	}
+}
+
+// parseCreditRequestPagination reads limit and offset query parameters
+func parseCreditRequestPagination(c *gin.Context) (int, int) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// DigestHandler handles HTTP requests for the weekly digest email
+type DigestHandler struct {
+	digestService *service.DigestService
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(digestService *service.DigestService) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+	}
+}
+
+// GetDigestPreview returns the weekly digest the current user would receive now
+// GET /api/v1/notifications/digest
+func (h *DigestHandler) GetDigestPreview(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	digest, err := h.digestService.GetDigest(userID)
+	if err != nil {
+		if errors.Is(err, utils.ErrUserNotFound) {
+			utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to build digest", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Digest retrieved successfully", gin.H{
+		"digest": digest,
+	})
+}
+
+// ConfirmUnsubscribe shows the confirmation page of the unsubscribe link in a digest email
+// GET /api/v1/digest/unsubscribe?token=...
+// Opening the link does not unsubscribe; the page posts to Unsubscribe.
+func (h *DigestHandler) ConfirmUnsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		utils.SendError(c, http.StatusBadRequest, "Missing unsubscribe token", nil)
+		return
+	}
+
+	if err := h.digestService.CheckUnsubscribeToken(token); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	page, err := utils.RenderDigestUnsubscribePage(token)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to render unsubscribe page", err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// Unsubscribe turns off the weekly digest
+// POST /api/v1/digest/unsubscribe?token=...
+// Used by mail clients (one-click unsubscribe, RFC 8058) and by the confirmation page;
+// browsers submitting the page are redirected to the notification settings.
+func (h *DigestHandler) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		utils.SendError(c, http.StatusBadRequest, "Missing unsubscribe token", nil)
+		return
+	}
+
+	if err := h.digestService.Unsubscribe(token); err != nil {
+		if errors.Is(err, utils.ErrInvalidToken) {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to unsubscribe", err)
+		return
+	}
+
+	if strings.Contains(c.GetHeader("Accept"), "text/html") {
+		c.Redirect(http.StatusSeeOther, utils.FrontendURL()+"/settings/notifications?digest=unsubscribed")
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Unsubscribed from the weekly digest", nil)
+}
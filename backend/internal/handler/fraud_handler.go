@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// FraudHandler handles fraud detection HTTP requests
+type FraudHandler struct {
+	fraudService *service.FraudDetectionService
+}
+
+// NewFraudHandler creates a new fraud handler
+func NewFraudHandler(fraudService *service.FraudDetectionService) *FraudHandler {
+	return &FraudHandler{fraudService: fraudService}
+}
+
+// RunDetection runs the fraud detection job immediately (admin only)
+// Findings are raised as "fraud" reports, listed in GET /api/v1/admin/reports
+// POST /api/v1/admin/fraud/run
+func (h *FraudHandler) RunDetection(c *gin.Context) {
+	result, err := h.fraudService.RunDetection()
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to run fraud detection", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Fraud detection completed", result)
+}
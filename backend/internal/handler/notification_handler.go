@@ -320,3 +320,42 @@ func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
 		"preferences": dto.MapPreferencesToResponse(pref),
 	})
 }
+
+// GetDeliveryStats returns how quickly notifications reach connected clients
+// GET /api/v1/admin/notifications/delivery-stats?hours=24
+// Latency is measured from creation to the client's acknowledgement over the WebSocket.
+func (h *NotificationHandler) GetDeliveryStats(c *gin.Context) {
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "24"))
+
+	stats, err := h.notificationService.GetDeliveryStats(hours)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch delivery stats", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Delivery stats retrieved successfully", gin.H{
+		"stats": stats,
+	})
+}
+
+// IssueStreamToken issues a short-lived token to open the notification stream
+// POST /api/v1/notifications/stream-token
+// The token is single-use: request a new one for every (re)connection.
+func (h *NotificationHandler) IssueStreamToken(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	token, err := utils.GetWSTokenStore().GenerateWSToken(userID, utils.NotificationStreamTokenScope)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to issue stream token", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Stream token issued successfully", gin.H{
+		"token":      token,
+		"expires_in": 300,
+	})
+}
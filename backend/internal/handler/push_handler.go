@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// PushHandler handles HTTP requests for browser push subscriptions
+type PushHandler struct {
+	pushService *service.PushService
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(pushService *service.PushService) *PushHandler {
+	return &PushHandler{
+		pushService: pushService,
+	}
+}
+
+// GetPublicKey returns the VAPID public key to subscribe with
+// GET /api/v1/notifications/push/public-key
+// Pass it as applicationServerKey to pushManager.subscribe().
+func (h *PushHandler) GetPublicKey(c *gin.Context) {
+	publicKey, err := h.pushService.GetPublicKey()
+	if err != nil {
+		utils.SendError(c, http.StatusServiceUnavailable, err.Error(), nil)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Push public key retrieved successfully", gin.H{
+		"public_key": publicKey,
+	})
+}
+
+// GetSubscriptions lists the current user's push-enabled devices
+// GET /api/v1/notifications/push/subscriptions
+func (h *PushHandler) GetSubscriptions(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	subscriptions, err := h.pushService.GetSubscriptions(userID)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch push subscriptions", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Push subscriptions retrieved successfully", gin.H{
+		"subscriptions": subscriptions,
+	})
+}
+
+// Subscribe registers the push subscription of the current device
+// POST /api/v1/notifications/push/subscriptions
+// Body: the browser's PushSubscription JSON ({"endpoint": "...", "keys": {"p256dh": "...", "auth": "..."}})
+func (h *PushHandler) Subscribe(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	var req dto.PushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	subscription, err := h.pushService.Subscribe(userID, req, c.GetHeader("User-Agent"))
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrPushNotConfigured):
+			utils.SendError(c, http.StatusServiceUnavailable, err.Error(), nil)
+		case errors.Is(err, utils.ErrInvalidPushSubscription):
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utils.SendError(c, http.StatusInternalServerError, "Failed to save push subscription", err)
+		}
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Push subscription saved successfully", gin.H{
+		"subscription": subscription,
+	})
+}
+
+// Unsubscribe removes the push subscription of the current device
+// DELETE /api/v1/notifications/push/subscriptions
+// Body: {"endpoint": "..."}
+func (h *PushHandler) Unsubscribe(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	var req dto.DeletePushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.pushService.Unsubscribe(userID, req.Endpoint); err != nil {
+		if errors.Is(err, utils.ErrPushSubscriptionNotFound) {
+			utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to remove push subscription", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Push subscription removed successfully", nil)
+}
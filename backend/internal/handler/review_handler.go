@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/service"
 	"github.com/timebankingskill/backend/internal/utils"
 )
@@ -49,6 +51,12 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 // GetReview retrieves a specific review by ID
 // GET /api/v1/reviews/:id
 func (h *ReviewHandler) GetReview(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -56,7 +64,7 @@ func (h *ReviewHandler) GetReview(c *gin.Context) {
 		return
 	}
 
-	review, err := h.reviewService.GetReview(uint(id))
+	review, err := h.reviewService.GetReview(uint(id), userID)
 	if err != nil {
 		utils.SendError(c, http.StatusNotFound, "Review not found", err)
 		return
@@ -66,6 +74,7 @@ func (h *ReviewHandler) GetReview(c *gin.Context) {
 }
 
 // GetUserReviews retrieves all reviews for a user
+// Query: sort=recent|helpful|highest|lowest, limit, offset
 // GET /api/v1/users/:userId/reviews
 func (h *ReviewHandler) GetUserReviews(c *gin.Context) {
 	userIDStr := c.Param("id")
@@ -89,8 +98,12 @@ func (h *ReviewHandler) GetUserReviews(c *gin.Context) {
 		offset = 0
 	}
 
-	reviews, total, err := h.reviewService.GetReviewsForUser(uint(userID), limit, offset)
+	reviews, total, err := h.reviewService.GetReviewsForUser(uint(userID), c.Query("sort"), limit, offset)
 	if err != nil {
+		if errors.Is(err, utils.ErrInvalidReviewSort) {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch reviews", err)
 		return
 	}
@@ -106,6 +119,7 @@ func (h *ReviewHandler) GetUserReviews(c *gin.Context) {
 }
 
 // GetUserReviewsByType retrieves reviews for a user filtered by type (teacher/student)
+// Query: sort=recent|helpful|highest|lowest, limit, offset
 // GET /api/v1/users/:userId/reviews/:type
 func (h *ReviewHandler) GetUserReviewsByType(c *gin.Context) {
 	userIDStr := c.Param("id")
@@ -135,8 +149,12 @@ func (h *ReviewHandler) GetUserReviewsByType(c *gin.Context) {
 		offset = 0
 	}
 
-	reviews, total, err := h.reviewService.GetReviewsForUserByType(uint(userID), reviewType, limit, offset)
+	reviews, total, err := h.reviewService.GetReviewsForUserByType(uint(userID), reviewType, c.Query("sort"), limit, offset)
 	if err != nil {
+		if errors.Is(err, utils.ErrInvalidReviewSort) {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch reviews", err)
 		return
 	}
@@ -194,7 +212,7 @@ func (h *ReviewHandler) UpdateReview(c *gin.Context) {
 
 	review, err := h.reviewService.UpdateReview(uint(id), userID, &req)
 	if err != nil {
-		if err.Error() == "you can only edit your own reviews" {
+		if err.Error() == "you can only edit your own reviews" || errors.Is(err, utils.ErrReviewLocked) {
 			utils.SendError(c, http.StatusForbidden, err.Error(), nil)
 		} else {
 			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
@@ -233,3 +251,318 @@ func (h *ReviewHandler) DeleteReview(c *gin.Context) {
 
 	utils.SendSuccess(c, http.StatusOK, "Review deleted successfully", nil)
 }
+
+// ReplyToReview adds the reviewee's public reply to a review
+// POST /api/v1/reviews/:id/reply
+func (h *ReviewHandler) ReplyToReview(c *gin.Context) {
+	h.saveReply(c, h.reviewService.ReplyToReview, http.StatusCreated, "Reply added successfully")
+}
+
+// UpdateReply changes the reviewee's reply to a review
+// PUT /api/v1/reviews/:id/reply
+func (h *ReviewHandler) UpdateReply(c *gin.Context) {
+	h.saveReply(c, h.reviewService.UpdateReply, http.StatusOK, "Reply updated successfully")
+}
+
+// DeleteReply removes the reviewee's reply to a review
+// DELETE /api/v1/reviews/:id/reply
+func (h *ReviewHandler) DeleteReply(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	reviewID, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.DeleteReply(reviewID, userID); err != nil {
+		h.sendModerationError(c, err, "Failed to delete reply")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Reply deleted successfully", nil)
+}
+
+// saveReply binds a reply and adds or updates it
+func (h *ReviewHandler) saveReply(c *gin.Context, save func(reviewID, userID uint, reply string) (*dto.ReviewResponse, error), status int, message string) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	reviewID, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReplyReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	review, err := save(reviewID, userID, req.Reply)
+	if err != nil {
+		h.sendModerationError(c, err, "Failed to save reply")
+		return
+	}
+
+	utils.SendSuccess(c, status, message, review)
+}
+
+// ReportReview reports a review for moderation
+// POST /api/v1/reviews/:id/report
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	reviewID, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	if err := h.reviewService.ReportReview(reviewID, userID, &req); err != nil {
+		h.sendModerationError(c, err, "Failed to report review")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Review reported successfully", nil)
+}
+
+// GetModerationQueue lists reported reviews awaiting a decision (admin only)
+// Query: status=reported (default) or hidden, page, limit
+// GET /api/v1/admin/reviews/moderation
+func (h *ReviewHandler) GetModerationQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", "reported")
+	if status != "reported" && status != "hidden" {
+		utils.SendError(c, http.StatusBadRequest, "Invalid status, expected reported or hidden", nil)
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	items, total, err := h.reviewService.GetModerationQueue(status == "hidden", page, limit)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch moderation queue", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Moderation queue retrieved successfully", gin.H{
+		"data":  items,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// HideReview hides a review and excludes it from rating aggregates (admin only)
+// POST /api/v1/admin/reviews/:id/hide
+func (h *ReviewHandler) HideReview(c *gin.Context) {
+	h.moderateReview(c, h.reviewService.HideReview, "Review hidden successfully")
+}
+
+// UnhideReview makes a hidden review visible again (admin only)
+// POST /api/v1/admin/reviews/:id/unhide
+func (h *ReviewHandler) UnhideReview(c *gin.Context) {
+	h.moderateReview(c, h.reviewService.UnhideReview, "Review unhidden successfully")
+}
+
+// DismissReviewReports dismisses the pending reports on a review (admin only)
+// POST /api/v1/admin/reviews/:id/dismiss
+func (h *ReviewHandler) DismissReviewReports(c *gin.Context) {
+	h.moderateReview(c, h.reviewService.DismissReviewReports, "Review reports dismissed successfully")
+}
+
+// GetModerationLog gets the moderation audit trail of a review (admin only)
+// GET /api/v1/admin/reviews/:id/moderation-log
+func (h *ReviewHandler) GetModerationLog(c *gin.Context) {
+	reviewID, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.reviewService.GetModerationLog(reviewID)
+	if err != nil {
+		h.sendModerationError(c, err, "Failed to fetch moderation log")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Moderation log retrieved successfully", gin.H{
+		"entries": entries,
+		"total":   len(entries),
+	})
+}
+
+// moderateReview binds the admin's reason and applies a moderation action
+func (h *ReviewHandler) moderateReview(c *gin.Context, action func(adminID, reviewID uint, reason string) (*models.ReviewModerationLog, error), message string) {
+	reviewID, ok := parseReviewID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	entry, err := action(c.GetUint("admin_id"), reviewID, req.Reason)
+	if err != nil {
+		h.sendModerationError(c, err, "Failed to moderate review")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, message, entry)
+}
+
+// GetReviewTags lists the review tags reviewers can choose from
+// Query: type=teacher or student (both if omitted)
+// GET /api/v1/review-tags
+func (h *ReviewHandler) GetReviewTags(c *gin.Context) {
+	h.listReviewTags(c, h.reviewService.GetReviewTags)
+}
+
+// GetAllReviewTags lists all review tags, inactive ones included (admin only)
+// GET /api/v1/admin/review-tags
+func (h *ReviewHandler) GetAllReviewTags(c *gin.Context) {
+	h.listReviewTags(c, h.reviewService.GetAllReviewTags)
+}
+
+// CreateReviewTag adds a tag to the review vocabulary (admin only)
+// POST /api/v1/admin/review-tags
+func (h *ReviewHandler) CreateReviewTag(c *gin.Context) {
+	var req dto.CreateReviewTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	tag, err := h.reviewService.CreateReviewTag(&req)
+	if err != nil {
+		h.sendReviewTagError(c, err, "Failed to create review tag")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Review tag created successfully", tag)
+}
+
+// UpdateReviewTag updates a review tag's label, active flag or order (admin only)
+// PUT /api/v1/admin/review-tags/:id
+func (h *ReviewHandler) UpdateReviewTag(c *gin.Context) {
+	tagID, ok := parseReviewTagID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateReviewTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err)
+		return
+	}
+
+	tag, err := h.reviewService.UpdateReviewTag(tagID, &req)
+	if err != nil {
+		h.sendReviewTagError(c, err, "Failed to update review tag")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Review tag updated successfully", tag)
+}
+
+// DeleteReviewTag removes a tag from the review vocabulary (admin only)
+// DELETE /api/v1/admin/review-tags/:id
+func (h *ReviewHandler) DeleteReviewTag(c *gin.Context) {
+	tagID, ok := parseReviewTagID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.DeleteReviewTag(tagID); err != nil {
+		h.sendReviewTagError(c, err, "Failed to delete review tag")
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Review tag deleted successfully", nil)
+}
+
+// listReviewTags validates the type filter and sends the tags listed
+func (h *ReviewHandler) listReviewTags(c *gin.Context, list func(reviewType string) ([]models.ReviewTag, error)) {
+	reviewType := c.Query("type")
+	if reviewType != "" && reviewType != string(models.ReviewTypeTeacher) && reviewType != string(models.ReviewTypeStudent) {
+		utils.SendError(c, http.StatusBadRequest, "Invalid type, expected teacher or student", nil)
+		return
+	}
+
+	tags, err := list(reviewType)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch review tags", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Review tags retrieved successfully", gin.H{
+		"tags":  tags,
+		"total": len(tags),
+	})
+}
+
+// parseReviewTagID reads the :id parameter, sending a 400 if it is invalid
+func parseReviewTagID(c *gin.Context) (uint, bool) {
+	tagID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid review tag ID", err)
+		return 0, false
+	}
+	return uint(tagID), true
+}
+
+// sendReviewTagError maps review tag errors to HTTP responses
+func (h *ReviewHandler) sendReviewTagError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidReviewTag):
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, utils.ErrReviewTagNotFound):
+		utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, utils.ErrReviewTagTaken):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, fallback, err)
+	}
+}
+
+// parseReviewID reads the :id parameter, sending a 400 if it is invalid
+func parseReviewID(c *gin.Context) (uint, bool) {
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid review ID", err)
+		return 0, false
+	}
+	return uint(reviewID), true
+}
+
+// sendModerationError maps review moderation and reply errors to HTTP responses
+func (h *ReviewHandler) sendModerationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidReportReason):
+		utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, utils.ErrCannotReportOwnReview), errors.Is(err, utils.ErrNotReviewee):
+		utils.SendError(c, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, utils.ErrReviewNotFound), errors.Is(err, utils.ErrNoReply):
+		utils.SendError(c, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, utils.ErrAlreadyReported), errors.Is(err, utils.ErrReviewAlreadyHidden),
+		errors.Is(err, utils.ErrReviewNotHidden), errors.Is(err, utils.ErrNoPendingReports),
+		errors.Is(err, utils.ErrReplyExists):
+		utils.SendError(c, http.StatusConflict, err.Error(), nil)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, fallback, err)
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/timebankingskill/backend/internal/dto"
@@ -34,6 +35,7 @@ func NewSkillHandler(skillService *service.SkillService) *SkillHandler {
 // @Param day query int false "Available day filter (0-6)"
 // @Param rating query number false "Minimum rating filter"
 // @Param location query string false "Location filter"
+// @Param tag query string false "Review tag filter (slug), e.g. explains-clearly"
 // @Param sort query string false "Sort by (popular, rating, newest)"
 // @Success 200 {object} utils.SuccessResponse
 // @Failure 500 {object} utils.ErrorResponse
@@ -47,6 +49,7 @@ func (h *SkillHandler) GetSkills(c *gin.Context) {
 	dayStr := c.Query("day")
 	ratingStr := c.Query("rating")
 	location := c.Query("location")
+	tag := strings.ToLower(strings.TrimSpace(c.Query("tag")))
 	sortBy := c.DefaultQuery("sort", "newest")
 
 	var dayOfWeek *int
@@ -91,7 +94,7 @@ func (h *SkillHandler) GetSkills(c *gin.Context) {
 
 	// Try to get from cache if no search/filter
 	cache := utils.GetCache()
-	if search == "" && category == "" && dayOfWeek == nil && minRating == nil && location == "" && sortBy == "newest" && page == 1 {
+	if search == "" && category == "" && dayOfWeek == nil && minRating == nil && location == "" && tag == "" && sortBy == "newest" && page == 1 {
 		if cached, found := cache.Get(utils.CacheKeySkills); found {
 			utils.SendSuccess(c, http.StatusOK, "Skills retrieved from cache", cached)
 			return
@@ -99,7 +102,7 @@ func (h *SkillHandler) GetSkills(c *gin.Context) {
 	}
 
 	// Get skills from service
-	skills, total, err := h.skillService.GetAllSkills(limit, offset, category, search, dayOfWeek, minRating, location, sortBy)
+	skills, total, err := h.skillService.GetAllSkills(limit, offset, category, search, dayOfWeek, minRating, location, tag, sortBy)
 	if err != nil {
 		utils.SendError(c, utils.MapErrorToStatus(err), err.Error(), nil)
 		return
@@ -119,7 +122,7 @@ func (h *SkillHandler) GetSkills(c *gin.Context) {
 	}
 
 	// Cache response if no search/filter and page 1
-	if search == "" && category == "" && page == 1 {
+	if search == "" && category == "" && tag == "" && page == 1 {
 		cache.Set(utils.CacheKeySkills, response)
 	}
 
@@ -189,6 +192,7 @@ func (h *SkillHandler) GetRecommendedSkills(c *gin.Context) {
 }
 
 // GetSkillTeachers handles GET /api/v1/skills/:id/teachers
+// Query: tag keeps teachers given that review tag (slug) for the skill
 func (h *SkillHandler) GetSkillTeachers(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -197,7 +201,8 @@ func (h *SkillHandler) GetSkillTeachers(c *gin.Context) {
 		return
 	}
 
-	teachers, err := h.skillService.GetSkillTeachers(uint(id))
+	tag := strings.ToLower(strings.TrimSpace(c.Query("tag")))
+	teachers, err := h.skillService.GetSkillTeachers(uint(id), tag)
 	if err != nil {
 		utils.SendError(c, utils.MapErrorToStatus(err), err.Error(), nil)
 		return
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -169,4 +171,40 @@ func (h *TransactionHandler) TransferCredits(c *gin.Context) {
 	}
 
 	utils.SendSuccess(c, http.StatusOK, "Credits transferred successfully", response)
-}
\ No newline at end of file
+}
+// DownloadStatement downloads the authenticated user's monthly statement
+// GET /api/v1/user/statements/:month?format=pdf|csv (month is yyyy-mm, format defaults to pdf)
+func (h *TransactionHandler) DownloadStatement(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	format := c.DefaultQuery("format", "pdf")
+	if format != "csv" && format != "pdf" {
+		utils.SendError(c, http.StatusBadRequest, "Unsupported format. Use 'csv' or 'pdf'", nil)
+		return
+	}
+
+	content, filename, err := h.transactionService.ExportMonthlyStatement(userID.(uint), c.Param("month"), format)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidStatementMonth) || errors.Is(err, utils.ErrFutureStatementMonth) {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to generate statement", err)
+		return
+	}
+
+	// Set headers for file download
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+
+	contentType := "application/pdf"
+	if format == "csv" {
+		contentType = "text/csv"
+	}
+	c.Data(http.StatusOK, contentType, content)
+}
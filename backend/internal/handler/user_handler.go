@@ -185,6 +185,8 @@ func (h *UserHandler) GetPublicProfile(c *gin.Context) {
 		TotalSessionsAsTeacher: profile.TotalSessionsAsTeacher,
 		AverageRatingAsTeacher: profile.AverageRatingAsTeacher,
 		TotalTeachingHours:   profile.TotalTeachingHours,
+		TeacherTags:          profile.TeacherTags,
+		StudentTags:          profile.StudentTags,
 		CreatedAt:            profile.CreatedAt,
 	}
 	utils.SendSuccess(c, http.StatusOK, "Public profile retrieved successfully", response)
@@ -223,6 +225,8 @@ func (h *UserHandler) GetPublicProfileByUsername(c *gin.Context) {
 		TotalSessionsAsTeacher: profile.TotalSessionsAsTeacher,
 		AverageRatingAsTeacher: profile.AverageRatingAsTeacher,
 		TotalTeachingHours:   profile.TotalTeachingHours,
+		TeacherTags:          profile.TeacherTags,
+		StudentTags:          profile.StudentTags,
 		CreatedAt:            profile.CreatedAt,
 	}
 	utils.SendSuccess(c, http.StatusOK, "Public profile retrieved successfully", response)
@@ -157,3 +157,68 @@ func (h *VoteHandler) GetStoryLikeStatus(c *gin.Context) {
 		"count": result.Count,
 	})
 }
+
+// ─────────────────────────────────────────────
+// REVIEW HELPFUL
+// ─────────────────────────────────────────────
+
+// ToggleReviewHelpful toggles the authenticated user's helpful vote on a review.
+// POST /api/v1/reviews/:id/helpful
+// Requires: Auth middleware
+func (h *VoteHandler) ToggleReviewHelpful(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid review ID", err)
+		return
+	}
+
+	result, err := h.voteService.ToggleReviewHelpful(userID.(uint), uint(reviewID))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	msg := "Review marked helpful"
+	if !result.Voted {
+		msg = "Helpful vote removed"
+	}
+
+	utils.SendSuccess(c, http.StatusOK, msg, gin.H{
+		"helpful": result.Voted,
+		"count":   result.Count,
+	})
+}
+
+// GetReviewHelpfulStatus returns whether the authenticated user has marked a review helpful.
+// GET /api/v1/reviews/:id/helpful
+// Requires: Auth middleware
+func (h *VoteHandler) GetReviewHelpfulStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid review ID", err)
+		return
+	}
+
+	result, err := h.voteService.GetReviewHelpfulStatus(userID.(uint), uint(reviewID))
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to fetch helpful status", err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Helpful status fetched", gin.H{
+		"helpful": result.Voted,
+		"count":   result.Count,
+	})
+}
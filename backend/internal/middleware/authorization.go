@@ -196,3 +196,42 @@ func RequireAdmin() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireActiveAdmin ensures the token belongs to an active admin account
+// Admin and user IDs share the same numeric space, so the token's email must match the
+// admin's: a regular user token with the same ID as an admin is rejected.
+// Stores the admin in context ("admin", "admin_id", "is_admin") for downstream handlers.
+//
+// Security:
+//   - Must be used AFTER AuthMiddleware
+//   - Handlers behind it must read the acting admin from "admin_id", not "user_id"
+func RequireActiveAdmin(adminRepo *repository.AdminRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID := c.GetUint("user_id")
+		email := c.GetString("email")
+		if adminID == 0 || email == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Authentication required",
+				"message": "Please login to access this resource",
+			})
+			c.Abort()
+			return
+		}
+
+		admin, err := adminRepo.GetByID(adminID)
+		if err != nil || !admin.IsActive || admin.Email != email {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Admin access required",
+				"message": "You don't have permission to access this resource",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("admin", admin)
+		c.Set("admin_id", admin.ID)
+		c.Set("is_admin", true)
+
+		c.Next()
+	}
+}
@@ -63,11 +63,12 @@ type UserBadge struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	UserID  uint `gorm:"not null;index" json:"user_id"`
-	BadgeID uint `gorm:"not null;index" json:"badge_id"`
+	// A user holds a badge at most once; revoked (soft-deleted) rows don't count
+	UserID  uint `gorm:"not null;index;uniqueIndex:idx_user_badges_active,where:deleted_at IS NULL" json:"user_id"`
+	BadgeID uint `gorm:"not null;index;uniqueIndex:idx_user_badges_active,where:deleted_at IS NULL" json:"badge_id"`
 
 	// When earned
-	EarnedAt time.Time `gorm:"not null" json:"earned_at"`
+	EarnedAt time.Time `gorm:"not null;index" json:"earned_at"`
 
 	// Progress tracking (for progressive badges)
 	Progress     int `gorm:"default:0" json:"progress"`      // Current progress
@@ -101,3 +102,85 @@ func (ub *UserBadge) ProgressPercentage() float64 {
 	}
 	return (float64(ub.Progress) / float64(ub.ProgressGoal)) * 100.0
 }
+
+// BadgeProgress tracks how close a user is to a badge they have not earned yet
+// Updated whenever the badge is evaluated for the user (see BadgeEventProcessor).
+type BadgeProgress struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID  uint `gorm:"not null;uniqueIndex:idx_badge_progress_user_badge" json:"user_id"`
+	BadgeID uint `gorm:"not null;uniqueIndex:idx_badge_progress_user_badge;index" json:"badge_id"`
+
+	Percent    int        `gorm:"default:0" json:"percent"` // 0-100
+	NotifiedAt *time.Time `json:"notified_at"`              // When the "almost there" notification was sent
+}
+
+// TableName specifies the table name for BadgeProgress model
+func (BadgeProgress) TableName() string {
+	return "badge_progress"
+}
+
+// BadgeRevocation records a badge taken back from a user and the bonus credits clawed back with it
+// The revoked UserBadge row is soft deleted, so the user can earn the badge again unless Permanent.
+type BadgeRevocation struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID      uint `gorm:"not null;index" json:"user_id"`
+	BadgeID     uint `gorm:"not null;index" json:"badge_id"`
+	UserBadgeID uint `gorm:"not null" json:"user_badge_id"`
+
+	Reason    string `gorm:"type:text;not null" json:"reason"`
+	RevokedBy *uint  `json:"revoked_by"`                     // Admin who revoked the badge; nil when requirements stopped being met
+	Permanent bool   `gorm:"default:false" json:"permanent"` // Blocks the badge from being awarded again automatically
+
+	// Bonus clawback
+	BonusTransactionID *uint   `gorm:"index" json:"bonus_transaction_id"` // Ledger entry of the badge bonus, if any
+	BonusRemaining     float64 `gorm:"default:0" json:"bonus_remaining"`  // Bonus not yet expired by the credit policy
+	ClawbackAmount     float64 `gorm:"default:0" json:"clawback_amount"`  // Amount actually debited
+	Override           bool    `gorm:"default:false" json:"override"`     // Admin allowed the balance to go below zero
+	TransactionID      *uint   `json:"transaction_id"`                    // Clawback ledger entry
+
+	// Relationships
+	User  User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Badge Badge `gorm:"foreignKey:BadgeID" json:"badge,omitempty"`
+}
+
+// TableName specifies the table name for BadgeRevocation model
+func (BadgeRevocation) TableName() string {
+	return "badge_revocations"
+}
+
+// BadgeBackfillStatus is the state of a badge backfill job
+type BadgeBackfillStatus string
+
+const (
+	BadgeBackfillRunning   BadgeBackfillStatus = "running"
+	BadgeBackfillCompleted BadgeBackfillStatus = "completed"
+	BadgeBackfillFailed    BadgeBackfillStatus = "failed"
+)
+
+// BadgeBackfill is a background job awarding a badge to every active user who already qualifies
+// Counters are saved after each batch of users, so the job reports progress while it runs.
+type BadgeBackfill struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	BadgeID      uint                `gorm:"not null;index" json:"badge_id"`
+	Status       BadgeBackfillStatus `gorm:"type:varchar(20);not null;default:'running'" json:"status"`
+	UsersChecked int                 `gorm:"default:0" json:"users_checked"`
+	Awarded      int                 `gorm:"default:0" json:"awarded"`
+	BonusCredits float64             `gorm:"default:0" json:"bonus_credits"` // Total bonus credits granted
+	Failed       int                 `gorm:"default:0" json:"failed"`
+	Error        string              `gorm:"type:text" json:"error,omitempty"`
+	FinishedAt   *time.Time          `json:"finished_at"`
+}
+
+// TableName specifies the table name for BadgeBackfill model
+func (BadgeBackfill) TableName() string {
+	return "badge_backfills"
+}
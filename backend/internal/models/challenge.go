@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ChallengeStatus is the phase of a challenge, derived from its dates
+type ChallengeStatus string
+
+const (
+	ChallengeUpcoming ChallengeStatus = "upcoming" // Enrollment open, not started yet
+	ChallengeRunning  ChallengeStatus = "active"   // Between StartsAt and EndsAt
+	ChallengeEnded    ChallengeStatus = "ended"    // Past EndsAt
+)
+
+// Challenge is a time-limited goal users opt into, e.g. a seasonal learning month
+// Unlike badges, only activity between StartsAt and EndsAt counts toward the goal.
+type Challenge struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Challenge Info
+	Title       string `gorm:"not null" json:"title"`
+	Description string `gorm:"type:text" json:"description"`
+	Icon        string `json:"icon"`
+	Season      string `gorm:"index" json:"season"` // Optional campaign tag, e.g. "ramadan-2026"
+
+	// Goal uses the badge rule format without window_days, e.g. {"metric":"sessions_as_student","op":">=","value":4}
+	Goal string `gorm:"type:jsonb;not null" json:"goal"`
+
+	StartsAt time.Time `gorm:"not null;index" json:"starts_at"`
+	EndsAt   time.Time `gorm:"not null;index" json:"ends_at"`
+
+	// Rewards granted on completion
+	RewardCredits float64 `gorm:"default:0" json:"reward_credits"`
+	RewardBadgeID *uint   `json:"reward_badge_id"`
+
+	MaxParticipants int  `gorm:"default:0" json:"max_participants"` // 0 = unlimited
+	IsActive        bool `gorm:"default:true" json:"is_active"`
+
+	// Stats
+	ParticipantCount int `gorm:"default:0" json:"participant_count"`
+	CompletedCount   int `gorm:"default:0" json:"completed_count"`
+
+	// Relationships
+	RewardBadge *Badge `gorm:"foreignKey:RewardBadgeID" json:"reward_badge,omitempty"`
+}
+
+// TableName specifies the table name for Challenge model
+func (Challenge) TableName() string {
+	return "challenges"
+}
+
+// StatusAt returns the phase of the challenge at the given time
+func (c *Challenge) StatusAt(now time.Time) ChallengeStatus {
+	switch {
+	case now.Before(c.StartsAt):
+		return ChallengeUpcoming
+	case now.Before(c.EndsAt):
+		return ChallengeRunning
+	default:
+		return ChallengeEnded
+	}
+}
+
+// ChallengeParticipant is a user enrolled in a challenge with their progress
+type ChallengeParticipant struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"` // Enrollment time
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ChallengeID uint `gorm:"not null;uniqueIndex:idx_challenge_participant" json:"challenge_id"`
+	UserID      uint `gorm:"not null;uniqueIndex:idx_challenge_participant;index" json:"user_id"`
+
+	Percent     int        `gorm:"default:0" json:"percent"` // 0-100, updated as events are evaluated
+	CompletedAt *time.Time `gorm:"index" json:"completed_at"`
+
+	RewardTransactionID *uint `json:"reward_transaction_id"`
+
+	// Relationships
+	User      User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Challenge Challenge `gorm:"foreignKey:ChallengeID" json:"challenge,omitempty"`
+}
+
+// TableName specifies the table name for ChallengeParticipant model
+func (ChallengeParticipant) TableName() string {
+	return "challenge_participants"
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// CommunityPoolID is the primary key of the single community pool account
+const CommunityPoolID uint = 1
+
+// PoolEntryType represents the source or destination of a community pool movement
+type PoolEntryType string
+
+const (
+	PoolEntryOverflow PoolEntryType = "overflow" // Earnings above the balance cap
+	PoolEntryDecay    PoolEntryType = "decay"    // Credits decayed from inactive accounts
+	PoolEntryExpiry   PoolEntryType = "expiry"   // Expired bonus credits
+	PoolEntryDonation PoolEntryType = "donation" // Voluntary donation from a user
+	PoolEntryGrant    PoolEntryType = "grant"    // Credits granted from the pool to a user
+)
+
+// CommunityPool is the shared credit account that collects redistributed credits
+// There is exactly one row (ID = CommunityPoolID)
+type CommunityPool struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Balance  float64 `gorm:"default:0" json:"balance"`
+	TotalIn  float64 `gorm:"default:0" json:"total_in"`  // All credits ever received
+	TotalOut float64 `gorm:"default:0" json:"total_out"` // All credits ever paid out
+}
+
+// TableName specifies the table name for CommunityPool model
+func (CommunityPool) TableName() string {
+	return "community_pools"
+}
+
+// CommunityPoolEntry is a ledger line of the community pool
+// Every entry mirrors a user Transaction so both sides of a movement are auditable
+type CommunityPoolEntry struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	Type         PoolEntryType `gorm:"not null;index" json:"type"`
+	Amount       float64       `gorm:"not null" json:"amount"` // Positive into the pool, negative out of it
+	BalanceAfter float64       `gorm:"not null" json:"balance_after"`
+	Description  string        `gorm:"type:text" json:"description"`
+
+	// Counterparty
+	UserID        *uint `gorm:"index" json:"user_id"`
+	TransactionID *uint `gorm:"index" json:"transaction_id"`
+
+	// GrantedBy is the admin who approved a grant (nil for automatic rules)
+	GrantedBy *uint `json:"granted_by,omitempty"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for CommunityPoolEntry model
+func (CommunityPoolEntry) TableName() string {
+	return "community_pool_entries"
+}
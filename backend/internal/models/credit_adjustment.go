@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// CreditAdjustmentStatus represents the review status of a credit adjustment
+type CreditAdjustmentStatus string
+
+const (
+	CreditAdjustmentPending  CreditAdjustmentStatus = "pending"  // Proposed, waiting for a second admin
+	CreditAdjustmentApproved CreditAdjustmentStatus = "approved" // Approved and posted to the ledger
+	CreditAdjustmentRejected CreditAdjustmentStatus = "rejected" // Rejected, nothing posted
+)
+
+// CreditAdjustment is an admin correction to a user's balance (maker-checker)
+// One admin proposes it; a different admin with manage_finance must approve it
+// before the ledger transaction is posted.
+type CreditAdjustment struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Target
+	UserID uint    `gorm:"not null;index" json:"user_id"`
+	Amount float64 `gorm:"not null" json:"amount"` // Positive to credit, negative to debit
+	Reason string  `gorm:"type:text;not null" json:"reason"`
+
+	// Review
+	Status      CreditAdjustmentStatus `gorm:"not null;default:'pending';index" json:"status"`
+	RequestedBy uint                   `gorm:"not null;index" json:"requested_by"` // Admin who proposed
+	ReviewedBy  *uint                  `json:"reviewed_by"`                        // Admin who approved or rejected
+	ReviewedAt  *time.Time             `json:"reviewed_at"`
+	ReviewNote  string                 `gorm:"type:text" json:"review_note,omitempty"`
+
+	// TransactionID is the posted ledger entry (approved adjustments only)
+	TransactionID *uint `json:"transaction_id"`
+
+	// Relationships
+	User      User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Requester Admin  `gorm:"foreignKey:RequestedBy" json:"requester,omitempty"`
+	Reviewer  *Admin `gorm:"foreignKey:ReviewedBy" json:"reviewer,omitempty"`
+}
+
+// TableName specifies the table name for CreditAdjustment model
+func (CreditAdjustment) TableName() string {
+	return "credit_adjustments"
+}
+
+// IsPending checks if the adjustment is waiting for review
+func (a *CreditAdjustment) IsPending() bool {
+	return a.Status == CreditAdjustmentPending
+}
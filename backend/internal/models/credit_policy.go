@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// CreditPolicyKind represents the rule that produced a credit adjustment
+type CreditPolicyKind string
+
+const (
+	PolicyBalanceCap  CreditPolicyKind = "balance_cap"  // Earnings above the maximum balance
+	PolicyDecay       CreditPolicyKind = "decay"        // Inactivity decay
+	PolicyBonusExpiry CreditPolicyKind = "bonus_expiry" // Bonus credits past their expiry
+)
+
+// CreditNoticeStatus represents the lifecycle of a policy notice
+type CreditNoticeStatus string
+
+const (
+	NoticePending   CreditNoticeStatus = "pending"   // Announced, waiting for DueAt
+	NoticeApplied   CreditNoticeStatus = "applied"   // Adjustment posted to the ledger
+	NoticeCancelled CreditNoticeStatus = "cancelled" // No longer applicable (user became active, spent the bonus, ...)
+)
+
+// CreditPolicyNotice records an announced credit adjustment
+// Decay and bonus expiry are announced NoticeDays before they are applied,
+// so users always get a chance to use their credits first
+type CreditPolicyNotice struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID uint               `gorm:"not null;index" json:"user_id"`
+	Kind   CreditPolicyKind   `gorm:"not null;index" json:"kind"`
+	Status CreditNoticeStatus `gorm:"not null;default:'pending';index" json:"status"`
+
+	// Amount announced to the user (the applied amount may be lower)
+	Amount float64 `gorm:"not null" json:"amount"`
+
+	// ReferenceID points to the bonus transaction for bonus expiry notices
+	ReferenceID *uint `gorm:"index" json:"reference_id"`
+
+	DueAt         time.Time  `gorm:"not null;index" json:"due_at"`
+	AppliedAt     *time.Time `json:"applied_at"`
+	TransactionID *uint      `json:"transaction_id"`
+}
+
+// TableName specifies the table name for CreditPolicyNotice model
+func (CreditPolicyNotice) TableName() string {
+	return "credit_policy_notices"
+}
+
+// IsDue checks if the announced adjustment can be applied
+func (n *CreditPolicyNotice) IsDue(now time.Time) bool {
+	return n.Status == NoticePending && !n.DueAt.After(now)
+}
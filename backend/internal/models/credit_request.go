@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreditRequestStatus represents the status of a credit request
+type CreditRequestStatus string
+
+const (
+	CreditRequestPending   CreditRequestStatus = "pending"   // Waiting for the payer
+	CreditRequestAccepted  CreditRequestStatus = "accepted"  // Payer accepted, credits transferred
+	CreditRequestDeclined  CreditRequestStatus = "declined"  // Payer declined
+	CreditRequestExpired   CreditRequestStatus = "expired"   // Payer did not respond before ExpiresAt
+	CreditRequestCancelled CreditRequestStatus = "cancelled" // Requester withdrew the request
+)
+
+// CreditRequest represents a request from one user asking another to send credits
+// e.g. "can you cover my half of the group session?"
+type CreditRequest struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Parties
+	RequesterID uint `gorm:"not null;index:idx_credit_requests_requester_status" json:"requester_id"` // Receives credits
+	PayerID     uint `gorm:"not null;index:idx_credit_requests_payer_status" json:"payer_id"`         // Asked to pay
+
+	// Request Details
+	Amount  float64             `gorm:"not null" json:"amount"`
+	Message string              `gorm:"type:text" json:"message"`
+	Status  CreditRequestStatus `gorm:"not null;default:'pending';index:idx_credit_requests_requester_status;index:idx_credit_requests_payer_status" json:"status"`
+
+	// Lifecycle
+	ExpiresAt     time.Time  `gorm:"not null;index" json:"expires_at"`
+	RespondedAt   *time.Time `json:"responded_at"`
+	DeclineReason string     `gorm:"type:text" json:"decline_reason,omitempty"`
+	TransactionID *uint      `json:"transaction_id"` // Payer's debit when accepted
+
+	// Relationships
+	Requester User `gorm:"foreignKey:RequesterID" json:"requester,omitempty"`
+	Payer     User `gorm:"foreignKey:PayerID" json:"payer,omitempty"`
+}
+
+// TableName specifies the table name for CreditRequest model
+func (CreditRequest) TableName() string {
+	return "credit_requests"
+}
+
+// IsExpired checks if a pending request is past its expiry time
+func (r *CreditRequest) IsExpired(now time.Time) bool {
+	return r.Status == CreditRequestPending && now.After(r.ExpiresAt)
+}
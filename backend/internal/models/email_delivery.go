@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// EmailDeliveryStatus is the state of a notification email
+type EmailDeliveryStatus string
+
+const (
+	EmailDeliveryPending EmailDeliveryStatus = "pending" // Waiting to be sent at SendAfter
+	EmailDeliverySent    EmailDeliveryStatus = "sent"
+	EmailDeliverySkipped EmailDeliveryStatus = "skipped" // Disabled by preferences or expired before sending
+	EmailDeliveryFailed  EmailDeliveryStatus = "failed"  // Gave up after repeated SMTP errors
+)
+
+// EmailDelivery is a notification queued for the email channel (outbox)
+// Deliveries are queued when a notification is created and sent by the email dispatcher,
+// which re-checks the user's preferences and defers non-urgent emails during quiet hours.
+type EmailDelivery struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	NotificationID uint                `gorm:"not null;index" json:"notification_id"`
+	UserID         uint                `gorm:"not null;index" json:"user_id"`
+	Status         EmailDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending';index:idx_email_delivery_due,priority:1" json:"status"`
+	SendAfter      time.Time           `gorm:"not null;index:idx_email_delivery_due,priority:2" json:"send_after"`
+	Attempts       int                 `gorm:"default:0" json:"attempts"`
+	LastError      string              `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt         *time.Time          `json:"sent_at"`
+
+	// Relationships
+	Notification Notification `gorm:"foreignKey:NotificationID" json:"notification,omitempty"`
+	User         User         `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName specifies the table name for EmailDelivery model
+func (EmailDelivery) TableName() string {
+	return "email_deliveries"
+}
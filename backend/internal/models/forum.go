@@ -122,6 +122,9 @@ type Endorsement struct {
 	IsAnonymous bool      `gorm:"default:false" json:"is_anonymous"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// TransactionID links the donation to the donor's ledger debit (nil for legacy records)
+	TransactionID *uint `gorm:"index" json:"transaction_id"`
 }
 
 // JSONArray is a custom type for JSONB arrays
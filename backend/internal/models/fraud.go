@@ -0,0 +1,37 @@
+package models
+
+// FraudSignal identifies the pattern a fraud report was raised for
+type FraudSignal string
+
+const (
+	FraudSignalReciprocalPair  FraudSignal = "reciprocal_pair"  // Two users repeatedly teaching each other
+	FraudSignalInstantSessions FraudSignal = "instant_sessions" // Sessions completed right after check-in
+	FraudSignalTransferCycle   FraudSignal = "transfer_cycle"   // Credits cycled back to the sender through transfers
+	FraudSignalNewAccountRing  FraudSignal = "new_account_ring" // New accounts trading sessions among themselves
+)
+
+// ReciprocalPairStat is a pair of users who taught each other (UserA < UserB)
+type ReciprocalPairStat struct {
+	UserA      uint   `json:"user_a"`
+	UserB      uint   `json:"user_b"`
+	ATaught    int    `json:"a_taught"`
+	BTaught    int    `json:"b_taught"`
+	SessionIDs string `json:"session_ids"` // Comma-separated
+}
+
+// InstantSessionStat counts a teacher's sessions completed too soon after check-in
+type InstantSessionStat struct {
+	TeacherID    uint   `json:"teacher_id"`
+	SessionCount int    `json:"session_count"`
+	SessionIDs   string `json:"session_ids"` // Comma-separated
+	StudentIDs   string `json:"student_ids"` // Comma-separated, distinct
+}
+
+// UserEdgeStat is an aggregated directed edge between two users
+// (transfers from sender to recipient, or sessions from teacher to student)
+type UserEdgeStat struct {
+	FromID uint    `json:"from_id"`
+	ToID   uint    `json:"to_id"`
+	Count  int     `json:"count"`
+	Amount float64 `json:"amount"`
+}
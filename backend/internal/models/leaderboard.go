@@ -0,0 +1,22 @@
+package models
+
+// LeaderboardType identifies what a leaderboard ranks users by
+type LeaderboardType string
+
+const (
+	LeaderboardBadges   LeaderboardType = "badges"   // Number of badges earned
+	LeaderboardRarity   LeaderboardType = "rarity"   // Sum of earned badge rarities
+	LeaderboardSessions LeaderboardType = "sessions" // Completed sessions, teaching + learning
+	LeaderboardRating   LeaderboardType = "rating"   // Average rating received
+	LeaderboardCredits  LeaderboardType = "credits"  // Credits earned from teaching
+)
+
+// LeaderboardPeriod is the time window a leaderboard counts activity in
+type LeaderboardPeriod string
+
+const (
+	PeriodAllTime  LeaderboardPeriod = "all"
+	PeriodWeek     LeaderboardPeriod = "week"     // Since Monday 00:00
+	PeriodMonth    LeaderboardPeriod = "month"    // Since the 1st of the month
+	PeriodSemester LeaderboardPeriod = "semester" // Since Feb 1 (even semester) or Aug 1 (odd semester)
+)
@@ -15,8 +15,16 @@ func AutoMigrate(db *gorm.DB) error {
 		&LearningSkill{},
 		&Session{},
 		&Review{},
+		&ReviewModerationLog{},
+		&ReviewHelpfulVote{},
+		&ReviewTag{},
 		&Badge{},
 		&UserBadge{},
+		&BadgeProgress{},
+		&BadgeRevocation{},
+		&BadgeBackfill{},
+		&Challenge{},
+		&ChallengeParticipant{},
 		&Transaction{},
 		&Notification{},
 		&ForumCategory{},
@@ -38,6 +46,14 @@ func AutoMigrate(db *gorm.DB) error {
 		&SessionTemplate{},
 		&UsedToken{},
 		&NotificationPreference{},
+		&EmailDelivery{},
+		&PushSubscription{},
+		&PushDelivery{},
+		&CommunityPool{},
+		&CommunityPoolEntry{},
+		&CreditPolicyNotice{},
+		&CreditRequest{},
+		&CreditAdjustment{},
 	}
 	
 	successCount := 0
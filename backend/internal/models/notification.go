@@ -28,6 +28,9 @@ const (
 //
 // The data field is JSONB to allow flexible payload structures
 // Different notification types can have different data structures
+//
+// The ID is the user's replay cursor: a client reconnecting after the last ID it saw
+// receives every notification with a greater ID, in order.
 type Notification struct {
 	ID        uint                   `gorm:"primaryKey" json:"id"`
 	UserID    uint                   `gorm:"index" json:"user_id"`
@@ -37,6 +40,7 @@ type Notification struct {
 	Data      json.RawMessage  `gorm:"type:jsonb" json:"data"`
 	IsRead    bool             `gorm:"default:false;index" json:"is_read"`
 	ReadAt    *time.Time             `json:"read_at"`
+	DeliveredAt *time.Time           `json:"delivered_at"` // First acknowledged by a connected client; nil if never delivered live
 	CreatedAt time.Time              `gorm:"index" json:"created_at"`
 	UpdatedAt time.Time              `json:"updated_at"`
 	DeletedAt gorm.DeletedAt         `gorm:"index" json:"deleted_at"`
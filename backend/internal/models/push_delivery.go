@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// PushDeliveryStatus is the state of a notification push
+type PushDeliveryStatus string
+
+const (
+	PushDeliveryPending PushDeliveryStatus = "pending" // Waiting to be pushed at SendAfter
+	PushDeliverySent    PushDeliveryStatus = "sent"    // Accepted by the push service of at least one device
+	PushDeliverySkipped PushDeliveryStatus = "skipped" // Delivered live, disabled by preferences, expired or no devices left
+	PushDeliveryFailed  PushDeliveryStatus = "failed"  // Gave up after repeated push service errors
+)
+
+// PushDelivery is a notification queued for the Web Push channel (outbox)
+// Deliveries are queued when a notification is created for a user with push subscriptions.
+// The push dispatcher waits briefly for a live connection to acknowledge the notification
+// and only pushes it to the user's devices if none did.
+type PushDelivery struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	NotificationID uint               `gorm:"not null;index" json:"notification_id"`
+	UserID         uint               `gorm:"not null;index" json:"user_id"`
+	Status         PushDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending';index:idx_push_delivery_due,priority:1" json:"status"`
+	SendAfter      time.Time          `gorm:"not null;index:idx_push_delivery_due,priority:2" json:"send_after"`
+	Attempts       int                `gorm:"default:0" json:"attempts"`
+	LastError      string             `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt         *time.Time         `json:"sent_at"`
+
+	// Relationships
+	Notification Notification `gorm:"foreignKey:NotificationID" json:"notification,omitempty"`
+}
+
+// TableName specifies the table name for PushDelivery model
+func (PushDelivery) TableName() string {
+	return "push_deliveries"
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// PushSubscription is a browser push subscription of a user, one per device
+// The endpoint is unique: a browser re-subscribing with the same endpoint (or after a
+// different user logged in on it) replaces the previous subscription.
+type PushSubscription struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID    uint   `gorm:"not null;index" json:"user_id"`
+	Endpoint  string `gorm:"type:text;not null;uniqueIndex" json:"endpoint"`
+	P256dh    string `gorm:"type:varchar(100);not null" json:"-"` // User agent public key, base64url
+	Auth      string `gorm:"type:varchar(50);not null" json:"-"`  // User agent authentication secret, base64url
+	UserAgent string `gorm:"type:varchar(255)" json:"user_agent"` // Helps users tell their devices apart
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName specifies the table name for PushSubscription model
+func (PushSubscription) TableName() string {
+	return "push_subscriptions"
+}
@@ -10,9 +10,11 @@ import (
 type ReportType string
 
 const (
-	ReportTypeForum ReportType = "forum"
-	ReportTypeStory ReportType = "story"
-	ReportTypeUser  ReportType = "user"
+	ReportTypeForum  ReportType = "forum"
+	ReportTypeStory  ReportType = "story"
+	ReportTypeUser   ReportType = "user"
+	ReportTypeReview ReportType = "review" // TargetID is a review, Reason is one of the ReviewReportReasons
+	ReportTypeFraud  ReportType = "fraud"  // Raised by the fraud detection job, TargetID is a user
 )
 
 // ReportStatus defines the status of a report
@@ -26,18 +28,28 @@ const (
 
 // Report represents a user report for moderation
 type Report struct {
-	ID             uint         `gorm:"primaryKey" json:"id"`
-	Type           ReportType   `gorm:"type:varchar(20);not null" json:"type"`
-	TargetID       uint         `gorm:"not null" json:"target_id"` // ID of the reported entity (thread_id, story_id, user_id)
-	ReportedBy     uint         `gorm:"not null" json:"-"`
-	Reporter       User         `gorm:"foreignKey:ReportedBy" json:"reporter"`
-	Reason         string       `gorm:"type:text;not null" json:"reason"`
-	Status         ReportStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
-	Resolution     string       `gorm:"type:text" json:"resolution,omitempty"`
-	ResolvedBy     *uint        `json:"-"`
-	Resolver       *User        `gorm:"foreignKey:ResolvedBy" json:"resolver,omitempty"`
-	ResolvedAt     *time.Time   `json:"resolved_at,omitempty"`
-	CreatedAt      time.Time    `json:"created_at"`
-	UpdatedAt      time.Time    `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Type        ReportType     `gorm:"type:varchar(20);not null" json:"type"`
+	TargetID    uint           `gorm:"not null" json:"target_id"` // ID of the reported entity (thread_id, story_id, user_id)
+	ReportedBy  *uint          `json:"-"`                         // nil for reports raised by the system
+	Reporter    *User          `gorm:"foreignKey:ReportedBy" json:"reporter"`
+	Reason      string         `gorm:"type:text;not null" json:"reason"`
+	Status      ReportStatus   `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	Metadata    string         `gorm:"type:jsonb" json:"metadata"` // Evidence for system reports (involved users, sessions, amounts)
+	Fingerprint string         `gorm:"size:191;index" json:"-"`    // Identifies the detected pattern, so it is not reported twice
+	Resolution  string         `gorm:"type:text" json:"resolution,omitempty"`
+	ResolvedBy  *uint          `json:"-"`
+	Resolver    *User          `gorm:"foreignKey:ResolvedBy" json:"resolver,omitempty"`
+	ResolvedAt  *time.Time     `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// BeforeCreate hook to ensure Metadata has valid JSON
+func (r *Report) BeforeCreate(tx *gorm.DB) error {
+	if r.Metadata == "" {
+		r.Metadata = "{}"
+	}
+	return nil
 }
@@ -17,7 +17,7 @@ const (
 // Review represents a rating and review after a session
 type Review struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
-	CreatedAt time.Time      `json:"created_at"`
+	CreatedAt time.Time      `gorm:"index" json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
@@ -35,8 +35,8 @@ type Review struct {
 	// Review Content
 	Comment string `gorm:"type:text" json:"comment"`
 	
-	// Tags/Badges (e.g., "Patient", "Clear Explanation", "Punctual")
-	Tags string `gorm:"type:text" json:"tags"` // Comma-separated or JSON array
+	// Tags from the ReviewTag vocabulary of the review's type (e.g., "explains-clearly,punctual")
+	Tags string `gorm:"type:text" json:"tags"` // Comma-separated ReviewTag slugs
 	
 	// Specific Ratings (optional, more detailed feedback)
 	CommunicationRating *int `gorm:"check:communication_rating >= 1 AND communication_rating <= 5" json:"communication_rating"`
@@ -44,11 +44,21 @@ type Review struct {
 	KnowledgeRating     *int `gorm:"check:knowledge_rating >= 1 AND knowledge_rating <= 5" json:"knowledge_rating"`
 	
 	// Helpful votes (other users can upvote helpful reviews)
-	HelpfulCount int `gorm:"default:0" json:"helpful_count"`
+	HelpfulCount int `gorm:"default:0;index" json:"helpful_count"`
+
+	// Public reply from the reviewee (at most one per review)
+	Reply     string     `gorm:"type:text" json:"reply"`
+	RepliedAt *time.Time `json:"replied_at"`
 	
 	// Moderation
 	IsReported bool   `gorm:"default:false" json:"is_reported"`
 	IsHidden   bool   `gorm:"default:false" json:"is_hidden"`
+
+	// Double-blind: a sealed review is hidden from its reviewee (and everyone but its
+	// reviewer) until the counterpart review is submitted or the review window closes.
+	// Revealed reviews can no longer be edited or deleted.
+	IsSealed   bool       `gorm:"default:false;index" json:"is_sealed"`
+	RevealedAt *time.Time `json:"revealed_at"`
 	
 	// Relationships
 	Session  Session `gorm:"foreignKey:SessionID" json:"session,omitempty"`
@@ -94,3 +104,88 @@ func (r *Review) AverageDetailedRating() float64 {
 	
 	return float64(sum) / float64(count)
 }
+
+// ReviewSort is an ordering of review lists
+type ReviewSort string
+
+const (
+	ReviewSortRecent  ReviewSort = "recent"  // Newest first (default)
+	ReviewSortHelpful ReviewSort = "helpful" // Most helpful votes first
+	ReviewSortHighest ReviewSort = "highest" // Highest rating first
+	ReviewSortLowest  ReviewSort = "lowest"  // Lowest rating first
+)
+
+// RatingScore summarizes the visible reviews received by a user or for a taught skill
+// Adjusted is the ranking score: a recency-weighted average pulled towards the
+// platform mean, so a few reviews cannot outrank a long, consistent record.
+type RatingScore struct {
+	Raw         float64 `json:"raw"`      // Plain average of the ratings
+	Adjusted    float64 `json:"adjusted"` // Bayesian, time-decayed average
+	ReviewCount int64   `json:"review_count"`
+
+	// Averages of the optional detailed ratings; nil if none were given
+	Communication *float64 `json:"communication,omitempty"`
+	Punctuality   *float64 `json:"punctuality,omitempty"`
+	Knowledge     *float64 `json:"knowledge,omitempty"`
+}
+
+// ReviewHelpfulVote tracks which users found which reviews helpful
+type ReviewHelpfulVote struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_review_helpful_user_review;index" json:"user_id"`
+	ReviewID  uint      `gorm:"uniqueIndex:idx_review_helpful_user_review;index" json:"review_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName overrides the table name
+func (ReviewHelpfulVote) TableName() string { return "review_helpful_votes" }
+
+// ReviewReportReasons are the reasons a user can give when reporting a review
+var ReviewReportReasons = []string{
+	"spam",                 // Advertising or unrelated content
+	"offensive",            // Insults, profanity or hate speech
+	"harassment",           // Personal attacks or threats
+	"false_information",    // Describes a session that did not happen this way
+	"conflict_of_interest", // Retaliation or a review from a friend
+	"other",
+}
+
+// IsValidReviewReportReason reports whether reason is one of the ReviewReportReasons
+func IsValidReviewReportReason(reason string) bool {
+	for _, valid := range ReviewReportReasons {
+		if reason == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ReviewModerationAction is an admin decision on a review
+type ReviewModerationAction string
+
+const (
+	ModerationHide    ReviewModerationAction = "hide"    // Review hidden from profiles and rating aggregates
+	ModerationUnhide  ReviewModerationAction = "unhide"  // Hidden review made visible again
+	ModerationDismiss ReviewModerationAction = "dismiss" // Reports dismissed, visibility unchanged
+)
+
+// ReviewModerationLog is the audit trail of moderation actions on a review
+type ReviewModerationLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	ReviewID uint                   `gorm:"not null;index" json:"review_id"`
+	AdminID  uint                   `gorm:"not null;index" json:"admin_id"`
+	Action   ReviewModerationAction `gorm:"type:varchar(20);not null" json:"action"`
+	Reason   string                 `gorm:"type:text;not null" json:"reason"`
+
+	ReportsClosed int `gorm:"default:0" json:"reports_closed"` // Pending reports resolved or dismissed by the action
+
+	// Relationships
+	Admin User `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
+}
+
+// TableName specifies the table name for ReviewModerationLog model
+func (ReviewModerationLog) TableName() string {
+	return "review_moderation_logs"
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxReviewTags is the most tags a single review can carry
+const MaxReviewTags = 5
+
+// ReviewTag is a tag of the curated, admin-managed review vocabulary
+// Teacher tags describe teachers ("explains clearly") and can only be given in teacher
+// reviews; student tags ("well prepared") only in student reviews. Reviews store the
+// slugs of their tags in Review.Tags.
+type ReviewTag struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Slug  string     `gorm:"type:varchar(50);uniqueIndex;not null" json:"slug"` // Stable identifier stored on reviews
+	Label string     `gorm:"type:varchar(100);not null" json:"label"`
+	Type  ReviewType `gorm:"type:varchar(20);not null;index" json:"type"` // Which reviews can use the tag
+
+	// Inactive tags can no longer be given and are left out of tag clouds
+	IsActive  bool `gorm:"default:true" json:"is_active"`
+	SortOrder int  `gorm:"default:0" json:"sort_order"`
+}
+
+// TableName specifies the table name for ReviewTag model
+func (ReviewTag) TableName() string {
+	return "review_tags"
+}
+
+// ReviewTagCount is a tag with the number of visible reviews giving it, an entry of a tag cloud
+type ReviewTagCount struct {
+	Slug  string `json:"slug"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// ParseReviewTags splits comma-separated review tags into trimmed, lower-case slugs
+// Empty entries and duplicates are dropped; the order of first occurrence is kept.
+func ParseReviewTags(tags string) []string {
+	slugs := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(tags, ",") {
+		slug := strings.ToLower(strings.TrimSpace(tag))
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		slugs = append(slugs, slug)
+	}
+	return slugs
+}
@@ -51,7 +51,7 @@ type Session struct {
 	// Scheduling
 	ScheduledAt   *time.Time `json:"scheduled_at"`    // When the session will happen
 	StartedAt     *time.Time `json:"started_at"`      // Actual start time
-	CompletedAt   *time.Time `json:"completed_at"`    // Actual completion time
+	CompletedAt   *time.Time `gorm:"index" json:"completed_at"` // Actual completion time
 	
 	// Status
 	Status SessionStatus `gorm:"not null;default:'pending';index" json:"status"`
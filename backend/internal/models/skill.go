@@ -48,6 +48,9 @@ type Skill struct {
 	MinRate          float64 `json:"min_rate"`
 	MaxRate          float64 `json:"max_rate"`
 	MaxTeacherRating float64 `json:"max_teacher_rating"`
+
+	// Best adjusted rating among its teachers; set by recommendations only
+	MaxTeacherAdjustedRating float64 `gorm:"-" json:"max_teacher_adjusted_rating,omitempty"`
 	
 	// Relationships
 	UserSkills []UserSkill `gorm:"foreignKey:SkillID" json:"-"`
@@ -89,6 +92,12 @@ type UserSkill struct {
 	TotalSessions   int     `gorm:"default:0" json:"total_sessions"`
 	AverageRating   float64 `gorm:"default:0" json:"average_rating"`
 	TotalReviews    int     `gorm:"default:0" json:"total_reviews"`
+
+	// Raw and adjusted rating with detailed averages; set by teacher lists only
+	Rating *RatingScore `gorm:"-" json:"rating,omitempty"`
+
+	// Tag cloud of the visible teacher reviews for the skill; set by teacher lists only
+	Tags []ReviewTagCount `gorm:"-" json:"tags,omitempty"`
 	
 	// Relationships
 	User  User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
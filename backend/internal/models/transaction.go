@@ -10,13 +10,20 @@ import (
 type TransactionType string
 
 const (
-	TransactionEarned  TransactionType = "earned"  // Earned from teaching
-	TransactionSpent   TransactionType = "spent"   // Spent on learning
-	TransactionBonus   TransactionType = "bonus"   // Bonus credits (achievements, etc)
-	TransactionRefund  TransactionType = "refund"  // Refunded from cancelled session
-	TransactionPenalty TransactionType = "penalty" // Penalty for no-show, etc
-	TransactionInitial TransactionType = "initial" // Initial free credits
-	TransactionHold    TransactionType = "hold"    // Credits held in escrow for pending session
+	TransactionEarned     TransactionType = "earned"     // Earned from teaching
+	TransactionSpent      TransactionType = "spent"      // Spent on learning
+	TransactionBonus      TransactionType = "bonus"      // Bonus credits (achievements, etc)
+	TransactionRefund     TransactionType = "refund"     // Refunded from cancelled session
+	TransactionPenalty    TransactionType = "penalty"    // Penalty for no-show, etc
+	TransactionInitial    TransactionType = "initial"    // Initial free credits
+	TransactionHold       TransactionType = "hold"       // Credits held in escrow for pending session
+	TransactionOverflow   TransactionType = "overflow"   // Earnings above the balance cap sent to the community pool
+	TransactionDecay      TransactionType = "decay"      // Inactivity decay sent to the community pool
+	TransactionExpiry     TransactionType = "expiry"     // Expired bonus credits sent to the community pool
+	TransactionDonation   TransactionType = "donation"   // Donation to the community pool
+	TransactionGrant      TransactionType = "grant"      // Grant received from the community pool
+	TransactionAdjustment TransactionType = "adjustment" // Admin balance correction (approved by a second admin)
+	TransactionClawback   TransactionType = "clawback"   // Badge bonus taken back when the badge is revoked
 )
 
 // Transaction represents a credit transaction history
@@ -68,4 +75,4 @@ func (t *Transaction) IsCredit() bool {
 // IsDebit checks if transaction deducts credits
 func (t *Transaction) IsDebit() bool {
 	return t.Amount < 0
-}
\ No newline at end of file
+}
@@ -20,7 +20,7 @@ type User struct {
 	Username  string `gorm:"uniqueIndex;not null" json:"username"`
 	
 	// Profile Info
-	School      string  `json:"school"`
+	School      string  `gorm:"index" json:"school"`
 	Grade       string  `json:"grade"`        
 	Major       string  `json:"major"`        
 	Bio         string  `gorm:"type:text" json:"bio"`
@@ -33,6 +33,11 @@ type User struct {
 	CreditHeld    float64 `gorm:"default:0" json:"credit_held"`       
 	TotalEarned   float64 `gorm:"default:0" json:"total_earned"`
 	TotalSpent    float64 `gorm:"default:0" json:"total_spent"`
+
+	// Payout freeze (set by fraud detection, lifted by an admin)
+	// While frozen, teaching earnings are credited but counted in FrozenCredits, so they cannot be spent
+	PayoutsFrozen bool    `gorm:"default:false" json:"payouts_frozen"`
+	FrozenCredits float64 `gorm:"default:0" json:"frozen_credits"`
 	
 	// Stats
 	TotalSessionsAsTeacher int     `gorm:"default:0" json:"total_sessions_as_teacher"`
@@ -72,6 +77,11 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// AvailableCredits returns the credits a user can spend: the balance minus escrow and frozen earnings
+func (u *User) AvailableCredits() float64 {
+	return u.CreditBalance - u.CreditHeld - u.FrozenCredits
+}
 // DailyStat represents daily aggregated statistics
 type DailyStat struct {
 	Date  string  `json:"date"`
@@ -0,0 +1,444 @@
+// Package redistest provides an embedded Redis stand-in for tests
+//
+// The server speaks RESP2 on 127.0.0.1 and implements the commands used by the app's
+// pub/sub and presence code (PING, PUBLISH, SUBSCRIBE, UNSUBSCRIBE, ZADD, ZREM, ZCOUNT,
+// PEXPIRE), plus the connection setup go-redis performs. HELLO is rejected, so clients
+// fall back to RESP2:
+//
+//	srv, err := redistest.NewServer()
+//	defer srv.Close()
+//	client := redis.NewClient(&redis.Options{Addr: srv.Addr})
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an in-memory Redis pub/sub server
+type Server struct {
+	Addr string // host:port to connect to
+
+	listener    net.Listener
+	mutex       sync.Mutex
+	conns       map[*conn]bool
+	subscribers map[string]map[*conn]bool // Channel -> subscribed connections
+	zsets       map[string]map[string]float64
+	expiries    map[string]time.Time
+	wg          sync.WaitGroup
+}
+
+// conn is a client connection
+type conn struct {
+	net.Conn
+	reader   *bufio.Reader
+	writeMu  sync.Mutex // Messages published by other connections are written concurrently
+	channels map[string]bool
+}
+
+// NewServer starts a server on a free local port
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("redistest: failed to listen: %w", err)
+	}
+
+	s := &Server{
+		Addr:        listener.Addr().String(),
+		listener:    listener,
+		conns:       make(map[*conn]bool),
+		subscribers: make(map[string]map[*conn]bool),
+		zsets:       make(map[string]map[string]float64),
+		expiries:    make(map[string]time.Time),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Subscribers returns the number of connections subscribed to a channel
+func (s *Server) Subscribers(channel string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.subscribers[channel])
+}
+
+// Close stops the server and drops every connection, as a Redis outage would
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mutex.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// serve accepts connections until the listener is closed
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		netConn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &conn{Conn: netConn, reader: bufio.NewReader(netConn), channels: make(map[string]bool)}
+		s.mutex.Lock()
+		s.conns[c] = true
+		s.mutex.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.drop(c)
+			s.handle(c)
+		}()
+	}
+}
+
+// drop closes a connection and removes its subscriptions
+func (s *Server) drop(c *conn) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for channel := range c.channels {
+		s.unsubscribeLocked(c, channel)
+	}
+	delete(s.conns, c)
+	c.Close()
+}
+
+// handle runs the commands of a connection until it is closed
+func (s *Server) handle(c *conn) {
+	for {
+		args, err := readCommand(c.reader)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			s.mutex.Lock()
+			subscribed := len(c.channels) > 0
+			s.mutex.Unlock()
+			payload := ""
+			if len(args) > 1 {
+				payload = args[1]
+			}
+			switch {
+			case subscribed:
+				c.write(array(bulk("pong"), bulk(payload)))
+			case len(args) > 1:
+				c.write(bulk(payload))
+			default:
+				c.write("+PONG\r\n")
+			}
+		case "HELLO":
+			c.write("-ERR unknown command 'HELLO'\r\n")
+		case "AUTH", "SELECT", "CLIENT":
+			c.write("+OK\r\n")
+		case "PUBLISH":
+			if len(args) != 3 {
+				c.write("-ERR wrong number of arguments for 'publish' command\r\n")
+				continue
+			}
+			c.write(integer(s.publish(args[1], args[2])))
+		case "SUBSCRIBE":
+			if len(args) < 2 {
+				c.write("-ERR wrong number of arguments for 'subscribe' command\r\n")
+				continue
+			}
+			for _, channel := range args[1:] {
+				c.write(array(bulk("subscribe"), bulk(channel), integer(s.subscribe(c, channel))))
+			}
+		case "UNSUBSCRIBE":
+			channels := args[1:]
+			if len(channels) == 0 {
+				s.mutex.Lock()
+				for channel := range c.channels {
+					channels = append(channels, channel)
+				}
+				s.mutex.Unlock()
+			}
+			if len(channels) == 0 {
+				c.write(array("$-1\r\n", integer(0)))
+				continue
+			}
+			for _, channel := range channels {
+				s.mutex.Lock()
+				s.unsubscribeLocked(c, channel)
+				count := len(c.channels)
+				s.mutex.Unlock()
+				c.write(array(bulk("unsubscribe"), bulk(channel), integer(count)))
+			}
+		case "ZADD":
+			if len(args) < 4 || len(args)%2 != 0 {
+				c.write("-ERR wrong number of arguments for 'zadd' command\r\n")
+				continue
+			}
+			added, err := s.zadd(args[1], args[2:])
+			if err != nil {
+				c.write("-ERR value is not a valid float\r\n")
+				continue
+			}
+			c.write(integer(added))
+		case "ZREM":
+			if len(args) < 3 {
+				c.write("-ERR wrong number of arguments for 'zrem' command\r\n")
+				continue
+			}
+			c.write(integer(s.zrem(args[1], args[2:])))
+		case "ZCOUNT":
+			if len(args) != 4 {
+				c.write("-ERR wrong number of arguments for 'zcount' command\r\n")
+				continue
+			}
+			count, err := s.zcount(args[1], args[2], args[3])
+			if err != nil {
+				c.write("-ERR min or max is not a float\r\n")
+				continue
+			}
+			c.write(integer(count))
+		case "PEXPIRE":
+			if len(args) != 3 {
+				c.write("-ERR wrong number of arguments for 'pexpire' command\r\n")
+				continue
+			}
+			ms, err := strconv.ParseInt(args[2], 10, 64)
+			if err != nil {
+				c.write("-ERR value is not an integer or out of range\r\n")
+				continue
+			}
+			c.write(integer(s.pexpire(args[1], time.Duration(ms)*time.Millisecond)))
+		case "QUIT":
+			c.write("+OK\r\n")
+			return
+		default:
+			c.write(fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0]))
+		}
+	}
+}
+
+// publish sends a message to the subscribers of a channel and returns how many got it
+func (s *Server) publish(channel, message string) int {
+	s.mutex.Lock()
+	receivers := make([]*conn, 0, len(s.subscribers[channel]))
+	for c := range s.subscribers[channel] {
+		receivers = append(receivers, c)
+	}
+	s.mutex.Unlock()
+
+	payload := array(bulk("message"), bulk(channel), bulk(message))
+	for _, c := range receivers {
+		c.write(payload)
+	}
+	return len(receivers)
+}
+
+// zsetLocked returns a sorted set, dropping it first if it expired; s.mutex must be held
+func (s *Server) zsetLocked(key string) map[string]float64 {
+	if expiry, ok := s.expiries[key]; ok && !time.Now().Before(expiry) {
+		delete(s.zsets, key)
+		delete(s.expiries, key)
+	}
+	return s.zsets[key]
+}
+
+// zadd sets member scores given as score/member pairs and returns how many members are new
+func (s *Server) zadd(key string, pairs []string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	set := s.zsetLocked(key)
+	if set == nil {
+		set = make(map[string]float64)
+		s.zsets[key] = set
+	}
+	added := 0
+	for i := 0; i < len(pairs); i += 2 {
+		score, err := strconv.ParseFloat(pairs[i], 64)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := set[pairs[i+1]]; !ok {
+			added++
+		}
+		set[pairs[i+1]] = score
+	}
+	return added, nil
+}
+
+// zrem removes members from a sorted set and returns how many were there
+func (s *Server) zrem(key string, members []string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	set := s.zsetLocked(key)
+	removed := 0
+	for _, member := range members {
+		if _, ok := set[member]; ok {
+			delete(set, member)
+			removed++
+		}
+	}
+	if set != nil && len(set) == 0 {
+		delete(s.zsets, key)
+		delete(s.expiries, key)
+	}
+	return removed
+}
+
+// zcount counts the members scored within [min, max]; "(" makes a bound exclusive
+func (s *Server) zcount(key, min, max string) (int, error) {
+	inMin, err := parseScoreBound(min, false)
+	if err != nil {
+		return 0, err
+	}
+	inMax, err := parseScoreBound(max, true)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	count := 0
+	for _, score := range s.zsetLocked(key) {
+		if inMin(score) && inMax(score) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// pexpire sets a key's time to live and returns 1, or 0 if the key does not exist
+func (s *Server) pexpire(key string, ttl time.Duration) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.zsetLocked(key) == nil {
+		return 0
+	}
+	s.expiries[key] = time.Now().Add(ttl)
+	return 1
+}
+
+// parseScoreBound parses a ZCOUNT bound into a check of a score against it
+func parseScoreBound(bound string, upper bool) (func(float64) bool, error) {
+	exclusive := strings.HasPrefix(bound, "(")
+	value, err := strconv.ParseFloat(strings.TrimPrefix(bound, "("), 64) // Accepts "+inf" and "-inf"
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(value) {
+		return nil, fmt.Errorf("redistest: bad score bound %q", bound)
+	}
+	switch {
+	case upper && exclusive:
+		return func(score float64) bool { return score < value }, nil
+	case upper:
+		return func(score float64) bool { return score <= value }, nil
+	case exclusive:
+		return func(score float64) bool { return score > value }, nil
+	default:
+		return func(score float64) bool { return score >= value }, nil
+	}
+}
+
+// subscribe subscribes a connection to a channel and returns its subscription count
+func (s *Server) subscribe(c *conn, channel string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.subscribers[channel] == nil {
+		s.subscribers[channel] = make(map[*conn]bool)
+	}
+	s.subscribers[channel][c] = true
+	c.channels[channel] = true
+	return len(c.channels)
+}
+
+// unsubscribeLocked removes a connection's subscription; s.mutex must be held
+func (s *Server) unsubscribeLocked(c *conn, channel string) {
+	delete(c.channels, channel)
+	delete(s.subscribers[channel], c)
+	if len(s.subscribers[channel]) == 0 {
+		delete(s.subscribers, channel)
+	}
+}
+
+// write writes a reply, ignoring errors of connections that went away
+func (c *conn) write(reply string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, _ = io.WriteString(c.Conn, reply)
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		// Inline command
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, fmt.Errorf("redistest: bad array length %q", line)
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(header, "$") {
+			return nil, fmt.Errorf("redistest: expected bulk string, got %q", header)
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redistest: bad bulk length %q", header)
+		}
+		data := make([]byte, size+2) // Data and CRLF
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:size]))
+	}
+	return args, nil
+}
+
+// readLine reads a CRLF-terminated line
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// bulk encodes a bulk string reply
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+// integer encodes an integer reply
+func integer(n int) string {
+	return ":" + strconv.Itoa(n) + "\r\n"
+}
+
+// array encodes an array reply of encoded elements
+func array(elements ...string) string {
+	return "*" + strconv.Itoa(len(elements)) + "\r\n" + strings.Join(elements, "")
+}
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // BadgeRepository handles database operations for badges
@@ -31,6 +33,16 @@ func (r *BadgeRepository) GetBadgeByID(id uint) (*models.Badge, error) {
 	return &badge, err
 }
 
+// GetBadgeByName gets a badge by its unique name
+func (r *BadgeRepository) GetBadgeByName(name string) (*models.Badge, error) {
+	var badge models.Badge
+	err := r.db.Where("name = ?", name).First(&badge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
 // GetBadgesByType gets badges filtered by type
 func (r *BadgeRepository) GetBadgesByType(badgeType models.BadgeType) ([]models.Badge, error) {
 	var badges []models.Badge
@@ -93,20 +105,41 @@ func (r *BadgeRepository) HasUserBadge(userID, badgeID uint) (bool, error) {
 }
 
 // AwardBadge awards a badge to a user
-func (r *BadgeRepository) AwardBadge(userID, badgeID uint) (*models.UserBadge, error) {
+// A non-nil bonus is posted to the user's balance in the same database transaction, so the
+// badge and its bonus are granted together or not at all.
+// Returns utils.ErrBadgeAlreadyHeld when the user already holds the badge (e.g. awarded
+// concurrently by another path); the unique index makes the insert a no-op in that case.
+func (r *BadgeRepository) AwardBadge(userID, badgeID uint, bonus *models.Transaction) (*models.UserBadge, error) {
 	userBadge := &models.UserBadge{
 		UserID:   userID,
 		BadgeID:  badgeID,
 		EarnedAt: time.Now(),
 	}
-	err := r.db.Create(userBadge).Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(userBadge)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrBadgeAlreadyHeld
+		}
+
+		// Increment badge total awarded count
+		if err := tx.Model(&models.Badge{}).Where("id = ?", badgeID).
+			Update("total_awarded", gorm.Expr("total_awarded + ?", 1)).Error; err != nil {
+			return err
+		}
+
+		if bonus != nil {
+			if _, err := postTransaction(tx, bonus); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	// Increment badge total awarded count
-	_ = r.db.Model(&models.Badge{}).Where("id = ?", badgeID).Update("total_awarded", gorm.Expr("total_awarded + ?", 1))
-
 	return userBadge, nil
 }
 
@@ -134,31 +167,6 @@ func (r *BadgeRepository) GetUserPinnedBadges(userID uint) ([]models.UserBadge,
 	return userBadges, err
 }
 
-// GetBadgeLeaderboard gets top users by badge count
-func (r *BadgeRepository) GetBadgeLeaderboard(limit int) ([]map[string]interface{}, error) {
-	var results []map[string]interface{}
-	err := r.db.Model(&models.UserBadge{}).
-		Select("user_id, COUNT(*) as badge_count").
-		Group("user_id").
-		Order("badge_count DESC").
-		Limit(limit).
-		Scan(&results).Error
-	return results, err
-}
-
-// GetRarityLeaderboard gets top users by badge rarity
-func (r *BadgeRepository) GetRarityLeaderboard(limit int) ([]map[string]interface{}, error) {
-	var results []map[string]interface{}
-	err := r.db.Model(&models.UserBadge{}).
-		Select("user_badges.user_id, SUM(badges.rarity) as total_rarity").
-		Joins("JOIN badges ON badges.id = user_badges.badge_id").
-		Group("user_badges.user_id").
-		Order("total_rarity DESC").
-		Limit(limit).
-		Scan(&results).Error
-	return results, err
-}
-
 // GetUserBadgeCount gets total badge count for a user
 func (r *BadgeRepository) GetUserBadgeCount(userID uint) (int64, error) {
 	var count int64
@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Session roles accepted by the badge metric queries
+const (
+	BadgeRoleAny     = ""
+	BadgeRoleTeacher = "teacher"
+	BadgeRoleStudent = "student"
+)
+
+// BadgePeriod bounds the activity a badge metric counts: at or after Since and before
+// Until. A nil bound leaves that side open; the zero value counts everything.
+type BadgePeriod struct {
+	Since *time.Time
+	Until *time.Time
+}
+
+// apply restricts a query to rows whose column falls within the period
+func (p BadgePeriod) apply(query *gorm.DB, column string) *gorm.DB {
+	if p.Since != nil {
+		query = query.Where(column+" >= ?", *p.Since)
+	}
+	if p.Until != nil {
+		query = query.Where(column+" < ?", *p.Until)
+	}
+	return query
+}
+
+// completedSessions scopes a query to the user's completed sessions in the given role,
+// optionally completed within period
+func (r *BadgeRepository) completedSessions(userID uint, role string, period BadgePeriod) *gorm.DB {
+	query := r.db.Model(&models.Session{}).Where("sessions.status = ?", models.StatusCompleted)
+	switch role {
+	case BadgeRoleTeacher:
+		query = query.Where("sessions.teacher_id = ?", userID)
+	case BadgeRoleStudent:
+		query = query.Where("sessions.student_id = ?", userID)
+	default:
+		query = query.Where("sessions.teacher_id = ? OR sessions.student_id = ?", userID, userID)
+	}
+	return period.apply(query, "sessions.completed_at")
+}
+
+// CountCompletedSessions counts completed sessions for a user in a role ("" for both)
+func (r *BadgeRepository) CountCompletedSessions(userID uint, role string, period BadgePeriod) (int64, error) {
+	var count int64
+	err := r.completedSessions(userID, role, period).Count(&count).Error
+	return count, err
+}
+
+// SumSessionHours sums the duration of completed sessions for a user in a role
+func (r *BadgeRepository) SumSessionHours(userID uint, role string, period BadgePeriod) (float64, error) {
+	var hours float64
+	err := r.completedSessions(userID, role, period).
+		Select("COALESCE(SUM(sessions.duration), 0)").
+		Scan(&hours).Error
+	return hours, err
+}
+
+// CountUniqueSkills counts distinct skills in a user's completed sessions in a role
+func (r *BadgeRepository) CountUniqueSkills(userID uint, role string, period BadgePeriod) (int64, error) {
+	var count int64
+	err := r.completedSessions(userID, role, period).
+		Joins("JOIN user_skills ON user_skills.id = sessions.user_skill_id").
+		Select("COUNT(DISTINCT user_skills.skill_id)").
+		Scan(&count).Error
+	return count, err
+}
+
+// CountStudentsTaught counts distinct students in a teacher's completed sessions
+// With firstTimeOnly, a student counts only if their first completed session with the
+// teacher is in the period, so returning students are not counted again.
+func (r *BadgeRepository) CountStudentsTaught(teacherID uint, firstTimeOnly bool, period BadgePeriod) (int64, error) {
+	var count int64
+	if !firstTimeOnly || period.Since == nil {
+		err := r.completedSessions(teacherID, BadgeRoleTeacher, period).
+			Select("COUNT(DISTINCT sessions.student_id)").
+			Scan(&count).Error
+		return count, err
+	}
+
+	firsts := r.db.Table("(?) AS firsts", r.completedSessions(teacherID, BadgeRoleTeacher, BadgePeriod{}).
+		Select("sessions.student_id, MIN(sessions.completed_at) AS first_at").
+		Group("sessions.student_id"))
+	err := period.apply(firsts, "firsts.first_at").Count(&count).Error
+	return count, err
+}
+
+// GetSessionDays returns the distinct days with a completed session, most recent first
+func (r *BadgeRepository) GetSessionDays(userID uint) ([]time.Time, error) {
+	var days []time.Time
+	err := r.completedSessions(userID, BadgeRoleAny, BadgePeriod{}).
+		Where("sessions.completed_at IS NOT NULL").
+		Distinct("DATE(sessions.completed_at)").
+		Order("DATE(sessions.completed_at) DESC").
+		Pluck("DATE(sessions.completed_at)", &days).Error
+	return days, err
+}
+
+// SumTransactions sums the absolute amount of a user's transactions of one type
+// With sessionOnly, only lines linked to a session count (peer transfers are also "spent").
+func (r *BadgeRepository) SumTransactions(userID uint, txType models.TransactionType, sessionOnly bool, period BadgePeriod) (float64, error) {
+	var total float64
+	query := r.db.Model(&models.Transaction{}).
+		Where("user_id = ? AND type = ?", userID, txType)
+	if sessionOnly {
+		query = query.Where("session_id IS NOT NULL")
+	}
+	query = period.apply(query, "created_at")
+	err := query.Select("COALESCE(SUM(ABS(amount)), 0)").Scan(&total).Error
+	return total, err
+}
+
+// CountReviews counts visible reviews written by (given) or about (received) a user
+func (r *BadgeRepository) CountReviews(userID uint, given bool, period BadgePeriod) (int64, error) {
+	var count int64
+	column := "reviewee_id"
+	if given {
+		column = "reviewer_id"
+	}
+	query := r.db.Model(&models.Review{}).Where(column+" = ? AND is_hidden = ? AND is_sealed = ?", userID, false, false)
+	query = period.apply(query, "created_at")
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// AverageReceivedRating averages the ratings of visible reviews about a user (0 when none)
+func (r *BadgeRepository) AverageReceivedRating(userID uint, period BadgePeriod) (float64, error) {
+	var avg float64
+	query := r.db.Model(&models.Review{}).Where("reviewee_id = ? AND is_hidden = ? AND is_sealed = ?", userID, false, false)
+	query = period.apply(query, "created_at")
+	err := query.Select("COALESCE(AVG(rating), 0)").Scan(&avg).Error
+	return avg, err
+}
+
+// CountForumThreads counts forum threads started by a user
+func (r *BadgeRepository) CountForumThreads(userID uint, period BadgePeriod) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.ForumThread{}).Where("author_id = ?", userID)
+	query = period.apply(query, "created_at")
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// CountForumReplies counts forum replies written by a user
+func (r *BadgeRepository) CountForumReplies(userID uint, period BadgePeriod) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.ForumReply{}).Where("author_id = ?", userID)
+	query = period.apply(query, "created_at")
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// CountEarnedBadges counts badges earned by a user
+func (r *BadgeRepository) CountEarnedBadges(userID uint, period BadgePeriod) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.UserBadge{}).Where("user_id = ?", userID)
+	query = period.apply(query, "earned_at")
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// GetUserBadgeIDs returns the IDs of the badges a user holds
+func (r *BadgeRepository) GetUserBadgeIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.UserBadge{}).
+		Where("user_id = ?", userID).
+		Pluck("badge_id", &ids).Error
+	return ids, err
+}
+
+// GetUserBadgeProgress returns a user's stored progress rows keyed by badge ID
+func (r *BadgeRepository) GetUserBadgeProgress(userID uint) (map[uint]*models.BadgeProgress, error) {
+	var rows []models.BadgeProgress
+	if err := r.db.Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	progress := make(map[uint]*models.BadgeProgress, len(rows))
+	for i := range rows {
+		progress[rows[i].BadgeID] = &rows[i]
+	}
+	return progress, nil
+}
+
+// SaveBadgeProgress inserts or updates a user's progress toward a badge
+func (r *BadgeRepository) SaveBadgeProgress(progress *models.BadgeProgress) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "badge_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"percent", "notified_at", "updated_at"}),
+	}).Create(progress).Error
+}
+
+// GetBadgeHolderIDs returns the IDs of the users holding a badge
+func (r *BadgeRepository) GetBadgeHolderIDs(badgeID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.UserBadge{}).
+		Where("badge_id = ?", badgeID).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// GetPermanentlyRevokedBadgeIDs returns the badges a user may not be awarded again automatically
+func (r *BadgeRepository) GetPermanentlyRevokedBadgeIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.BadgeRevocation{}).
+		Where("user_id = ? AND permanent = ?", userID, true).
+		Distinct("badge_id").
+		Pluck("badge_id", &ids).Error
+	return ids, err
+}
+
+// IsBadgePermanentlyRevoked checks if a badge was permanently revoked from a user
+func (r *BadgeRepository) IsBadgePermanentlyRevoked(userID, badgeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.BadgeRevocation{}).
+		Where("user_id = ? AND badge_id = ? AND permanent = ?", userID, badgeID, true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetRevocations returns revocations with optional user and badge filters, newest first
+func (r *BadgeRepository) GetRevocations(userID, badgeID uint, limit, offset int) ([]models.BadgeRevocation, int64, error) {
+	var revocations []models.BadgeRevocation
+	var total int64
+
+	query := r.db.Model(&models.BadgeRevocation{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if badgeID != 0 {
+		query = query.Where("badge_id = ?", badgeID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Preload("Badge", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&revocations).Error
+	return revocations, total, err
+}
+
+// RevokeBadge takes a badge back from a user in one database transaction: the UserBadge row
+// is soft deleted, the badge's total_awarded is decremented and the revocation is stored.
+//
+// With clawback, the remaining badge bonus (the bonus transaction minus what the credit policy
+// already expired) is debited as a clawback transaction and pending expiry notices for it are
+// cancelled. Unless revocation.Override is set, the debit is capped at the user's available
+// credits (balance minus escrow and frozen earnings), so the balance never goes below zero.
+//
+// Returns utils.ErrBadgeNotHeld if the user does not hold the badge.
+func (r *BadgeRepository) RevokeBadge(revocation *models.BadgeRevocation, clawback bool, description string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var userBadge models.UserBadge
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND badge_id = ?", revocation.UserID, revocation.BadgeID).
+			First(&userBadge).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.ErrBadgeNotHeld
+			}
+			return err
+		}
+		revocation.UserBadgeID = userBadge.ID
+
+		if err := tx.Delete(&userBadge).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Badge{}).
+			Where("id = ? AND total_awarded > 0", revocation.BadgeID).
+			Update("total_awarded", gorm.Expr("total_awarded - ?", 1)).Error; err != nil {
+			return err
+		}
+
+		if clawback {
+			if err := clawBackBadgeBonus(tx, revocation, description); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(revocation).Error
+	})
+}
+
+// clawBackBadgeBonus debits the remaining bonus of a revoked badge inside tx
+// The bonus is the latest bonus transaction for the badge that was not clawed back before.
+func clawBackBadgeBonus(tx *gorm.DB, revocation *models.BadgeRevocation, description string) error {
+	var bonus models.Transaction
+	err := tx.Where("user_id = ? AND type = ? AND amount > 0", revocation.UserID, models.TransactionBonus).
+		Where("metadata->>'badge_id' = ?", strconv.FormatUint(uint64(revocation.BadgeID), 10)).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM badge_revocations br WHERE br.bonus_transaction_id = transactions.id
+		)`).
+		Order("created_at DESC").
+		First(&bonus).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // No ledgered bonus to take back
+		}
+		return err
+	}
+	revocation.BonusTransactionID = &bonus.ID
+
+	var expired float64
+	if err := tx.Table("credit_policy_notices n").
+		Joins("JOIN transactions t ON t.id = n.transaction_id").
+		Where("n.kind = ? AND n.reference_id = ? AND n.status = ?", models.PolicyBonusExpiry, bonus.ID, models.NoticeApplied).
+		Select("COALESCE(SUM(ABS(t.amount)), 0)").
+		Scan(&expired).Error; err != nil {
+		return err
+	}
+	revocation.BonusRemaining = math.Max(0, bonus.Amount-expired)
+
+	// The bonus is gone with the badge, so it can no longer expire
+	if err := tx.Model(&models.CreditPolicyNotice{}).
+		Where("kind = ? AND reference_id = ? AND status = ?", models.PolicyBonusExpiry, bonus.ID, models.NoticePending).
+		Update("status", models.NoticeCancelled).Error; err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&user, revocation.UserID).Error; err != nil {
+		return err
+	}
+
+	amount := revocation.BonusRemaining
+	if !revocation.Override {
+		amount = math.Min(amount, math.Max(0, user.AvailableCredits()))
+	}
+	amount = math.Round(amount*100) / 100
+	if amount <= 0 {
+		return nil
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"badge_id":             revocation.BadgeID,
+		"bonus_transaction_id": bonus.ID,
+		"override":             revocation.Override,
+	})
+	transaction := &models.Transaction{
+		UserID:      revocation.UserID,
+		Type:        models.TransactionClawback,
+		Amount:      -amount,
+		Description: description,
+		Metadata:    string(metadata),
+	}
+	if _, err := postTransaction(tx, transaction); err != nil {
+		return err
+	}
+	revocation.ClawbackAmount = amount
+	revocation.TransactionID = &transaction.ID
+	return nil
+}
+
+// CreateBackfill records a new badge backfill job
+func (r *BadgeRepository) CreateBackfill(backfill *models.BadgeBackfill) error {
+	return r.db.Create(backfill).Error
+}
+
+// UpdateBackfill saves the progress or outcome of a badge backfill job
+func (r *BadgeRepository) UpdateBackfill(backfill *models.BadgeBackfill) error {
+	return r.db.Model(backfill).
+		Select("status", "users_checked", "awarded", "bonus_credits", "failed", "error", "finished_at").
+		Updates(backfill).Error
+}
+
+// GetBackfillByID finds a badge backfill job by ID
+func (r *BadgeRepository) GetBackfillByID(id uint) (*models.BadgeBackfill, error) {
+	var backfill models.BadgeBackfill
+	if err := r.db.First(&backfill, id).Error; err != nil {
+		return nil, err
+	}
+	return &backfill, nil
+}
+
+// GetRunningBackfill returns the running backfill of a badge that saved progress since
+// the given time, or nil if there is none
+func (r *BadgeRepository) GetRunningBackfill(badgeID uint, since time.Time) (*models.BadgeBackfill, error) {
+	var backfill models.BadgeBackfill
+	err := r.db.Where("badge_id = ? AND status = ? AND updated_at >= ?", badgeID, models.BadgeBackfillRunning, since).
+		Order("id DESC").
+		First(&backfill).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &backfill, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+func TestAwardBadgePostsBonusInTheSameTransaction(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewBadgeRepository(db)
+
+	mock.ExpectBegin()
+	expectInsert(mock, "user_badges", 3)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "badges" SET "total_awarded"=total_awarded + $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectLockedUser(mock, 7, 10)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "credit_balance"=$1`)).
+		WithArgs(12.0, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectInsert(mock, "transactions", 9)
+	mock.ExpectCommit()
+
+	bonus := &models.Transaction{UserID: 7, Type: models.TransactionBonus, Amount: 2}
+	userBadge, err := repo.AwardBadge(7, 4, bonus)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), userBadge.ID)
+	assert.Equal(t, 12.0, bonus.BalanceAfter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAwardBadgeRollsBackWhenTheBonusFails(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewBadgeRepository(db)
+
+	mock.ExpectBegin()
+	expectInsert(mock, "user_badges", 3)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "badges" SET "total_awarded"=total_awarded + $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	bonus := &models.Transaction{UserID: 7, Type: models.TransactionBonus, Amount: 2}
+	_, err := repo.AwardBadge(7, 4, bonus)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAwardBadgeAlreadyHeld(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewBadgeRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "user_badges"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err := repo.AwardBadge(7, 4, &models.Transaction{UserID: 7, Amount: 2})
+	assert.ErrorIs(t, err, utils.ErrBadgeAlreadyHeld)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChallengeRepository handles database operations for challenges and their participants
+type ChallengeRepository struct {
+	db *gorm.DB
+}
+
+// NewChallengeRepository creates a new challenge repository
+func NewChallengeRepository(db *gorm.DB) *ChallengeRepository {
+	return &ChallengeRepository{db: db}
+}
+
+// Create creates a new challenge (the reward badge itself is not saved)
+func (r *ChallengeRepository) Create(challenge *models.Challenge) error {
+	return r.db.Omit(clause.Associations).Create(challenge).Error
+}
+
+// Update saves a challenge (the reward badge itself is not saved)
+func (r *ChallengeRepository) Update(challenge *models.Challenge) error {
+	return r.db.Omit(clause.Associations).Save(challenge).Error
+}
+
+// Delete deletes a challenge (soft delete)
+func (r *ChallengeRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Challenge{}, id).Error
+}
+
+// GetByID gets a challenge by ID with its reward badge
+func (r *ChallengeRepository) GetByID(id uint) (*models.Challenge, error) {
+	var challenge models.Challenge
+	if err := r.db.Preload("RewardBadge").First(&challenge, id).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// GetAll returns challenges, soonest ending first
+// With activeOnly, only challenges published to users (is_active) are returned;
+// status ("upcoming", "active", "ended") filters by phase at now, "" returns every phase.
+func (r *ChallengeRepository) GetAll(activeOnly bool, status models.ChallengeStatus, now time.Time) ([]models.Challenge, error) {
+	var challenges []models.Challenge
+	query := r.db.Preload("RewardBadge")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	switch status {
+	case models.ChallengeUpcoming:
+		query = query.Where("starts_at > ?", now)
+	case models.ChallengeRunning:
+		query = query.Where("starts_at <= ? AND ends_at > ?", now, now)
+	case models.ChallengeEnded:
+		query = query.Where("ends_at <= ?", now)
+	}
+	err := query.Order("ends_at ASC").Find(&challenges).Error
+	return challenges, err
+}
+
+// GetEvaluable returns the published challenges that have started and ended after since
+// since is the previous evaluation, so activity right before the end is still counted.
+func (r *ChallengeRepository) GetEvaluable(now, since time.Time) ([]models.Challenge, error) {
+	var challenges []models.Challenge
+	err := r.db.Preload("RewardBadge").
+		Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, now, since).
+		Find(&challenges).Error
+	return challenges, err
+}
+
+// GetParticipant gets a user's enrollment in a challenge
+func (r *ChallengeRepository) GetParticipant(challengeID, userID uint) (*models.ChallengeParticipant, error) {
+	var participant models.ChallengeParticipant
+	err := r.db.Where("challenge_id = ? AND user_id = ?", challengeID, userID).First(&participant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// GetUserParticipations returns a user's enrollments keyed by challenge ID
+func (r *ChallengeRepository) GetUserParticipations(userID uint) (map[uint]*models.ChallengeParticipant, error) {
+	var rows []models.ChallengeParticipant
+	if err := r.db.Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	participations := make(map[uint]*models.ChallengeParticipant, len(rows))
+	for i := range rows {
+		participations[rows[i].ChallengeID] = &rows[i]
+	}
+	return participations, nil
+}
+
+// GetOpenParticipations returns the user's enrollments that are not completed yet
+// in the given challenges
+func (r *ChallengeRepository) GetOpenParticipations(userID uint, challengeIDs []uint) ([]models.ChallengeParticipant, error) {
+	var rows []models.ChallengeParticipant
+	if len(challengeIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.Where("user_id = ? AND challenge_id IN ? AND completed_at IS NULL", userID, challengeIDs).
+		Find(&rows).Error
+	return rows, err
+}
+
+// Enroll adds a participant and increments the challenge's participant count in one
+// database transaction, respecting max_participants
+// Returns utils.ErrChallengeFull or utils.ErrAlreadyEnrolled on failure.
+func (r *ChallengeRepository) Enroll(participant *models.ChallengeParticipant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Challenge{}).
+			Where("id = ? AND (max_participants = 0 OR participant_count < max_participants)", participant.ChallengeID).
+			Update("participant_count", gorm.Expr("participant_count + ?", 1))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrChallengeFull
+		}
+
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(participant)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrAlreadyEnrolled
+		}
+		return nil
+	})
+}
+
+// Withdraw removes an uncompleted participant and decrements the participant count
+// Returns utils.ErrNotEnrolled if there is no uncompleted enrollment.
+func (r *ChallengeRepository) Withdraw(challengeID, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("challenge_id = ? AND user_id = ? AND completed_at IS NULL", challengeID, userID).
+			Delete(&models.ChallengeParticipant{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrNotEnrolled
+		}
+		return tx.Model(&models.Challenge{}).
+			Where("id = ? AND participant_count > 0", challengeID).
+			Update("participant_count", gorm.Expr("participant_count - ?", 1)).Error
+	})
+}
+
+// UpdateProgress stores a participant's progress percentage
+func (r *ChallengeRepository) UpdateProgress(participantID uint, percent int) error {
+	return r.db.Model(&models.ChallengeParticipant{}).
+		Where("id = ?", participantID).
+		Update("percent", percent).Error
+}
+
+// MarkCompleted marks a participant as completed and increments the challenge's completed count
+// A non-nil reward is posted to the participant's balance and linked to the participation in
+// the same database transaction, so a completion is never recorded without its reward.
+// Returns false if the participant was already completed, so rewards are granted once.
+func (r *ChallengeRepository) MarkCompleted(participant *models.ChallengeParticipant, completedAt time.Time, reward *models.Transaction) (bool, error) {
+	completed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ChallengeParticipant{}).
+			Where("id = ? AND completed_at IS NULL", participant.ID).
+			Updates(map[string]interface{}{"percent": 100, "completed_at": completedAt})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.Challenge{}).
+			Where("id = ?", participant.ChallengeID).
+			Update("completed_count", gorm.Expr("completed_count + ?", 1)).Error; err != nil {
+			return err
+		}
+
+		if reward != nil {
+			if _, err := postTransaction(tx, reward); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.ChallengeParticipant{}).
+				Where("id = ?", participant.ID).
+				Update("reward_transaction_id", reward.ID).Error; err != nil {
+				return err
+			}
+		}
+		completed = true
+		return nil
+	})
+	return completed, err
+}
+
+// GetLeaderboard ranks a challenge's participants: completed first by completion time,
+// then by progress, earlier enrollment breaking ties
+func (r *ChallengeRepository) GetLeaderboard(challengeID uint, limit int) ([]models.ChallengeParticipant, error) {
+	var participants []models.ChallengeParticipant
+	err := r.db.Preload("User").
+		Where("challenge_id = ?", challengeID).
+		Order("completed_at IS NULL, completed_at ASC, percent DESC, created_at ASC").
+		Limit(limit).
+		Find(&participants).Error
+	return participants, err
+}
+
+// GetRank returns a participant's 1-based position in the challenge leaderboard
+func (r *ChallengeRepository) GetRank(participant *models.ChallengeParticipant) (int, error) {
+	var ahead int64
+	query := r.db.Model(&models.ChallengeParticipant{}).Where("challenge_id = ?", participant.ChallengeID)
+	if participant.CompletedAt != nil {
+		query = query.Where("completed_at IS NOT NULL AND (completed_at < ? OR (completed_at = ? AND created_at < ?))",
+			*participant.CompletedAt, *participant.CompletedAt, participant.CreatedAt)
+	} else {
+		query = query.Where("completed_at IS NOT NULL OR percent > ? OR (percent = ? AND created_at < ?)",
+			participant.Percent, participant.Percent, participant.CreatedAt)
+	}
+	if err := query.Count(&ahead).Error; err != nil {
+		return 0, err
+	}
+	return int(ahead) + 1, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+func expectParticipantCompleted(mock sqlmock.Sqlmock, participantID uint) {
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "challenge_participants" SET "completed_at"=$1,"percent"=$2`)).
+		WithArgs(sqlmock.AnyArg(), 100, sqlmock.AnyArg(), participantID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "challenges" SET "completed_count"=completed_count + $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestMarkCompletedPostsRewardInTheSameTransaction(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewChallengeRepository(db)
+
+	mock.ExpectBegin()
+	expectParticipantCompleted(mock, 4)
+	expectLockedUser(mock, 7, 10)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "credit_balance"=$1`)).
+		WithArgs(15.0, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectInsert(mock, "transactions", 9)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "challenge_participants" SET "reward_transaction_id"=$1`)).
+		WithArgs(9, sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	participant := &models.ChallengeParticipant{ID: 4, ChallengeID: 2, UserID: 7}
+	reward := &models.Transaction{UserID: 7, Type: models.TransactionBonus, Amount: 5}
+	completed, err := repo.MarkCompleted(participant, time.Now(), reward)
+	assert.NoError(t, err)
+	assert.True(t, completed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkCompletedRollsBackWhenTheRewardFails(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewChallengeRepository(db)
+
+	mock.ExpectBegin()
+	expectParticipantCompleted(mock, 4)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	participant := &models.ChallengeParticipant{ID: 4, ChallengeID: 2, UserID: 7}
+	reward := &models.Transaction{UserID: 7, Type: models.TransactionBonus, Amount: 5}
+	completed, err := repo.MarkCompleted(participant, time.Now(), reward)
+	assert.Error(t, err)
+	assert.False(t, completed, "the participant stays open so the next evaluation retries")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
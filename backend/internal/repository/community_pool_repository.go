@@ -0,0 +1,467 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// balanceCap is the maximum balance a posting may leave a user with (0 means uncapped)
+// Set once at startup with SetBalanceCap, before any request is served.
+var balanceCap float64
+
+// SetBalanceCap sets the balance cap applied by every ledger posting (0 disables it)
+func SetBalanceCap(max float64) {
+	balanceCap = max
+}
+
+// BalanceCapOverflow returns how many available credits are above max
+func BalanceCapOverflow(user *models.User, max float64) float64 {
+	overflow := math.Min(user.CreditBalance-max, user.AvailableCredits())
+	return math.Max(0, math.Round(overflow*100)/100)
+}
+
+// CommunityPoolRepository handles the community pool account and its ledger
+type CommunityPoolRepository struct {
+	db *gorm.DB
+}
+
+// NewCommunityPoolRepository creates a new community pool repository
+func NewCommunityPoolRepository(db *gorm.DB) *CommunityPoolRepository {
+	return &CommunityPoolRepository{db: db}
+}
+
+// GetPool returns the community pool account, creating it on first use
+func (r *CommunityPoolRepository) GetPool() (*models.CommunityPool, error) {
+	pool := models.CommunityPool{ID: models.CommunityPoolID}
+	if err := r.db.FirstOrCreate(&pool, models.CommunityPool{ID: models.CommunityPoolID}).Error; err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+// ApplyPolicyNotice atomically applies a pending credit policy notice by moving credits
+// from the user's balance into the pool.
+//
+// The notice is claimed first (pending -> applied, conditional on its current status),
+// so a notice is debited at most once even when several runs process it concurrently.
+// Returns utils.ErrNoticeNotPending when the notice was already applied or cancelled.
+//
+// amountFn is evaluated against the locked user row and returns how much to move,
+// so the amount is always computed from the current balance (never a stale read).
+// A non-positive amount means there is nothing to move: the notice is cancelled and
+// (nil, nil) is returned. A bonus expiry never moves more than the unspent part of its
+// bonus, replayed from the ledger under the same lock.
+//
+// Writes, in a single database transaction:
+//   - the notice status, applied_at and transaction_id
+//   - a negative user Transaction of txType (with balance snapshot)
+//   - the users.credit_balance decrement
+//   - a positive CommunityPoolEntry of entryType linked to that transaction
+//   - the community_pools balance and total_in increment
+func (r *CommunityPoolRepository) ApplyPolicyNotice(
+	notice *models.CreditPolicyNotice,
+	txType models.TransactionType,
+	entryType models.PoolEntryType,
+	description string,
+	metadata string,
+	amountFn func(user *models.User) float64,
+) (*models.Transaction, error) {
+	var transaction *models.Transaction
+	now := time.Now()
+	status := models.NoticeApplied
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		claim := tx.Model(&models.CreditPolicyNotice{}).
+			Where("id = ? AND status = ?", notice.ID, models.NoticePending).
+			Updates(map[string]interface{}{"status": models.NoticeApplied, "applied_at": now})
+		if claim.Error != nil {
+			return fmt.Errorf("claim notice: %w", claim.Error)
+		}
+		if claim.RowsAffected != 1 {
+			return utils.ErrNoticeNotPending
+		}
+
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&user, notice.UserID).Error; err != nil {
+			return fmt.Errorf("lock user: %w", err)
+		}
+
+		amount := amountFn(&user)
+		if notice.Kind == models.PolicyBonusExpiry && notice.ReferenceID != nil {
+			unused, err := unusedBonuses(tx, notice.UserID)
+			if err != nil {
+				return fmt.Errorf("replay bonus: %w", err)
+			}
+			amount = math.Min(amount, unused[*notice.ReferenceID])
+		}
+		if amount <= 0 {
+			status = models.NoticeCancelled
+			return tx.Model(&models.CreditPolicyNotice{}).
+				Where("id = ?", notice.ID).
+				Updates(map[string]interface{}{"status": models.NoticeCancelled, "applied_at": nil}).Error
+		}
+
+		var err error
+		transaction, err = depositLocked(tx, notice.UserID, amount, txType, entryType, description, metadata)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.CreditPolicyNotice{}).
+			Where("id = ?", notice.ID).
+			Update("transaction_id", transaction.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notice.Status = status
+	if transaction != nil {
+		notice.AppliedAt = &now
+		notice.TransactionID = &transaction.ID
+	}
+	return transaction, nil
+}
+
+// EnforceBalanceCap moves the user's available credits above the balance cap into the pool
+// Returns the overflow transaction, or nil when the balance is within the cap (or uncapped).
+func (r *CommunityPoolRepository) EnforceBalanceCap(userID uint) (*models.Transaction, error) {
+	if balanceCap <= 0 {
+		return nil, nil
+	}
+
+	var transaction *models.Transaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&user, userID).Error; err != nil {
+			return utils.ErrUserNotFound
+		}
+
+		var err error
+		transaction, err = applyBalanceCapLocked(tx, &user)
+		return err
+	})
+	return transaction, err
+}
+
+// Donate debits a donation from the donor's available credits into the pool and
+// stores the donation record linked to its ledger transaction, all in one database transaction
+func (r *CommunityPoolRepository) Donate(donation *models.Endorsement) error {
+	if donation.DonorID == nil {
+		return errors.New("donation requires a donor")
+	}
+	donorID := *donation.DonorID
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var donor models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&donor, donorID).Error; err != nil {
+			return utils.ErrUserNotFound
+		}
+
+		if donor.AvailableCredits() < donation.Amount {
+			return utils.ErrInsufficientCredits
+		}
+
+		metadata := fmt.Sprintf(`{"anonymous": %t}`, donation.IsAnonymous)
+		transaction, err := depositLocked(tx, donorID, donation.Amount,
+			models.TransactionDonation, models.PoolEntryDonation,
+			"Donation to the community pool", metadata)
+		if err != nil {
+			return err
+		}
+
+		donation.TransactionID = &transaction.ID
+		if err := tx.Create(donation).Error; err != nil {
+			return fmt.Errorf("create donation: %w", err)
+		}
+		return nil
+	})
+}
+
+// GrantToUser pays credits from the pool to a user
+//
+// grantedBy is the admin who approved the grant, nil for automatic rule grants.
+// cooldownSince, when set, is re-checked under the user's row lock: the grant is refused
+// with utils.ErrRecentGrant if the user received a grant since then, so concurrent runs
+// of the automatic grant job cannot grant a user twice.
+// Returns utils.ErrPoolInsufficient when the pool balance cannot cover the amount.
+func (r *CommunityPoolRepository) GrantToUser(
+	userID uint,
+	amount float64,
+	description string,
+	metadata string,
+	grantedBy *uint,
+	cooldownSince *time.Time,
+) (*models.Transaction, error) {
+	var transaction *models.Transaction
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the user before the pool, in the same order as deposits
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&user, userID).Error; err != nil {
+			return utils.ErrUserNotFound
+		}
+
+		if cooldownSince != nil {
+			var recent int64
+			if err := tx.Model(&models.CommunityPoolEntry{}).
+				Where("user_id = ? AND type = ? AND created_at >= ?", userID, models.PoolEntryGrant, *cooldownSince).
+				Count(&recent).Error; err != nil {
+				return fmt.Errorf("check recent grants: %w", err)
+			}
+			if recent > 0 {
+				return utils.ErrRecentGrant
+			}
+		}
+
+		pool, err := lockPool(tx)
+		if err != nil {
+			return err
+		}
+		if pool.Balance < amount {
+			return utils.ErrPoolInsufficient
+		}
+
+		transaction = &models.Transaction{
+			UserID:      userID,
+			Type:        models.TransactionGrant,
+			Amount:      amount,
+			Description: description,
+			Metadata:    metadata,
+		}
+		if _, err := postTransaction(tx, transaction); err != nil {
+			return fmt.Errorf("post user transaction: %w", err)
+		}
+
+		// Reload: a grant above the balance cap has already moved the overflow back
+		if pool, err = lockPool(tx); err != nil {
+			return err
+		}
+		pool.Balance -= amount
+		pool.TotalOut += amount
+		if err := tx.Save(pool).Error; err != nil {
+			return fmt.Errorf("update pool: %w", err)
+		}
+
+		entry := &models.CommunityPoolEntry{
+			Type:          models.PoolEntryGrant,
+			Amount:        -amount,
+			BalanceAfter:  pool.Balance,
+			Description:   description,
+			UserID:        &userID,
+			TransactionID: &transaction.ID,
+			GrantedBy:     grantedBy,
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return fmt.Errorf("create pool entry: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// GetTotalsByType returns the absolute pool volume per entry type
+func (r *CommunityPoolRepository) GetTotalsByType() (map[models.PoolEntryType]float64, error) {
+	var rows []struct {
+		Type  models.PoolEntryType
+		Total float64
+	}
+	if err := r.db.Model(&models.CommunityPoolEntry{}).
+		Select("type, COALESCE(SUM(ABS(amount)), 0) AS total").
+		Group("type").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[models.PoolEntryType]float64, len(rows))
+	for _, row := range rows {
+		totals[row.Type] = row.Total
+	}
+	return totals, nil
+}
+
+// CountDistinctUsers counts the distinct users involved in entries of the given type
+func (r *CommunityPoolRepository) CountDistinctUsers(entryType models.PoolEntryType) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CommunityPoolEntry{}).
+		Where("type = ?", entryType).
+		Distinct("user_id").
+		Count(&count).Error
+	return count, err
+}
+
+// FindAutoGrantCandidates returns active users whose available balance is below threshold,
+// who have at least one learning wish, and who received no grant since the given time
+func (r *CommunityPoolRepository) FindAutoGrantCandidates(threshold float64, since time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Model(&models.User{}).
+		Where("is_active = ?", true).
+		Where("credit_balance - credit_held - frozen_credits < ?", threshold).
+		Where(`EXISTS (
+			SELECT 1 FROM learning_skills ls
+			WHERE ls.user_id = users.id AND ls.deleted_at IS NULL
+		)`).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM community_pool_entries e
+			WHERE e.user_id = users.id AND e.type = ? AND e.created_at >= ?
+		)`, models.PoolEntryGrant, since).
+		Order("credit_balance - credit_held - frozen_credits ASC, id ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// GetEntries returns the pool ledger, newest first, optionally filtered by type
+func (r *CommunityPoolRepository) GetEntries(limit, offset int, entryType string) ([]models.CommunityPoolEntry, int64, error) {
+	var entries []models.CommunityPoolEntry
+	var total int64
+
+	query := r.db.Model(&models.CommunityPoolEntry{})
+	if entryType != "" {
+		query = query.Where("type = ?", entryType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+	return entries, total, err
+}
+
+// depositLocked moves amount from an already locked user row into the pool inside tx
+func depositLocked(
+	tx *gorm.DB,
+	userID uint,
+	amount float64,
+	txType models.TransactionType,
+	entryType models.PoolEntryType,
+	description string,
+	metadata string,
+) (*models.Transaction, error) {
+	transaction := &models.Transaction{
+		UserID:      userID,
+		Type:        txType,
+		Amount:      -amount,
+		Description: description,
+		Metadata:    metadata,
+	}
+	if _, err := postTransaction(tx, transaction); err != nil {
+		return nil, fmt.Errorf("post user transaction: %w", err)
+	}
+
+	pool, err := lockPool(tx)
+	if err != nil {
+		return nil, err
+	}
+	pool.Balance += amount
+	pool.TotalIn += amount
+	if err := tx.Save(pool).Error; err != nil {
+		return nil, fmt.Errorf("update pool: %w", err)
+	}
+
+	entry := &models.CommunityPoolEntry{
+		Type:          entryType,
+		Amount:        amount,
+		BalanceAfter:  pool.Balance,
+		Description:   description,
+		UserID:        &userID,
+		TransactionID: &transaction.ID,
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return nil, fmt.Errorf("create pool entry: %w", err)
+	}
+	return transaction, nil
+}
+
+// applyBalanceCapLocked moves the available credits of an already locked user row above
+// the balance cap into the pool inside tx, and records the applied notice and the in-app
+// notification with it. user.CreditBalance is updated; nil is returned when nothing is over the cap.
+func applyBalanceCapLocked(tx *gorm.DB, user *models.User) (*models.Transaction, error) {
+	if balanceCap <= 0 {
+		return nil, nil
+	}
+	overflow := BalanceCapOverflow(user, balanceCap)
+	if overflow <= 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	notice := &models.CreditPolicyNotice{
+		UserID:    user.ID,
+		Kind:      models.PolicyBalanceCap,
+		Status:    models.NoticeApplied,
+		Amount:    overflow,
+		DueAt:     now,
+		AppliedAt: &now,
+	}
+	if err := tx.Create(notice).Error; err != nil {
+		return nil, fmt.Errorf("create cap notice: %w", err)
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"policy":    notice.Kind,
+		"notice_id": notice.ID,
+	})
+	transaction, err := depositLocked(tx, user.ID, overflow,
+		models.TransactionOverflow, models.PoolEntryOverflow,
+		fmt.Sprintf("Balance above %.1f credit cap shared with the community pool", balanceCap),
+		string(metadata))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Model(notice).Update("transaction_id", transaction.ID).Error; err != nil {
+		return nil, fmt.Errorf("link cap notice: %w", err)
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"notice_id": notice.ID,
+		"policy":    notice.Kind,
+		"amount":    overflow,
+		"due_at":    now,
+	})
+	if err := tx.Create(&models.Notification{
+		UserID:  user.ID,
+		Type:    models.NotificationTypeCredit,
+		Title:   "Balance Cap Reached",
+		Message: fmt.Sprintf("Your balance is above the %.1f credit cap. %.1f credits were shared with the community pool.", balanceCap, overflow),
+		Data:    data,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("create cap notification: %w", err)
+	}
+
+	user.CreditBalance = transaction.BalanceAfter
+	return transaction, nil
+}
+
+// lockPool ensures the pool row exists and locks it for update inside tx
+func lockPool(tx *gorm.DB) (*models.CommunityPool, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.CommunityPool{ID: models.CommunityPoolID}).Error; err != nil {
+		return nil, fmt.Errorf("ensure pool: %w", err)
+	}
+
+	var pool models.CommunityPool
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&pool, models.CommunityPoolID).Error; err != nil {
+		return nil, fmt.Errorf("lock pool: %w", err)
+	}
+	return &pool, nil
+}
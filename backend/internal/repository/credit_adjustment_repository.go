@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreditAdjustmentRepository handles database operations for admin credit adjustments
+type CreditAdjustmentRepository struct {
+	db *gorm.DB
+}
+
+// NewCreditAdjustmentRepository creates a new credit adjustment repository
+func NewCreditAdjustmentRepository(db *gorm.DB) *CreditAdjustmentRepository {
+	return &CreditAdjustmentRepository{db: db}
+}
+
+// Create creates a new (pending) credit adjustment
+func (r *CreditAdjustmentRepository) Create(adjustment *models.CreditAdjustment) error {
+	return r.db.Create(adjustment).Error
+}
+
+// GetByID finds a credit adjustment by ID with the user and both admins
+func (r *CreditAdjustmentRepository) GetByID(id uint) (*models.CreditAdjustment, error) {
+	var adjustment models.CreditAdjustment
+	err := r.db.Preload("User").Preload("Requester").Preload("Reviewer").
+		First(&adjustment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+// GetAll returns adjustments with optional status and user filters, newest first
+func (r *CreditAdjustmentRepository) GetAll(status string, userID uint, limit, offset int) ([]models.CreditAdjustment, int64, error) {
+	var adjustments []models.CreditAdjustment
+	var total int64
+
+	query := r.db.Model(&models.CreditAdjustment{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Preload("Requester").Preload("Reviewer").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&adjustments).Error
+	return adjustments, total, err
+}
+
+// Reject marks a pending adjustment as rejected. Nothing is posted to the ledger.
+// Returns utils.ErrAdjustmentNotPending if it was already reviewed.
+func (r *CreditAdjustmentRepository) Reject(id, reviewerID uint, note string) error {
+	result := r.db.Model(&models.CreditAdjustment{}).
+		Where("id = ? AND status = ?", id, models.CreditAdjustmentPending).
+		Updates(map[string]interface{}{
+			"status":      models.CreditAdjustmentRejected,
+			"reviewed_by": reviewerID,
+			"reviewed_at": time.Now(),
+			"review_note": note,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrAdjustmentNotPending
+	}
+	return nil
+}
+
+// Approve marks a pending adjustment as approved and posts its ledger transaction,
+// in one database transaction.
+// A debit may not take the balance below the user's escrowed and frozen credits.
+// Returns utils.ErrAdjustmentNotPending or utils.ErrInsufficientCredits on failure.
+func (r *CreditAdjustmentRepository) Approve(id, reviewerID uint, note, description, metadata string) (*models.Transaction, error) {
+	var transaction *models.Transaction
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var adjustment models.CreditAdjustment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&adjustment, id).Error; err != nil {
+			return err
+		}
+		if !adjustment.IsPending() {
+			return utils.ErrAdjustmentNotPending
+		}
+
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&user, adjustment.UserID).Error; err != nil {
+			return err
+		}
+		if adjustment.Amount < 0 && user.AvailableCredits()+adjustment.Amount < 0 {
+			return utils.ErrInsufficientCredits
+		}
+
+		transaction = &models.Transaction{
+			UserID:      adjustment.UserID,
+			Type:        models.TransactionAdjustment,
+			Amount:      adjustment.Amount,
+			Description: description,
+			Metadata:    metadata,
+		}
+		if _, err := postTransaction(tx, transaction); err != nil {
+			return err
+		}
+
+		return tx.Model(&adjustment).Updates(map[string]interface{}{
+			"status":         models.CreditAdjustmentApproved,
+			"reviewed_by":    reviewerID,
+			"reviewed_at":    time.Now(),
+			"review_note":    note,
+			"transaction_id": transaction.ID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
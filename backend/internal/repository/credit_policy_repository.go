@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// policyTransactionTypes are ledger entries written by the credit policy itself;
+// they never count as user activity
+var policyTransactionTypes = []models.TransactionType{
+	models.TransactionOverflow,
+	models.TransactionDecay,
+	models.TransactionExpiry,
+}
+
+// CreditPolicyRepository handles credit policy notices and candidate lookups
+type CreditPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewCreditPolicyRepository creates a new credit policy repository
+func NewCreditPolicyRepository(db *gorm.DB) *CreditPolicyRepository {
+	return &CreditPolicyRepository{db: db}
+}
+
+// CreateNotice creates a new policy notice
+func (r *CreditPolicyRepository) CreateNotice(notice *models.CreditPolicyNotice) error {
+	return r.db.Create(notice).Error
+}
+
+// CancelNotice marks a pending policy notice as cancelled
+// Notices that were already applied or cancelled are left untouched
+func (r *CreditPolicyRepository) CancelNotice(id uint) error {
+	return r.db.Model(&models.CreditPolicyNotice{}).
+		Where("id = ? AND status = ?", id, models.NoticePending).
+		Update("status", models.NoticeCancelled).Error
+}
+
+// HasRecentNotice checks if a user has a pending notice of the given kind,
+// or one that was applied since the given time
+func (r *CreditPolicyRepository) HasRecentNotice(userID uint, kind models.CreditPolicyKind, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.CreditPolicyNotice{}).
+		Where("user_id = ? AND kind = ?", userID, kind).
+		Where("status = ? OR (status = ? AND applied_at >= ?)", models.NoticePending, models.NoticeApplied, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetDueNotices returns pending notices whose due date has passed
+func (r *CreditPolicyRepository) GetDueNotices(now time.Time) ([]models.CreditPolicyNotice, error) {
+	var notices []models.CreditPolicyNotice
+	err := r.db.Where("status = ? AND due_at <= ?", models.NoticePending, now).
+		Order("due_at ASC").
+		Find(&notices).Error
+	return notices, err
+}
+
+// GetUserNotices returns a user's notices, newest first
+func (r *CreditPolicyRepository) GetUserNotices(userID uint, limit int) ([]models.CreditPolicyNotice, error) {
+	var notices []models.CreditPolicyNotice
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&notices).Error
+	return notices, err
+}
+
+// FindInactiveUsers returns active accounts holding more than floor available credits
+// with no ledger or session activity since cutoff
+func (r *CreditPolicyRepository) FindInactiveUsers(cutoff time.Time, floor float64) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Model(&models.User{}).
+		Where("is_active = ? AND created_at < ?", true, cutoff).
+		Where("credit_balance - credit_held - frozen_credits > ?", floor).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM transactions t
+			WHERE t.user_id = users.id AND t.created_at >= ? AND t.type NOT IN ? AND t.deleted_at IS NULL
+		)`, cutoff, policyTransactionTypes).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM sessions s
+			WHERE (s.teacher_id = users.id OR s.student_id = users.id) AND s.updated_at >= ?
+		)`, cutoff).
+		Find(&users).Error
+	return users, err
+}
+
+// HasActivitySince checks if a user made any non-policy transaction or touched a session since the given time
+func (r *CreditPolicyRepository) HasActivitySince(userID uint, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Transaction{}).
+		Where("user_id = ? AND created_at >= ? AND type NOT IN ?", userID, since, policyTransactionTypes).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = r.db.Model(&models.Session{}).
+		Where("(teacher_id = ? OR student_id = ?) AND updated_at >= ?", userID, userID, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindExpiringBonuses returns expirable bonus transactions created before cutoff
+// that have not been announced for expiry yet (badge bonuses clawed back on revocation are skipped)
+func (r *CreditPolicyRepository) FindExpiringBonuses(cutoff time.Time) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Model(&models.Transaction{}).
+		Where("type = ? AND amount > 0 AND created_at < ?", models.TransactionBonus, cutoff).
+		Where("metadata->>'expirable' = 'true'").
+		Where(`NOT EXISTS (
+			SELECT 1 FROM credit_policy_notices n
+			WHERE n.kind = ? AND n.reference_id = transactions.id
+		)`, models.PolicyBonusExpiry).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM badge_revocations br WHERE br.bonus_transaction_id = transactions.id
+		)`).
+		Order("created_at ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// GetUnusedBonuses returns the unspent part of each expirable bonus of a user, by bonus
+// transaction ID (fully spent bonuses are left out)
+func (r *CreditPolicyRepository) GetUnusedBonuses(userID uint) (map[uint]float64, error) {
+	return unusedBonuses(r.db, userID)
+}
+
+// unusedBonuses replays a user's ledger to find what is left of each expirable bonus
+func unusedBonuses(tx *gorm.DB, userID uint) (map[uint]float64, error) {
+	var ledger []models.Transaction
+	err := tx.Model(&models.Transaction{}).
+		Select("id", "type", "amount", "balance_before", "balance_after", "metadata").
+		Where("user_id = ?", userID).
+		Order("created_at ASC, id ASC").
+		Find(&ledger).Error
+	if err != nil {
+		return nil, err
+	}
+	return replayBonusLots(ledger), nil
+}
+
+// bonusLot is what is left of one expirable bonus while replaying a ledger
+type bonusLot struct {
+	id        uint
+	remaining float64
+}
+
+// replayBonusLots walks a ledger in order and tracks how much of each expirable bonus
+// is still unspent
+// Bonus credits are spent first, oldest bonus first, by every entry that lowers the
+// balance; credits received later never refill a bonus. An expiry or a badge clawback
+// consumes the bonus it refers to.
+func replayBonusLots(ledger []models.Transaction) map[uint]float64 {
+	var lots []*bonusLot
+	byID := make(map[uint]*bonusLot)
+
+	for i := range ledger {
+		t := &ledger[i]
+		var metadata struct {
+			Expirable          bool  `json:"expirable"`
+			ReferenceID        *uint `json:"reference_id"`         // Bonus expired by a policy notice
+			BonusTransactionID *uint `json:"bonus_transaction_id"` // Bonus clawed back with a badge
+		}
+		_ = json.Unmarshal([]byte(t.Metadata), &metadata)
+
+		if t.Type == models.TransactionBonus && t.Amount > 0 && metadata.Expirable {
+			lot := &bonusLot{id: t.ID, remaining: t.Amount}
+			lots = append(lots, lot)
+			byID[t.ID] = lot
+			continue
+		}
+
+		debit := t.BalanceBefore - t.BalanceAfter
+		if debit <= 0 {
+			continue
+		}
+
+		target := metadata.ReferenceID
+		if t.Type == models.TransactionClawback {
+			target = metadata.BonusTransactionID
+		}
+		if t.Type == models.TransactionExpiry || t.Type == models.TransactionClawback {
+			if target != nil {
+				if lot, ok := byID[*target]; ok {
+					lot.remaining = math.Max(0, lot.remaining-debit)
+				}
+			}
+			continue
+		}
+
+		for _, lot := range lots {
+			if debit <= 0 {
+				break
+			}
+			used := math.Min(lot.remaining, debit)
+			lot.remaining -= used
+			debit -= used
+		}
+	}
+
+	unused := make(map[uint]float64)
+	for _, lot := range lots {
+		if remaining := math.Round(lot.remaining*100) / 100; remaining > 0 {
+			unused[lot.id] = remaining
+		}
+	}
+	return unused
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+// ledgerEntry builds a ledger transaction moving the balance from before by amount
+func ledgerEntry(id uint, txType models.TransactionType, before, amount float64, metadata string) models.Transaction {
+	return models.Transaction{
+		ID:            id,
+		Type:          txType,
+		Amount:        amount,
+		BalanceBefore: before,
+		BalanceAfter:  before + amount,
+		Metadata:      metadata,
+	}
+}
+
+func TestReplayBonusLots(t *testing.T) {
+	const expirable = `{"expirable": true}`
+
+	tests := []struct {
+		name   string
+		ledger []models.Transaction
+		unused map[uint]float64
+	}{
+		{
+			name: "untouched bonus",
+			ledger: []models.Transaction{
+				ledgerEntry(1, models.TransactionInitial, 0, 3, ""),
+				ledgerEntry(2, models.TransactionBonus, 3, 5, expirable),
+			},
+			unused: map[uint]float64{2: 5},
+		},
+		{
+			name: "spent bonus stays spent when credits are earned later",
+			ledger: []models.Transaction{
+				ledgerEntry(1, models.TransactionBonus, 0, 5, expirable),
+				ledgerEntry(2, models.TransactionSpent, 5, -5, ""),
+				ledgerEntry(3, models.TransactionEarned, 0, 10, ""),
+			},
+			unused: map[uint]float64{},
+		},
+		{
+			name: "partly spent bonus",
+			ledger: []models.Transaction{
+				ledgerEntry(1, models.TransactionBonus, 0, 5, expirable),
+				ledgerEntry(2, models.TransactionSpent, 5, -2, ""),
+			},
+			unused: map[uint]float64{1: 3},
+		},
+		{
+			name: "oldest bonus is spent first",
+			ledger: []models.Transaction{
+				ledgerEntry(1, models.TransactionBonus, 0, 2, expirable),
+				ledgerEntry(2, models.TransactionBonus, 2, 4, expirable),
+				ledgerEntry(3, models.TransactionSpent, 6, -3, ""),
+			},
+			unused: map[uint]float64{2: 3},
+		},
+		{
+			name: "holds do not spend the bonus",
+			ledger: []models.Transaction{
+				ledgerEntry(1, models.TransactionBonus, 0, 5, expirable),
+				{ID: 2, Type: models.TransactionHold, Amount: 5, BalanceBefore: 5, BalanceAfter: 5},
+			},
+			unused: map[uint]float64{1: 5},
+		},
+		{
+			name: "bonuses that cannot expire are ignored",
+			ledger: []models.Transaction{
+				ledgerEntry(1, models.TransactionBonus, 0, 5, `{}`),
+				ledgerEntry(2, models.TransactionBonus, 5, 5, expirable),
+				ledgerEntry(3, models.TransactionSpent, 10, -1, ""),
+			},
+			unused: map[uint]float64{2: 4},
+		},
+		{
+			name: "expiry and clawback consume the bonus they refer to",
+			ledger: []models.Transaction{
+				ledgerEntry(1, models.TransactionBonus, 0, 5, expirable),
+				ledgerEntry(2, models.TransactionBonus, 5, 4, expirable),
+				ledgerEntry(3, models.TransactionExpiry, 9, -2, `{"reference_id": 2}`),
+				ledgerEntry(4, models.TransactionClawback, 7, -5, `{"bonus_transaction_id": 1}`),
+			},
+			unused: map[uint]float64{2: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.unused, replayBonusLots(tt.ledger))
+		})
+	}
+}
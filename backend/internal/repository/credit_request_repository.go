@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreditRequestRepository handles database operations for credit requests
+type CreditRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewCreditRequestRepository creates a new credit request repository
+func NewCreditRequestRepository(db *gorm.DB) *CreditRequestRepository {
+	return &CreditRequestRepository{db: db}
+}
+
+// Create creates a new credit request without saving its Requester and Payer
+func (r *CreditRequestRepository) Create(request *models.CreditRequest) error {
+	return r.db.Omit(clause.Associations).Create(request).Error
+}
+
+// GetByID finds a credit request by ID with both parties
+func (r *CreditRequestRepository) GetByID(id uint) (*models.CreditRequest, error) {
+	var request models.CreditRequest
+	err := r.db.Preload("Requester").Preload("Payer").First(&request, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetIncoming returns requests where the user is asked to pay, newest first
+func (r *CreditRequestRepository) GetIncoming(payerID uint, status string, limit, offset int) ([]models.CreditRequest, int64, error) {
+	return r.list(r.db.Where("payer_id = ?", payerID), status, limit, offset)
+}
+
+// GetOutgoing returns requests created by the user, newest first
+func (r *CreditRequestRepository) GetOutgoing(requesterID uint, status string, limit, offset int) ([]models.CreditRequest, int64, error) {
+	return r.list(r.db.Where("requester_id = ?", requesterID), status, limit, offset)
+}
+
+// list applies status filter and pagination to a scoped query
+func (r *CreditRequestRepository) list(scope *gorm.DB, status string, limit, offset int) ([]models.CreditRequest, int64, error) {
+	var requests []models.CreditRequest
+	var total int64
+
+	query := scope.Model(&models.CreditRequest{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Requester").Preload("Payer").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&requests).Error
+	return requests, total, err
+}
+
+// CountPendingOutgoing counts a requester's pending requests
+func (r *CreditRequestRepository) CountPendingOutgoing(requesterID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CreditRequest{}).
+		Where("requester_id = ? AND status = ?", requesterID, models.CreditRequestPending).
+		Count(&count).Error
+	return count, err
+}
+
+// CountCreatedSince counts requests created by a requester since the given time
+// When payerID is non-zero only requests to that payer are counted
+func (r *CreditRequestRepository) CountCreatedSince(requesterID, payerID uint, since time.Time) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.CreditRequest{}).
+		Where("requester_id = ? AND created_at >= ?", requesterID, since)
+	if payerID != 0 {
+		query = query.Where("payer_id = ?", payerID)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// HasDeclinedSince checks if the payer declined a request from the requester since the given time
+func (r *CreditRequestRepository) HasDeclinedSince(requesterID, payerID uint, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.CreditRequest{}).
+		Where("requester_id = ? AND payer_id = ? AND status = ? AND responded_at >= ?",
+			requesterID, payerID, models.CreditRequestDeclined, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ExpireOverdue marks pending requests past their expiry time as expired
+// and returns the requests that were expired by this call
+func (r *CreditRequestRepository) ExpireOverdue(now time.Time) ([]models.CreditRequest, error) {
+	var expired []models.CreditRequest
+	err := r.db.Clauses(clause.Returning{}).
+		Model(&expired).
+		Where("status = ? AND expires_at < ?", models.CreditRequestPending, now).
+		Update("status", models.CreditRequestExpired).Error
+	return expired, err
+}
+
+// Resolve moves a pending request to a final status without moving credits
+// (declined or cancelled). Returns utils.ErrCreditRequestNotPending if it was already resolved.
+func (r *CreditRequestRepository) Resolve(id uint, status models.CreditRequestStatus, reason string) error {
+	now := time.Now()
+	result := r.db.Model(&models.CreditRequest{}).
+		Where("id = ? AND status = ? AND expires_at >= ?", id, models.CreditRequestPending, now).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"responded_at":   now,
+			"decline_reason": reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrCreditRequestNotPending
+	}
+	return nil
+}
+
+// Accept marks a pending request as accepted and transfers the credits from payer
+// to requester, in one database transaction.
+// Returns utils.ErrCreditRequestNotPending or utils.ErrInsufficientCredits on failure.
+func (r *CreditRequestRepository) Accept(id uint, payerDescription, requesterDescription string) (*models.Transaction, error) {
+	var debit *models.Transaction
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var request models.CreditRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&request, id).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if request.Status != models.CreditRequestPending || request.IsExpired(now) {
+			return utils.ErrCreditRequestNotPending
+		}
+
+		metadata := fmt.Sprintf(`{"credit_request_id": %d}`, request.ID)
+		var err error
+		debit, _, err = transferLocked(tx, request.PayerID, request.RequesterID, request.Amount,
+			payerDescription, requesterDescription, metadata)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&request).Updates(map[string]interface{}{
+			"status":         models.CreditRequestAccepted,
+			"responded_at":   now,
+			"transaction_id": debit.ID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return debit, nil
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// DigestRecipient is a user due a weekly digest
+type DigestRecipient struct {
+	UserID           uint
+	Email            string
+	FullName         string
+	CreditBalance    float64
+	LastDigestSentAt *time.Time
+}
+
+// DigestSessionRow is an upcoming session of the digest's user
+type DigestSessionRow struct {
+	ID          uint
+	Title       string
+	ScheduledAt time.Time
+	Duration    float64
+	Mode        models.SessionMode
+	Role        string // "teacher" or "student": the digest user's role
+	PartnerName string
+}
+
+// DigestRequestRow is a session request waiting for the digest user's approval
+type DigestRequestRow struct {
+	ID          uint
+	Title       string
+	StudentName string
+	ScheduledAt *time.Time
+	CreatedAt   time.Time
+}
+
+// DigestTeacherRow is a teacher who recently started teaching a skill on the digest
+// user's wishlist
+type DigestTeacherRow struct {
+	UserSkillID uint
+	SkillName   string
+	TeacherID   uint
+	TeacherName string
+	Level       models.SkillLevel
+}
+
+// DigestThreadRow counts new replies in a thread the digest user authored
+type DigestThreadRow struct {
+	ThreadID    uint
+	Title       string
+	NewReplies  int
+	LastReplyAt time.Time
+}
+
+// DigestBadgeRow is an unearned badge the digest user has made progress toward
+type DigestBadgeRow struct {
+	BadgeID uint
+	Name    string
+	Icon    string
+	Percent int
+}
+
+// DigestRepositoryInterface defines the queries behind the weekly digest
+type DigestRepositoryInterface interface {
+	GetDigestRecipients(weekday time.Weekday, sentBefore, failedBefore time.Time, limit int) ([]DigestRecipient, error)
+	GetDigestRecipient(userID uint) (*DigestRecipient, error)
+	ClaimDigest(userID uint, now, sentBefore time.Time) (bool, error)
+	ReleaseDigest(userID uint, lastSentAt *time.Time, failedAt time.Time) error
+	SetWeeklyDigest(userID uint, enabled bool) error
+	GetUpcomingSessions(userID uint, from, to time.Time, limit int) ([]DigestSessionRow, error)
+	GetPendingRequests(teacherID uint, limit int) ([]DigestRequestRow, error)
+	GetNewWishlistTeachers(userID uint, since time.Time, limit int) ([]DigestTeacherRow, error)
+	GetThreadReplies(authorID uint, since time.Time, limit int) ([]DigestThreadRow, error)
+	GetBalanceChanges(userID uint, since time.Time) ([]models.Transaction, error)
+	GetNearlyEarnedBadges(userID uint, minPercent, limit int) ([]DigestBadgeRow, error)
+}
+
+// DigestRepository runs the queries behind the weekly digest
+type DigestRepository struct {
+	db *gorm.DB
+}
+
+// NewDigestRepository creates a new digest repository
+func NewDigestRepository(db *gorm.DB) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+// GetDigestRecipients gets active, verified users with email and the digest on, whose
+// digest weekday is weekday and who were last sent a digest before sentBefore (or never)
+// Users whose last digest failed after failedBefore are left out, so addresses that keep
+// failing do not take up every batch. Users without a preferences row get the defaults:
+// digest on, sent on Monday.
+func (r *DigestRepository) GetDigestRecipients(weekday time.Weekday, sentBefore, failedBefore time.Time, limit int) ([]DigestRecipient, error) {
+	var recipients []DigestRecipient
+	err := r.db.Raw(`
+		SELECT u.id AS user_id, u.email, u.full_name, u.credit_balance, p.last_digest_sent_at
+		FROM users u
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE u.deleted_at IS NULL AND u.is_active AND u.is_verified
+			AND COALESCE(p.email_notifications, TRUE) AND COALESCE(p.weekly_digest, TRUE)
+			AND COALESCE(p.digest_weekday, 1) = ?
+			AND (p.last_digest_sent_at IS NULL OR p.last_digest_sent_at < ?)
+			AND (p.last_digest_attempt_at IS NULL OR p.last_digest_attempt_at < ?)
+		ORDER BY u.id
+		LIMIT ?
+	`, int(weekday), sentBefore, failedBefore, limit).Scan(&recipients).Error
+	return recipients, err
+}
+
+// GetDigestRecipient gets a user to build a digest for, whatever their digest settings
+func (r *DigestRepository) GetDigestRecipient(userID uint) (*DigestRecipient, error) {
+	var recipient DigestRecipient
+	result := r.db.Raw(`
+		SELECT u.id AS user_id, u.email, u.full_name, u.credit_balance, p.last_digest_sent_at
+		FROM users u
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE u.id = ? AND u.deleted_at IS NULL
+	`, userID).Scan(&recipient)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &recipient, nil
+}
+
+// ClaimDigest records that a digest is being sent to a user at now, unless one was
+// already sent since sentBefore
+// Claiming before sending keeps two instances from emailing the same digest.
+//
+// Returns:
+//   - bool: Whether the digest was claimed
+//   - error: If database error
+func (r *DigestRepository) ClaimDigest(userID uint, now, sentBefore time.Time) (bool, error) {
+	var pref models.NotificationPreference
+	if err := r.db.Where(models.NotificationPreference{UserID: userID}).FirstOrCreate(&pref).Error; err != nil {
+		return false, err
+	}
+	result := r.db.Model(&models.NotificationPreference{}).
+		Where("user_id = ? AND (last_digest_sent_at IS NULL OR last_digest_sent_at < ?)", userID, sentBefore).
+		UpdateColumn("last_digest_sent_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReleaseDigest restores the last digest time of a claimed digest that could not be sent
+// and records the failed attempt, which holds the user back from the next runs for a while
+func (r *DigestRepository) ReleaseDigest(userID uint, lastSentAt *time.Time, failedAt time.Time) error {
+	return r.db.Model(&models.NotificationPreference{}).
+		Where("user_id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"last_digest_sent_at":    lastSentAt,
+			"last_digest_attempt_at": failedAt,
+		}).Error
+}
+
+// SetWeeklyDigest turns a user's weekly digest on or off
+func (r *DigestRepository) SetWeeklyDigest(userID uint, enabled bool) error {
+	var pref models.NotificationPreference
+	if err := r.db.Where(models.NotificationPreference{UserID: userID}).FirstOrCreate(&pref).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&pref).UpdateColumn("weekly_digest", enabled).Error
+}
+
+// GetUpcomingSessions gets the user's approved sessions scheduled between from and to,
+// as teacher or student, soonest first
+func (r *DigestRepository) GetUpcomingSessions(userID uint, from, to time.Time, limit int) ([]DigestSessionRow, error) {
+	var rows []DigestSessionRow
+	err := r.db.Raw(`
+		SELECT s.id, s.title, s.scheduled_at, s.duration, s.mode,
+			CASE WHEN s.teacher_id = @user THEN 'teacher' ELSE 'student' END AS role,
+			partner.full_name AS partner_name
+		FROM sessions s
+		JOIN users partner ON partner.id = CASE WHEN s.teacher_id = @user THEN s.student_id ELSE s.teacher_id END
+		WHERE s.deleted_at IS NULL AND (s.teacher_id = @user OR s.student_id = @user)
+			AND s.status = @status AND s.scheduled_at >= @from AND s.scheduled_at < @to
+		ORDER BY s.scheduled_at
+		LIMIT @limit
+	`, map[string]interface{}{
+		"user":   userID,
+		"status": models.StatusApproved,
+		"from":   from,
+		"to":     to,
+		"limit":  limit,
+	}).Scan(&rows).Error
+	return rows, err
+}
+
+// GetPendingRequests gets the session requests waiting for the teacher's approval, oldest first
+func (r *DigestRepository) GetPendingRequests(teacherID uint, limit int) ([]DigestRequestRow, error) {
+	var rows []DigestRequestRow
+	err := r.db.Raw(`
+		SELECT s.id, s.title, student.full_name AS student_name, s.scheduled_at, s.created_at
+		FROM sessions s
+		JOIN users student ON student.id = s.student_id
+		WHERE s.deleted_at IS NULL AND s.teacher_id = ? AND s.status = ?
+		ORDER BY s.created_at
+		LIMIT ?
+	`, teacherID, models.StatusPending, limit).Scan(&rows).Error
+	return rows, err
+}
+
+// GetNewWishlistTeachers gets the available teachers who started teaching a skill on the
+// user's LearningSkill wishlist since the given time, newest first
+func (r *DigestRepository) GetNewWishlistTeachers(userID uint, since time.Time, limit int) ([]DigestTeacherRow, error) {
+	var rows []DigestTeacherRow
+	err := r.db.Raw(`
+		SELECT us.id AS user_skill_id, sk.name AS skill_name, teacher.id AS teacher_id,
+			teacher.full_name AS teacher_name, us.level
+		FROM user_skills us
+		JOIN learning_skills ls ON ls.skill_id = us.skill_id AND ls.user_id = @user AND ls.deleted_at IS NULL
+		JOIN skills sk ON sk.id = us.skill_id AND sk.deleted_at IS NULL
+		JOIN users teacher ON teacher.id = us.user_id AND teacher.deleted_at IS NULL AND teacher.is_active
+		WHERE us.deleted_at IS NULL AND us.is_available AND us.user_id <> @user AND us.created_at >= @since
+		ORDER BY us.created_at DESC
+		LIMIT @limit
+	`, map[string]interface{}{
+		"user":  userID,
+		"since": since,
+		"limit": limit,
+	}).Scan(&rows).Error
+	return rows, err
+}
+
+// GetThreadReplies counts the replies by others since the given time in threads the
+// user authored, most recently active thread first
+func (r *DigestRepository) GetThreadReplies(authorID uint, since time.Time, limit int) ([]DigestThreadRow, error) {
+	var rows []DigestThreadRow
+	err := r.db.Raw(`
+		SELECT t.id AS thread_id, t.title, COUNT(*) AS new_replies, MAX(fr.created_at) AS last_reply_at
+		FROM forum_replies fr
+		JOIN forum_threads t ON t.id = fr.thread_id
+		WHERE t.author_id = ? AND fr.author_id <> t.author_id AND fr.created_at >= ?
+		GROUP BY t.id, t.title
+		ORDER BY last_reply_at DESC
+		LIMIT ?
+	`, authorID, since, limit).Scan(&rows).Error
+	return rows, err
+}
+
+// GetBalanceChanges gets the user's ledger lines since the given time, oldest first,
+// with only their type, amount and balances loaded
+func (r *DigestRepository) GetBalanceChanges(userID uint, since time.Time) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Select("id", "type", "amount", "balance_before", "balance_after").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Order("created_at ASC, id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// GetNearlyEarnedBadges gets the active badges the user has at least minPercent progress
+// toward but has not earned, closest to completion first
+// Permanently revoked badges are left out, as they cannot be earned again.
+func (r *DigestRepository) GetNearlyEarnedBadges(userID uint, minPercent, limit int) ([]DigestBadgeRow, error) {
+	var rows []DigestBadgeRow
+	err := r.db.Raw(`
+		SELECT b.id AS badge_id, b.name, b.icon, bp.percent
+		FROM badge_progress bp
+		JOIN badges b ON b.id = bp.badge_id AND b.deleted_at IS NULL AND b.is_active
+		WHERE bp.user_id = @user AND bp.percent >= @min AND bp.percent < 100
+			AND NOT EXISTS (
+				SELECT 1 FROM user_badges ub
+				WHERE ub.user_id = bp.user_id AND ub.badge_id = bp.badge_id AND ub.deleted_at IS NULL
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM badge_revocations rv
+				WHERE rv.user_id = bp.user_id AND rv.badge_id = bp.badge_id AND rv.permanent
+			)
+		ORDER BY bp.percent DESC, b.id
+		LIMIT @limit
+	`, map[string]interface{}{
+		"user":  userID,
+		"min":   minPercent,
+		"limit": limit,
+	}).Scan(&rows).Error
+	return rows, err
+}
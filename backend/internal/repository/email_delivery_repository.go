@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailDeliveryRepositoryInterface defines the contract for the notification email outbox
+type EmailDeliveryRepositoryInterface interface {
+	ClaimDueEmailDeliveries(now time.Time, lease time.Duration, limit int) ([]models.EmailDelivery, error)
+	UpdateEmailDelivery(delivery *models.EmailDelivery) error
+	GetPreferences(userID uint) (*models.NotificationPreference, error)
+}
+
+// CreateEmailDelivery queues a notification for the email channel
+func (r *NotificationRepository) CreateEmailDelivery(delivery *models.EmailDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// ClaimDueEmailDeliveries gets pending deliveries due by now, oldest first, with their
+// notification and user, and leases them by moving SendAfter to now+lease
+// Rows locked by another dispatcher are skipped, and a dispatcher that dies mid-batch
+// leaves its deliveries to be retried once the lease runs out. The returned deliveries
+// keep their SendAfter from before the lease.
+func (r *NotificationRepository) ClaimDueEmailDeliveries(now time.Time, lease time.Duration, limit int) ([]models.EmailDelivery, error) {
+	var deliveries []models.EmailDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ? AND send_after <= ?", models.EmailDeliveryPending, now).
+			Order("send_after ASC, id ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&models.EmailDelivery{}).
+			Where("id IN ?", ids).
+			UpdateColumn("send_after", now.Add(lease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return deliveries, err
+	}
+
+	// Load relationships outside the locking query
+	ids := make([]uint, len(deliveries))
+	for i, delivery := range deliveries {
+		ids[i] = delivery.ID
+	}
+	var loaded []models.EmailDelivery
+	if err := r.db.Preload("Notification").Preload("User").
+		Where("id IN ?", ids).
+		Find(&loaded).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.EmailDelivery, len(loaded))
+	for _, delivery := range loaded {
+		byID[delivery.ID] = delivery
+	}
+	for i := range deliveries {
+		if delivery, ok := byID[deliveries[i].ID]; ok {
+			delivery.SendAfter = deliveries[i].SendAfter
+			deliveries[i] = delivery
+		}
+	}
+	return deliveries, nil
+}
+
+// UpdateEmailDelivery saves the outcome of a delivery attempt
+func (r *NotificationRepository) UpdateEmailDelivery(delivery *models.EmailDelivery) error {
+	return r.db.Model(delivery).Select("status", "send_after", "attempts", "last_error", "sent_at").Updates(delivery).Error
+}
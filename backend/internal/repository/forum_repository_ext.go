@@ -29,3 +29,12 @@ func (r *ForumRepository) GetAllThreads(limit, offset int, search string) ([]mod
 
 	return threads, total, nil
 }
+
+// GetReplyByID gets a forum reply by ID
+func (r *ForumRepository) GetReplyByID(replyID uint) (*models.ForumReply, error) {
+	var reply models.ForumReply
+	if err := r.db.First(&reply, replyID).Error; err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// FraudRepository runs the aggregate queries behind fraud detection
+type FraudRepository struct {
+	db *gorm.DB
+}
+
+// NewFraudRepository creates a new fraud repository
+func NewFraudRepository(db *gorm.DB) *FraudRepository {
+	return &FraudRepository{db: db}
+}
+
+// FindReciprocalPairs finds pairs of users who both taught and learned from each other
+// in completed sessions since the given time, with at least minSessions sessions in total
+func (r *FraudRepository) FindReciprocalPairs(since time.Time, minSessions int) ([]models.ReciprocalPairStat, error) {
+	var pairs []models.ReciprocalPairStat
+	err := r.db.Raw(`
+		SELECT
+			LEAST(teacher_id, student_id) AS user_a,
+			GREATEST(teacher_id, student_id) AS user_b,
+			SUM(CASE WHEN teacher_id < student_id THEN 1 ELSE 0 END) AS a_taught,
+			SUM(CASE WHEN teacher_id > student_id THEN 1 ELSE 0 END) AS b_taught,
+			STRING_AGG(id::text, ',' ORDER BY id) AS session_ids
+		FROM sessions
+		WHERE status = ? AND completed_at >= ? AND deleted_at IS NULL
+		GROUP BY 1, 2
+		HAVING SUM(CASE WHEN teacher_id < student_id THEN 1 ELSE 0 END) > 0
+			AND SUM(CASE WHEN teacher_id > student_id THEN 1 ELSE 0 END) > 0
+			AND COUNT(*) >= ?
+	`, models.StatusCompleted, since, minSessions).Scan(&pairs).Error
+	return pairs, err
+}
+
+// FindInstantSessions finds teachers with at least minCount completed sessions that ended
+// less than minMinutes after the last check-in (or with no check-in or start time at all)
+func (r *FraudRepository) FindInstantSessions(since time.Time, minMinutes, minCount int) ([]models.InstantSessionStat, error) {
+	var stats []models.InstantSessionStat
+	err := r.db.Raw(`
+		SELECT
+			teacher_id,
+			COUNT(*) AS session_count,
+			STRING_AGG(id::text, ',' ORDER BY id) AS session_ids,
+			STRING_AGG(DISTINCT student_id::text, ',') AS student_ids
+		FROM sessions
+		WHERE status = ? AND completed_at >= ? AND deleted_at IS NULL
+			AND completed_at - COALESCE(GREATEST(teacher_checked_in_at, student_checked_in_at), started_at, completed_at)
+				< make_interval(mins => ?)
+		GROUP BY teacher_id
+		HAVING COUNT(*) >= ?
+	`, models.StatusCompleted, since, minMinutes, minCount).Scan(&stats).Error
+	return stats, err
+}
+
+// GetTransferEdges aggregates peer transfers since the given time by sender and recipient
+// Only transfers that record both parties in their metadata are included
+func (r *FraudRepository) GetTransferEdges(since time.Time) ([]models.UserEdgeStat, error) {
+	var edges []models.UserEdgeStat
+	err := r.db.Raw(`
+		SELECT
+			(metadata->>'sender_id')::bigint AS from_id,
+			(metadata->>'recipient_id')::bigint AS to_id,
+			COUNT(*) AS count,
+			SUM(-amount) AS amount
+		FROM transactions
+		WHERE type = ? AND amount < 0 AND created_at >= ? AND deleted_at IS NULL
+			AND metadata->>'sender_id' IS NOT NULL
+			AND metadata->>'recipient_id' IS NOT NULL
+		GROUP BY 1, 2
+	`, models.TransactionSpent, since).Scan(&edges).Error
+	return edges, err
+}
+
+// GetNewAccountSessionEdges aggregates completed sessions since the given time
+// between teachers and students whose accounts were both created after createdAfter
+func (r *FraudRepository) GetNewAccountSessionEdges(since, createdAfter time.Time) ([]models.UserEdgeStat, error) {
+	var edges []models.UserEdgeStat
+	err := r.db.Raw(`
+		SELECT
+			s.teacher_id AS from_id,
+			s.student_id AS to_id,
+			COUNT(*) AS count,
+			COALESCE(SUM(s.credit_amount), 0) AS amount
+		FROM sessions s
+		JOIN users t ON t.id = s.teacher_id
+		JOIN users st ON st.id = s.student_id
+		WHERE s.status = ? AND s.completed_at >= ? AND s.deleted_at IS NULL
+			AND t.created_at >= ? AND st.created_at >= ?
+		GROUP BY 1, 2
+	`, models.StatusCompleted, since, createdAfter, createdAfter).Scan(&edges).Error
+	return edges, err
+}
+
+// GetNewAccountTradeEdges aggregates completed sessions and peer transfers since the given time
+// in which at least one party's account was created after createdAfter
+// Used to tell new accounts that trade only among themselves from ones that also trade with others.
+func (r *FraudRepository) GetNewAccountTradeEdges(since, createdAfter time.Time) ([]models.UserEdgeStat, error) {
+	var edges []models.UserEdgeStat
+	err := r.db.Raw(`
+		SELECT from_id, to_id, SUM(count) AS count, SUM(amount) AS amount
+		FROM (
+			SELECT s.teacher_id AS from_id, s.student_id AS to_id, COUNT(*) AS count,
+				COALESCE(SUM(s.credit_amount), 0) AS amount
+			FROM sessions s
+			JOIN users t ON t.id = s.teacher_id
+			JOIN users st ON st.id = s.student_id
+			WHERE s.status = ? AND s.completed_at >= ? AND s.deleted_at IS NULL
+				AND (t.created_at >= ? OR st.created_at >= ?)
+			GROUP BY 1, 2
+			UNION ALL
+			SELECT (tr.metadata->>'sender_id')::bigint, (tr.metadata->>'recipient_id')::bigint, COUNT(*),
+				SUM(-tr.amount)
+			FROM transactions tr
+			JOIN users sd ON sd.id = (tr.metadata->>'sender_id')::bigint
+			JOIN users rc ON rc.id = (tr.metadata->>'recipient_id')::bigint
+			WHERE tr.type = ? AND tr.amount < 0 AND tr.created_at >= ? AND tr.deleted_at IS NULL
+				AND (sd.created_at >= ? OR rc.created_at >= ?)
+			GROUP BY 1, 2
+		) trades
+		GROUP BY 1, 2
+	`, models.StatusCompleted, since, createdAfter, createdAfter,
+		models.TransactionSpent, since, createdAfter, createdAfter).Scan(&edges).Error
+	return edges, err
+}
+
+// HasReport checks if a report with the fingerprint is pending or was raised since the given time
+func (r *FraudRepository) HasReport(fingerprint string, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Report{}).
+		Where("fingerprint = ? AND (status = ? OR created_at >= ?)", fingerprint, models.ReportStatusPending, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateReport creates a fraud report and, when freeze is set, freezes the payouts
+// of the involved users, in one database transaction
+func (r *FraudRepository) CreateReport(report *models.Report, userIDs []uint, freeze bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(report).Error; err != nil {
+			return err
+		}
+		if !freeze || len(userIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&models.User{}).
+			Where("id IN ?", userIDs).
+			Update("payouts_frozen", true).Error
+	})
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// leaderboardTTL is how long a computed ranking is reused before it is recomputed
+const leaderboardTTL = 5 * time.Minute
+
+// leaderboardRankings caches full rankings by board and filter, shared by all repositories
+// Ranking every user takes full aggregates and a window function, so it is computed at
+// most once per TTL and GetTop and GetAround are answered from the cached ranking.
+var leaderboardRankings = utils.NewCache(leaderboardTTL)
+
+// LeaderboardFilter narrows a leaderboard to a time window and a population of users
+// At most one scope (School, SkillID or Category) is expected to be set.
+type LeaderboardFilter struct {
+	Since    *time.Time // Only count activity from this time; nil = all time
+	School   string     // Users of this school (User.School)
+	SkillID  uint       // Users teaching or learning this skill
+	Category string     // Users teaching or learning a skill in this category
+}
+
+// LeaderboardRow is a user's score and 1-based position on a leaderboard
+type LeaderboardRow struct {
+	UserID   uint
+	Username string
+	FullName string
+	Avatar   string
+	Score    float64
+	RawScore *float64 // Unadjusted score, for boards ranking by an adjusted one
+	Position int
+}
+
+// LeaderboardRepository computes leaderboards with SQL window functions
+type LeaderboardRepository struct {
+	db *gorm.DB
+}
+
+// NewLeaderboardRepository creates a new leaderboard repository
+func NewLeaderboardRepository(db *gorm.DB) *LeaderboardRepository {
+	return &LeaderboardRepository{db: db}
+}
+
+// GetTop returns the first limit rows of a leaderboard
+func (r *LeaderboardRepository) GetTop(board models.LeaderboardType, filter LeaderboardFilter, limit int) ([]LeaderboardRow, error) {
+	ranking, err := r.ranking(board, filter)
+	if err != nil {
+		return nil, err
+	}
+	if limit < len(ranking) {
+		ranking = ranking[:limit]
+	}
+	return ranking, nil
+}
+
+// GetAround returns the user's row with up to radius rows above and below it,
+// plus the number of users on the leaderboard
+// rows is empty if the user has no score on the leaderboard.
+func (r *LeaderboardRepository) GetAround(board models.LeaderboardType, filter LeaderboardFilter, userID uint, radius int) ([]LeaderboardRow, int64, error) {
+	ranking, err := r.ranking(board, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(ranking))
+	for i, row := range ranking {
+		if row.UserID != userID {
+			continue
+		}
+		from, to := i-radius, i+radius+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(ranking) {
+			to = len(ranking)
+		}
+		return ranking[from:to], total, nil
+	}
+	return []LeaderboardRow{}, total, nil
+}
+
+// ranking returns every ranked row of a leaderboard, best first, from the cache when
+// it was computed less than leaderboardTTL ago
+// The returned slice is shared: callers must not modify it.
+func (r *LeaderboardRepository) ranking(board models.LeaderboardType, filter LeaderboardFilter) ([]LeaderboardRow, error) {
+	key := leaderboardRankingKey(board, filter)
+	if cached, ok := leaderboardRankings.Get(key); ok {
+		return cached.([]LeaderboardRow), nil
+	}
+
+	ranked, args, err := rankedQuery(board, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []LeaderboardRow
+	if err := r.db.Raw(ranked+" SELECT * FROM ranked ORDER BY position", args...).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	leaderboardRankings.Set(key, rows)
+	return rows, nil
+}
+
+// leaderboardRankingKey identifies a ranking by board, period start and scope
+func leaderboardRankingKey(board models.LeaderboardType, filter LeaderboardFilter) string {
+	since := "all"
+	if filter.Since != nil {
+		since = filter.Since.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%s|%s|school=%s|skill=%d|category=%s",
+		board, since, filter.School, filter.SkillID, filter.Category)
+}
+
+// rankedQuery builds a "WITH scores AS (...), ranked AS (...)" prefix numbering the
+// users of the filtered population with a positive score; ties go to the lower user ID
+func rankedQuery(board models.LeaderboardType, filter LeaderboardFilter) (string, []interface{}, error) {
+	scores, args, err := scoreQuery(board, filter.Since)
+	if err != nil {
+		return "", nil, err
+	}
+
+	conditions := []string{"u.deleted_at IS NULL", "u.is_active = true", "sc.score > 0"}
+	switch {
+	case filter.School != "":
+		conditions = append(conditions, "u.school = ?")
+		args = append(args, filter.School)
+	case filter.SkillID != 0:
+		conditions = append(conditions, `(EXISTS (SELECT 1 FROM user_skills us WHERE us.user_id = u.id AND us.skill_id = ? AND us.deleted_at IS NULL)
+			OR EXISTS (SELECT 1 FROM learning_skills ls WHERE ls.user_id = u.id AND ls.skill_id = ? AND ls.deleted_at IS NULL))`)
+		args = append(args, filter.SkillID, filter.SkillID)
+	case filter.Category != "":
+		conditions = append(conditions, `(EXISTS (SELECT 1 FROM user_skills us JOIN skills sk ON sk.id = us.skill_id
+				WHERE us.user_id = u.id AND sk.category = ? AND us.deleted_at IS NULL)
+			OR EXISTS (SELECT 1 FROM learning_skills ls JOIN skills sk ON sk.id = ls.skill_id
+				WHERE ls.user_id = u.id AND sk.category = ? AND ls.deleted_at IS NULL))`)
+		args = append(args, filter.Category, filter.Category)
+	}
+
+	query := fmt.Sprintf(`
+		WITH scores AS (%s),
+		ranked AS (
+			SELECT u.id AS user_id, u.username, u.full_name, u.avatar, sc.score, %s AS raw_score,
+				ROW_NUMBER() OVER (ORDER BY sc.score DESC, u.id ASC) AS position
+			FROM scores sc
+			JOIN users u ON u.id = sc.user_id
+			WHERE %s
+		)`, scores, rawScoreColumn(board), strings.Join(conditions, " AND "))
+	return query, args, nil
+}
+
+// rawScoreColumn returns the raw score column of a leaderboard's scores
+func rawScoreColumn(board models.LeaderboardType) string {
+	if board == models.LeaderboardRating {
+		return "sc.raw_score"
+	}
+	return "NULL::float8"
+}
+
+// scoreQuery returns a query selecting (user_id, score) for a leaderboard type
+// Rating ranks by the DefaultRatingScorer adjusted rating of reviews received in
+// the window, with the plain average as raw_score. All-time credits use the total
+// kept on users; windowed credits are computed from earned transactions.
+func scoreQuery(board models.LeaderboardType, since *time.Time) (string, []interface{}, error) {
+	var args []interface{}
+	window := func(column string) string {
+		if since == nil {
+			return ""
+		}
+		args = append(args, *since)
+		return " AND " + column + " >= ?"
+	}
+
+	switch board {
+	case models.LeaderboardBadges:
+		return `SELECT ub.user_id, COUNT(*) AS score FROM user_badges ub
+			WHERE ub.deleted_at IS NULL` + window("ub.earned_at") + `
+			GROUP BY ub.user_id`, args, nil
+	case models.LeaderboardRarity:
+		return `SELECT ub.user_id, SUM(b.rarity) AS score FROM user_badges ub
+			JOIN badges b ON b.id = ub.badge_id
+			WHERE ub.deleted_at IS NULL` + window("ub.earned_at") + `
+			GROUP BY ub.user_id`, args, nil
+	case models.LeaderboardSessions:
+		return `SELECT p.user_id, COUNT(*) AS score FROM sessions s
+			CROSS JOIN LATERAL (VALUES (s.teacher_id), (s.student_id)) AS p(user_id)
+			WHERE s.status = 'completed' AND s.deleted_at IS NULL` + window("s.completed_at") + `
+			GROUP BY p.user_id`, args, nil
+	case models.LeaderboardRating:
+		scores, args := DefaultRatingScorer.scoresQuery(ratingByReviewee, "", since, nil)
+		return `SELECT rs.subject_id AS user_id, rs.adjusted_rating AS score, rs.raw_rating AS raw_score
+			FROM (` + scores + `) AS rs`, args, nil
+	case models.LeaderboardCredits:
+		if since == nil {
+			return `SELECT id AS user_id, total_earned AS score FROM users`, args, nil
+		}
+		return `SELECT t.user_id, SUM(t.amount) AS score FROM transactions t
+			WHERE t.type = 'earned' AND t.deleted_at IS NULL` + window("t.created_at") + `
+			GROUP BY t.user_id`, args, nil
+	}
+	return "", nil, fmt.Errorf("unknown leaderboard type %q", board)
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+func TestLeaderboardRankingIsComputedOncePerTTL(t *testing.T) {
+	leaderboardRankings.Clear()
+	t.Cleanup(leaderboardRankings.Clear)
+
+	db, mock := newMockDB(t)
+	repo := NewLeaderboardRepository(db)
+
+	rows := sqlmock.NewRows([]string{"user_id", "username", "score", "position"})
+	for id := 1; id <= 6; id++ {
+		rows.AddRow(id*10, "user", 10-id, id)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM ranked ORDER BY position`)).WillReturnRows(rows)
+
+	top, err := repo.GetTop(models.LeaderboardSessions, LeaderboardFilter{}, 3)
+	require.NoError(t, err)
+	assert.Len(t, top, 3)
+	assert.Equal(t, uint(10), top[0].UserID)
+
+	around, total, err := repo.GetAround(models.LeaderboardSessions, LeaderboardFilter{}, 20, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), total)
+	assert.Equal(t, []int{1, 2, 3, 4}, positions(around))
+
+	around, _, err = repo.GetAround(models.LeaderboardSessions, LeaderboardFilter{}, 60, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 6}, positions(around))
+
+	around, _, err = repo.GetAround(models.LeaderboardSessions, LeaderboardFilter{}, 99, 1)
+	require.NoError(t, err)
+	assert.Empty(t, around, "users without a score are not ranked")
+
+	// Every call above was answered by the single ranking query
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func positions(rows []LeaderboardRow) []int {
+	result := make([]int, len(rows))
+	for i, row := range rows {
+		result[i] = row.Position
+	}
+	return result
+}
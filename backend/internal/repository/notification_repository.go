@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/timebankingskill/backend/internal/models"
 	"gorm.io/gorm"
 )
@@ -233,3 +235,75 @@ func (r *NotificationRepository) GetPreferences(userID uint) (*models.Notificati
 func (r *NotificationRepository) UpdatePreferences(pref *models.NotificationPreference) error {
 	return r.db.Save(pref).Error
 }
+
+// GetSince retrieves a user's notifications created after the given cursor, oldest first
+// Used to replay the notifications a client missed while disconnected.
+// Parameters:
+//   - userID: User ID
+//   - afterID: ID of the last notification the client received
+//   - limit: Maximum number of notifications to return
+// Returns:
+//   - []Notification: Notifications with an ID greater than afterID, in ID order
+//   - error: If database error
+func (r *NotificationRepository) GetSince(userID uint, afterID uint, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.
+		Where("user_id = ? AND id > ?", userID, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkDelivered records when a client acknowledged one of a user's notifications
+// Acks are per notification: live notifications are not sent in ID order, so a
+// cumulative ack could mark one the client never received.
+// Parameters:
+//   - userID: User ID
+//   - notificationID: ID of the notification acknowledged
+//   - at: Time of the acknowledgement
+// Returns:
+//   - int64: Number of notifications marked (0 if already delivered)
+//   - error: If database error
+func (r *NotificationRepository) MarkDelivered(userID uint, notificationID uint, at time.Time) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND id = ? AND delivered_at IS NULL", userID, notificationID).
+		UpdateColumn("delivered_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// NotificationDeliveryStats summarizes the real-time delivery latency of notifications,
+// from creation to the client's acknowledgement
+type NotificationDeliveryStats struct {
+	Total      int64   `json:"total"`
+	Delivered  int64   `json:"delivered"` // Acknowledged by a connected client
+	AvgSeconds float64 `json:"avg_seconds"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P95Seconds float64 `json:"p95_seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+// GetDeliveryStats computes the delivery latency of notifications created since the given time
+// Parameters:
+//   - since: Start of the period
+// Returns:
+//   - *NotificationDeliveryStats: Counts and latency percentiles (zero when nothing was delivered)
+//   - error: If database error
+func (r *NotificationRepository) GetDeliveryStats(since time.Time) (*NotificationDeliveryStats, error) {
+	var stats NotificationDeliveryStats
+	err := r.db.Raw(`
+		SELECT
+			COUNT(*) AS total,
+			COUNT(delivered_at) AS delivered,
+			COALESCE(AVG(latency), 0) AS avg_seconds,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY latency), 0) AS p50_seconds,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency), 0) AS p95_seconds,
+			COALESCE(MAX(latency), 0) AS max_seconds
+		FROM (
+			SELECT delivered_at, EXTRACT(EPOCH FROM delivered_at - created_at) AS latency
+			FROM notifications
+			WHERE created_at >= ? AND deleted_at IS NULL
+		) n
+	`, since).Scan(&stats).Error
+	return &stats, err
+}
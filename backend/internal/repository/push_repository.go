@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PushDeliveryRepositoryInterface defines the contract for the notification push outbox
+type PushDeliveryRepositoryInterface interface {
+	ClaimDuePushDeliveries(now time.Time, lease time.Duration, limit int) ([]models.PushDelivery, error)
+	UpdatePushDelivery(delivery *models.PushDelivery) error
+	GetPreferences(userID uint) (*models.NotificationPreference, error)
+	GetPushSubscriptions(userID uint) ([]models.PushSubscription, error)
+	DeletePushSubscription(id uint) error
+}
+
+// SavePushSubscription creates a push subscription, or takes over the one with the same endpoint
+// A browser keeps its endpoint across re-subscriptions and logins, so the latest user and
+// keys win.
+func (r *NotificationRepository) SavePushSubscription(sub *models.PushSubscription) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "p256dh", "auth", "user_agent", "updated_at"}),
+	}).Create(sub).Error
+}
+
+// GetPushSubscriptions gets a user's push subscriptions, oldest first
+func (r *NotificationRepository) GetPushSubscriptions(userID uint) ([]models.PushSubscription, error) {
+	var subs []models.PushSubscription
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&subs).Error
+	return subs, err
+}
+
+// CountPushSubscriptions counts a user's push subscriptions
+func (r *NotificationRepository) CountPushSubscriptions(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PushSubscription{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// DeletePushSubscription deletes a push subscription the push service no longer knows
+func (r *NotificationRepository) DeletePushSubscription(id uint) error {
+	return r.db.Delete(&models.PushSubscription{}, id).Error
+}
+
+// DeleteUserPushSubscription deletes a user's push subscription by endpoint
+// Returns the number of subscriptions deleted (0 if the user had none with that endpoint).
+func (r *NotificationRepository) DeleteUserPushSubscription(userID uint, endpoint string) (int64, error) {
+	result := r.db.Where("user_id = ? AND endpoint = ?", userID, endpoint).Delete(&models.PushSubscription{})
+	return result.RowsAffected, result.Error
+}
+
+// CreatePushDelivery queues a notification for the push channel
+func (r *NotificationRepository) CreatePushDelivery(delivery *models.PushDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// ClaimDuePushDeliveries gets pending pushes due by now, oldest first, with their
+// notification, and leases them by moving SendAfter to now+lease
+// Works like ClaimDueEmailDeliveries: rows locked by another dispatcher are skipped and
+// the returned deliveries keep their SendAfter from before the lease.
+func (r *NotificationRepository) ClaimDuePushDeliveries(now time.Time, lease time.Duration, limit int) ([]models.PushDelivery, error) {
+	var deliveries []models.PushDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ? AND send_after <= ?", models.PushDeliveryPending, now).
+			Order("send_after ASC, id ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&models.PushDelivery{}).
+			Where("id IN ?", ids).
+			UpdateColumn("send_after", now.Add(lease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return deliveries, err
+	}
+
+	// Load notifications outside the locking query; they carry delivered_at, set when a
+	// live connection acknowledged them
+	notificationIDs := make([]uint, len(deliveries))
+	for i, delivery := range deliveries {
+		notificationIDs[i] = delivery.NotificationID
+	}
+	var notifications []models.Notification
+	if err := r.db.Where("id IN ?", notificationIDs).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Notification, len(notifications))
+	for _, notification := range notifications {
+		byID[notification.ID] = notification
+	}
+	for i := range deliveries {
+		deliveries[i].Notification = byID[deliveries[i].NotificationID]
+	}
+	return deliveries, nil
+}
+
+// UpdatePushDelivery saves the outcome of a push attempt
+func (r *NotificationRepository) UpdatePushDelivery(delivery *models.PushDelivery) error {
+	return r.db.Model(delivery).Select("status", "send_after", "attempts", "last_error", "sent_at").Updates(delivery).Error
+}
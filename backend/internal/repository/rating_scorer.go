@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// RatingScorer scores ratings for ranking with a Bayesian prior and time decay
+//
+//	adjusted = (PriorWeight * mean + Σ wᵢ·ratingᵢ) / (PriorWeight + Σ wᵢ)
+//	wᵢ       = 0.5 ^ (ageᵢ / HalfLife)
+//
+// mean is the platform average of visible reviews of the same type. Every subject
+// starts as if it had PriorWeight reviews at the mean, so a single 5-star review
+// moves the score only a little, while hundreds of reviews dominate the prior.
+// Older reviews weigh less, so the score follows recent teaching quality.
+type RatingScorer struct {
+	PriorWeight float64       // Confidence in the platform mean, in full-weight reviews
+	HalfLife    time.Duration // Age at which a review counts half
+}
+
+// DefaultRatingScorer is the scorer used by leaderboards, teacher lists and recommendations
+var DefaultRatingScorer = RatingScorer{
+	PriorWeight: 5,
+	HalfLife:    365 * 24 * time.Hour,
+}
+
+// Subjects reviews are grouped by when scoring
+const (
+	ratingByReviewee  = "r.reviewee_id"
+	ratingByUserSkill = "s.user_skill_id"
+)
+
+// ratingScoreRow is a RatingScore as scanned from scoresQuery
+type ratingScoreRow struct {
+	SubjectID           uint
+	RawRating           float64
+	AdjustedRating      float64
+	ReviewCount         int64
+	CommunicationRating *float64
+	PunctualityRating   *float64
+	KnowledgeRating     *float64
+}
+
+// scoresQuery returns a query selecting (subject_id, raw_rating, adjusted_rating,
+// review_count, communication_rating, punctuality_rating, knowledge_rating) per subject
+// An empty reviewType scores reviews of both types; since limits the reviews scored
+// (the prior always uses all reviews) and subjectIDs limits the subjects.
+func (sc RatingScorer) scoresQuery(subject string, reviewType models.ReviewType, since *time.Time, subjectIDs []uint) (string, []interface{}) {
+	visible := "is_hidden = false AND is_sealed = false AND deleted_at IS NULL"
+	args := []interface{}{sc.PriorWeight, sc.PriorWeight}
+
+	prior := "SELECT COALESCE(AVG(rating), 0) AS mean FROM reviews WHERE " + visible
+	if reviewType != "" {
+		prior += " AND type = ?"
+		args = append(args, reviewType)
+	}
+	args = append(args, sc.HalfLife.Seconds())
+
+	conditions := "r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL"
+	if reviewType != "" {
+		conditions += " AND r.type = ?"
+		args = append(args, reviewType)
+	}
+	if since != nil {
+		conditions += " AND r.created_at >= ?"
+		args = append(args, *since)
+	}
+	if subjectIDs != nil {
+		conditions += " AND " + subject + " IN ?"
+		args = append(args, subjectIDs)
+	}
+
+	return fmt.Sprintf(`
+		SELECT %[1]s AS subject_id,
+			AVG(r.rating) AS raw_rating,
+			(? * prior.mean + SUM(d.weight * r.rating)) / (? + SUM(d.weight)) AS adjusted_rating,
+			COUNT(*) AS review_count,
+			AVG(r.communication_rating) AS communication_rating,
+			AVG(r.punctuality_rating) AS punctuality_rating,
+			AVG(r.knowledge_rating) AS knowledge_rating
+		FROM reviews r
+		JOIN sessions s ON s.id = r.session_id
+		CROSS JOIN (%[2]s) AS prior
+		CROSS JOIN LATERAL (
+			SELECT POWER(0.5, EXTRACT(EPOCH FROM NOW() - r.created_at)::float8 / ?) AS weight
+		) AS d
+		WHERE %[3]s
+		GROUP BY %[1]s, prior.mean`, subject, prior, conditions), args
+}
+
+// scores returns the rating scores of the given subjects, keyed by subject ID
+// Subjects without visible reviews are missing from the map.
+func (sc RatingScorer) scores(db *gorm.DB, subject string, reviewType models.ReviewType, subjectIDs []uint) (map[uint]models.RatingScore, error) {
+	scores := make(map[uint]models.RatingScore)
+	if len(subjectIDs) == 0 {
+		return scores, nil
+	}
+
+	query, args := sc.scoresQuery(subject, reviewType, nil, subjectIDs)
+	var rows []ratingScoreRow
+	if err := db.Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		scores[row.SubjectID] = models.RatingScore{
+			Raw:           row.RawRating,
+			Adjusted:      row.AdjustedRating,
+			ReviewCount:   row.ReviewCount,
+			Communication: row.CommunicationRating,
+			Punctuality:   row.PunctualityRating,
+			Knowledge:     row.KnowledgeRating,
+		}
+	}
+	return scores, nil
+}
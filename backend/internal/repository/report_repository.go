@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/timebankingskill/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ReportRepository handles moderation reports
@@ -64,3 +68,73 @@ func (r *ReportRepository) GetAllWithFilters(limit, offset int, status string, s
 
 	return reports, total, nil
 }
+
+// HasPendingReport reports whether a user already has a pending report on a target
+func (r *ReportRepository) HasPendingReport(reportType models.ReportType, targetID, reporterID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Report{}).
+		Where("type = ? AND target_id = ? AND reported_by = ? AND status = ?",
+			reportType, targetID, reporterID, models.ReportStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetPendingByTargets returns the pending reports on the given targets, oldest first
+func (r *ReportRepository) GetPendingByTargets(reportType models.ReportType, targetIDs []uint) ([]models.Report, error) {
+	var reports []models.Report
+	if len(targetIDs) == 0 {
+		return reports, nil
+	}
+	err := r.db.Where("type = ? AND target_id IN ? AND status = ?", reportType, targetIDs, models.ReportStatusPending).
+		Order("created_at ASC").
+		Find(&reports).Error
+	return reports, err
+}
+
+// DismissFraudReport saves a dismissed fraud report and lifts the payout freeze of the
+// named users, in one database transaction
+// A user another pending fraud report still names stays frozen. Each user row is locked
+// before that check, so a report raised concurrently freezes the user after the release.
+//
+// Returns:
+//   - []uint: IDs of the users whose payouts were released
+//   - error: Database error
+func (r *ReportRepository) DismissFraudReport(report *models.Report, userIDs []uint) ([]uint, error) {
+	var released []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(report).Select("status", "resolved_by").Updates(report).Error; err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			var user models.User
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				return err
+			}
+
+			var open int64
+			if err := tx.Model(&models.Report{}).
+				Where("type = ? AND status = ? AND id <> ? AND metadata->'user_ids' @> ?::jsonb",
+					models.ReportTypeFraud, models.ReportStatusPending, report.ID, fmt.Sprintf("[%d]", userID)).
+				Count(&open).Error; err != nil {
+				return err
+			}
+			if open > 0 {
+				continue
+			}
+
+			if err := unfreezePayouts(tx, &user); err != nil {
+				return err
+			}
+			released = append(released, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
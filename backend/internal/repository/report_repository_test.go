@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db, mock
+}
+
+func expectFrozenUser(mock sqlmock.Sqlmock, userID uint) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)+`.*FOR UPDATE`).
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "frozen_credits", "payouts_frozen"}).
+			AddRow(userID, 5.0, true))
+}
+
+func expectOtherPendingFraudReports(mock sqlmock.Sqlmock, reportID, userID uint, count int) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "reports"`)).
+		WithArgs(models.ReportTypeFraud, models.ReportStatusPending, reportID, fmt.Sprintf("[%d]", userID)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestDismissFraudReportKeepsUsersNamedInAnotherPendingReportFrozen(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewReportRepository(db)
+
+	resolver := uint(1)
+	report := &models.Report{ID: 10, Type: models.ReportTypeFraud, Status: "dismissed", ResolvedBy: &resolver}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reports" SET "status"=$1,"resolved_by"=$2`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// User 7 is also named in another pending fraud report: no payout update
+	expectFrozenUser(mock, 7)
+	expectOtherPendingFraudReports(mock, 10, 7, 1)
+
+	// User 8 is only named in the dismissed report: the freeze is lifted
+	expectFrozenUser(mock, 8)
+	expectOtherPendingFraudReports(mock, 10, 8, 0)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "frozen_credits"=$1,"payouts_frozen"=$2`)).
+		WithArgs(0, false, sqlmock.AnyArg(), 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	released, err := repo.DismissFraudReport(report, []uint{7, 8})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{8}, released)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
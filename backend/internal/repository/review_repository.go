@@ -1,8 +1,13 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ReviewRepository handles database operations for reviews
@@ -15,9 +20,90 @@ func NewReviewRepository(db *gorm.DB) *ReviewRepository {
 	return &ReviewRepository{db: db}
 }
 
-// Create creates a new review
+// Create creates a new review and updates the reviewee's ratings
 func (r *ReviewRepository) Create(review *models.Review) error {
-	return r.db.Create(review).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		return refreshReviewStats(tx, review)
+	})
+}
+
+// CreateSealed creates a review sealed from its reviewee and, if the counterpart review
+// of the session is still sealed, reveals both, in one database transaction
+// The session row is locked so that two reviews submitted at once still reveal each other.
+// Returns the reviews revealed, empty when the new review stays sealed.
+func (r *ReviewRepository) CreateSealed(review *models.Review) ([]models.Review, error) {
+	var revealed []models.Review
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&models.Session{}, review.SessionID).Error; err != nil {
+			return err
+		}
+
+		review.IsSealed = true
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+
+		var counterpart models.Review
+		err := tx.Where("session_id = ? AND reviewer_id = ? AND is_sealed = ?", review.SessionID, review.RevieweeID, true).
+			First(&counterpart).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		revealed = []models.Review{*review, counterpart}
+		return revealReviews(tx, revealed)
+	})
+	return revealed, err
+}
+
+// RevealExpired reveals the sealed reviews of sessions completed before cutoff, in one
+// database transaction, and returns them
+func (r *ReviewRepository) RevealExpired(cutoff time.Time) ([]models.Review, error) {
+	var revealed []models.Review
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Joins("JOIN sessions ON sessions.id = reviews.session_id").
+			Where("reviews.is_sealed = ? AND COALESCE(sessions.completed_at, sessions.updated_at) < ?", true, cutoff).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "reviews"}}).
+			Find(&revealed).Error; err != nil {
+			return err
+		}
+		return revealReviews(tx, revealed)
+	})
+	return revealed, err
+}
+
+// revealReviews unseals reviews and updates the ratings they now count towards
+func revealReviews(tx *gorm.DB, reviews []models.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	ids := make([]uint, len(reviews))
+	for i := range reviews {
+		ids[i] = reviews[i].ID
+		reviews[i].IsSealed = false
+		reviews[i].RevealedAt = &now
+	}
+	if err := tx.Model(&models.Review{}).
+		Where("id IN ?", ids).
+		UpdateColumns(map[string]interface{}{"is_sealed": false, "revealed_at": now}).Error; err != nil {
+		return err
+	}
+
+	for i := range reviews {
+		if err := refreshReviewStats(tx, &reviews[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetByID gets a review by ID
@@ -34,20 +120,20 @@ func (r *ReviewRepository) GetBySessionAndReviewer(sessionID, reviewerID uint) (
 	return &review, err
 }
 
-// GetReviewsForUser gets all reviews for a user (as reviewee)
-func (r *ReviewRepository) GetReviewsForUser(userID uint, limit, offset int) ([]models.Review, int64, error) {
+// GetReviewsForUser gets all visible reviews for a user (as reviewee)
+func (r *ReviewRepository) GetReviewsForUser(userID uint, sort models.ReviewSort, limit, offset int) ([]models.Review, int64, error) {
 	var reviews []models.Review
 	var total int64
 
 	// Count total reviews
-	if err := r.db.Model(&models.Review{}).Where("reviewee_id = ? AND is_hidden = ?", userID, false).Count(&total).Error; err != nil {
+	if err := r.db.Model(&models.Review{}).Where("reviewee_id = ? AND is_hidden = ? AND is_sealed = ?", userID, false, false).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated reviews
-	err := r.db.Where("reviewee_id = ? AND is_hidden = ?", userID, false).
+	err := r.db.Where("reviewee_id = ? AND is_hidden = ? AND is_sealed = ?", userID, false, false).
 		Preload("Reviewer").
-		Order("created_at DESC").
+		Order(reviewOrder(sort)).
 		Limit(limit).
 		Offset(offset).
 		Find(&reviews).Error
@@ -86,21 +172,35 @@ func (r *ReviewRepository) GetReviewsForSession(sessionID uint) ([]models.Review
 	return reviews, err
 }
 
-// Update updates a review
+// Update saves changes to a review and updates the reviewee's ratings
 func (r *ReviewRepository) Update(review *models.Review) error {
-	return r.db.Save(review).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(review).Error; err != nil {
+			return err
+		}
+		return refreshReviewStats(tx, review)
+	})
 }
 
-// Delete deletes a review (soft delete)
+// Delete deletes a review (soft delete) and updates the reviewee's ratings
 func (r *ReviewRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Review{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var review models.Review
+		if err := tx.First(&review, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&review).Error; err != nil {
+			return err
+		}
+		return refreshReviewStats(tx, &review)
+	})
 }
 
 // GetAverageRatingForUser calculates average rating for a user
 func (r *ReviewRepository) GetAverageRatingForUser(userID uint) (float64, error) {
 	var avgRating float64
 	err := r.db.Model(&models.Review{}).
-		Where("reviewee_id = ? AND is_hidden = ?", userID, false).
+		Where("reviewee_id = ? AND is_hidden = ? AND is_sealed = ?", userID, false, false).
 		Select("COALESCE(AVG(rating), 0)").
 		Scan(&avgRating).Error
 	return avgRating, err
@@ -110,30 +210,79 @@ func (r *ReviewRepository) GetAverageRatingForUser(userID uint) (float64, error)
 func (r *ReviewRepository) GetRatingCountForUser(userID uint) (int64, error) {
 	var count int64
 	err := r.db.Model(&models.Review{}).
-		Where("reviewee_id = ? AND is_hidden = ?", userID, false).
+		Where("reviewee_id = ? AND is_hidden = ? AND is_sealed = ?", userID, false, false).
 		Count(&count).Error
 	return count, err
 }
 
-// GetReviewsForUserByType gets reviews for a user filtered by type (teacher/student)
-func (r *ReviewRepository) GetReviewsForUserByType(userID uint, reviewType models.ReviewType, limit, offset int) ([]models.Review, int64, error) {
+// GetRatingScore scores the visible reviews a user received of one type, or of both
+// types for an empty reviewType, with the DefaultRatingScorer
+// The score is zero with no reviews.
+func (r *ReviewRepository) GetRatingScore(userID uint, reviewType models.ReviewType) (*models.RatingScore, error) {
+	scores, err := DefaultRatingScorer.scores(r.db, ratingByReviewee, reviewType, []uint{userID})
+	if err != nil {
+		return nil, err
+	}
+	score := scores[userID]
+	return &score, nil
+}
+
+// GetReviewsForUserByType gets visible reviews for a user filtered by type (teacher/student)
+func (r *ReviewRepository) GetReviewsForUserByType(userID uint, reviewType models.ReviewType, sort models.ReviewSort, limit, offset int) ([]models.Review, int64, error) {
 	var reviews []models.Review
 	var total int64
 
 	// Count total reviews
 	if err := r.db.Model(&models.Review{}).
-		Where("reviewee_id = ? AND type = ? AND is_hidden = ?", userID, reviewType, false).
+		Where("reviewee_id = ? AND type = ? AND is_hidden = ? AND is_sealed = ?", userID, reviewType, false, false).
 		Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated reviews
-	err := r.db.Where("reviewee_id = ? AND type = ? AND is_hidden = ?", userID, reviewType, false).
+	err := r.db.Where("reviewee_id = ? AND type = ? AND is_hidden = ? AND is_sealed = ?", userID, reviewType, false, false).
 		Preload("Reviewer").
-		Order("created_at DESC").
+		Order(reviewOrder(sort)).
 		Limit(limit).
 		Offset(offset).
 		Find(&reviews).Error
 
 	return reviews, total, err
 }
+
+// reviewOrder returns the ORDER BY clause of a review sort, newest first by default
+func reviewOrder(sort models.ReviewSort) string {
+	switch sort {
+	case models.ReviewSortHelpful:
+		return "helpful_count DESC, created_at DESC"
+	case models.ReviewSortHighest:
+		return "rating DESC, created_at DESC"
+	case models.ReviewSortLowest:
+		return "rating ASC, created_at DESC"
+	}
+	return "created_at DESC"
+}
+
+// AddReply sets the reviewee's public reply on a review that has none yet
+// The condition is checked in the update itself, so of two concurrent replies only one
+// is saved and the other gets utils.ErrReplyExists.
+func (r *ReviewRepository) AddReply(reviewID uint, reply string, repliedAt time.Time) error {
+	result := r.db.Model(&models.Review{}).
+		Where("id = ? AND reply = ''", reviewID).
+		UpdateColumns(map[string]interface{}{"reply": reply, "replied_at": repliedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrReplyExists
+	}
+	return nil
+}
+
+// SetReply sets or clears the reviewee's public reply
+// The review's updated_at is left alone, as it marks edits by the reviewer.
+func (r *ReviewRepository) SetReply(reviewID uint, reply string, repliedAt *time.Time) error {
+	return r.db.Model(&models.Review{}).
+		Where("id = ?", reviewID).
+		UpdateColumns(map[string]interface{}{"reply": reply, "replied_at": repliedAt}).Error
+}
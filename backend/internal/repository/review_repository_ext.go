@@ -1,14 +1,109 @@
 package repository
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
 )
 
-// GetAveragePlatformRating calculates average rating across all reviews
+// GetAveragePlatformRating calculates average rating across all visible reviews
 func (r *ReviewRepository) GetAveragePlatformRating() (float64, error) {
 	var avgRating float64
 	err := r.db.Model(&models.Review{}).
+		Where("is_hidden = ? AND is_sealed = ?", false, false).
 		Select("COALESCE(AVG(rating), 0)").
 		Scan(&avgRating).Error
 	return avgRating, err
 }
+
+// ReportReview stores a review report and flags the review for moderation in one
+// database transaction
+func (r *ReviewRepository) ReportReview(report *models.Report) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(report).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Review{}).
+			Where("id = ?", report.TargetID).
+			Update("is_reported", true).Error
+	})
+}
+
+// GetModerationQueue returns reviews awaiting moderation
+// Without hidden, these are reported reviews that are still visible, most reported first;
+// with hidden, the reviews currently hidden, most recently changed first.
+func (r *ReviewRepository) GetModerationQueue(hidden bool, limit, offset int) ([]models.Review, int64, error) {
+	var reviews []models.Review
+	var total int64
+
+	query := r.db.Model(&models.Review{})
+	order := "updated_at DESC"
+	if hidden {
+		query = query.Where("is_hidden = ?", true)
+	} else {
+		query = query.Where("is_reported = ? AND is_hidden = ?", true, false)
+		order = fmt.Sprintf(`(SELECT COUNT(*) FROM reports WHERE reports.type = '%s' AND reports.target_id = reviews.id
+			AND reports.status = '%s' AND reports.deleted_at IS NULL) DESC, reviews.created_at ASC`,
+			models.ReportTypeReview, models.ReportStatusPending)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Reviewer").Preload("Reviewee").
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&reviews).Error
+	return reviews, total, err
+}
+
+// ApplyModeration records a moderation action in one database transaction: it sets the
+// review's visibility, closes its pending reports with reportStatus, writes the audit
+// entry and, when the visibility changed, recalculates the reviewee's rating aggregates
+func (r *ReviewRepository) ApplyModeration(review *models.Review, entry *models.ReviewModerationLog, hidden bool, reportStatus models.ReportStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Review{}).
+			Where("id = ?", review.ID).
+			Updates(map[string]interface{}{"is_hidden": hidden, "is_reported": false}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.Report{}).
+			Where("type = ? AND target_id = ? AND status = ?", models.ReportTypeReview, review.ID, models.ReportStatusPending).
+			Updates(map[string]interface{}{
+				"status":      reportStatus,
+				"resolved_by": entry.AdminID,
+				"resolved_at": now,
+				"resolution":  entry.Reason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		entry.ReportsClosed = int(result.RowsAffected)
+
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+
+		if hidden == review.IsHidden {
+			return nil
+		}
+		review.IsHidden = hidden
+		return refreshReviewStats(tx, review)
+	})
+}
+
+// GetModerationLog returns the moderation actions taken on a review, oldest first
+func (r *ReviewRepository) GetModerationLog(reviewID uint) ([]models.ReviewModerationLog, error) {
+	var entries []models.ReviewModerationLog
+	err := r.db.Preload("Admin").
+		Where("review_id = ?", reviewID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
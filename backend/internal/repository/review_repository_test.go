@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+func TestAddReply(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		err          error
+	}{
+		{name: "review without reply", rowsAffected: 1},
+		{name: "review already replied to", rowsAffected: 0, err: utils.ErrReplyExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			repo := NewReviewRepository(db)
+			now := time.Now()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reviews" SET "replied_at"=$1,"reply"=$2 WHERE (id = $3 AND reply = '')`)).
+				WithArgs(now, "Thanks!", 5).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			mock.ExpectCommit()
+
+			assert.ErrorIs(t, repo.AddReply(5, "Thanks!", now), tt.err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
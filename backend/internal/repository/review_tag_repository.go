@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReviewTagRepository handles database operations for the review tag vocabulary
+type ReviewTagRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewTagRepository creates a new review tag repository
+func NewReviewTagRepository(db *gorm.DB) *ReviewTagRepository {
+	return &ReviewTagRepository{db: db}
+}
+
+// GetAll gets the tags of a review type (both types if empty) in display order
+// Inactive tags are included only if includeInactive is set.
+func (r *ReviewTagRepository) GetAll(reviewType models.ReviewType, includeInactive bool) ([]models.ReviewTag, error) {
+	var tags []models.ReviewTag
+	query := r.db.Model(&models.ReviewTag{})
+	if reviewType != "" {
+		query = query.Where("type = ?", reviewType)
+	}
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("type ASC, sort_order ASC, label ASC").Find(&tags).Error
+	return tags, err
+}
+
+// GetByID gets a tag by ID
+func (r *ReviewTagRepository) GetByID(id uint) (*models.ReviewTag, error) {
+	var tag models.ReviewTag
+	err := r.db.First(&tag, id).Error
+	return &tag, err
+}
+
+// GetBySlug gets a tag by slug, including deleted tags, whose slugs stay reserved
+func (r *ReviewTagRepository) GetBySlug(slug string) (*models.ReviewTag, error) {
+	var tag models.ReviewTag
+	err := r.db.Unscoped().Where("slug = ?", slug).First(&tag).Error
+	return &tag, err
+}
+
+// Create creates a tag
+func (r *ReviewTagRepository) Create(tag *models.ReviewTag) error {
+	return r.db.Create(tag).Error
+}
+
+// Update saves changes to a tag
+func (r *ReviewTagRepository) Update(tag *models.ReviewTag) error {
+	return r.db.Save(tag).Error
+}
+
+// Delete deletes a tag (soft delete)
+// Reviews keep its slug, but it no longer shows in tag clouds.
+func (r *ReviewTagRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ReviewTag{}, id).Error
+}
+
+// GetTagCountsForUser gets the tag cloud of a user from the visible reviews they received
+// of a review type, most given tags first
+func (r *ReviewTagRepository) GetTagCountsForUser(userID uint, reviewType models.ReviewType) ([]models.ReviewTagCount, error) {
+	counts, err := reviewTagCounts(r.db, ratingByReviewee, reviewType, []uint{userID})
+	if err != nil {
+		return nil, err
+	}
+	if counts[userID] == nil {
+		return []models.ReviewTagCount{}, nil
+	}
+	return counts[userID], nil
+}
+
+// reviewTagCountRow is a ReviewTagCount of a subject as scanned from reviewTagCounts
+type reviewTagCountRow struct {
+	SubjectID uint
+	Slug      string
+	Label     string
+	Count     int64
+}
+
+// reviewTagCounts returns the tag clouds of the given subjects (see RatingScorer subjects),
+// keyed by subject ID, most given tags first
+// Only visible reviews of reviewType and active tags of the same type are counted;
+// subjects without tagged reviews are missing from the map.
+func reviewTagCounts(db *gorm.DB, subject string, reviewType models.ReviewType, subjectIDs []uint) (map[uint][]models.ReviewTagCount, error) {
+	counts := make(map[uint][]models.ReviewTagCount)
+	if len(subjectIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []reviewTagCountRow
+	err := db.Raw(fmt.Sprintf(`
+		SELECT %[1]s AS subject_id, t.slug, t.label, COUNT(*) AS count
+		FROM reviews r
+		JOIN sessions s ON s.id = r.session_id
+		CROSS JOIN LATERAL unnest(string_to_array(r.tags, ',')) AS rt(slug)
+		JOIN review_tags t ON t.slug = rt.slug AND t.type = r.type
+			AND t.is_active = true AND t.deleted_at IS NULL
+		WHERE r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL
+			AND r.type = ? AND %[1]s IN ?
+		GROUP BY %[1]s, t.slug, t.label, t.sort_order
+		ORDER BY count DESC, t.sort_order ASC, t.label ASC`, subject), reviewType, subjectIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.SubjectID] = append(counts[row.SubjectID], models.ReviewTagCount{
+			Slug:  row.Slug,
+			Label: row.Label,
+			Count: row.Count,
+		})
+	}
+	return counts, nil
+}
+
+// userSkillsWithTag is a condition on user_skills matching those with a visible teacher
+// review giving the active tag with the slug as its only argument
+const userSkillsWithTag = `EXISTS (
+	SELECT 1 FROM reviews r
+	JOIN sessions s ON s.id = r.session_id
+	JOIN review_tags t ON t.type = r.type AND t.is_active = true AND t.deleted_at IS NULL
+	WHERE s.user_skill_id = user_skills.id
+		AND r.type = 'teacher' AND r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL
+		AND t.slug = ? AND t.slug = ANY(string_to_array(r.tags, ','))
+)`
@@ -48,7 +48,7 @@ func (r *SessionRepository) Create(session *models.Session) error {
 // GetByID retrieves a session by ID with related data
 func (r *SessionRepository) GetByID(id uint) (*models.Session, error) {
 	var session models.Session
-	err := r.db.Preload("Teacher").Preload("Student").Preload("UserSkill").Preload("UserSkill.Skill").Preload("Review").
+	err := r.db.Preload("Teacher").Preload("Student").Preload("UserSkill").Preload("UserSkill.Skill").Preload("Review", "is_sealed = ?", false).
 		First(&session, id).Error
 	if err != nil {
 		return nil, err
@@ -81,7 +81,7 @@ func (r *SessionRepository) GetUserSessionsAsTeacher(userID uint, status string,
 		return nil, 0, err
 	}
 
-	err := query.Preload("Student").Preload("UserSkill").Preload("UserSkill.Skill").Preload("Review").
+	err := query.Preload("Student").Preload("UserSkill").Preload("UserSkill.Skill").Preload("Review", "is_sealed = ?", false).
 		Order("created_at DESC").
 		Limit(limit).Offset(offset).
 		Find(&sessions).Error
@@ -104,7 +104,7 @@ func (r *SessionRepository) GetUserSessionsAsStudent(userID uint, status string,
 		return nil, 0, err
 	}
 
-	err := query.Preload("Teacher").Preload("UserSkill").Preload("UserSkill.Skill").Preload("Review").
+	err := query.Preload("Teacher").Preload("UserSkill").Preload("UserSkill.Skill").Preload("Review", "is_sealed = ?", false).
 		Order("created_at DESC").
 		Limit(limit).Offset(offset).
 		Find(&sessions).Error
@@ -127,7 +127,7 @@ func (r *SessionRepository) GetAllUserSessions(userID uint, status string, limit
 		return nil, 0, err
 	}
 
-	err := query.Preload("Teacher").Preload("Student").Preload("UserSkill").Preload("UserSkill.Skill").Preload("Review").
+	err := query.Preload("Teacher").Preload("Student").Preload("UserSkill").Preload("UserSkill.Skill").Preload("Review", "is_sealed = ?", false).
 		Order("created_at DESC").
 		Limit(limit).Offset(offset).
 		Find(&sessions).Error
@@ -2,15 +2,32 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/utils"
 	"gorm.io/gorm"
 )
 
+// recommendationsTTL is how long a computed recommendation ranking is reused
+const recommendationsTTL = 5 * time.Minute
+
+// skillRecommendations caches recommendation rankings by limit, shared by all repositories
+// Scoring every teacher's reviews with time decay is too costly to repeat on each request;
+// the skills themselves are still loaded fresh.
+var skillRecommendations = utils.NewCache(recommendationsTTL)
+
+// recommendedSkill is a skill's position in the recommendation ranking
+type recommendedSkill struct {
+	SkillID           uint
+	MaxAdjustedRating float64
+}
+
 // SkillRepositoryInterface defines the contract for skill repository
 type SkillRepositoryInterface interface {
-	GetAllWithFilters(limit, offset int, category, search string, dayOfWeek *int, minRating *float64, location, sortBy string) ([]models.Skill, int64, error)
+	GetAllWithFilters(limit, offset int, category, search string, dayOfWeek *int, minRating *float64, location, tag, sortBy string) ([]models.Skill, int64, error)
 	GetByID(id uint) (*models.Skill, error)
 	Create(skill *models.Skill) error
 	Update(skill *models.Skill) error
@@ -23,7 +40,7 @@ type SkillRepositoryInterface interface {
 	GetUserSkillByID(id uint) (*models.UserSkill, error)
 	UpdateUserSkill(userSkill *models.UserSkill) error
 	DeleteUserSkill(userID, skillID uint) error
-	GetTeachersBySkillID(skillID uint) ([]models.UserSkill, error)
+	GetTeachersBySkillID(skillID uint, tag string) ([]models.UserSkill, error)
 
 	// Learning Skills
 	CreateLearningSkill(learningSkill *models.LearningSkill) error
@@ -86,7 +103,8 @@ func (r *SkillRepository) Create(skill *models.Skill) error {
 }
 
 // GetAllWithFilters returns skills with pagination and filters
-func (r *SkillRepository) GetAllWithFilters(limit, offset int, category, search string, dayOfWeek *int, minRating *float64, location, sortBy string) ([]models.Skill, int64, error) {
+// tag keeps skills with an available teacher given that review tag for the skill.
+func (r *SkillRepository) GetAllWithFilters(limit, offset int, category, search string, dayOfWeek *int, minRating *float64, location, tag, sortBy string) ([]models.Skill, int64, error) {
 	var skills []models.Skill
 	var total int64
 
@@ -101,7 +119,7 @@ func (r *SkillRepository) GetAllWithFilters(limit, offset int, category, search
 	}
 	
 	// Join conditions for filtering
-	needJoin := dayOfWeek != nil || location != "" || minRating != nil
+	needJoin := dayOfWeek != nil || location != "" || minRating != nil || tag != ""
 	if needJoin {
 		query = query.Joins("JOIN user_skills ON user_skills.skill_id = skills.id AND user_skills.is_available = true")
 		
@@ -116,6 +134,9 @@ func (r *SkillRepository) GetAllWithFilters(limit, offset int, category, search
 		if minRating != nil {
 			query = query.Where("user_skills.average_rating >= ?", *minRating)
 		}
+		if tag != "" {
+			query = query.Where(userSkillsWithTag, tag)
+		}
 		
 		query = query.Group("skills.id")
 	}
@@ -146,15 +167,64 @@ func (r *SkillRepository) GetAllWithFilters(limit, offset int, category, search
 	return skills, total, err
 }
 
-// GetRecommendations returns recommended skills
+// GetRecommendations returns recommended skills, those with the best-rated teachers first
+// Teachers are compared by their DefaultRatingScorer adjusted rating, so a skill is not
+// recommended on the strength of a single review.
 func (r *SkillRepository) GetRecommendations(limit int) ([]models.Skill, error) {
-	var skills []models.Skill
-	err := r.db.Model(&models.Skill{}).
-		Order("max_teacher_rating DESC, total_sessions DESC").
-		Limit(limit).
-		Find(&skills).Error
+	ranked, err := r.recommendationRanking(limit)
+	if err != nil || len(ranked) == 0 {
+		return nil, err
+	}
 
-	return skills, err
+	ids := make([]uint, len(ranked))
+	for i, row := range ranked {
+		ids[i] = row.SkillID
+	}
+	var found []models.Skill
+	if err := r.db.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Skill, len(found))
+	for _, skill := range found {
+		byID[skill.ID] = skill
+	}
+
+	skills := make([]models.Skill, 0, len(ranked))
+	for _, row := range ranked {
+		if skill, ok := byID[row.SkillID]; ok {
+			skill.MaxTeacherAdjustedRating = row.MaxAdjustedRating
+			skills = append(skills, skill)
+		}
+	}
+	return skills, nil
+}
+
+// recommendationRanking returns the best-rated skills with their best teacher's adjusted
+// rating, from the cache when computed less than recommendationsTTL ago
+// The returned slice is shared: callers must not modify it.
+func (r *SkillRepository) recommendationRanking(limit int) ([]recommendedSkill, error) {
+	key := fmt.Sprintf("limit=%d", limit)
+	if cached, ok := skillRecommendations.Get(key); ok {
+		return cached.([]recommendedSkill), nil
+	}
+
+	scores, args := DefaultRatingScorer.scoresQuery(ratingByUserSkill, models.ReviewTypeTeacher, nil, nil)
+	var ranked []recommendedSkill
+	err := r.db.Raw(`
+		SELECT sk.id AS skill_id, COALESCE(MAX(rs.adjusted_rating), 0) AS max_adjusted_rating
+		FROM skills sk
+		LEFT JOIN user_skills us ON us.skill_id = sk.id AND us.is_available = true AND us.deleted_at IS NULL
+		LEFT JOIN (`+scores+`) AS rs ON rs.subject_id = us.id
+		WHERE sk.deleted_at IS NULL
+		GROUP BY sk.id
+		ORDER BY max_adjusted_rating DESC, sk.total_sessions DESC, sk.id ASC
+		LIMIT ?`, append(args, limit)...).
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, err
+	}
+	skillRecommendations.Set(key, ranked)
+	return ranked, nil
 }
 
 // GetByID finds a skill by ID
@@ -209,24 +279,69 @@ func (r *SkillRepository) UpdateUserSkill(userSkill *models.UserSkill) error {
 	return r.db.Save(userSkill).Error
 }
 
-// GetUserSkillByID returns user skill by ID with preloaded relationships
+// GetUserSkillByID returns user skill by ID with preloaded relationships and its tag cloud
 func (r *SkillRepository) GetUserSkillByID(id uint) (*models.UserSkill, error) {
 	var userSkill models.UserSkill
 	err := r.db.Preload("Skill").Preload("User").First(&userSkill, id).Error
 	if err != nil {
 		return nil, err
 	}
+
+	tags, err := reviewTagCounts(r.db, ratingByUserSkill, models.ReviewTypeTeacher, []uint{userSkill.ID})
+	if err != nil {
+		return nil, err
+	}
+	userSkill.Tags = tags[userSkill.ID]
 	return &userSkill, nil
 }
 
-// GetTeachersBySkillID returns all users teaching a specific skill
-func (r *SkillRepository) GetTeachersBySkillID(skillID uint) ([]models.UserSkill, error) {
+// GetTeachersBySkillID returns all users teaching a specific skill, best rated first
+// Teachers are ranked by their DefaultRatingScorer adjusted rating for the skill, with
+// Rating and Tags set; teachers without reviews come last. A non-empty tag keeps only
+// teachers given that review tag for the skill.
+func (r *SkillRepository) GetTeachersBySkillID(skillID uint, tag string) ([]models.UserSkill, error) {
 	var userSkills []models.UserSkill
-	err := r.db.Preload("Skill").Preload("User").
-		Where("skill_id = ? AND is_available = ?", skillID, true).
-		Order("average_rating DESC, total_sessions DESC").
-		Find(&userSkills).Error
-	return userSkills, err
+	query := r.db.Preload("Skill").Preload("User").
+		Where("skill_id = ? AND is_available = ?", skillID, true)
+	if tag != "" {
+		query = query.Where(userSkillsWithTag, tag)
+	}
+	err := query.Order("total_sessions DESC, id ASC").Find(&userSkills).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(userSkills))
+	for i, userSkill := range userSkills {
+		ids[i] = userSkill.ID
+	}
+	scores, err := DefaultRatingScorer.scores(r.db, ratingByUserSkill, models.ReviewTypeTeacher, ids)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := reviewTagCounts(r.db, ratingByUserSkill, models.ReviewTypeTeacher, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range userSkills {
+		if score, ok := scores[userSkills[i].ID]; ok {
+			userSkills[i].Rating = &score
+		}
+		userSkills[i].Tags = tags[userSkills[i].ID]
+	}
+
+	sort.SliceStable(userSkills, func(i, j int) bool {
+		return adjustedRating(userSkills[i].Rating) > adjustedRating(userSkills[j].Rating)
+	})
+	return userSkills, nil
+}
+
+// adjustedRating returns a score's adjusted rating, or -1 for no score
+func adjustedRating(score *models.RatingScore) float64 {
+	if score == nil {
+		return -1
+	}
+	return score.Adjusted
 }
 
 // DeleteUserSkill deletes user skill
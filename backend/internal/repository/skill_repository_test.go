@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendationsAreRankedOncePerTTL(t *testing.T) {
+	skillRecommendations.Clear()
+	t.Cleanup(skillRecommendations.Clear)
+
+	db, mock := newMockDB(t)
+	repo := NewSkillRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT sk.id AS skill_id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"skill_id", "max_adjusted_rating"}).
+			AddRow(2, 4.8).AddRow(1, 4.1))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "skills" WHERE id IN`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Go").AddRow(2, "Calculus"))
+	}
+
+	for i := 0; i < 2; i++ {
+		skills, err := repo.GetRecommendations(2)
+		require.NoError(t, err)
+		require.Len(t, skills, 2)
+		assert.Equal(t, "Calculus", skills[0].Name)
+		assert.Equal(t, 4.8, skills[0].MaxTeacherAdjustedRating)
+	}
+
+	// The rating aggregate ran once; only the skills were loaded again
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// StatsRebuildResult counts the rows updated by a full stats rebuild
+type StatsRebuildResult struct {
+	Users      int64 `json:"users"`
+	UserSkills int64 `json:"user_skills"`
+	Skills     int64 `json:"skills"`
+}
+
+// StatsRepositoryInterface defines the stats maintenance used by services
+type StatsRepositoryInterface interface {
+	SaveCompletedSession(session *models.Session) error
+	RebuildAll() (*StatsRebuildResult, error)
+}
+
+// StatsRepository maintains the denormalized stats kept on users, user skills and skills
+// Stats are recomputed from sessions, reviews and transactions rather than incremented,
+// so a refresh is idempotent and repairs any earlier drift.
+//
+// Maintained columns:
+//   - users: total_sessions_as_teacher/student, total_earned, total_spent,
+//     average_rating_as_teacher/student
+//   - user_skills: total_sessions, average_rating, total_reviews
+//   - skills: total_sessions, max_teacher_rating
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository creates a new stats repository
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// SaveCompletedSession saves a completed session and updates the stats of its teacher,
+// student and taught skill in the same transaction
+func (r *StatsRepository) SaveCompletedSession(session *models.Session) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(session).Error; err != nil {
+			return err
+		}
+		return refreshSessionStats(tx, session)
+	})
+}
+
+// RebuildAll recomputes the stats of every user, user skill and skill in one transaction
+func (r *StatsRepository) RebuildAll() (*StatsRebuildResult, error) {
+	result := &StatsRebuildResult{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if result.Users, err = refreshUserStats(tx, nil); err != nil {
+			return err
+		}
+		if result.UserSkills, err = refreshUserSkillStats(tx, nil); err != nil {
+			return err
+		}
+		result.Skills, err = refreshSkillStats(tx, nil)
+		return err
+	})
+	return result, err
+}
+
+// refreshSessionStats updates the stats touched by a completed session
+func refreshSessionStats(tx *gorm.DB, session *models.Session) error {
+	if _, err := refreshUserStats(tx, []uint{session.TeacherID, session.StudentID}); err != nil {
+		return err
+	}
+	_, err := refreshUserSkillStats(tx, []uint{session.UserSkillID})
+	return err
+}
+
+// refreshReviewStats updates the ratings touched by a review being created, edited,
+// deleted, hidden or unhidden
+// Only reviews of a teacher count towards the stats of the skill taught in the session.
+func refreshReviewStats(tx *gorm.DB, review *models.Review) error {
+	if _, err := refreshUserStats(tx, []uint{review.RevieweeID}); err != nil {
+		return err
+	}
+	if review.Type != models.ReviewTypeTeacher {
+		return nil
+	}
+
+	var userSkillIDs []uint
+	if err := tx.Model(&models.Session{}).Unscoped().
+		Where("id = ?", review.SessionID).
+		Pluck("user_skill_id", &userSkillIDs).Error; err != nil {
+		return err
+	}
+	_, err := refreshUserSkillStats(tx, userSkillIDs)
+	return err
+}
+
+// refreshUserStats recomputes the session counts, credit totals and average ratings of
+// the given users, or of every user for nil userIDs
+// Credit totals only count transactions linked to a session, like the badge rules.
+func refreshUserStats(tx *gorm.DB, userIDs []uint) (int64, error) {
+	if userIDs != nil && len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	result := tx.Exec(`
+		UPDATE users u SET
+			total_sessions_as_teacher = (SELECT COUNT(*) FROM sessions s
+				WHERE s.teacher_id = u.id AND s.status = @completed AND s.deleted_at IS NULL),
+			total_sessions_as_student = (SELECT COUNT(*) FROM sessions s
+				WHERE s.student_id = u.id AND s.status = @completed AND s.deleted_at IS NULL),
+			total_earned = COALESCE((SELECT SUM(ABS(t.amount)) FROM transactions t
+				WHERE t.user_id = u.id AND t.type = @earned AND t.session_id IS NOT NULL AND t.deleted_at IS NULL), 0),
+			total_spent = COALESCE((SELECT SUM(ABS(t.amount)) FROM transactions t
+				WHERE t.user_id = u.id AND t.type = @spent AND t.session_id IS NOT NULL AND t.deleted_at IS NULL), 0),
+			average_rating_as_teacher = COALESCE((SELECT AVG(r.rating) FROM reviews r
+				WHERE r.reviewee_id = u.id AND r.type = @teacher AND r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL), 0),
+			average_rating_as_student = COALESCE((SELECT AVG(r.rating) FROM reviews r
+				WHERE r.reviewee_id = u.id AND r.type = @student AND r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL), 0)
+		WHERE `+statsScope("u.id", userIDs),
+		map[string]interface{}{
+			"completed": models.StatusCompleted,
+			"earned":    models.TransactionEarned,
+			"spent":     models.TransactionSpent,
+			"teacher":   models.ReviewTypeTeacher,
+			"student":   models.ReviewTypeStudent,
+			"ids":       userIDs,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// refreshUserSkillStats recomputes the session count and teacher rating of the given
+// user skills, or of every user skill for nil userSkillIDs, then of their skills
+func refreshUserSkillStats(tx *gorm.DB, userSkillIDs []uint) (int64, error) {
+	if userSkillIDs != nil && len(userSkillIDs) == 0 {
+		return 0, nil
+	}
+
+	result := tx.Exec(`
+		UPDATE user_skills us SET
+			total_sessions = (SELECT COUNT(*) FROM sessions s
+				WHERE s.user_skill_id = us.id AND s.status = @completed AND s.deleted_at IS NULL),
+			average_rating = COALESCE((SELECT AVG(r.rating) FROM reviews r JOIN sessions s ON s.id = r.session_id
+				WHERE s.user_skill_id = us.id AND r.type = @teacher AND r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL), 0),
+			total_reviews = (SELECT COUNT(*) FROM reviews r JOIN sessions s ON s.id = r.session_id
+				WHERE s.user_skill_id = us.id AND r.type = @teacher AND r.is_hidden = false AND r.is_sealed = false AND r.deleted_at IS NULL)
+		WHERE `+statsScope("us.id", userSkillIDs),
+		map[string]interface{}{
+			"completed": models.StatusCompleted,
+			"teacher":   models.ReviewTypeTeacher,
+			"ids":       userSkillIDs,
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	if userSkillIDs == nil {
+		return result.RowsAffected, nil
+	}
+	var skillIDs []uint
+	if err := tx.Model(&models.UserSkill{}).Unscoped().
+		Where("id IN ?", userSkillIDs).
+		Distinct().Pluck("skill_id", &skillIDs).Error; err != nil {
+		return 0, err
+	}
+	_, err := refreshSkillStats(tx, skillIDs)
+	return result.RowsAffected, err
+}
+
+// refreshSkillStats recomputes the session total and best teacher rating of the given
+// skills, or of every skill for nil skillIDs, from their available user skills
+// Raw updates skip the UserSkill AfterSave hook, which keeps these in step otherwise.
+func refreshSkillStats(tx *gorm.DB, skillIDs []uint) (int64, error) {
+	if skillIDs != nil && len(skillIDs) == 0 {
+		return 0, nil
+	}
+
+	result := tx.Exec(`
+		UPDATE skills sk SET
+			total_sessions = COALESCE((SELECT SUM(us.total_sessions) FROM user_skills us
+				WHERE us.skill_id = sk.id AND us.is_available = true AND us.deleted_at IS NULL), 0),
+			max_teacher_rating = COALESCE((SELECT MAX(us.average_rating) FROM user_skills us
+				WHERE us.skill_id = sk.id AND us.is_available = true AND us.deleted_at IS NULL), 0)
+		WHERE `+statsScope("sk.id", skillIDs),
+		map[string]interface{}{"ids": skillIDs})
+	return result.RowsAffected, result.Error
+}
+
+// statsScope limits a stats update to the @ids rows, or to every row for nil ids
+func statsScope(column string, ids []uint) string {
+	if ids == nil {
+		return "TRUE"
+	}
+	return column + " IN @ids"
+}
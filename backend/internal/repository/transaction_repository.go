@@ -15,7 +15,7 @@ type TransactionRepositoryInterface interface {
 	GetUserTransactionHistory(userID uint, limit, offset int) ([]models.Transaction, int64, error)
 	CountTotal() (int64, error)
 	GetTotalVolume() (float64, error)
-	GetAllWithFilters(limit, offset int, typeFilter, search string) ([]models.Transaction, int64, error)
+	GetAllWithFilters(limit, offset int, typeFilter, search string, userID uint) ([]models.Transaction, int64, error)
 	GetCreditVolumeTrend(days int) ([]models.DailyStat, error)
 }
 
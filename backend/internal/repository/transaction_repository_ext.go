@@ -1,7 +1,15 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CountTotal counts all transactions
@@ -23,7 +31,8 @@ func (r *TransactionRepository) GetTotalVolume() (float64, error) {
 }
 
 // GetAllWithFilters returns transactions with pagination and filters
-func (r *TransactionRepository) GetAllWithFilters(limit, offset int, typeFilter, search string) ([]models.Transaction, int64, error) {
+// userID 0 means all users
+func (r *TransactionRepository) GetAllWithFilters(limit, offset int, typeFilter, search string, userID uint) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
 	var total int64
 
@@ -34,6 +43,10 @@ func (r *TransactionRepository) GetAllWithFilters(limit, offset int, typeFilter,
 		query = query.Where("type = ?", typeFilter)
 	}
 
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
 	if search != "" {
 		// Join with users to search by user name if needed (assuming user_name is stored or relatable)
 		// Since Transaction model usually has UserID, we might need to join or assume description search
@@ -61,3 +74,171 @@ func (r *TransactionRepository) GetAllWithFilters(limit, offset int, typeFilter,
 
 	return transactions, total, err
 }
+
+// CreateWithBalance records a transaction and applies its amount to the user's
+// credit balance in one database transaction.
+// BalanceBefore and BalanceAfter are taken from the locked user row, so
+// concurrent postings cannot leave the ledger and users.credit_balance out of sync.
+func (r *TransactionRepository) CreateWithBalance(transaction *models.Transaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		_, err := postTransaction(tx, transaction)
+		return err
+	})
+}
+
+// postTransaction applies a ledger transaction to the (locked) user row inside tx
+// and returns the updated user. Callers are responsible for balance validation.
+// A credit that leaves the user above the balance cap moves the overflow to the
+// community pool in the same database transaction (see SetBalanceCap).
+func postTransaction(tx *gorm.DB, transaction *models.Transaction) (*models.User, error) {
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&user, transaction.UserID).Error; err != nil {
+		return nil, err
+	}
+
+	transaction.BalanceBefore = user.CreditBalance
+	transaction.BalanceAfter = user.CreditBalance + transaction.Amount
+	user.CreditBalance = transaction.BalanceAfter
+
+	if err := tx.Model(&models.User{}).
+		Where("id = ?", user.ID).
+		Update("credit_balance", user.CreditBalance).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Create(transaction).Error; err != nil {
+		return nil, err
+	}
+
+	if transaction.Amount > 0 {
+		if _, err := applyBalanceCapLocked(tx, &user); err != nil {
+			return nil, fmt.Errorf("apply balance cap: %w", err)
+		}
+	}
+	return &user, nil
+}
+
+// Transfer moves credits from one user's available balance to another's in one database transaction
+//
+// Returns the sender's debit and the recipient's credit ledger entries, or
+// utils.ErrInsufficientCredits when the sender's available balance (balance minus escrow and frozen earnings) is too low.
+func (r *TransactionRepository) Transfer(
+	senderID uint,
+	recipientID uint,
+	amount float64,
+	senderDescription string,
+	recipientDescription string,
+	metadata string,
+) (debit *models.Transaction, credit *models.Transaction, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var txErr error
+		debit, credit, txErr = transferLocked(tx, senderID, recipientID, amount, senderDescription, recipientDescription, metadata)
+		return txErr
+	})
+	return debit, credit, err
+}
+
+// transferLocked locks both users (in ID order, so opposite transfers cannot deadlock),
+// checks the sender's available balance and posts the debit and credit inside tx
+func transferLocked(
+	tx *gorm.DB,
+	senderID uint,
+	recipientID uint,
+	amount float64,
+	senderDescription string,
+	recipientDescription string,
+	metadata string,
+) (*models.Transaction, *models.Transaction, error) {
+	var users []models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", []uint{senderID, recipientID}).
+		Order("id ASC").
+		Find(&users).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(users) != 2 {
+		return nil, nil, utils.ErrUserNotFound
+	}
+
+	for _, user := range users {
+		if user.ID == senderID && user.AvailableCredits() < amount {
+			return nil, nil, utils.ErrInsufficientCredits
+		}
+	}
+
+	// Both ledger lines name both parties, so transfers can be traced (e.g. by fraud detection)
+	metadata, err := withTransferParties(metadata, senderID, recipientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	debit := &models.Transaction{
+		UserID:      senderID,
+		Type:        models.TransactionSpent,
+		Amount:      -amount,
+		Description: senderDescription,
+		Metadata:    metadata,
+	}
+	if _, err := postTransaction(tx, debit); err != nil {
+		return nil, nil, err
+	}
+
+	credit := &models.Transaction{
+		UserID:      recipientID,
+		Type:        models.TransactionBonus, // Peer transfers are recorded as bonus for the recipient
+		Amount:      amount,
+		Description: recipientDescription,
+		Metadata:    metadata,
+	}
+	if _, err := postTransaction(tx, credit); err != nil {
+		return nil, nil, err
+	}
+
+	return debit, credit, nil
+}
+
+// withTransferParties adds sender_id and recipient_id to a transfer's JSON metadata
+func withTransferParties(metadata string, senderID, recipientID uint) (string, error) {
+	fields := map[string]interface{}{}
+	if metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &fields); err != nil {
+			return "", err
+		}
+	}
+	fields["sender_id"] = senderID
+	fields["recipient_id"] = recipientID
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// GetUserTransactionsBetween returns a user's transactions in [from, to), oldest first,
+// with the related session preloaded for statements
+func (r *TransactionRepository) GetUserTransactionsBetween(userID uint, from, to time.Time) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := r.db.Preload("Session").
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Order("created_at ASC, id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// GetLastBefore returns the user's most recent transaction before the given time
+// Returns nil without error if there is none
+func (r *TransactionRepository) GetLastBefore(userID uint, before time.Time) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.Where("user_id = ? AND created_at < ?", userID, before).
+		Order("created_at DESC, id DESC").
+		First(&transaction).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
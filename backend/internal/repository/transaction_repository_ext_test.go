@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+func expectLockedUser(mock sqlmock.Sqlmock, userID uint, balance float64) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users"`)+`.*FOR UPDATE`).
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "credit_balance"}).AddRow(userID, balance))
+}
+
+func expectInsert(mock sqlmock.Sqlmock, table string, id uint) {
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "` + table + `"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+}
+
+func TestCreateWithBalanceMovesOverflowToPool(t *testing.T) {
+	SetBalanceCap(100)
+	t.Cleanup(func() { SetBalanceCap(0) })
+
+	db, mock := newMockDB(t)
+	repo := NewTransactionRepository(db)
+
+	mock.ExpectBegin()
+	// The grant itself: 90 -> 120
+	expectLockedUser(mock, 7, 90)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "credit_balance"=$1`)).
+		WithArgs(120.0, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectInsert(mock, "transactions", 1)
+
+	// The 20 credits above the cap move to the pool
+	expectInsert(mock, "credit_policy_notices", 2)
+	expectLockedUser(mock, 7, 120)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "credit_balance"=$1`)).
+		WithArgs(100.0, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectInsert(mock, "transactions", 3)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "community_pools"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "community_pools"`) + `.*FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "balance", "total_in"}).AddRow(models.CommunityPoolID, 5.0, 5.0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "community_pools"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectInsert(mock, "community_pool_entries", 4)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "credit_policy_notices" SET "transaction_id"=$1`)).
+		WithArgs(3, sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectInsert(mock, "notifications", 5)
+	mock.ExpectCommit()
+
+	transaction := &models.Transaction{UserID: 7, Type: models.TransactionGrant, Amount: 30}
+	require.NoError(t, repo.CreateWithBalance(transaction))
+	assert.Equal(t, 120.0, transaction.BalanceAfter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateWithBalanceSkipsCapForDebits(t *testing.T) {
+	SetBalanceCap(100)
+	t.Cleanup(func() { SetBalanceCap(0) })
+
+	db, mock := newMockDB(t)
+	repo := NewTransactionRepository(db)
+
+	mock.ExpectBegin()
+	expectLockedUser(mock, 7, 150)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "credit_balance"=$1`)).
+		WithArgs(140.0, sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectInsert(mock, "transactions", 1)
+	mock.ExpectCommit()
+
+	transaction := &models.Transaction{UserID: 7, Type: models.TransactionSpent, Amount: -10}
+	require.NoError(t, repo.CreateWithBalance(transaction))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"github.com/timebankingskill/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CountTotal counts all users
@@ -47,3 +49,41 @@ func (r *UserRepository) GetAllWithFilters(limit, offset int, search, status str
 	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&users).Error
 	return users, total, err
 }
+
+// ReleaseFrozenPayouts lifts a user's payout freeze and makes the frozen earnings spendable
+func (r *UserRepository) ReleaseFrozenPayouts(userID uint) (*models.User, error) {
+	var user models.User
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&user, userID).Error; err != nil {
+			return err
+		}
+		return unfreezePayouts(tx, &user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// unfreezePayouts releases the frozen credits of a user row locked by the transaction
+func unfreezePayouts(tx *gorm.DB, user *models.User) error {
+	user.FrozenCredits = 0
+	user.PayoutsFrozen = false
+
+	return tx.Model(user).Updates(map[string]interface{}{
+		"frozen_credits": 0,
+		"payouts_frozen": false,
+	}).Error
+}
+
+// GetActiveBatch returns up to limit active users with an ID above afterID, in ID order
+// Pass the last ID of a batch to get the next one.
+func (r *UserRepository) GetActiveBatch(afterID uint, limit int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("is_active = ? AND id > ?", true, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
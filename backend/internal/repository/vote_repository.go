@@ -153,3 +153,72 @@ func (r *VoteRepository) GetStoryLikeCount(storyID uint) (int64, error) {
 	}
 	return int64(story.LikeCount), nil
 }
+
+// ─────────────────────────────────────────────
+// REVIEW HELPFUL
+// ─────────────────────────────────────────────
+
+// ToggleReviewHelpful atomically inserts or deletes a helpful vote and keeps the
+// reviews.helpful_count denormalized counter in sync.
+// The counter is updated without touching updated_at, which marks review edits.
+// Returns (helpful, newCount, error).
+func (r *VoteRepository) ToggleReviewHelpful(userID, reviewID uint) (helpful bool, newCount int64, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// Try to delete first (un-vote path)
+		result := tx.Where("user_id = ? AND review_id = ?", userID, reviewID).
+			Delete(&models.ReviewHelpfulVote{})
+		if result.Error != nil {
+			return fmt.Errorf("toggle helpful delete: %w", result.Error)
+		}
+
+		if result.RowsAffected > 0 {
+			// Was voted → now removed
+			helpful = false
+			if err2 := tx.Model(&models.Review{}).
+				Where("id = ? AND helpful_count > 0", reviewID).
+				UpdateColumn("helpful_count", gorm.Expr("helpful_count - 1")).Error; err2 != nil {
+				return fmt.Errorf("decrement helpful_count: %w", err2)
+			}
+		} else {
+			// Not voted yet → insert
+			vote := &models.ReviewHelpfulVote{UserID: userID, ReviewID: reviewID}
+			if err2 := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(vote).Error; err2 != nil {
+				return fmt.Errorf("insert helpful vote: %w", err2)
+			}
+			helpful = true
+			if err2 := tx.Model(&models.Review{}).
+				Where("id = ?", reviewID).
+				UpdateColumn("helpful_count", gorm.Expr("helpful_count + 1")).Error; err2 != nil {
+				return fmt.Errorf("increment helpful_count: %w", err2)
+			}
+		}
+
+		// Fetch the latest count
+		var review models.Review
+		if err2 := tx.Select("helpful_count").First(&review, reviewID).Error; err2 != nil {
+			return fmt.Errorf("fetch helpful_count: %w", err2)
+		}
+		newCount = int64(review.HelpfulCount)
+		return nil
+	})
+	return helpful, newCount, err
+}
+
+// HasMarkedReviewHelpful checks whether a user has already marked a review helpful.
+func (r *VoteRepository) HasMarkedReviewHelpful(userID, reviewID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ReviewHelpfulVote{}).
+		Where("user_id = ? AND review_id = ?", userID, reviewID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetReviewHelpfulCount returns the current helpful count for a review.
+func (r *VoteRepository) GetReviewHelpfulCount(reviewID uint) (int64, error) {
+	var review models.Review
+	if err := r.db.Select("helpful_count").First(&review, reviewID).Error; err != nil {
+		return 0, err
+	}
+	return int64(review.HelpfulCount), nil
+}
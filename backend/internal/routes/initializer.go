@@ -3,10 +3,13 @@ package routes
 import (
 
 	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/events"
 	"github.com/timebankingskill/backend/internal/handler"
 	"github.com/timebankingskill/backend/internal/middleware"
 	"github.com/timebankingskill/backend/internal/repository"
 	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+	"github.com/timebankingskill/backend/internal/webpush"
 	"github.com/timebankingskill/backend/internal/websocket"
 	"gorm.io/gorm"
 )
@@ -42,11 +45,13 @@ func InitializeSkillHandler(db *gorm.DB) *handler.SkillHandler {
 }
 
 // InitializeUserHandler initializes user handler with dependencies
-// Includes session repository for calculating teaching/learning hours
+// Includes session repository for calculating teaching/learning hours and review tag
+// repository for profile tag clouds
 func InitializeUserHandler(db *gorm.DB) *handler.UserHandler {
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
-	userService := service.NewUserServiceWithSession(userRepo, sessionRepo)
+	tagRepo := repository.NewReviewTagRepository(db)
+	userService := service.NewUserServiceWithSession(userRepo, sessionRepo, tagRepo)
 	return handler.NewUserHandler(userService)
 }
 
@@ -61,7 +66,8 @@ func InitializeTransactionHandler(db *gorm.DB) *handler.TransactionHandler {
 }
 
 // InitializeSessionHandler initializes session handler with dependencies
-func InitializeSessionHandler(db *gorm.DB) *handler.SessionHandler {
+// Session completion enforces the credit balance cap through the credit policy service
+func InitializeSessionHandler(db *gorm.DB, cfg *config.Config) *handler.SessionHandler {
 	sessionRepo := repository.NewSessionRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	skillRepo := repository.NewSkillRepository(db)
@@ -69,33 +75,41 @@ func InitializeSessionHandler(db *gorm.DB) *handler.SessionHandler {
 	notificationRepo := repository.NewNotificationRepository(db)
 	notificationService := service.NewNotificationService(notificationRepo, userRepo)
 	badgeRepo := repository.NewBadgeRepository(db)
-	badgeService := service.NewBadgeService(badgeRepo, userRepo, sessionRepo, notificationService)
+	leaderboardRepo := repository.NewLeaderboardRepository(db)
+	badgeService := service.NewBadgeService(badgeRepo, userRepo, sessionRepo, transactionRepo, leaderboardRepo, notificationService)
 
-	sessionService := service.NewSessionService(
+	statsRepo := repository.NewStatsRepository(db)
+
+	sessionService := service.NewSessionServiceWithCreditPolicy(
 		db,
 		sessionRepo,
 		userRepo,
 		transactionRepo,
 		skillRepo,
+		statsRepo,
 		badgeService,
 		notificationService,
+		InitializeCreditPolicyService(db, cfg),
 	)
 	return handler.NewSessionHandler(sessionService)
 }
 
-// InitializeReviewHandler initializes review handler with dependencies
-func InitializeReviewHandler(db *gorm.DB) *handler.ReviewHandler {
+// InitializeReviewService initializes the review service with dependencies
+func InitializeReviewService(db *gorm.DB, cfg *config.Config) *service.ReviewService {
 	reviewRepo := repository.NewReviewRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	tagRepo := repository.NewReviewTagRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
 	notificationService := service.NewNotificationService(notificationRepo, userRepo)
-	
-	badgeRepo := repository.NewBadgeRepository(db)
-	badgeService := service.NewBadgeService(badgeRepo, userRepo, sessionRepo, notificationService)
 
-	reviewService := service.NewReviewService(reviewRepo, sessionRepo, userRepo, notificationService, badgeService)
-	return handler.NewReviewHandler(reviewService)
+	return service.NewReviewService(reviewRepo, sessionRepo, userRepo, reportRepo, tagRepo, notificationService, cfg.Reviews)
+}
+
+// InitializeReviewHandler initializes review handler with dependencies
+func InitializeReviewHandler(db *gorm.DB, cfg *config.Config) *handler.ReviewHandler {
+	return handler.NewReviewHandler(InitializeReviewService(db, cfg))
 }
 
 // InitializeBadgeHandler initializes badge handler with dependencies
@@ -103,12 +117,83 @@ func InitializeBadgeHandler(db *gorm.DB) *handler.BadgeHandler {
 	badgeRepo := repository.NewBadgeRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
 	notificationService := service.NewNotificationService(notificationRepo, userRepo)
-	badgeService := service.NewBadgeService(badgeRepo, userRepo, sessionRepo, notificationService)
+	leaderboardRepo := repository.NewLeaderboardRepository(db)
+	badgeService := service.NewBadgeService(badgeRepo, userRepo, sessionRepo, transactionRepo, leaderboardRepo, notificationService)
 	return handler.NewBadgeHandler(badgeService)
 }
 
+// InitializeBadgeEventProcessor initializes the processor that awards badges from domain events
+func InitializeBadgeEventProcessor(db *gorm.DB, cfg *config.Config) *service.BadgeEventProcessor {
+	badgeRepo := repository.NewBadgeRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	leaderboardRepo := repository.NewLeaderboardRepository(db)
+	badgeService := service.NewBadgeService(badgeRepo, userRepo, sessionRepo, transactionRepo, leaderboardRepo, notificationService)
+	return service.NewBadgeEventProcessor(badgeService, events.Default, cfg.Badges.EvaluationInterval)
+}
+
+// InitializeNotificationEmailDispatcher initializes the notification email dispatcher
+func InitializeNotificationEmailDispatcher(db *gorm.DB, cfg *config.Config) *service.NotificationEmailDispatcher {
+	notificationRepo := repository.NewNotificationRepository(db)
+	return service.NewNotificationEmailDispatcher(notificationRepo, cfg.Email)
+}
+
+// InitializeNotificationPushDispatcher initializes the notification push dispatcher
+// Used by main to start the scheduler that pushes notifications to offline users' devices.
+// Returns an error if the VAPID keys are missing or invalid.
+func InitializeNotificationPushDispatcher(db *gorm.DB, cfg *config.Config) (*service.NotificationPushDispatcher, error) {
+	client, err := webpush.NewClient(cfg.Push.VAPIDPublicKey, cfg.Push.VAPIDPrivateKey, cfg.Push.VAPIDSubject)
+	if err != nil {
+		return nil, err
+	}
+	notificationRepo := repository.NewNotificationRepository(db)
+	return service.NewNotificationPushDispatcher(notificationRepo, client, cfg.Push, cfg.Email.Location), nil
+}
+
+// InitializePushHandler initializes the push subscription handler
+func InitializePushHandler(db *gorm.DB, cfg *config.Config) *handler.PushHandler {
+	notificationRepo := repository.NewNotificationRepository(db)
+	return handler.NewPushHandler(service.NewPushService(notificationRepo, cfg.Push))
+}
+
+// InitializeDigestService initializes the weekly digest service
+// Also used by main to start the scheduler that sends due digests
+func InitializeDigestService(db *gorm.DB, cfg *config.Config) *service.DigestService {
+	digestRepo := repository.NewDigestRepository(db)
+	return service.NewDigestService(digestRepo, cfg.Digest, cfg.Email.Location)
+}
+
+// InitializeDigestHandler initializes digest handler with dependencies
+func InitializeDigestHandler(db *gorm.DB, cfg *config.Config) *handler.DigestHandler {
+	return handler.NewDigestHandler(InitializeDigestService(db, cfg))
+}
+
+// InitializeChallengeService initializes the challenge service with dependencies
+// Also used by main to start the scheduler that updates progress from domain events
+func InitializeChallengeService(db *gorm.DB, cfg *config.Config) *service.ChallengeService {
+	challengeRepo := repository.NewChallengeRepository(db)
+	badgeRepo := repository.NewBadgeRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	leaderboardRepo := repository.NewLeaderboardRepository(db)
+	badgeService := service.NewBadgeService(badgeRepo, userRepo, sessionRepo, transactionRepo, leaderboardRepo, notificationService)
+	return service.NewChallengeService(challengeRepo, userRepo, transactionRepo, badgeService, notificationService, events.Default, cfg.Challenges.EvaluationInterval)
+}
+
+// InitializeChallengeHandler initializes challenge handler with dependencies
+func InitializeChallengeHandler(db *gorm.DB, cfg *config.Config) *handler.ChallengeHandler {
+	return handler.NewChallengeHandler(InitializeChallengeService(db, cfg))
+}
+
 // InitializeNotificationHandler initializes notification handler with dependencies
 func InitializeNotificationHandler(db *gorm.DB) *handler.NotificationHandler {
 	notificationRepo := repository.NewNotificationRepository(db)
@@ -143,8 +228,9 @@ func InitializeEndorsementHandler(db *gorm.DB) *handler.EndorsementHandler {
 	userRepo := repository.NewUserRepository(db)
 	skillRepo := repository.NewSkillRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
+	poolRepo := repository.NewCommunityPoolRepository(db)
 	notificationService := service.NewNotificationService(notificationRepo, userRepo)
-	endorsementService := service.NewEndorsementServiceWithNotification(endorsementRepo, userRepo, skillRepo, notificationService)
+	endorsementService := service.NewEndorsementServiceWithNotification(endorsementRepo, userRepo, skillRepo, poolRepo, notificationService)
 	return handler.NewEndorsementHandler(endorsementService)
 }
 
@@ -211,6 +297,14 @@ func InitializeNotificationWebSocket(db *gorm.DB) *websocket.NotificationWebSock
 	return websocket.NewNotificationWebSocket(notificationService)
 }
 
+// InitializeNotificationStream initializes the Server-Sent Events notification stream
+func InitializeNotificationStream(db *gorm.DB) *websocket.NotificationStream {
+	notificationRepo := repository.NewNotificationRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	return websocket.NewNotificationStream(notificationService, utils.GetWSTokenStore())
+}
+
 // InitializeAvailabilityHandler initializes availability handler with dependencies
 func InitializeAvailabilityHandler(db *gorm.DB) *handler.AvailabilityHandler {
 	availabilityRepo := repository.NewAvailabilityRepository(db)
@@ -233,11 +327,76 @@ func InitializeTemplateHandler(db *gorm.DB) *handler.TemplateHandler {
 }
 
 // InitializeVoteHandler initializes vote handler with dependencies
-// handles forum thread upvotes and success story likes
+// handles forum thread upvotes, success story likes and helpful review votes
 func InitializeVoteHandler(db *gorm.DB) *handler.VoteHandler {
 	voteRepo := repository.NewVoteRepository(db)
 	forumRepo := repository.NewForumRepository(db)
 	storyRepo := repository.NewStoryRepository(db)
-	voteService := service.NewVoteService(voteRepo, forumRepo, storyRepo)
+	reviewRepo := repository.NewReviewRepository(db)
+	voteService := service.NewVoteService(voteRepo, forumRepo, storyRepo, reviewRepo)
 	return handler.NewVoteHandler(voteService)
 }
+
+// InitializeCreditPolicyService initializes the credit policy service with dependencies
+// Also used by main to start the periodic decay/expiry job
+func InitializeCreditPolicyService(db *gorm.DB, cfg *config.Config) *service.CreditPolicyService {
+	policyRepo := repository.NewCreditPolicyRepository(db)
+	poolRepo := repository.NewCommunityPoolRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	return service.NewCreditPolicyService(cfg.CreditPolicy, policyRepo, poolRepo, userRepo, notificationService)
+}
+
+// InitializeCreditPolicyHandler initializes credit policy handler with dependencies
+func InitializeCreditPolicyHandler(db *gorm.DB, cfg *config.Config) *handler.CreditPolicyHandler {
+	return handler.NewCreditPolicyHandler(InitializeCreditPolicyService(db, cfg))
+}
+
+// InitializeCommunityPoolService initializes the community pool service with dependencies
+// Also used by main to start the automatic grant job
+func InitializeCommunityPoolService(db *gorm.DB, cfg *config.Config) *service.CommunityPoolService {
+	poolRepo := repository.NewCommunityPoolRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	return service.NewCommunityPoolService(cfg.CommunityPool, poolRepo, userRepo, notificationService)
+}
+
+// InitializeCommunityPoolHandler initializes community pool handler with dependencies
+func InitializeCommunityPoolHandler(db *gorm.DB, cfg *config.Config) *handler.CommunityPoolHandler {
+	return handler.NewCommunityPoolHandler(InitializeCommunityPoolService(db, cfg))
+}
+
+// InitializeCreditRequestHandler initializes credit request handler with dependencies
+func InitializeCreditRequestHandler(db *gorm.DB) *handler.CreditRequestHandler {
+	requestRepo := repository.NewCreditRequestRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	requestService := service.NewCreditRequestService(requestRepo, userRepo, notificationService)
+	return handler.NewCreditRequestHandler(requestService)
+}
+
+// InitializeCreditAdjustmentHandler initializes admin credit adjustment handler with dependencies
+func InitializeCreditAdjustmentHandler(db *gorm.DB) *handler.CreditAdjustmentHandler {
+	adjustmentRepo := repository.NewCreditAdjustmentRepository(db)
+	adminRepo := repository.NewAdminRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	adjustmentService := service.NewCreditAdjustmentService(adjustmentRepo, adminRepo, userRepo, notificationService)
+	return handler.NewCreditAdjustmentHandler(adjustmentService)
+}
+
+// InitializeFraudDetectionService initializes the fraud detection service with dependencies
+// Also used by main to start the periodic detection job
+func InitializeFraudDetectionService(db *gorm.DB, cfg *config.Config) *service.FraudDetectionService {
+	fraudRepo := repository.NewFraudRepository(db)
+	return service.NewFraudDetectionService(cfg.Fraud, fraudRepo)
+}
+
+// InitializeFraudHandler initializes fraud detection handler with dependencies
+func InitializeFraudHandler(db *gorm.DB, cfg *config.Config) *handler.FraudHandler {
+	return handler.NewFraudHandler(InitializeFraudDetectionService(db, cfg))
+}
@@ -38,10 +38,12 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	skillHandler := InitializeSkillHandler(db)
 	userHandler := InitializeUserHandler(db)
 	transactionHandler := InitializeTransactionHandler(db)
-	sessionHandler := InitializeSessionHandler(db)
-	reviewHandler := InitializeReviewHandler(db)
+	sessionHandler := InitializeSessionHandler(db, cfg)
+	reviewHandler := InitializeReviewHandler(db, cfg)
 	badgeHandler := InitializeBadgeHandler(db)
 	notificationHandler := InitializeNotificationHandler(db)
+	digestHandler := InitializeDigestHandler(db, cfg)
+	pushHandler := InitializePushHandler(db, cfg)
 	forumHandler := InitializeForumHandler(db)
 	storyHandler := InitializeStoryHandler(db)
 	endorsementHandler := InitializeEndorsementHandler(db)
@@ -54,6 +56,12 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	favoriteHandler := InitializeFavoriteHandler(db)
 	templateHandler := InitializeTemplateHandler(db)
 	voteHandler := InitializeVoteHandler(db)
+	creditPolicyHandler := InitializeCreditPolicyHandler(db, cfg)
+	communityPoolHandler := InitializeCommunityPoolHandler(db, cfg)
+	creditRequestHandler := InitializeCreditRequestHandler(db)
+	creditAdjustmentHandler := InitializeCreditAdjustmentHandler(db)
+	fraudHandler := InitializeFraudHandler(db, cfg)
+	challengeHandler := InitializeChallengeHandler(db, cfg)
 
 	// Initialize repository for IDOR middleware
 	sessionRepo := repository.NewSessionRepository(db)
@@ -162,6 +170,9 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 	notificationWS := InitializeNotificationWebSocket(db)
 	router.GET("/api/v1/ws/notifications", notificationWS.HandleConnection)
 
+	// Notification SSE stream (fallback where WebSocket upgrades are blocked, token auth)
+	notificationStream := InitializeNotificationStream(db)
+
 	// API v1 group
 	v1 := router.Group("/api/v1")
 	v1.Use(middleware.RateLimitMiddleware(60)) // Default limit: 60 req/min
@@ -204,10 +215,10 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 		// Public Skills routes
 		skills := v1.Group("/skills")
 		{
-			skills.GET("", skillHandler.GetSkills)                     // GET /api/v1/skills?limit=10&page=1&category=&search=
+			skills.GET("", skillHandler.GetSkills)                     // GET /api/v1/skills?limit=10&page=1&category=&search=&tag=
 			skills.GET("/recommended", skillHandler.GetRecommendedSkills) // GET /api/v1/skills/recommended
 			skills.GET("/user-skills/:id", skillHandler.GetUserSkillByID) // GET /api/v1/skills/user-skills/1
-			skills.GET("/:id/teachers", skillHandler.GetSkillTeachers) // GET /api/v1/skills/1/teachers
+			skills.GET("/:id/teachers", skillHandler.GetSkillTeachers) // GET /api/v1/skills/1/teachers?tag=explains-clearly
 			skills.GET("/:id", skillHandler.GetSkillByID)              // GET /api/v1/skills/1
 		}
 
@@ -216,13 +227,26 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 		{
 			publicUsers.GET("/@:username", userHandler.GetPublicProfileByUsername) // GET /api/v1/users/@johndoe (must be before /:id)
 			publicUsers.GET("/:id/profile", userHandler.GetPublicProfile)          // GET /api/v1/users/1/profile
-			publicUsers.GET("/:id/reviews", reviewHandler.GetUserReviews)          // GET /api/v1/users/1/reviews
+			publicUsers.GET("/:id/reviews", reviewHandler.GetUserReviews)          // GET /api/v1/users/1/reviews?sort=helpful
 			publicUsers.GET("/:id/reviews/:type", reviewHandler.GetUserReviewsByType) // GET /api/v1/users/1/reviews/teacher
 			publicUsers.GET("/:id/rating-summary", reviewHandler.GetUserRatingSummary) // GET /api/v1/users/1/rating-summary
 			publicUsers.GET("/:id/availability", availabilityHandler.GetUserAvailability) // GET /api/v1/users/1/availability
 			publicUsers.GET("/:id/availability/check", availabilityHandler.CheckAvailability) // GET /api/v1/users/1/availability/check?day=1&time=14:00
 		}
 
+		// Weekly digest unsubscribe (public: authenticated by the token in the link)
+		digest := v1.Group("/digest")
+		{
+			digest.GET("/unsubscribe", middleware.RateLimitMiddleware(10), digestHandler.ConfirmUnsubscribe) // GET /api/v1/digest/unsubscribe?token=...
+			digest.POST("/unsubscribe", middleware.RateLimitMiddleware(10), digestHandler.Unsubscribe)        // POST /api/v1/digest/unsubscribe?token=...
+		}
+
+		// Notification stream (public: authenticated by a single-use stream token)
+		v1.GET("/notifications/stream", notificationStream.HandleStream) // GET /api/v1/notifications/stream?token=...&since=123
+
+		// Public review tag vocabulary
+		v1.GET("/review-tags", reviewHandler.GetReviewTags) // GET /api/v1/review-tags?type=teacher
+
 		// Public Badges
 		badges := v1.Group("/badges")
 		{
@@ -240,6 +264,9 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 			endorsements.GET("/user/:user_id/reputation", endorsementHandler.GetUserReputation) // GET /api/v1/endorsements/user/:user_id/reputation
 		}
 
+		// Public Community Pool
+		v1.GET("/community-pool/stats", communityPoolHandler.GetStats) // GET /api/v1/community-pool/stats
+
 		// Public Forum
 		publicForum := v1.Group("/forum")
 		{
@@ -265,7 +292,7 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 		// Public Leaderboards
 		leaderboards := v1.Group("/leaderboard")
 		{
-			leaderboards.GET("/badges", badgeHandler.GetBadgeLeaderboard)       // GET /api/v1/leaderboard/badges
+			leaderboards.GET("/badges", badgeHandler.GetBadgeLeaderboard)       // GET /api/v1/leaderboard/badges?period=week&school=SMAN 1
 			leaderboards.GET("/rarity", badgeHandler.GetRarityLeaderboard)      // GET /api/v1/leaderboard/rarity
 			leaderboards.GET("/sessions", badgeHandler.GetSessionLeaderboard)   // GET /api/v1/leaderboard/sessions
 			leaderboards.GET("/rating", badgeHandler.GetRatingLeaderboard)      // GET /api/v1/leaderboard/rating
@@ -300,6 +327,16 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 				user.GET("/transactions", transactionHandler.GetUserTransactions)    // GET /api/v1/user/transactions
 				user.GET("/transactions/:id", transactionHandler.GetTransactionByID) // GET /api/v1/user/transactions/1
 				user.POST("/transfer", transactionHandler.TransferCredits)           // POST /api/v1/user/transfer
+				user.GET("/statements/:month", transactionHandler.DownloadStatement) // GET /api/v1/user/statements/2026-01?format=pdf
+				user.GET("/credit-notices", creditPolicyHandler.GetMyNotices)        // GET /api/v1/user/credit-notices
+
+				// Credit requests (ask another user for credits)
+				user.POST("/credit-requests", creditRequestHandler.CreateRequest)               // POST /api/v1/user/credit-requests
+				user.GET("/credit-requests/incoming", creditRequestHandler.GetIncoming)         // GET /api/v1/user/credit-requests/incoming
+				user.GET("/credit-requests/outgoing", creditRequestHandler.GetOutgoing)         // GET /api/v1/user/credit-requests/outgoing
+				user.POST("/credit-requests/:id/accept", creditRequestHandler.AcceptRequest)    // POST /api/v1/user/credit-requests/1/accept
+				user.POST("/credit-requests/:id/decline", creditRequestHandler.DeclineRequest)  // POST /api/v1/user/credit-requests/1/decline
+				user.POST("/credit-requests/:id/cancel", creditRequestHandler.CancelRequest)    // POST /api/v1/user/credit-requests/1/cancel
 
 				// Video Session Management
 				user.GET("/video-history", videoSessionHandler.GetVideoHistory)      // GET /api/v1/user/video-history
@@ -311,16 +348,19 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 				user.DELETE("/availability", availabilityHandler.ClearMyAvailability) // DELETE /api/v1/user/availability
 			}
 
-			// Admin Skills Management (future: add admin middleware)
-			adminSkills := protected.Group("/admin/skills")
+			// Admin routes require an active admin account, not just a valid token
+			requireAdmin := middleware.RequireActiveAdmin(repository.NewAdminRepository(db))
+
+			// Admin Skills Management
+			adminSkills := protected.Group("/admin/skills", requireAdmin)
 			{
 				adminSkills.POST("", skillHandler.CreateSkill)       // POST /api/v1/admin/skills
 				adminSkills.PUT("/:id", skillHandler.UpdateSkill)    // PUT /api/v1/admin/skills/1
 				adminSkills.DELETE("/:id", skillHandler.DeleteSkill) // DELETE /api/v1/admin/skills/1
 			}
 
-			// Admin Session Management (requires admin middleware)
-			adminProtected := protected.Group("/admin")
+			// Admin Session Management
+			adminProtected := protected.Group("/admin", requireAdmin)
 			{
 				adminProtected.GET("/users", adminHandler.GetAllUsers)               // GET /api/v1/admin/users
 				adminProtected.GET("/sessions", adminHandler.GetAllSessions)         // GET /api/v1/admin/sessions
@@ -331,6 +371,8 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 				
 				adminProtected.POST("/users/:id/suspend", adminHandler.SuspendUser)   // POST /api/v1/admin/users/:id/suspend
 				adminProtected.POST("/users/:id/activate", adminHandler.ActivateUser) // POST /api/v1/admin/users/:id/activate
+				adminProtected.POST("/users/:id/release-payouts", adminHandler.ReleasePayouts) // POST /api/v1/admin/users/:id/release-payouts
+				adminProtected.POST("/fraud/run", fraudHandler.RunDetection)                   // POST /api/v1/admin/fraud/run
 				
 				adminProtected.POST("/sessions/:id/resolve", sessionHandler.AdminResolveSession) // POST /api/v1/admin/sessions/:id/resolve
 				adminProtected.POST("/sessions/:id/approve", sessionHandler.AdminApproveSession) // POST /api/v1/admin/sessions/:id/approve
@@ -340,10 +382,52 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 				// Admin Report Management
 				adminProtected.POST("/reports/:id/resolve", adminHandler.ResolveReport) // POST /api/v1/admin/reports/:id/resolve
 				adminProtected.POST("/reports/:id/dismiss", adminHandler.DismissReport) // POST /api/v1/admin/reports/:id/dismiss
+
+				// Admin Review Moderation
+				adminProtected.GET("/reviews/moderation", reviewHandler.GetModerationQueue)         // GET /api/v1/admin/reviews/moderation?status=reported
+				adminProtected.POST("/reviews/:id/hide", reviewHandler.HideReview)                  // POST /api/v1/admin/reviews/1/hide
+				adminProtected.POST("/reviews/:id/unhide", reviewHandler.UnhideReview)              // POST /api/v1/admin/reviews/1/unhide
+				adminProtected.POST("/reviews/:id/dismiss", reviewHandler.DismissReviewReports)     // POST /api/v1/admin/reviews/1/dismiss
+				adminProtected.GET("/reviews/:id/moderation-log", reviewHandler.GetModerationLog)   // GET /api/v1/admin/reviews/1/moderation-log
+
+				// Review tag vocabulary
+				adminProtected.GET("/review-tags", reviewHandler.GetAllReviewTags)          // GET /api/v1/admin/review-tags?type=teacher
+				adminProtected.POST("/review-tags", reviewHandler.CreateReviewTag)          // POST /api/v1/admin/review-tags
+				adminProtected.PUT("/review-tags/:id", reviewHandler.UpdateReviewTag)       // PUT /api/v1/admin/review-tags/1
+				adminProtected.DELETE("/review-tags/:id", reviewHandler.DeleteReviewTag)    // DELETE /api/v1/admin/review-tags/1
 				
 				// Admin Badge Management
 				adminProtected.GET("/badges", badgeHandler.GetAllBadges)      // GET /api/v1/admin/badges (reuse public/list handler or make admin specific)
+				adminProtected.GET("/badges/metrics", badgeHandler.GetBadgeMetrics) // GET /api/v1/admin/badges/metrics
+				adminProtected.POST("/badges", badgeHandler.CreateBadge)            // POST /api/v1/admin/badges
+				adminProtected.POST("/badges/dry-run", badgeHandler.DryRunBadge)    // POST /api/v1/admin/badges/dry-run
+				adminProtected.PUT("/badges/:id", badgeHandler.UpdateBadge)         // PUT /api/v1/admin/badges/1
 				adminProtected.DELETE("/badges/:id", badgeHandler.DeleteBadge) // DELETE /api/v1/admin/badges/:id
+				adminProtected.POST("/badges/:id/revoke", badgeHandler.RevokeBadge)      // POST /api/v1/admin/badges/1/revoke
+				adminProtected.GET("/badges/revocations", badgeHandler.GetRevocations)   // GET /api/v1/admin/badges/revocations
+				adminProtected.GET("/badges/backfills/:id", badgeHandler.GetBackfill)    // GET /api/v1/admin/badges/backfills/1
+
+				// Admin Challenges
+				adminProtected.GET("/challenges", challengeHandler.GetAllChallenges)          // GET /api/v1/admin/challenges
+				adminProtected.POST("/challenges", challengeHandler.CreateChallenge)          // POST /api/v1/admin/challenges
+				adminProtected.PUT("/challenges/:id", challengeHandler.UpdateChallenge)       // PUT /api/v1/admin/challenges/1
+				adminProtected.DELETE("/challenges/:id", challengeHandler.DeleteChallenge)    // DELETE /api/v1/admin/challenges/1
+
+				// Admin Credit Policy & Community Pool
+				adminProtected.GET("/credit-policy", creditPolicyHandler.GetPolicy)                // GET /api/v1/admin/credit-policy
+				adminProtected.POST("/credit-policy/run", creditPolicyHandler.RunPolicies)         // POST /api/v1/admin/credit-policy/run
+				adminProtected.GET("/community-pool/entries", creditPolicyHandler.GetPoolEntries) // GET /api/v1/admin/community-pool/entries
+				adminProtected.POST("/community-pool/grants", communityPoolHandler.Grant)            // POST /api/v1/admin/community-pool/grants
+				adminProtected.POST("/community-pool/auto-grants/run", communityPoolHandler.RunAutoGrants) // POST /api/v1/admin/community-pool/auto-grants/run
+
+				// Credit adjustments (maker-checker: a second admin with manage_finance approves)
+				adminProtected.GET("/credit-adjustments", creditAdjustmentHandler.GetAdjustments)                  // GET /api/v1/admin/credit-adjustments
+				adminProtected.POST("/credit-adjustments", creditAdjustmentHandler.ProposeAdjustment)              // POST /api/v1/admin/credit-adjustments
+				adminProtected.POST("/credit-adjustments/:id/approve", creditAdjustmentHandler.ApproveAdjustment)  // POST /api/v1/admin/credit-adjustments/1/approve
+				adminProtected.POST("/credit-adjustments/:id/reject", creditAdjustmentHandler.RejectAdjustment)    // POST /api/v1/admin/credit-adjustments/1/reject
+
+				// Notification delivery monitoring
+				adminProtected.GET("/notifications/delivery-stats", notificationHandler.GetDeliveryStats) // GET /api/v1/admin/notifications/delivery-stats?hours=24
 			}
 
 			// Analytics Routes (Authenticated)
@@ -483,17 +567,40 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 				reviews.GET("/:id", reviewHandler.GetReview)              // GET /api/v1/reviews/:id - Get a review
 				reviews.PUT("/:id", reviewHandler.UpdateReview)           // PUT /api/v1/reviews/:id - Update a review
 				reviews.DELETE("/:id", reviewHandler.DeleteReview)        // DELETE /api/v1/reviews/:id - Delete a review
+				reviews.POST("/:id/report", reviewHandler.ReportReview)   // POST /api/v1/reviews/:id/report - Report a review
+				reviews.POST("/:id/helpful", voteHandler.ToggleReviewHelpful)   // POST /api/v1/reviews/:id/helpful - Toggle helpful vote
+				reviews.GET("/:id/helpful", voteHandler.GetReviewHelpfulStatus) // GET /api/v1/reviews/:id/helpful - Helpful vote status
+				reviews.POST("/:id/reply", reviewHandler.ReplyToReview)         // POST /api/v1/reviews/:id/reply - Reviewee replies
+				reviews.PUT("/:id/reply", reviewHandler.UpdateReply)            // PUT /api/v1/reviews/:id/reply - Edit the reply
+				reviews.DELETE("/:id/reply", reviewHandler.DeleteReply)         // DELETE /api/v1/reviews/:id/reply - Remove the reply
 			}
 
 			// User Badges routes
 			userBadges := protected.Group("/user/badges")
 			{
 				userBadges.GET("", badgeHandler.GetUserBadges)                    // GET /api/v1/user/badges
+				userBadges.GET("/progress", badgeHandler.GetBadgeProgress)        // GET /api/v1/user/badges/progress
 				userBadges.GET("/:type", badgeHandler.GetUserBadgesByType)        // GET /api/v1/user/badges/achievement
 				userBadges.POST("/check", badgeHandler.CheckAndAwardBadges)       // POST /api/v1/user/badges/check
 				userBadges.POST("/:id/pin", badgeHandler.PinBadge)                // POST /api/v1/user/badges/1/pin
 			}
 
+			// Leaderboard rank of the current user
+			myLeaderboard := protected.Group("/leaderboard")
+			{
+				myLeaderboard.GET("/me", badgeHandler.GetMyRank) // GET /api/v1/leaderboard/me?type=sessions&period=week
+			}
+
+			// Challenges routes
+			challenges := protected.Group("/challenges")
+			{
+				challenges.GET("", challengeHandler.GetChallenges)                     // GET /api/v1/challenges?status=active
+				challenges.GET("/:id", challengeHandler.GetChallenge)                  // GET /api/v1/challenges/1
+				challenges.GET("/:id/leaderboard", challengeHandler.GetLeaderboard)    // GET /api/v1/challenges/1/leaderboard
+				challenges.POST("/:id/join", challengeHandler.JoinChallenge)           // POST /api/v1/challenges/1/join
+				challenges.DELETE("/:id/join", challengeHandler.LeaveChallenge)        // DELETE /api/v1/challenges/1/join
+			}
+
 			// Notifications routes
 			notifications := protected.Group("/notifications")
 			{
@@ -502,6 +609,12 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 				notifications.GET("/unread/count", notificationHandler.GetUnreadCount)        // GET /api/v1/notifications/unread/count
 				notifications.GET("/type/:type", notificationHandler.GetNotificationsByType)  // GET /api/v1/notifications/type/session
 				notifications.GET("/preferences", notificationHandler.GetPreferences)         // GET /api/v1/notifications/preferences
+				notifications.GET("/digest", digestHandler.GetDigestPreview)                  // GET /api/v1/notifications/digest
+				notifications.POST("/stream-token", notificationHandler.IssueStreamToken)     // POST /api/v1/notifications/stream-token
+				notifications.GET("/push/public-key", pushHandler.GetPublicKey)               // GET /api/v1/notifications/push/public-key
+				notifications.GET("/push/subscriptions", pushHandler.GetSubscriptions)        // GET /api/v1/notifications/push/subscriptions
+				notifications.POST("/push/subscriptions", pushHandler.Subscribe)              // POST /api/v1/notifications/push/subscriptions
+				notifications.DELETE("/push/subscriptions", pushHandler.Unsubscribe)          // DELETE /api/v1/notifications/push/subscriptions
 				notifications.PUT("/:id/read", notificationHandler.MarkAsRead)                // PUT /api/v1/notifications/1/read
 				notifications.PUT("/preferences", notificationHandler.UpdatePreferences)      // PUT /api/v1/notifications/preferences
 				notifications.PUT("/read-all", notificationHandler.MarkAllAsRead)             // PUT /api/v1/notifications/read-all
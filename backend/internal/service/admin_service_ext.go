@@ -1,7 +1,10 @@
 package service
 
 import (
+	"encoding/json"
+
 	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
 )
 
 // GetAllUsers gets all users with filters (admin only)
@@ -17,7 +20,8 @@ func (s *AdminService) GetAllSessions(page, limit int, search, filter string) ([
 }
 
 // GetAllTransactions returns paginated transactions with filters
-func (s *AdminService) GetAllTransactions(page, limit int, typeFilter, search string) ([]models.Transaction, int64, error) {
+// Filter by type "adjustment" for the history of approved admin balance corrections
+func (s *AdminService) GetAllTransactions(page, limit int, typeFilter, search string, userID uint) ([]models.Transaction, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -26,7 +30,7 @@ func (s *AdminService) GetAllTransactions(page, limit int, typeFilter, search st
 	}
 	offset := (page - 1) * limit
 
-	transactions, total, err := s.transactionRepo.GetAllWithFilters(limit, offset, typeFilter, search)
+	transactions, total, err := s.transactionRepo.GetAllWithFilters(limit, offset, typeFilter, search, userID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -46,7 +50,7 @@ func (s *AdminService) GetAllSkills(page, limit int, category, search string) ([
 
 	// Pass nil/empty for unused filters in admin view (dayOfWeek, minRating, location, sortBy)
 	// Admin typically wants to sort by newest first (default in repo) or maybe by ID
-	skills, total, err := s.skillRepo.GetAllWithFilters(limit, offset, category, search, nil, nil, "", "newest")
+	skills, total, err := s.skillRepo.GetAllWithFilters(limit, offset, category, search, nil, nil, "", "", "newest")
 	if err != nil {
 		return nil, 0, err
 	}
@@ -113,6 +117,8 @@ func (s *AdminService) ResolveReport(reportID, adminID uint, resolution string)
 }
 
 // DismissReport dismisses a report
+// A dismissed fraud report was a false positive: the payout freezes it caused are lifted,
+// except for users another pending fraud report still names.
 func (s *AdminService) DismissReport(reportID, adminID uint) error {
 	report, err := s.reportRepo.GetByID(reportID)
 	if err != nil {
@@ -120,5 +126,25 @@ func (s *AdminService) DismissReport(reportID, adminID uint) error {
 	}
 	report.Status = "dismissed"
 	report.ResolvedBy = &adminID
+
+	if report.Type == models.ReportTypeFraud {
+		var evidence struct {
+			UserIDs       []uint `json:"user_ids"`
+			PayoutsFrozen bool   `json:"payouts_frozen"`
+		}
+		if err := json.Unmarshal([]byte(report.Metadata), &evidence); err == nil && evidence.PayoutsFrozen {
+			_, err := s.reportRepo.DismissFraudReport(report, evidence.UserIDs)
+			return err
+		}
+	}
 	return s.reportRepo.Update(report)
 }
+
+// ReleasePayouts lifts a user's payout freeze and makes the held earnings spendable
+// Use after reviewing a fraud report; confirmed fraud can be clawed back with a credit adjustment first.
+func (s *AdminService) ReleasePayouts(userID uint) (*models.User, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	return s.userRepo.ReleaseFrozenPayouts(userID)
+}
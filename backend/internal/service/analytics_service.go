@@ -198,7 +198,7 @@ func (s *AnalyticsService) fetchRecentActivity() []dto.ActivityItem {
 
 	// 3. Get recent skills
 	// Note: GetAllWithFilters returns ([]models.Skill, int64, error)
-	skills, _, _ := s.skillRepo.GetAllWithFilters(5, 0, "", "", nil, nil, "", "newest")
+	skills, _, _ := s.skillRepo.GetAllWithFilters(5, 0, "", "", nil, nil, "", "", "newest")
 	for _, sk := range skills {
 		activities = append(activities, dto.ActivityItem{
 			ID:        sk.ID,
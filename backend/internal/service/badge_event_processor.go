@@ -0,0 +1,234 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/events"
+)
+
+// BadgeEventProcessor awards badges in response to domain events
+//
+// Events are only recorded when they are published. Every interval the processor
+// evaluates, for each affected user, just the badges whose rules depend on that user's
+// events (see BadgeRule.Events). A burst of events for one user costs a single
+// evaluation, and each metric is queried at most once per user per flush.
+// After removal events (see removalEvents) the affected badges the user holds are
+// re-checked first and revoked if their requirements are no longer met.
+type BadgeEventProcessor struct {
+	badgeService *BadgeService
+	bus          *events.Bus
+	interval     time.Duration
+
+	mu      sync.Mutex
+	pending map[uint]map[events.Type]bool
+	stale   bool
+
+	// Only used by flush, which flushMu serializes
+	flushMu  sync.Mutex
+	index    map[events.Type][]*compiledBadge
+	anyEvent []*compiledBadge // Rules with a metric that may change with any event
+}
+
+// NewBadgeEventProcessor creates a badge event processor for the given bus
+func NewBadgeEventProcessor(badgeService *BadgeService, bus *events.Bus, interval time.Duration) *BadgeEventProcessor {
+	return &BadgeEventProcessor{
+		badgeService: badgeService,
+		bus:          bus,
+		interval:     interval,
+		pending:      make(map[uint]map[events.Type]bool),
+		stale:        true,
+	}
+}
+
+// StartScheduler subscribes to the bus and starts a background goroutine that
+// periodically evaluates the recorded events
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler (pending events are flushed first)
+func (p *BadgeEventProcessor) StartScheduler() chan struct{} {
+	p.bus.Subscribe(p.handle)
+
+	stop := make(chan struct{})
+	interval := p.interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				p.flushAndLog()
+			case <-stop:
+				ticker.Stop()
+				p.flushAndLog()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// handle records an event for the next flush
+func (p *BadgeEventProcessor) handle(event events.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if event.Type == events.BadgesChanged {
+		p.stale = true
+		return
+	}
+	for _, userID := range event.UserIDs {
+		if userID == 0 {
+			continue
+		}
+		if p.pending[userID] == nil {
+			p.pending[userID] = make(map[events.Type]bool)
+		}
+		p.pending[userID][event.Type] = true
+	}
+}
+
+// Flush evaluates the badges affected by the events recorded since the last flush
+//
+// Returns:
+//   - awarded: Number of badges awarded
+//   - revoked: Number of badges revoked because their requirements are no longer met
+//   - err: If the badge definitions could not be loaded (the events are kept for the next flush)
+func (p *BadgeEventProcessor) Flush() (awarded, revoked int, err error) {
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+
+	p.mu.Lock()
+	pending := p.pending
+	stale := p.stale
+	p.pending = make(map[uint]map[events.Type]bool)
+	p.stale = false
+	p.mu.Unlock()
+
+	if stale {
+		if err := p.reload(); err != nil {
+			p.requeue(pending)
+			return 0, 0, err
+		}
+	}
+
+	for userID, types := range pending {
+		badges := p.badgesFor(types)
+		if len(badges) == 0 {
+			continue
+		}
+
+		user, err := p.badgeService.userRepo.GetByID(userID)
+		if err != nil || !user.IsActive {
+			continue
+		}
+
+		if hasRemoval(types) {
+			revocations, err := p.badgeService.revokeUnmet(user, badges)
+			if err != nil {
+				log.Printf("ERROR: Failed to re-check badges for user %d: %v", userID, err)
+			}
+			revoked += len(revocations)
+		}
+
+		userBadges, err := p.badgeService.awardQualifying(user, badges)
+		if err != nil {
+			log.Printf("ERROR: Failed to evaluate badges for user %d: %v", userID, err)
+			continue
+		}
+		awarded += len(userBadges)
+	}
+	return awarded, revoked, nil
+}
+
+// flushAndLog runs Flush from the scheduler
+func (p *BadgeEventProcessor) flushAndLog() {
+	awarded, revoked, err := p.Flush()
+	if err != nil {
+		log.Printf("⚠️  Badge event processing error: %v", err)
+	}
+	if awarded > 0 {
+		log.Printf("🏅 Awarded %d badges from events", awarded)
+	}
+	if revoked > 0 {
+		log.Printf("🏅 Revoked %d badges whose requirements are no longer met", revoked)
+	}
+}
+
+// hasRemoval reports whether any of the event types can take a held badge's requirements away
+func hasRemoval(types map[events.Type]bool) bool {
+	for t := range types {
+		if removalEvents[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// reload rebuilds the event → badges index from the active badges
+func (p *BadgeEventProcessor) reload() error {
+	badges, err := p.badgeService.compileActiveBadges()
+	if err != nil {
+		p.mu.Lock()
+		p.stale = true
+		p.mu.Unlock()
+		return err
+	}
+
+	index := make(map[events.Type][]*compiledBadge)
+	var anyEvent []*compiledBadge
+	for _, badge := range badges {
+		types := badge.rule.Events()
+		if types == nil {
+			anyEvent = append(anyEvent, badge)
+			continue
+		}
+		for _, t := range types {
+			index[t] = append(index[t], badge)
+		}
+	}
+
+	p.index = index
+	p.anyEvent = anyEvent
+	return nil
+}
+
+// badgesFor returns the badges subscribed to any of the given event types, each once
+func (p *BadgeEventProcessor) badgesFor(types map[events.Type]bool) []*compiledBadge {
+	seen := make(map[uint]bool)
+	var badges []*compiledBadge
+
+	add := func(list []*compiledBadge) {
+		for _, badge := range list {
+			if !seen[badge.badge.ID] {
+				seen[badge.badge.ID] = true
+				badges = append(badges, badge)
+			}
+		}
+	}
+	for t := range types {
+		add(p.index[t])
+	}
+	add(p.anyEvent)
+	return badges
+}
+
+// requeue puts events back after a failed flush
+func (p *BadgeEventProcessor) requeue(pending map[uint]map[events.Type]bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for userID, types := range pending {
+		if p.pending[userID] == nil {
+			p.pending[userID] = make(map[events.Type]bool)
+		}
+		for t := range types {
+			p.pending[userID][t] = true
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// GetLeaderboard gets the top users of a leaderboard for a period and scope
+//
+// Scores:
+//   - badges, rarity: badges earned in the period
+//   - sessions: sessions completed in the period, teaching + learning
+//   - rating: Bayesian, recency-weighted average of reviews received in the period,
+//     with the plain average as RawScore; stored as int x100 (450 = 4.5 stars)
+//   - credits: total earned all time, or credits earned from teaching in the period
+//
+// Scopes restrict who is ranked: users of a school, or users teaching or learning
+// a skill or a skill in a category.
+//
+// Returns:
+//   - []LeaderboardEntry: Entries with Rank set, best first
+//   - error: utils.ErrInvalidLeaderboard for a bad query, or a database error
+func (s *BadgeService) GetLeaderboard(board models.LeaderboardType, query dto.LeaderboardQuery, limit int) ([]dto.LeaderboardEntry, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	filter, err := leaderboardFilter(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.leaderboardRepo.GetTop(board, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch leaderboard: %w", err)
+	}
+	return mapLeaderboardRows(board, rows), nil
+}
+
+// GetMyRank gets the user's position on a leaderboard with up to radius users
+// above and below them
+func (s *BadgeService) GetMyRank(userID uint, board models.LeaderboardType, query dto.LeaderboardQuery, radius int) (*dto.MyRankResponse, error) {
+	if radius < 0 || radius > 10 {
+		radius = 2
+	}
+
+	filter, err := leaderboardFilter(query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, total, err := s.leaderboardRepo.GetAround(board, filter, userID, radius)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch leaderboard rank: %w", err)
+	}
+
+	response := &dto.MyRankResponse{
+		Type:       string(board),
+		Period:     string(leaderboardPeriod(query)),
+		Total:      total,
+		Neighbours: mapLeaderboardRows(board, rows),
+	}
+	for _, entry := range response.Neighbours {
+		if entry.UserID == userID {
+			response.Rank = entry.Rank
+			response.Score = entry.Score
+		}
+	}
+	return response, nil
+}
+
+// leaderboardPeriod returns the query's period, defaulting to all time
+func leaderboardPeriod(query dto.LeaderboardQuery) models.LeaderboardPeriod {
+	if query.Period == "" {
+		return models.PeriodAllTime
+	}
+	return models.LeaderboardPeriod(query.Period)
+}
+
+// leaderboardFilter validates a leaderboard query and resolves its period at now
+func leaderboardFilter(query dto.LeaderboardQuery, now time.Time) (repository.LeaderboardFilter, error) {
+	filter := repository.LeaderboardFilter{
+		School:   query.School,
+		SkillID:  query.SkillID,
+		Category: query.Category,
+	}
+
+	scopes := 0
+	for _, set := range []bool{query.School != "", query.SkillID != 0, query.Category != ""} {
+		if set {
+			scopes++
+		}
+	}
+	if scopes > 1 {
+		return filter, fmt.Errorf("%w: use only one of school, skill_id and category", utils.ErrInvalidLeaderboard)
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var since time.Time
+	switch leaderboardPeriod(query) {
+	case models.PeriodAllTime:
+		return filter, nil
+	case models.PeriodWeek:
+		since = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.PeriodMonth:
+		since = day.AddDate(0, 0, 1-day.Day())
+	case models.PeriodSemester:
+		// Odd semester runs August-January, even semester February-July
+		switch {
+		case now.Month() >= time.August:
+			since = time.Date(now.Year(), time.August, 1, 0, 0, 0, 0, now.Location())
+		case now.Month() >= time.February:
+			since = time.Date(now.Year(), time.February, 1, 0, 0, 0, 0, now.Location())
+		default:
+			since = time.Date(now.Year()-1, time.August, 1, 0, 0, 0, 0, now.Location())
+		}
+	default:
+		return filter, fmt.Errorf("%w: period must be all, week, month or semester", utils.ErrInvalidLeaderboard)
+	}
+	filter.Since = &since
+	return filter, nil
+}
+
+// mapLeaderboardRows converts leaderboard rows to entries
+func mapLeaderboardRows(board models.LeaderboardType, rows []repository.LeaderboardRow) []dto.LeaderboardEntry {
+	entries := make([]dto.LeaderboardEntry, 0, len(rows))
+	for _, row := range rows {
+		score := row.Score
+		if board == models.LeaderboardRating {
+			// Store rating as integer to avoid floating-point precision issues in JSON
+			score *= 100
+		}
+		entry := dto.LeaderboardEntry{
+			UserID:    row.UserID,
+			Username:  row.Username,
+			FullName:  row.FullName,
+			Avatar:    row.Avatar,
+			Score:     int(score),
+			ScoreType: string(board),
+			Rank:      row.Position,
+		}
+		if row.RawScore != nil {
+			raw := int(*row.RawScore * 100)
+			entry.RawScore = &raw
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+// BadgeAlmostThereThreshold is the progress percentage that triggers the "almost there" notification
+const BadgeAlmostThereThreshold = 80
+
+// GetBadgeProgress computes the user's progress toward every active badge they have not earned yet
+// Values are computed live, so they always reflect the user's current metrics.
+//
+// Returns:
+//   - []BadgeProgressResponse: One entry per unearned badge, closest to completion first
+//   - error: If user not found or database error
+func (s *BadgeService) GetBadgeProgress(userID uint) ([]dto.BadgeProgressResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	badges, err := s.compileActiveBadges()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch badges: %w", err)
+	}
+	held, err := s.closedBadgeIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := s.ruleEngine.NewEvaluation(user)
+	progress := make([]dto.BadgeProgressResponse, 0, len(badges))
+	order := make(map[uint]int, len(badges))
+	for i, compiled := range badges {
+		if held[compiled.badge.ID] {
+			continue
+		}
+		order[compiled.badge.ID] = i
+
+		result, err := evaluation.Evaluate(compiled.rule)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, mapBadgeProgress(&compiled.badge, result))
+	}
+
+	// Closest to completion first, then the badge display order
+	sort.SliceStable(progress, func(i, j int) bool {
+		if progress[i].Percent != progress[j].Percent {
+			return progress[i].Percent > progress[j].Percent
+		}
+		return order[progress[i].Badge.ID] < order[progress[j].Badge.ID]
+	})
+	return progress, nil
+}
+
+// updateProgress stores the progress of evaluated, unearned badges and sends the
+// "almost there" notification the first time a badge reaches BadgeAlmostThereThreshold
+// Only changed rows are written.
+func (s *BadgeService) updateProgress(userID uint, results map[*compiledBadge]*BadgeRuleResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	stored, err := s.badgeRepo.GetUserBadgeProgress(userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to load badge progress for user %d: %v", userID, err)
+		return
+	}
+
+	for compiled, result := range results {
+		percent := rulePercent(result)
+		row := stored[compiled.badge.ID]
+		if row == nil {
+			row = &models.BadgeProgress{UserID: userID, BadgeID: compiled.badge.ID}
+		} else if row.Percent == percent {
+			continue
+		}
+		row.Percent = percent
+
+		notify := percent >= BadgeAlmostThereThreshold && row.NotifiedAt == nil
+		if notify {
+			now := time.Now()
+			row.NotifiedAt = &now
+		}
+
+		if err := s.badgeRepo.SaveBadgeProgress(row); err != nil {
+			log.Printf("ERROR: Failed to save badge progress for user %d: %v", userID, err)
+			continue
+		}
+		if notify {
+			s.notifyAlmostThere(userID, &compiled.badge, result, percent)
+		}
+	}
+}
+
+// notifyAlmostThere tells a user they are close to earning a badge
+func (s *BadgeService) notifyAlmostThere(userID uint, badge *models.Badge, result *BadgeRuleResult, percent int) {
+	message := fmt.Sprintf("You're %d%% of the way to the %s badge!", percent, badge.Name)
+	if headline := headlineCondition(result); headline != nil && isThreshold(headline.Op) {
+		message = fmt.Sprintf("You're almost there: %s/%s %s for the %s badge!",
+			formatMetricValue(headline.Actual), formatMetricValue(headline.Target),
+			strings.ToLower(badgeMetrics[headline.Metric].description), badge.Name)
+	}
+
+	_, _ = s.notificationService.CreateNotification(
+		userID,
+		models.NotificationTypeAchievement,
+		"Almost There! 🎯",
+		message,
+		map[string]interface{}{
+			"badgeID":   badge.ID,
+			"badgeName": badge.Name,
+			"percent":   percent,
+		},
+	)
+}
+
+// mapBadgeProgress builds the progress response of one badge from its evaluation
+func mapBadgeProgress(badge *models.Badge, result *BadgeRuleResult) dto.BadgeProgressResponse {
+	response := dto.BadgeProgressResponse{
+		Badge:      dto.MapBadgeToResponse(badge),
+		Percent:    rulePercent(result),
+		Conditions: mapConditionProgress(result),
+	}
+
+	if headline := headlineCondition(result); headline != nil {
+		response.Metric = headline.Metric
+		response.Progress = headline.Actual
+		response.ProgressGoal = headline.Target
+	}
+	return response
+}
+
+// mapConditionProgress flattens the conditions of a rule result with their completion
+func mapConditionProgress(result *BadgeRuleResult) []dto.BadgeConditionProgress {
+	conditions := []dto.BadgeConditionProgress{}
+
+	var collect func(r *BadgeRuleResult)
+	collect = func(r *BadgeRuleResult) {
+		if r.Metric == "" {
+			for i := range r.Children {
+				collect(&r.Children[i])
+			}
+			return
+		}
+		conditions = append(conditions, dto.BadgeConditionProgress{
+			Metric:      r.Metric,
+			Description: badgeMetrics[r.Metric].description,
+			Op:          r.Op,
+			Target:      r.Target,
+			Actual:      r.Actual,
+			WindowDays:  r.WindowDays,
+			Met:         r.Met,
+			Percent:     conditionPercent(r),
+		})
+	}
+	collect(result)
+	return conditions
+}
+
+// rulePercent is the completion of a rule result from 0 to 100
+// "all" groups average their children, "any" groups take the best child.
+// Only a met rule reaches 100.
+func rulePercent(result *BadgeRuleResult) int {
+	if result.Met {
+		return 100
+	}
+	if result.Metric != "" {
+		return conditionPercent(result)
+	}
+
+	percent := 0
+	for i := range result.Children {
+		child := rulePercent(&result.Children[i])
+		if result.Group == "any" {
+			if child > percent {
+				percent = child
+			}
+		} else {
+			percent += child
+		}
+	}
+	if result.Group != "any" && len(result.Children) > 0 {
+		percent /= len(result.Children)
+	}
+	if percent > 99 {
+		percent = 99
+	}
+	return percent
+}
+
+// conditionPercent is the completion of a single condition from 0 to 100
+// Thresholds (>=, >) progress proportionally; other comparisons are met or not.
+func conditionPercent(result *BadgeRuleResult) int {
+	if result.Met {
+		return 100
+	}
+	if !isThreshold(result.Op) || result.Target <= 0 || result.Actual <= 0 {
+		return 0
+	}
+	percent := int(math.Floor(result.Actual / result.Target * 100))
+	if percent > 99 {
+		percent = 99
+	}
+	return percent
+}
+
+// headlineCondition picks the condition that best describes what is left to do:
+// the least complete child of an "all" group, the most complete child of an "any" group
+func headlineCondition(result *BadgeRuleResult) *BadgeRuleResult {
+	if result.Metric != "" {
+		return result
+	}
+
+	var best *BadgeRuleResult
+	bestPercent := 0
+	for i := range result.Children {
+		child := &result.Children[i]
+		if result.Group == "all" && child.Met {
+			continue
+		}
+		percent := rulePercent(child)
+		if best == nil ||
+			(result.Group == "any" && percent > bestPercent) ||
+			(result.Group == "all" && percent < bestPercent) {
+			best = child
+			bestPercent = percent
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return headlineCondition(best)
+}
+
+// isThreshold reports whether an operator is a "reach at least" comparison
+func isThreshold(op string) bool {
+	return op == ">=" || op == ">"
+}
+
+// formatMetricValue prints whole numbers without decimals (7) and others with one (4.5)
+func formatMetricValue(value float64) string {
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// Reasons recorded for revocations that no admin asked for
+const (
+	revokeReasonUnmet   = "Badge requirements are no longer met"
+	revokeReasonRetired = "Badge was retired"
+)
+
+// revokeOptions describes how a badge is taken back
+type revokeOptions struct {
+	reason    string
+	revokedBy *uint // Admin ID; nil for automatic revocations
+	clawback  bool  // Debit the remaining badge bonus
+	override  bool  // Let the clawback take the balance below zero
+	permanent bool  // Block automatic re-awards
+}
+
+// RevokeBadge takes a badge back from a user (admin only)
+// By default the remaining bonus is clawed back, capped at the user's available credits
+// unless req.Override is set, and the badge is not awarded again automatically.
+//
+// Returns:
+//   - *BadgeRevocation: The recorded revocation, with the clawback transaction if any
+//   - error: utils.ErrInvalidRevocation, utils.ErrBadgeNotFound, utils.ErrBadgeNotHeld, or a database error
+func (s *BadgeService) RevokeBadge(adminID, badgeID uint, req *dto.RevokeBadgeRequest) (*models.BadgeRevocation, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required", utils.ErrInvalidRevocation)
+	}
+
+	badge, err := s.getBadge(badgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revokeBadge(req.UserID, badge, revokeOptions{
+		reason:    reason,
+		revokedBy: &adminID,
+		clawback:  !req.KeepBonus,
+		override:  req.Override,
+		permanent: !req.AllowReaward,
+	})
+}
+
+// GetRevocations lists badge revocations, optionally for one user and/or badge (admin only)
+func (s *BadgeService) GetRevocations(userID, badgeID uint, page, limit int) ([]models.BadgeRevocation, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	return s.badgeRepo.GetRevocations(userID, badgeID, limit, offset)
+}
+
+// revokeBadge revokes a badge, records the revocation and notifies the user
+func (s *BadgeService) revokeBadge(userID uint, badge *models.Badge, opts revokeOptions) (*models.BadgeRevocation, error) {
+	revocation := &models.BadgeRevocation{
+		UserID:    userID,
+		BadgeID:   badge.ID,
+		Reason:    opts.reason,
+		RevokedBy: opts.revokedBy,
+		Permanent: opts.permanent,
+		Override:  opts.override,
+	}
+	if err := s.badgeRepo.RevokeBadge(revocation, opts.clawback, "Badge revoked: "+badge.Name); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Your %s badge was revoked: %s.", badge.Name, opts.reason)
+	if revocation.ClawbackAmount > 0 {
+		message += fmt.Sprintf(" %.1f bonus credits were taken back.", revocation.ClawbackAmount)
+	}
+	_, _ = s.notificationService.CreateNotification(
+		userID,
+		models.NotificationTypeAchievement,
+		"Badge Revoked",
+		message,
+		map[string]interface{}{
+			"badgeID":      badge.ID,
+			"badgeName":    badge.Name,
+			"reason":       opts.reason,
+			"clawback":     revocation.ClawbackAmount,
+			"revocationID": revocation.ID,
+		},
+	)
+
+	events.PublishWithData(events.BadgeRevoked, map[string]interface{}{"badge_id": badge.ID}, userID)
+	return revocation, nil
+}
+
+// revokeUnmet re-checks the given badges the user holds and revokes those whose
+// requirements are no longer met, clawing back their bonus
+// Called after removal events (a review or forum reply deleted, a badge revoked), see removalEvents.
+func (s *BadgeService) revokeUnmet(user *models.User, badges []*compiledBadge) ([]models.BadgeRevocation, error) {
+	revoked := []models.BadgeRevocation{}
+
+	held, err := s.heldBadgeIDs(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := s.ruleEngine.NewEvaluation(user)
+	for _, compiled := range badges {
+		if !held[compiled.badge.ID] {
+			continue
+		}
+
+		result, err := evaluation.Recheck(compiled.rule)
+		if err != nil {
+			log.Printf("ERROR: Failed to re-check badge %d for user %d: %v", compiled.badge.ID, user.ID, err)
+			continue
+		}
+		if result.Met {
+			continue
+		}
+
+		revocation, err := s.revokeBadge(user.ID, &compiled.badge, revokeOptions{
+			reason:   revokeReasonUnmet,
+			clawback: true,
+		})
+		if err != nil {
+			log.Printf("ERROR: Failed to revoke badge %d from user %d: %v", compiled.badge.ID, user.ID, err)
+			continue
+		}
+		revoked = append(revoked, *revocation)
+	}
+	return revoked, nil
+}
+
+// revokeFromHolders revokes a badge from everyone holding it
+// Used when a badge is deleted; returns the number of users it was revoked from.
+func (s *BadgeService) revokeFromHolders(badge *models.Badge, opts revokeOptions) (int, error) {
+	holderIDs, err := s.badgeRepo.GetBadgeHolderIDs(badge.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch badge holders: %w", err)
+	}
+
+	revoked := 0
+	for _, userID := range holderIDs {
+		if _, err := s.revokeBadge(userID, badge, opts); err != nil {
+			log.Printf("ERROR: Failed to revoke badge %d from user %d: %v", badge.ID, userID, err)
+			continue
+		}
+		revoked++
+	}
+	return revoked, nil
+}
@@ -0,0 +1,552 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/events"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// Limits that keep admin-authored badge rules cheap to evaluate
+const (
+	maxBadgeRuleDepth      = 4
+	maxBadgeRuleConditions = 20
+	maxBadgeRuleWindowDays = 3650
+)
+
+// BadgeRule is a node of a badge requirement tree, stored as JSON in Badge.Requirements
+//
+// A node is either a group or a condition:
+//
+//	{"all": [ ... ]}                                            every child must hold
+//	{"any": [ ... ]}                                            at least one child must hold
+//	{"metric": "sessions_as_teacher", "op": ">=", "value": 10}  a metric comparison
+//	{"metric": "forum_posts", "value": 5, "window_days": 30}    counted over the last 30 days
+//
+// The legacy flat form {"sessions": 20, "rating": 4.8} is still accepted and means
+// "all of these metrics are at least their value".
+type BadgeRule struct {
+	All        []BadgeRule `json:"all,omitempty"`
+	Any        []BadgeRule `json:"any,omitempty"`
+	Metric     string      `json:"metric,omitempty"`
+	Op         string      `json:"op,omitempty"`
+	Value      float64     `json:"value,omitempty"`
+	WindowDays int         `json:"window_days,omitempty"`
+}
+
+// BadgeRuleResult is the outcome of evaluating a BadgeRule for one user
+// Conditions carry the actual metric value, so the tree doubles as a progress report.
+type BadgeRuleResult struct {
+	Met        bool              `json:"met"`
+	Group      string            `json:"group,omitempty"` // "all" or "any" for groups
+	Metric     string            `json:"metric,omitempty"`
+	Op         string            `json:"op,omitempty"`
+	Target     float64           `json:"target"`
+	Actual     float64           `json:"actual"`
+	WindowDays int               `json:"window_days,omitempty"`
+	Children   []BadgeRuleResult `json:"children,omitempty"`
+}
+
+// BadgeMetricInfo describes a metric that badge rules can reference
+type BadgeMetricInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Windowed    bool   `json:"windowed"` // Whether window_days is supported
+}
+
+// badgeMetric computes one metric for the user of an evaluation
+// period is unbounded unless the condition has a window or the evaluation a period.
+type badgeMetric struct {
+	description string
+	windowed    bool
+	events      []events.Type // Events that can change the metric; nil means any event
+	compute     func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error)
+}
+
+// Event groups that badge metrics depend on
+var (
+	sessionEvents     = []events.Type{events.SessionCompleted}
+	reviewEvents      = []events.Type{events.ReviewCreated, events.ReviewDeleted}
+	forumThreadEvents = []events.Type{events.ForumThreadCreated}
+	forumPostEvents   = []events.Type{events.ForumThreadCreated, events.ForumReplyCreated, events.ForumReplyDeleted}
+	donationEvents    = []events.Type{events.DonationMade}
+	badgeEvents       = []events.Type{events.BadgeAwarded, events.BadgeRevoked}
+)
+
+// removalEvents are the events after which badges a user holds are re-checked (see BadgeService.revokeUnmet)
+var removalEvents = map[events.Type]bool{
+	events.ReviewDeleted:     true,
+	events.ForumReplyDeleted: true,
+	events.BadgeRevoked:      true,
+}
+
+// badgeDecayingMetrics fall over time without anything being removed
+// Re-checks after a removal treat their conditions as met, like windowed conditions.
+var badgeDecayingMetrics = map[string]bool{
+	"streak_days":  true,
+	"streak_weeks": true,
+}
+
+// badgeMetrics is the registry of metrics badge rules can reference
+var badgeMetrics = map[string]badgeMetric{
+	"sessions": {"Completed sessions as teacher or student", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountCompletedSessions(ev.user.ID, repository.BadgeRoleAny, period)
+		return float64(n), err
+	}},
+	"sessions_as_teacher": {"Completed sessions as teacher", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountCompletedSessions(ev.user.ID, repository.BadgeRoleTeacher, period)
+		return float64(n), err
+	}},
+	"sessions_as_student": {"Completed sessions as student", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountCompletedSessions(ev.user.ID, repository.BadgeRoleStudent, period)
+		return float64(n), err
+	}},
+	"hours_taught": {"Hours of completed sessions as teacher", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		return ev.repo.SumSessionHours(ev.user.ID, repository.BadgeRoleTeacher, period)
+	}},
+	"hours_learned": {"Hours of completed sessions as student", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		return ev.repo.SumSessionHours(ev.user.ID, repository.BadgeRoleStudent, period)
+	}},
+	"students_taught": {"Different students taught in completed sessions", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountStudentsTaught(ev.user.ID, false, period)
+		return float64(n), err
+	}},
+	"new_students_taught": {"Students taught for the first time", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountStudentsTaught(ev.user.ID, true, period)
+		return float64(n), err
+	}},
+	"unique_skills_taught": {"Different skills taught in completed sessions", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountUniqueSkills(ev.user.ID, repository.BadgeRoleTeacher, period)
+		return float64(n), err
+	}},
+	"unique_skills_learned": {"Different skills learned in completed sessions", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountUniqueSkills(ev.user.ID, repository.BadgeRoleStudent, period)
+		return float64(n), err
+	}},
+	"credits_earned": {"Credits earned from teaching", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		return ev.repo.SumTransactions(ev.user.ID, models.TransactionEarned, true, period)
+	}},
+	"credits_spent": {"Credits spent on learning", true, sessionEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		return ev.repo.SumTransactions(ev.user.ID, models.TransactionSpent, true, period)
+	}},
+	"credits_donated": {"Credits donated to the community pool", true, donationEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		return ev.repo.SumTransactions(ev.user.ID, models.TransactionDonation, false, period)
+	}},
+	"rating": {"Average rating received in reviews (0-5)", true, reviewEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		return ev.repo.AverageReceivedRating(ev.user.ID, period)
+	}},
+	"reviews_given": {"Reviews written", true, reviewEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountReviews(ev.user.ID, true, period)
+		return float64(n), err
+	}},
+	"reviews_received": {"Reviews received", true, reviewEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountReviews(ev.user.ID, false, period)
+		return float64(n), err
+	}},
+	"forum_threads": {"Forum threads started", true, forumThreadEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountForumThreads(ev.user.ID, period)
+		return float64(n), err
+	}},
+	"forum_posts": {"Forum threads and replies written", true, forumPostEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		threads, err := ev.repo.CountForumThreads(ev.user.ID, period)
+		if err != nil {
+			return 0, err
+		}
+		replies, err := ev.repo.CountForumReplies(ev.user.ID, period)
+		return float64(threads + replies), err
+	}},
+	"badges_earned": {"Badges earned", true, badgeEvents, func(ev *BadgeEvaluation, period repository.BadgePeriod) (float64, error) {
+		n, err := ev.repo.CountEarnedBadges(ev.user.ID, period)
+		return float64(n), err
+	}},
+	"streak_days": {"Consecutive days with a completed session, up to today or yesterday", false, sessionEvents, func(ev *BadgeEvaluation, _ repository.BadgePeriod) (float64, error) {
+		days, err := ev.sessionDays()
+		return float64(sessionStreak(days, time.Now(), 1)), err
+	}},
+	"streak_weeks": {"Consecutive weeks with a completed session, up to this week or last week", false, sessionEvents, func(ev *BadgeEvaluation, _ repository.BadgePeriod) (float64, error) {
+		days, err := ev.sessionDays()
+		return float64(sessionStreak(days, time.Now(), 7)), err
+	}},
+	"account_age_days": {"Days since the account was created", false, nil, func(ev *BadgeEvaluation, _ repository.BadgePeriod) (float64, error) {
+		return math.Floor(time.Since(ev.user.CreatedAt).Hours() / 24), nil
+	}},
+}
+
+// badgeMetricAliases maps the names used by older badge definitions to registry metrics
+var badgeMetricAliases = map[string]string{
+	"teaching_sessions": "sessions_as_teacher",
+	"learning_sessions": "sessions_as_student",
+	"total_earned":      "credits_earned",
+	"unique_skills":     "unique_skills_taught",
+}
+
+// badgeOperators are the comparison operators a condition may use
+var badgeOperators = map[string]func(actual, target float64) bool{
+	">=": func(a, t float64) bool { return a >= t },
+	">":  func(a, t float64) bool { return a > t },
+	"<=": func(a, t float64) bool { return a <= t },
+	"<":  func(a, t float64) bool { return a < t },
+	"==": func(a, t float64) bool { return math.Abs(a-t) < 1e-9 },
+	"!=": func(a, t float64) bool { return math.Abs(a-t) >= 1e-9 },
+}
+
+// ListBadgeMetrics returns the registered badge metrics sorted by name
+func ListBadgeMetrics() []BadgeMetricInfo {
+	metrics := make([]BadgeMetricInfo, 0, len(badgeMetrics))
+	for name, metric := range badgeMetrics {
+		metrics = append(metrics, BadgeMetricInfo{
+			Name:        name,
+			Description: metric.description,
+			Windowed:    metric.windowed,
+		})
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
+
+// ParseBadgeRule parses and validates badge requirements JSON
+// Aliases are resolved and missing operators default to ">=", so the returned rule
+// only uses registry metric names.
+//
+// Returns:
+//   - *BadgeRule: The normalized rule
+//   - error: Wraps utils.ErrInvalidBadgeRule (unknown metric, bad operator, empty group, ...)
+func ParseBadgeRule(requirements string) (*BadgeRule, error) {
+	if strings.TrimSpace(requirements) == "" {
+		return nil, fmt.Errorf("%w: requirements are empty", utils.ErrInvalidBadgeRule)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(requirements), &fields); err != nil {
+		return nil, fmt.Errorf("%w: requirements must be a JSON object", utils.ErrInvalidBadgeRule)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: requirements are empty", utils.ErrInvalidBadgeRule)
+	}
+
+	var rule BadgeRule
+	_, isGroup := fields["all"]
+	_, isAny := fields["any"]
+	_, isCondition := fields["metric"]
+	if isGroup || isAny || isCondition {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(requirements)))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&rule); err != nil {
+			return nil, fmt.Errorf("%w: %v", utils.ErrInvalidBadgeRule, err)
+		}
+	} else {
+		// Legacy flat form: every key is a metric with a minimum value
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var value float64
+			if err := json.Unmarshal(fields[name], &value); err != nil {
+				return nil, fmt.Errorf("%w: value of %q must be a number", utils.ErrInvalidBadgeRule, name)
+			}
+			rule.All = append(rule.All, BadgeRule{Metric: name, Op: ">=", Value: value})
+		}
+	}
+
+	conditions := 0
+	if err := normalizeBadgeRule(&rule, 1, &conditions); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// normalizeBadgeRule validates a rule node in place and resolves metric aliases
+func normalizeBadgeRule(rule *BadgeRule, depth int, conditions *int) error {
+	if depth > maxBadgeRuleDepth {
+		return fmt.Errorf("%w: rules may be nested at most %d levels deep", utils.ErrInvalidBadgeRule, maxBadgeRuleDepth)
+	}
+
+	kinds := 0
+	if rule.All != nil {
+		kinds++
+	}
+	if rule.Any != nil {
+		kinds++
+	}
+	if rule.Metric != "" {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("%w: each rule must have exactly one of \"all\", \"any\" or \"metric\"", utils.ErrInvalidBadgeRule)
+	}
+
+	if rule.Metric == "" {
+		children := rule.All
+		group := "all"
+		if rule.Any != nil {
+			children = rule.Any
+			group = "any"
+		}
+		if len(children) == 0 {
+			return fmt.Errorf("%w: %q group is empty", utils.ErrInvalidBadgeRule, group)
+		}
+		if rule.Op != "" || rule.Value != 0 || rule.WindowDays != 0 {
+			return fmt.Errorf("%w: %q group cannot have op, value or window_days", utils.ErrInvalidBadgeRule, group)
+		}
+		for i := range children {
+			if err := normalizeBadgeRule(&children[i], depth+1, conditions); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	*conditions++
+	if *conditions > maxBadgeRuleConditions {
+		return fmt.Errorf("%w: at most %d conditions are allowed", utils.ErrInvalidBadgeRule, maxBadgeRuleConditions)
+	}
+
+	if canonical, ok := badgeMetricAliases[rule.Metric]; ok {
+		rule.Metric = canonical
+	}
+	metric, ok := badgeMetrics[rule.Metric]
+	if !ok {
+		return fmt.Errorf("%w: unknown metric %q", utils.ErrInvalidBadgeRule, rule.Metric)
+	}
+
+	if rule.Op == "" {
+		rule.Op = ">="
+	}
+	if _, ok := badgeOperators[rule.Op]; !ok {
+		return fmt.Errorf("%w: unknown operator %q", utils.ErrInvalidBadgeRule, rule.Op)
+	}
+
+	if rule.WindowDays < 0 || rule.WindowDays > maxBadgeRuleWindowDays {
+		return fmt.Errorf("%w: window_days must be between 0 and %d", utils.ErrInvalidBadgeRule, maxBadgeRuleWindowDays)
+	}
+	if rule.WindowDays > 0 && !metric.windowed {
+		return fmt.Errorf("%w: metric %q does not support window_days", utils.ErrInvalidBadgeRule, rule.Metric)
+	}
+	return nil
+}
+
+// Events returns the event types that can change the outcome of a rule
+// A nil result means the rule depends on a metric that can change with any event.
+func (r *BadgeRule) Events() []events.Type {
+	seen := make(map[events.Type]bool)
+	var types []events.Type
+	anyEvent := false
+
+	var walk func(rule *BadgeRule)
+	walk = func(rule *BadgeRule) {
+		for i := range rule.All {
+			walk(&rule.All[i])
+		}
+		for i := range rule.Any {
+			walk(&rule.Any[i])
+		}
+		if rule.Metric == "" {
+			return
+		}
+		metric := badgeMetrics[rule.Metric]
+		if metric.events == nil {
+			anyEvent = true
+		}
+		for _, t := range metric.events {
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	walk(r)
+
+	if anyEvent {
+		return nil
+	}
+	return types
+}
+
+// BadgeRuleEngine evaluates badge rules against users
+type BadgeRuleEngine struct {
+	badgeRepo *repository.BadgeRepository
+}
+
+// NewBadgeRuleEngine creates a new badge rule engine
+func NewBadgeRuleEngine(badgeRepo *repository.BadgeRepository) *BadgeRuleEngine {
+	return &BadgeRuleEngine{badgeRepo: badgeRepo}
+}
+
+// NewEvaluation starts evaluating rules for one user
+// Metric values are cached, so checking every badge runs each query once.
+func (e *BadgeRuleEngine) NewEvaluation(user *models.User) *BadgeEvaluation {
+	return &BadgeEvaluation{
+		repo:   e.badgeRepo,
+		user:   user,
+		values: make(map[string]float64),
+	}
+}
+
+// NewPeriodEvaluation starts evaluating rules for one user counting only activity from
+// from until (excluded)
+// Used for challenges, whose goals count between the challenge start and end instead of over a window.
+func (e *BadgeRuleEngine) NewPeriodEvaluation(user *models.User, from, until time.Time) *BadgeEvaluation {
+	ev := e.NewEvaluation(user)
+	ev.from = &from
+	ev.until = &until
+	return ev
+}
+
+// BadgeEvaluation evaluates rules for a single user with cached metric values
+type BadgeEvaluation struct {
+	repo   *repository.BadgeRepository
+	user   *models.User
+	from   *time.Time // Period start for conditions without a window (challenges)
+	until  *time.Time // Period end for every windowed metric (challenges)
+	values map[string]float64
+	days   []time.Time
+	loaded bool
+}
+
+// Evaluate evaluates a rule returned by ParseBadgeRule
+// Every condition is evaluated (no short-circuit) so the result shows full progress.
+func (ev *BadgeEvaluation) Evaluate(rule *BadgeRule) (*BadgeRuleResult, error) {
+	return ev.evaluate(rule, false)
+}
+
+// Recheck evaluates a rule for a badge the user already holds
+// Windowed conditions and decaying metrics (streaks) are expected to lapse with time,
+// so they count as met: only lifetime totals that went down can fail a re-check.
+func (ev *BadgeEvaluation) Recheck(rule *BadgeRule) (*BadgeRuleResult, error) {
+	return ev.evaluate(rule, true)
+}
+
+// evaluate implements Evaluate and Recheck
+func (ev *BadgeEvaluation) evaluate(rule *BadgeRule, recheck bool) (*BadgeRuleResult, error) {
+	if rule.Metric != "" && recheck && (rule.WindowDays > 0 || badgeDecayingMetrics[rule.Metric]) {
+		return &BadgeRuleResult{
+			Met:        true,
+			Metric:     rule.Metric,
+			Op:         rule.Op,
+			Target:     rule.Value,
+			WindowDays: rule.WindowDays,
+		}, nil
+	}
+	if rule.Metric != "" {
+		actual, err := ev.value(rule.Metric, rule.WindowDays)
+		if err != nil {
+			return nil, err
+		}
+		compare, ok := badgeOperators[rule.Op]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown operator %q", utils.ErrInvalidBadgeRule, rule.Op)
+		}
+		return &BadgeRuleResult{
+			Met:        compare(actual, rule.Value),
+			Metric:     rule.Metric,
+			Op:         rule.Op,
+			Target:     rule.Value,
+			Actual:     actual,
+			WindowDays: rule.WindowDays,
+		}, nil
+	}
+
+	result := &BadgeRuleResult{Group: "all", Met: true}
+	children := rule.All
+	if rule.Any != nil {
+		result.Group = "any"
+		result.Met = false
+		children = rule.Any
+	}
+
+	for i := range children {
+		child, err := ev.evaluate(&children[i], recheck)
+		if err != nil {
+			return nil, err
+		}
+		if result.Group == "all" {
+			result.Met = result.Met && child.Met
+		} else {
+			result.Met = result.Met || child.Met
+		}
+		result.Children = append(result.Children, *child)
+	}
+	return result, nil
+}
+
+// value returns a metric for the evaluation's user, computing it at most once per window
+func (ev *BadgeEvaluation) value(name string, windowDays int) (float64, error) {
+	key := fmt.Sprintf("%s:%d", name, windowDays)
+	if v, ok := ev.values[key]; ok {
+		return v, nil
+	}
+
+	metric, ok := badgeMetrics[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown metric %q", utils.ErrInvalidBadgeRule, name)
+	}
+
+	var period repository.BadgePeriod
+	if windowDays > 0 {
+		t := time.Now().AddDate(0, 0, -windowDays)
+		period.Since = &t
+	} else if ev.from != nil && metric.windowed {
+		period.Since = ev.from
+	}
+	if metric.windowed {
+		period.Until = ev.until
+	}
+
+	v, err := metric.compute(ev, period)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute badge metric %s: %w", name, err)
+	}
+	ev.values[key] = v
+	return v, nil
+}
+
+// sessionDays loads the user's session days once for the streak metrics
+func (ev *BadgeEvaluation) sessionDays() ([]time.Time, error) {
+	if !ev.loaded {
+		days, err := ev.repo.GetSessionDays(ev.user.ID)
+		if err != nil {
+			return nil, err
+		}
+		ev.days = days
+		ev.loaded = true
+	}
+	return ev.days, nil
+}
+
+// sessionStreak counts consecutive periods of periodDays days that contain a session day
+// The streak may end in the current or the previous period, so it is not broken
+// before the user had a chance to keep it going today (or this week).
+func sessionStreak(days []time.Time, now time.Time, periodDays int) int {
+	period := func(t time.Time) int {
+		// Whole days since the Unix epoch, shifted so weekly periods start on Monday
+		day := int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+		return (day + 3) / periodDays
+	}
+
+	active := make(map[int]bool, len(days))
+	for _, d := range days {
+		// DATE() values come back as UTC midnight
+		active[period(d.UTC())] = true
+	}
+
+	current := period(now)
+	if !active[current] {
+		current--
+	}
+	streak := 0
+	for active[current] {
+		streak++
+		current--
+	}
+	return streak
+}
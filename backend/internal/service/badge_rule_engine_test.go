@@ -0,0 +1,89 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+func TestParseBadgeRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements string
+		normalized   string // Expected normalizeBadgeRequirements output; empty when the rule is invalid
+	}{
+		{
+			name:         "condition with default operator",
+			requirements: `{"metric": "sessions_as_teacher", "value": 10}`,
+			normalized:   `{"metric":"sessions_as_teacher","op":">=","value":10}`,
+		},
+		{
+			name:         "legacy flat form in key order",
+			requirements: `{"sessions": 20, "rating": 4.8}`,
+			normalized:   `{"all":[{"metric":"rating","op":">=","value":4.8},{"metric":"sessions","op":">=","value":20}]}`,
+		},
+		{
+			name:         "aliases are resolved",
+			requirements: `{"teaching_sessions": 5, "total_earned": 50}`,
+			normalized:   `{"all":[{"metric":"sessions_as_teacher","op":">=","value":5},{"metric":"credits_earned","op":">=","value":50}]}`,
+		},
+		{
+			name:         "nested groups with a window",
+			requirements: `{"any": [{"metric": "forum_posts", "value": 5, "window_days": 30}, {"all": [{"metric": "unique_skills", "op": ">", "value": 2}]}]}`,
+			normalized:   `{"any":[{"metric":"forum_posts","op":">=","value":5,"window_days":30},{"all":[{"metric":"unique_skills_taught","op":">","value":2}]}]}`,
+		},
+		{name: "empty", requirements: ``},
+		{name: "empty object", requirements: `{}`},
+		{name: "not an object", requirements: `[1, 2]`},
+		{name: "unknown metric", requirements: `{"metric": "followers", "value": 1}`},
+		{name: "unknown legacy metric", requirements: `{"followers": 1}`},
+		{name: "legacy value not a number", requirements: `{"sessions": "ten"}`},
+		{name: "unknown operator", requirements: `{"metric": "sessions", "op": "=>", "value": 1}`},
+		{name: "unknown field", requirements: `{"metric": "sessions", "value": 1, "min": 2}`},
+		{name: "empty group", requirements: `{"all": []}`},
+		{name: "group and metric", requirements: `{"all": [{"metric": "sessions", "value": 1}], "metric": "rating"}`},
+		{name: "group with value", requirements: `{"any": [{"metric": "sessions", "value": 1}], "value": 3}`},
+		{name: "window on unwindowed metric", requirements: `{"metric": "streak_days", "value": 7, "window_days": 30}`},
+		{name: "window too long", requirements: `{"metric": "sessions", "value": 1, "window_days": 4000}`},
+		{name: "negative window", requirements: `{"metric": "sessions", "value": 1, "window_days": -1}`},
+		{name: "nested too deep", requirements: `{"all": [{"all": [{"all": [{"all": [{"metric": "sessions", "value": 1}]}]}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBadgeRule(tt.requirements)
+			normalized, normErr := normalizeBadgeRequirements(json.RawMessage(tt.requirements))
+
+			if tt.normalized == "" {
+				assert.ErrorIs(t, err, utils.ErrInvalidBadgeRule)
+				assert.ErrorIs(t, normErr, utils.ErrInvalidBadgeRule)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, normErr)
+			assert.JSONEq(t, tt.normalized, normalized)
+
+			// Normalized requirements parse to themselves
+			again, err := normalizeBadgeRequirements(json.RawMessage(normalized))
+			require.NoError(t, err)
+			assert.Equal(t, normalized, again)
+		})
+	}
+}
+
+func TestParseBadgeRuleConditionLimit(t *testing.T) {
+	conditions := make([]BadgeRule, maxBadgeRuleConditions+1)
+	for i := range conditions {
+		conditions[i] = BadgeRule{Metric: "sessions", Value: float64(i)}
+	}
+
+	atLimit, _ := json.Marshal(BadgeRule{All: conditions[:maxBadgeRuleConditions]})
+	_, err := ParseBadgeRule(string(atLimit))
+	assert.NoError(t, err)
+
+	overLimit, _ := json.Marshal(BadgeRule{All: conditions})
+	_, err = ParseBadgeRule(string(overLimit))
+	assert.ErrorIs(t, err, utils.ErrInvalidBadgeRule)
+}
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
+	"log"
 
 	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
 	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
 )
 
 // BadgeService handles badge business logic
@@ -16,7 +18,10 @@ type BadgeService struct {
 	badgeRepo           *repository.BadgeRepository
 	userRepo            *repository.UserRepository
 	sessionRepo         *repository.SessionRepository
+	transactionRepo     *repository.TransactionRepository
+	leaderboardRepo     *repository.LeaderboardRepository
 	notificationService *NotificationService
+	ruleEngine          *BadgeRuleEngine
 }
 
 // NewBadgeService creates a new badge service
@@ -24,13 +29,18 @@ func NewBadgeService(
 	badgeRepo *repository.BadgeRepository,
 	userRepo *repository.UserRepository,
 	sessionRepo *repository.SessionRepository,
+	transactionRepo *repository.TransactionRepository,
+	leaderboardRepo *repository.LeaderboardRepository,
 	notificationService *NotificationService,
 ) *BadgeService {
 	return &BadgeService{
 		badgeRepo:           badgeRepo,
 		userRepo:            userRepo,
 		sessionRepo:         sessionRepo,
+		transactionRepo:     transactionRepo,
+		leaderboardRepo:     leaderboardRepo,
 		notificationService: notificationService,
+		ruleEngine:          NewBadgeRuleEngine(badgeRepo),
 	}
 }
 
@@ -86,19 +96,19 @@ func (s *BadgeService) GetUserBadgesByType(userID uint, badgeType string) ([]dto
 }
 
 // CheckAndAwardBadges checks if user qualifies for any badges and awards them
-// This function is called after session completion to automatically award earned badges
-// Performance: O(n*m) where n = number of badges, m = average requirement checks
-// Should be called asynchronously to avoid blocking session completion
+// This is the full check behind POST /user/badges/check; automatic awards after
+// sessions, reviews, etc. go through BadgeEventProcessor, which only evaluates the
+// badges affected by the events that happened.
+// Performance: one query per distinct metric (cached across badges)
 //
 // Algorithm:
 // 1. Fetch user profile with all stats
-// 2. Fetch all available badges
-// 3. For each badge:
-//    a. Check if user already has badge (skip if yes)
-//    b. Parse badge requirements JSON
-//    c. Check if user meets ALL requirements
-//    d. If qualified: award badge and grant bonus credits
-// 4. Return list of newly awarded badges
+// 2. Fetch all active badges and parse their requirements (BadgeRule)
+// 3. Fetch the IDs of badges the user already holds (one query)
+// 4. For each badge not held yet:
+//    a. Evaluate the rule against the user's metrics
+//    b. If qualified: award badge and grant bonus credits
+// 5. Return list of newly awarded badges
 //
 // Side Effects:
 //   - Updates user credit balance if bonus credits awarded
@@ -116,532 +126,246 @@ func (s *BadgeService) GetUserBadgesByType(userID uint, badgeType string) ([]dto
 //   awarded, err := badgeService.CheckAndAwardBadges(userID)
 //   // Automatically awards badges user qualifies for
 func (s *BadgeService) CheckAndAwardBadges(userID uint) ([]dto.UserBadgeResponse, error) {
-	awardedBadges := []models.UserBadge{}
-
 	// Fetch user with current stats (teaching hours, learning hours, ratings, etc)
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
 
-	// Fetch all available badges in system
-	allBadges, err := s.badgeRepo.GetAllBadges()
+	// Fetch all available badges with their parsed rules
+	badges, err := s.compileActiveBadges()
 	if err != nil {
 		return nil, err
 	}
 
-	// Iterate through all badges and check qualification
-	for _, badge := range allBadges {
-		// Skip if user already has this badge (prevent duplicate awards)
-		hasIt, _ := s.badgeRepo.HasUserBadge(userID, badge.ID)
-		if hasIt {
-			continue
-		}
-
-		// Parse badge requirements from JSON string
-		// Requirements define what user must achieve to earn badge
-		var requirements map[string]interface{}
-		if err := json.Unmarshal([]byte(badge.Requirements), &requirements); err != nil {
-			// Skip badge if requirements are malformed
-			continue
-		}
-
-		// Check if user meets ALL badge requirements
-		if s.qualifiesForBadge(user, requirements) {
-			// Award badge to user
-			userBadge, err := s.badgeRepo.AwardBadge(userID, badge.ID)
-			if err == nil {
-				// Grant bonus credits if badge has bonus
-				// This incentivizes users to earn badges
-				if badge.BonusCredits > 0 {
-					user.CreditBalance += badge.BonusCredits
-					_ = s.userRepo.Update(user)
-				}
-				awardedBadges = append(awardedBadges, *userBadge)
-
-				// Send badge achievement notification
-				notificationData := map[string]interface{}{
-					"badgeID":   badge.ID,
-					"badgeName": badge.Name,
-					"rarity":    badge.Rarity,
-					"bonus":     badge.BonusCredits,
-				}
-				_, _ = s.notificationService.CreateNotification(
-					userID,
-					models.NotificationTypeAchievement,
-					"Badge Unlocked! 🏆",
-					fmt.Sprintf("You unlocked the %s badge! Rarity: %d/5", badge.Name, badge.Rarity),
-					notificationData,
-				)
-			}
-		}
+	awardedBadges, err := s.awardQualifying(user, badges)
+	if err != nil {
+		return nil, err
 	}
-
 	return dto.MapUserBadgesToResponse(awardedBadges), nil
 }
 
-// PinBadge pins or unpins a badge for a user
-// This allows users to showcase their favorite badges on their profile
-func (s *BadgeService) PinBadge(userID, badgeID uint, isPinned bool) error {
-	// Verify user has this badge
-	hasBadge, err := s.badgeRepo.HasUserBadge(userID, badgeID)
-	if err != nil {
-		return fmt.Errorf("failed to verify badge ownership: %w", err)
-	}
-	if !hasBadge {
-		return errors.New("user does not have this badge")
-	}
-
-	// Update pin status in database
-	if err := s.badgeRepo.PinBadge(userID, badgeID, isPinned); err != nil {
-		return fmt.Errorf("failed to update badge pin status: %w", err)
-	}
-
-	return nil
+// compiledBadge is an active badge with its parsed rule
+type compiledBadge struct {
+	badge models.Badge
+	rule  *BadgeRule
 }
 
-// qualifiesForBadge checks if user meets badge requirements
-// This is a private method that evaluates badge qualification criteria
-// Performance: O(1) - constant time lookup and comparison
-// Logic: AND operation - ALL requirements must be met to qualify
-// Extensibility: New requirements can be added without breaking existing logic
-//
-// Supported Requirements (optional - any can be omitted):
-//   - "sessions": Total sessions (teaching + learning) required
-//   - "teaching_sessions": Minimum teaching sessions required
-//   - "learning_sessions": Minimum learning sessions required
-//   - "rating": Minimum average rating required (0-5 scale)
-//   - "credits_earned": Minimum credits earned from teaching required
-//
-// If a requirement is not specified in the badge, it's skipped (no check)
-// This allows flexible badge definitions
-//
-// Parameters:
-//   - user: User to check (must have stats pre-loaded)
-//   - requirements: Map of requirement keys to values (parsed from JSON)
-//
-// Returns:
-//   - true: User meets ALL specified requirements
-//   - false: User fails at least one requirement
-//
-// Example:
-//   requirements := map[string]interface{}{
-//     "teaching_sessions": 20.0,
-//     "rating": 4.5,
-//   }
-//   qualifies := qualifiesForBadge(user, requirements)
-func (s *BadgeService) qualifiesForBadge(user *models.User, requirements map[string]interface{}) bool {
-	// TOTAL SESSIONS CHECK: Verify combined teaching + learning sessions
-	// Used for badges like "Dedicated Participant" (10+ total sessions)
-	// Represents overall engagement in the platform
-	if sessionsReq, ok := requirements["sessions"].(float64); ok {
-		totalSessions := user.TotalSessionsAsTeacher + user.TotalSessionsAsStudent
-		if totalSessions < int(sessionsReq) {
-			return false // Doesn't meet minimum total sessions
-		}
-	}
-
-	// TEACHING SESSIONS CHECK: Verify teaching experience
-	// Used for badges like "Dedicated Teacher" (20+ teaching sessions)
-	// Represents commitment to helping others
-	if teachingReq, ok := requirements["teaching_sessions"].(float64); ok {
-		if user.TotalSessionsAsTeacher < int(teachingReq) {
-			return false // Doesn't meet minimum teaching sessions
-		}
-	}
-
-	// LEARNING SESSIONS CHECK: Verify learning experience
-	// Used for badges like "Knowledge Seeker" (10+ learning sessions)
-	// Represents commitment to personal growth
-	if learningReq, ok := requirements["learning_sessions"].(float64); ok {
-		if user.TotalSessionsAsStudent < int(learningReq) {
-			return false // Doesn't meet minimum learning sessions
-		}
-	}
-
-	// RATING CHECK: Verify quality of teaching/learning
-	// Calculates average of teacher and student ratings: (teacher_rating + student_rating) / 2
-	// Used for badges like "Top Tutor" (4.8+ rating)
-	// Represents user's reputation and quality
-	if ratingReq, ok := requirements["rating"].(float64); ok {
-		avgRating := (user.AverageRatingAsTeacher + user.AverageRatingAsStudent) / 2
-		if avgRating < ratingReq {
-			return false // Doesn't meet minimum rating threshold
-		}
+// compileActiveBadges loads the active badges and parses their requirements
+// A badge with invalid requirements is logged and skipped, so it is never awarded.
+func (s *BadgeService) compileActiveBadges() ([]*compiledBadge, error) {
+	allBadges, err := s.badgeRepo.GetAllBadges()
+	if err != nil {
+		return nil, err
 	}
 
-	// CREDITS EARNED CHECK: Verify teaching productivity
-	// Used for badges like "Platinum Teacher" (100+ hours taught)
-	// Represents total time credits earned from teaching
-	if creditsReq, ok := requirements["credits_earned"].(float64); ok {
-		if int(user.TotalEarned) < int(creditsReq) {
-			return false // Doesn't meet minimum credits earned
+	compiled := make([]*compiledBadge, 0, len(allBadges))
+	for _, badge := range allBadges {
+		rule, err := ParseBadgeRule(badge.Requirements)
+		if err != nil {
+			log.Printf("WARNING: Skipping badge %d (%s): %v", badge.ID, badge.Name, err)
+			continue
 		}
+		compiled = append(compiled, &compiledBadge{badge: badge, rule: rule})
 	}
-
-	// All requirements met - user qualifies for this badge
-	return true
+	return compiled, nil
 }
 
-// GetBadgeLeaderboard gets top users by badge count
-func (s *BadgeService) GetBadgeLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
+// awardQualifying evaluates the badges a user does not hold yet and awards those they qualify for
+// Held badges are loaded with one query, and metric values are shared by all badges.
+func (s *BadgeService) awardQualifying(user *models.User, badges []*compiledBadge) ([]models.UserBadge, error) {
+	awardedBadges := []models.UserBadge{}
 
-	results, err := s.badgeRepo.GetBadgeLeaderboard(limit)
+	held, err := s.closedBadgeIDs(user.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch leaderboard: %w", err)
+		return nil, err
 	}
 
-	var leaderboard []dto.LeaderboardEntry
-	for _, result := range results {
-		var userID uint
-		var badgeCount int
-
-		// Robust type extraction for user_id (can be int64 or float64 depending on driver/aggregation)
-		if uid, ok := result["user_id"].(int64); ok {
-			userID = uint(uid)
-		} else if uid, ok := result["user_id"].(float64); ok {
-			userID = uint(uid)
-		} else {
-			continue // Skip invalid entries
-		}
-
-		// Robust type extraction for badge_count
-		if count, ok := result["badge_count"].(int64); ok {
-			badgeCount = int(count)
-		} else if count, ok := result["badge_count"].(int); ok {
-			badgeCount = count
-		} else if count, ok := result["badge_count"].(float64); ok {
-			badgeCount = int(count)
+	evaluation := s.ruleEngine.NewEvaluation(user)
+	unmet := make(map[*compiledBadge]*BadgeRuleResult)
+	for _, compiled := range badges {
+		// Skip if user already has this badge (prevent duplicate awards) or it was permanently revoked
+		if held[compiled.badge.ID] {
+			continue
 		}
 
-		user, err := s.userRepo.GetByID(userID)
+		result, err := evaluation.Evaluate(compiled.rule)
 		if err != nil {
+			log.Printf("ERROR: Failed to evaluate badge %d for user %d: %v", compiled.badge.ID, user.ID, err)
 			continue
 		}
-
-		leaderboard = append(leaderboard, dto.LeaderboardEntry{
-			UserID:    userID,
-			Username:  user.Username,
-			FullName:  user.FullName,
-			Avatar:    user.Avatar,
-			Score:     badgeCount,
-			ScoreType: "badges",
-		})
-	}
-
-	return leaderboard, nil
-}
-
-// GetRarityLeaderboard gets top users by badge rarity
-func (s *BadgeService) GetRarityLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
-
-	results, err := s.badgeRepo.GetRarityLeaderboard(limit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch leaderboard: %w", err)
-	}
-
-	var leaderboard []dto.LeaderboardEntry
-	for _, result := range results {
-		var userID uint
-		var totalRarity int
-
-		// Robust type extraction for user_id
-		if uid, ok := result["user_id"].(int64); ok {
-			userID = uint(uid)
-		} else if uid, ok := result["user_id"].(float64); ok {
-			userID = uint(uid)
-		} else {
+		if !result.Met {
+			unmet[compiled] = result
 			continue
 		}
 
-		// Robust type extraction for total_rarity
-		if rarity, ok := result["total_rarity"].(int64); ok {
-			totalRarity = int(rarity)
-		} else if rarity, ok := result["total_rarity"].(int); ok {
-			totalRarity = rarity
-		} else if rarity, ok := result["total_rarity"].(float64); ok {
-			totalRarity = int(rarity)
+		// Award badge to user (grants bonus credits and notifies)
+		userBadge, err := s.awardBadge(user.ID, &compiled.badge)
+		if errors.Is(err, utils.ErrBadgeAlreadyHeld) {
+			// Awarded concurrently by another path
+			held[compiled.badge.ID] = true
+			continue
 		}
-
-		user, err := s.userRepo.GetByID(userID)
 		if err != nil {
+			log.Printf("ERROR: Failed to award badge %d to user %d: %v", compiled.badge.ID, user.ID, err)
 			continue
 		}
-
-		leaderboard = append(leaderboard, dto.LeaderboardEntry{
-			UserID:    userID,
-			Username:  user.Username,
-			FullName:  user.FullName,
-			Avatar:    user.Avatar,
-			Score:     totalRarity,
-			ScoreType: "rarity",
-		})
+		held[compiled.badge.ID] = true
+		awardedBadges = append(awardedBadges, *userBadge)
 	}
 
-	return leaderboard, nil
+	// Keep progress toward the remaining badges current
+	s.updateProgress(user.ID, unmet)
+
+	return awardedBadges, nil
 }
 
-// GetSessionLeaderboard retrieves top users ranked by total session count (teaching + learning)
-// Performance: O(n log n) due to sort.Slice
-// Caching: Results should be cached for 5-10 minutes to reduce database load
-//
-// Algorithm:
-// 1. Fetch all users from database
-// 2. Count sessions for each user (teaching + learning)
-// 3. Filter users with at least 1 session
-// 4. Sort by total session count (descending)
-// 5. Return top N entries
-//
-// Parameters:
-//   - limit: Maximum number of entries (clamped to 1-100, default 10)
-//
-// Returns:
-//   - []LeaderboardEntry: Sorted leaderboard entries
-//   - error: If database fetch fails
-//
-// Example:
-//   leaderboard, err := badgeService.GetSessionLeaderboard(10)
-//   // Returns top 10 users by session count
-func (s *BadgeService) GetSessionLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
-	// Validate and normalize limit parameter
-	if limit <= 0 || limit > 100 {
-		limit = 10
+// heldBadgeIDs returns the set of badges a user holds
+func (s *BadgeService) heldBadgeIDs(userID uint) (map[uint]bool, error) {
+	heldIDs, err := s.badgeRepo.GetUserBadgeIDs(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user badges: %w", err)
+	}
+	held := make(map[uint]bool, len(heldIDs))
+	for _, id := range heldIDs {
+		held[id] = true
 	}
+	return held, nil
+}
 
-	// Fetch all users from database
-	users, err := s.userRepo.GetAll()
+// closedBadgeIDs returns the set of badges a user cannot be awarded: held or permanently revoked
+func (s *BadgeService) closedBadgeIDs(userID uint) (map[uint]bool, error) {
+	closed, err := s.heldBadgeIDs(userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch users: %w", err)
+		return nil, err
 	}
-
-	// Helper struct to hold user with their session count
-	type userSessionCount struct {
-		User  models.User
-		Count int64
+	revokedIDs, err := s.badgeRepo.GetPermanentlyRevokedBadgeIDs(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch revoked badges: %w", err)
 	}
-	var userCounts []userSessionCount
-
-	// Count total sessions for each user (teaching + learning)
-	// Only include users with at least 1 session to reduce leaderboard noise
-	for _, user := range users {
-		// Count sessions where user is the teacher
-		teacherCount, _ := s.sessionRepo.CountUserSessionsAsTeacher(user.ID)
-		// Count sessions where user is the student/learner
-		studentCount, _ := s.sessionRepo.CountUserSessionsAsStudent(user.ID)
-		totalCount := teacherCount + studentCount
-
-		// Only include users with activity
-		if totalCount > 0 {
-			userCounts = append(userCounts, userSessionCount{
-				User:  user,
-				Count: totalCount,
-			})
-		}
+	for _, id := range revokedIDs {
+		closed[id] = true
 	}
+	return closed, nil
+}
 
-	// Sort by session count in descending order using efficient sort.Slice
-	// Time complexity: O(n log n) instead of O(n²) with bubble sort
-	sort.Slice(userCounts, func(i, j int) bool {
-		return userCounts[i].Count > userCounts[j].Count
-	})
-
-	// Build leaderboard response with top N entries
-	var leaderboard []dto.LeaderboardEntry
-	for i, uc := range userCounts {
-		// Stop after reaching limit
-		if i >= limit {
-			break
-		}
-		leaderboard = append(leaderboard, dto.LeaderboardEntry{
-			UserID:    uc.User.ID,
-			Username:  uc.User.Username,
-			FullName:  uc.User.FullName,
-			Avatar:    uc.User.Avatar,
-			Score:     int(uc.Count),
-			ScoreType: "sessions",
+// awardBadge awards a badge to a user, grants its bonus credits and sends the notification
+// Returns utils.ErrBadgeAlreadyHeld, without granting anything, when the user already holds it.
+// Bonus credits are posted with the award itself and recorded as an expirable bonus so the
+// credit policy can expire them when unused.
+func (s *BadgeService) awardBadge(userID uint, badge *models.Badge) (*models.UserBadge, error) {
+	// Grant bonus credits if badge has bonus
+	// This incentivizes users to earn badges
+	var bonus *models.Transaction
+	if badge.BonusCredits > 0 {
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"badge_id":  badge.ID,
+			"expirable": true,
 		})
+		bonus = &models.Transaction{
+			UserID:      userID,
+			Type:        models.TransactionBonus,
+			Amount:      badge.BonusCredits,
+			Description: "Badge bonus: " + badge.Name,
+			Metadata:    string(metadata),
+		}
 	}
 
-	return leaderboard, nil
-}
+	userBadge, err := s.badgeRepo.AwardBadge(userID, badge.ID, bonus)
+	if err != nil {
+		return nil, err
+	}
 
-// GetRatingLeaderboard retrieves top users ranked by average rating (teacher + student average)
-// Performance: O(n log n) due to sort.Slice
-// Caching: Results should be cached for 5-10 minutes to reduce computation
-//
-// Algorithm:
-// 1. Fetch all users from database
-// 2. Calculate average rating for each user: (teacherRating + studentRating) / 2
-// 3. Filter users with at least some rating (> 0)
-// 4. Sort by average rating (descending)
-// 5. Return top N entries
-//
-// Rating Calculation:
-//   - Combines both teaching and learning ratings equally
-//   - Provides balanced view of user quality
-//   - Only includes users with at least one rating
-//
-// Parameters:
-//   - limit: Maximum number of entries (clamped to 1-100, default 10)
-//
-// Returns:
-//   - []LeaderboardEntry: Sorted leaderboard entries (score stored as int: 450 = 4.5 stars)
-//   - error: If database fetch fails
-//
-// Example:
-//   leaderboard, err := badgeService.GetRatingLeaderboard(10)
-//   // Returns top 10 users by average rating
-func (s *BadgeService) GetRatingLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
-	// Validate and normalize limit parameter
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	// Send badge achievement notification
+	notificationData := map[string]interface{}{
+		"badgeID":   badge.ID,
+		"badgeName": badge.Name,
+		"rarity":    badge.Rarity,
+		"bonus":     badge.BonusCredits,
 	}
+	_, _ = s.notificationService.CreateNotification(
+		userID,
+		models.NotificationTypeAchievement,
+		"Badge Unlocked! 🏆",
+		fmt.Sprintf("You unlocked the %s badge! Rarity: %d/5", badge.Name, badge.Rarity),
+		notificationData,
+	)
 
-	// Fetch all users from database
-	users, err := s.userRepo.GetAll()
+	events.PublishWithData(events.BadgeAwarded, map[string]interface{}{"badge_id": badge.ID}, userID)
+	return userBadge, nil
+}
+
+// PinBadge pins or unpins a badge for a user
+// This allows users to showcase their favorite badges on their profile
+func (s *BadgeService) PinBadge(userID, badgeID uint, isPinned bool) error {
+	// Verify user has this badge
+	hasBadge, err := s.badgeRepo.HasUserBadge(userID, badgeID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch users: %w", err)
+		return fmt.Errorf("failed to verify badge ownership: %w", err)
 	}
-
-	// Helper struct to hold user with their calculated average rating
-	type userRating struct {
-		User      models.User
-		AvgRating float64
+	if !hasBadge {
+		return errors.New("user does not have this badge")
 	}
-	var userRatings []userRating
-
-	// Calculate average rating for each user
-	// Average combines both teaching and learning ratings equally
-	for _, user := range users {
-		// Calculate average: (teacher_rating + student_rating) / 2
-		// This gives balanced view of user quality in both roles
-		avgRating := (user.AverageRatingAsTeacher + user.AverageRatingAsStudent) / 2
-		// Only include users with at least some rating
-		if avgRating > 0 {
-			userRatings = append(userRatings, userRating{
-				User:      user,
-				AvgRating: avgRating,
-			})
-		}
+
+	// Update pin status in database
+	if err := s.badgeRepo.PinBadge(userID, badgeID, isPinned); err != nil {
+		return fmt.Errorf("failed to update badge pin status: %w", err)
 	}
 
-	// Sort by average rating in descending order using efficient sort.Slice
-	// Time complexity: O(n log n) instead of O(n²) with bubble sort
-	sort.Slice(userRatings, func(i, j int) bool {
-		return userRatings[i].AvgRating > userRatings[j].AvgRating
-	})
+	return nil
+}
 
+// GetBadgeLeaderboard gets top users by badge count (all time, global)
+func (s *BadgeService) GetBadgeLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
+	return s.GetLeaderboard(models.LeaderboardBadges, dto.LeaderboardQuery{}, limit)
+}
 
-	// Build leaderboard response with top N entries
-	var leaderboard []dto.LeaderboardEntry
-	for i, ur := range userRatings {
-		// Stop after reaching limit
-		if i >= limit {
-			break
-		}
-		leaderboard = append(leaderboard, dto.LeaderboardEntry{
-			UserID:    ur.User.ID,
-			Username:  ur.User.Username,
-			FullName:  ur.User.FullName,
-			Avatar:    ur.User.Avatar,
-			// Store rating as integer: multiply by 100 (e.g., 4.5 stars = 450)
-			// This avoids floating-point precision issues in JSON
-			Score:     int(ur.AvgRating * 100),
-			ScoreType: "rating",
-		})
-	}
+// GetRarityLeaderboard gets top users by badge rarity (all time, global)
+func (s *BadgeService) GetRarityLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
+	return s.GetLeaderboard(models.LeaderboardRarity, dto.LeaderboardQuery{}, limit)
+}
 
-	return leaderboard, nil
+// GetSessionLeaderboard gets top users by completed session count, teaching + learning
+// (all time, global)
+func (s *BadgeService) GetSessionLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
+	return s.GetLeaderboard(models.LeaderboardSessions, dto.LeaderboardQuery{}, limit)
 }
 
-// GetCreditLeaderboard retrieves top users ranked by total credits earned from teaching
-// Performance: O(n log n) due to sort.Slice
-// Caching: Results should be cached for 5-10 minutes to reduce computation
-//
-// Algorithm:
-// 1. Fetch all users from database
-// 2. Filter users with at least 1 credit earned
-// 3. Sort by total credits earned (descending)
-// 4. Return top N entries
-//
-// Credits Earned:
-//   - Represents total time credits user has earned from teaching
-//   - Higher value indicates more teaching experience and productivity
-//   - Only includes completed sessions
-//
-// Parameters:
-//   - limit: Maximum number of entries (clamped to 1-100, default 10)
-//
-// Returns:
-//   - []LeaderboardEntry: Sorted leaderboard entries
-//   - error: If database fetch fails
-//
-// Example:
-//   leaderboard, err := badgeService.GetCreditLeaderboard(10)
-//   // Returns top 10 users by credits earned
+// GetRatingLeaderboard gets top users by average rating, (teacher + student) / 2
+// (all time, global); score is stored as int x100 (450 = 4.5 stars)
+func (s *BadgeService) GetRatingLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
+	return s.GetLeaderboard(models.LeaderboardRating, dto.LeaderboardQuery{}, limit)
+}
+
+// GetCreditLeaderboard gets top users by total credits earned (all time, global)
 func (s *BadgeService) GetCreditLeaderboard(limit int) ([]dto.LeaderboardEntry, error) {
-	// Validate and normalize limit parameter
-	if limit <= 0 || limit > 100 {
-		limit = 10
-	}
+	return s.GetLeaderboard(models.LeaderboardCredits, dto.LeaderboardQuery{}, limit)
+}
 
-	// Fetch all users from database
-	users, err := s.userRepo.GetAll()
+// DeleteBadge deletes a badge by ID (admin only)
+// The badge is revoked from every holder first; with clawback their remaining bonus is taken back.
+//
+// Returns:
+//   - int: Number of users the badge was revoked from
+//   - error: utils.ErrBadgeNotFound or a database error
+func (s *BadgeService) DeleteBadge(adminID, badgeID uint, clawback bool) (int, error) {
+	badge, err := s.getBadge(badgeID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch users: %w", err)
+		return 0, err
 	}
 
-	// Helper struct to hold user with their total credits earned
-	type userCredit struct {
-		User   models.User
-		Credit int
-	}
-	var userCredits []userCredit
-
-	// Collect users with at least some credits earned
-	// Only include active teachers to reduce leaderboard noise
-	for _, user := range users {
-		if user.TotalEarned > 0 {
-			userCredits = append(userCredits, userCredit{
-				User:   user,
-				Credit: int(user.TotalEarned),
-			})
-		}
-	}
-
-	// Sort by credits earned in descending order using efficient sort.Slice
-	// Time complexity: O(n log n) instead of O(n²) with bubble sort
-	sort.Slice(userCredits, func(i, j int) bool {
-		return userCredits[i].Credit > userCredits[j].Credit
+	revoked, err := s.revokeFromHolders(badge, revokeOptions{
+		reason:    revokeReasonRetired,
+		revokedBy: &adminID,
+		clawback:  clawback,
 	})
-
-	// Build leaderboard response with top N entries
-	var leaderboard []dto.LeaderboardEntry
-	for i, uc := range userCredits {
-		// Stop after reaching limit
-		if i >= limit {
-			break
-		}
-		leaderboard = append(leaderboard, dto.LeaderboardEntry{
-			UserID:    uc.User.ID,
-			Username:  uc.User.Username,
-			FullName:  uc.User.FullName,
-			Avatar:    uc.User.Avatar,
-			Score:     uc.Credit,
-			ScoreType: "credits",
-		})
+	if err != nil {
+		return 0, err
 	}
 
-	return leaderboard, nil
-}
-
-// DeleteBadge deletes a badge by ID (admin only)
-func (s *BadgeService) DeleteBadge(badgeID uint) error {
-	return s.badgeRepo.Delete(badgeID)
+	if err := s.badgeRepo.Delete(badgeID); err != nil {
+		return revoked, err
+	}
+	events.Publish(events.BadgesChanged)
+	return revoked, nil
 }
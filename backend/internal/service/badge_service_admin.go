@@ -0,0 +1,384 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// defaultBadgeDryRunSample is the number of qualifying users listed by a dry run
+const defaultBadgeDryRunSample = 10
+
+// badgeUserBatchSize is the number of users loaded at a time when evaluating a rule for everyone
+const badgeUserBatchSize = 200
+
+// badgeBackfillStaleAfter is how long a running backfill may go without saving progress
+// before it is considered dead (e.g. the server restarted) and a new one may start
+const badgeBackfillStaleAfter = 10 * time.Minute
+
+// CreateBadge creates a badge after validating its requirements (admin only)
+// Requirements are stored in normalized form, so aliases are resolved on save.
+//
+// Returns:
+//   - *AdminBadgeResponse: The badge, plus the backfill outcome if requested
+//   - error: utils.ErrInvalidBadgeRule, utils.ErrBadgeNameTaken, or a database error
+func (s *BadgeService) CreateBadge(req *dto.CreateBadgeRequest) (*dto.AdminBadgeResponse, error) {
+	requirements, err := normalizeBadgeRequirements(req.Requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkBadgeName(name, 0); err != nil {
+		return nil, err
+	}
+
+	badge := &models.Badge{
+		Name:         name,
+		Description:  req.Description,
+		Icon:         req.Icon,
+		Type:         models.BadgeType(req.Type),
+		Requirements: requirements,
+		BonusCredits: req.BonusCredits,
+		Rarity:       req.Rarity,
+		Color:        req.Color,
+		IsActive:     true,
+		DisplayOrder: req.DisplayOrder,
+	}
+	if badge.Rarity == 0 {
+		badge.Rarity = 1
+	}
+	if req.IsActive != nil {
+		badge.IsActive = *req.IsActive
+	}
+
+	if err := s.badgeRepo.CreateBadge(badge); err != nil {
+		return nil, fmt.Errorf("failed to create badge: %w", err)
+	}
+	// GORM skips zero values that have a column default, so store an explicit inactive flag
+	if !badge.IsActive {
+		if err := s.badgeRepo.UpdateBadge(badge); err != nil {
+			return nil, fmt.Errorf("failed to create badge: %w", err)
+		}
+	}
+	events.Publish(events.BadgesChanged)
+
+	return s.adminBadgeResponse(badge, req.Backfill)
+}
+
+// UpdateBadge updates a badge (admin only); omitted fields are left unchanged
+// Changing requirements does not revoke the badge from users who already hold it (see RevokeBadge).
+//
+// Returns:
+//   - *AdminBadgeResponse: The badge, plus the backfill outcome if requested
+//   - error: utils.ErrBadgeNotFound, utils.ErrInvalidBadgeRule, utils.ErrBadgeNameTaken, or a database error
+func (s *BadgeService) UpdateBadge(badgeID uint, req *dto.UpdateBadgeRequest) (*dto.AdminBadgeResponse, error) {
+	badge, err := s.getBadge(badgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Requirements) > 0 {
+		requirements, err := normalizeBadgeRequirements(req.Requirements)
+		if err != nil {
+			return nil, err
+		}
+		badge.Requirements = requirements
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := s.checkBadgeName(name, badge.ID); err != nil {
+			return nil, err
+		}
+		badge.Name = name
+	}
+	if req.Description != nil {
+		badge.Description = *req.Description
+	}
+	if req.Icon != nil {
+		badge.Icon = *req.Icon
+	}
+	if req.Type != nil {
+		badge.Type = models.BadgeType(*req.Type)
+	}
+	if req.BonusCredits != nil {
+		badge.BonusCredits = *req.BonusCredits
+	}
+	if req.Rarity != nil {
+		badge.Rarity = *req.Rarity
+	}
+	if req.Color != nil {
+		badge.Color = *req.Color
+	}
+	if req.IsActive != nil {
+		badge.IsActive = *req.IsActive
+	}
+	if req.DisplayOrder != nil {
+		badge.DisplayOrder = *req.DisplayOrder
+	}
+
+	if err := s.badgeRepo.UpdateBadge(badge); err != nil {
+		return nil, fmt.Errorf("failed to update badge: %w", err)
+	}
+	events.Publish(events.BadgesChanged)
+
+	return s.adminBadgeResponse(badge, req.Backfill)
+}
+
+// DryRunBadge reports how many active users meet the requirements today, without awarding anything
+// Every active user is evaluated (in batches), so this is meant for occasional admin use.
+//
+// Returns:
+//   - *BadgeDryRunResponse: Counts and a sample of qualifying users who would be awarded
+//   - error: utils.ErrInvalidBadgeRule, utils.ErrBadgeNotFound, or a database error
+func (s *BadgeService) DryRunBadge(req *dto.BadgeDryRunRequest) (*dto.BadgeDryRunResponse, error) {
+	rule, err := ParseBadgeRule(string(req.Requirements))
+	if err != nil {
+		return nil, err
+	}
+	if req.BadgeID != 0 {
+		if _, err := s.getBadge(req.BadgeID); err != nil {
+			return nil, err
+		}
+	}
+
+	sampleSize := req.SampleSize
+	if sampleSize <= 0 {
+		sampleSize = defaultBadgeDryRunSample
+	}
+
+	result := &dto.BadgeDryRunResponse{Sample: []dto.BadgeQualifier{}}
+	err = s.forEachQualifyingUser(rule, func(user *models.User) error {
+		result.Qualifying++
+		if req.BadgeID != 0 {
+			hasIt, err := s.badgeRepo.HasUserBadge(user.ID, req.BadgeID)
+			if err != nil {
+				return err
+			}
+			if hasIt {
+				result.AlreadyAwarded++
+				return nil
+			}
+		}
+
+		result.WouldBeAwarded++
+		if len(result.Sample) < sampleSize {
+			result.Sample = append(result.Sample, dto.BadgeQualifier{
+				UserID:   user.ID,
+				Username: user.Username,
+				FullName: user.FullName,
+				Avatar:   user.Avatar,
+			})
+		}
+		return nil
+	}, &result.UsersChecked, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// startBackfill starts a background job awarding an active badge, with its bonus credits,
+// to every active user who qualifies today, does not hold it yet and did not have it
+// permanently revoked
+// If a backfill of the badge is already running, that job is returned instead.
+func (s *BadgeService) startBackfill(badge *models.Badge) (*models.BadgeBackfill, error) {
+	if !badge.IsActive {
+		return nil, errors.New("cannot backfill an inactive badge")
+	}
+	rule, err := ParseBadgeRule(badge.Requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := s.badgeRepo.GetRunningBackfill(badge.ID, time.Now().Add(-badgeBackfillStaleAfter))
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return running, nil
+	}
+
+	backfill := &models.BadgeBackfill{BadgeID: badge.ID, Status: models.BadgeBackfillRunning}
+	if err := s.badgeRepo.CreateBackfill(backfill); err != nil {
+		return nil, fmt.Errorf("failed to create backfill: %w", err)
+	}
+
+	// The job works on its own copies; the caller keeps using backfill and badge
+	job, jobBadge := *backfill, *badge
+	go s.runBackfill(&job, &jobBadge, rule)
+	return backfill, nil
+}
+
+// runBackfill awards the badge to qualifying users, saving progress after each batch
+func (s *BadgeService) runBackfill(backfill *models.BadgeBackfill, badge *models.Badge, rule *BadgeRule) {
+	saveProgress := func() {
+		if err := s.badgeRepo.UpdateBackfill(backfill); err != nil {
+			log.Printf("⚠️  Failed to save progress of badge backfill %d: %v", backfill.ID, err)
+		}
+	}
+
+	err := s.forEachQualifyingUser(rule, func(user *models.User) error {
+		hasIt, err := s.badgeRepo.HasUserBadge(user.ID, badge.ID)
+		if err != nil {
+			return err
+		}
+		if hasIt {
+			return nil
+		}
+		revoked, err := s.badgeRepo.IsBadgePermanentlyRevoked(user.ID, badge.ID)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return nil
+		}
+
+		if _, err := s.awardBadge(user.ID, badge); errors.Is(err, utils.ErrBadgeAlreadyHeld) {
+			return nil
+		} else if err != nil {
+			log.Printf("ERROR: Failed to backfill badge %d for user %d: %v", badge.ID, user.ID, err)
+			backfill.Failed++
+			return nil
+		}
+		backfill.Awarded++
+		backfill.BonusCredits += badge.BonusCredits
+		return nil
+	}, &backfill.UsersChecked, saveProgress)
+
+	now := time.Now()
+	backfill.FinishedAt = &now
+	backfill.Status = models.BadgeBackfillCompleted
+	if err != nil {
+		backfill.Status = models.BadgeBackfillFailed
+		backfill.Error = err.Error()
+		log.Printf("⚠️  Badge backfill %d of %s failed: %v", backfill.ID, badge.Name, err)
+	} else {
+		log.Printf("🏅 Backfilled badge %s to %d of %d users", badge.Name, backfill.Awarded, backfill.UsersChecked)
+	}
+	saveProgress()
+}
+
+// GetBackfill returns a badge backfill job with its progress or outcome (admin only)
+//
+// Returns:
+//   - *BadgeBackfill: The job
+//   - error: utils.ErrBackfillNotFound or a database error
+func (s *BadgeService) GetBackfill(backfillID uint) (*models.BadgeBackfill, error) {
+	backfill, err := s.badgeRepo.GetBackfillByID(backfillID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrBackfillNotFound
+		}
+		return nil, err
+	}
+	return backfill, nil
+}
+
+// forEachQualifyingUser evaluates a rule for every active user and calls fn for those who meet it
+// Users are loaded in batches of badgeUserBatchSize. checked receives the number of users
+// evaluated; afterBatch, if set, is called after each batch.
+func (s *BadgeService) forEachQualifyingUser(rule *BadgeRule, fn func(user *models.User) error, checked *int, afterBatch func()) error {
+	var lastID uint
+	for {
+		users, err := s.userRepo.GetActiveBatch(lastID, badgeUserBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch users: %w", err)
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		for i := range users {
+			user := &users[i]
+			*checked++
+
+			result, err := s.ruleEngine.NewEvaluation(user).Evaluate(rule)
+			if err != nil {
+				return err
+			}
+			if !result.Met {
+				continue
+			}
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+
+		if afterBatch != nil {
+			afterBatch()
+		}
+		if len(users) < badgeUserBatchSize {
+			return nil
+		}
+		lastID = users[len(users)-1].ID
+	}
+}
+
+// adminBadgeResponse maps a saved badge and starts the backfill if requested
+func (s *BadgeService) adminBadgeResponse(badge *models.Badge, backfill bool) (*dto.AdminBadgeResponse, error) {
+	response := &dto.AdminBadgeResponse{Badge: dto.MapBadgeToResponse(badge)}
+	if !backfill {
+		return response, nil
+	}
+
+	job, err := s.startBackfill(badge)
+	if err != nil {
+		return nil, fmt.Errorf("badge saved but backfill could not start: %w", err)
+	}
+	response.Backfill = job
+	return response, nil
+}
+
+// getBadge loads a badge or returns utils.ErrBadgeNotFound
+func (s *BadgeService) getBadge(badgeID uint) (*models.Badge, error) {
+	badge, err := s.badgeRepo.GetBadgeByID(badgeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrBadgeNotFound
+		}
+		return nil, err
+	}
+	return badge, nil
+}
+
+// checkBadgeName rejects empty names and names used by another badge
+func (s *BadgeService) checkBadgeName(name string, badgeID uint) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", utils.ErrInvalidBadge)
+	}
+	existing, err := s.badgeRepo.GetBadgeByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != badgeID {
+		return utils.ErrBadgeNameTaken
+	}
+	return nil
+}
+
+// normalizeBadgeRequirements validates requirements JSON and returns it in normalized form
+func normalizeBadgeRequirements(raw json.RawMessage) (string, error) {
+	rule, err := ParseBadgeRule(string(raw))
+	if err != nil {
+		return "", err
+	}
+	normalized, err := json.Marshal(rule)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode badge requirements: %w", err)
+	}
+	return string(normalized), nil
+}
@@ -0,0 +1,740 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// defaultChallengeLeaderboardSize is the number of entries returned by GetLeaderboard
+const defaultChallengeLeaderboardSize = 20
+
+// ChallengeService handles time-limited challenges: admin authoring, opt-in enrollment,
+// progress and completion rewards
+//
+// Goals reuse the badge rule engine, but every condition counts only activity between the
+// challenge start and end (see BadgeRuleEngine.NewPeriodEvaluation). Progress is updated from
+// domain events the same way badges are: events are recorded when published and the
+// affected users' enrollments are evaluated every interval.
+type ChallengeService struct {
+	challengeRepo       *repository.ChallengeRepository
+	userRepo            *repository.UserRepository
+	transactionRepo     *repository.TransactionRepository
+	badgeService        *BadgeService
+	notificationService *NotificationService
+	ruleEngine          *BadgeRuleEngine
+	bus                 *events.Bus
+	interval            time.Duration
+
+	mu      sync.Mutex
+	pending map[uint]map[events.Type]bool
+
+	// Only used by Flush, which flushMu serializes
+	flushMu   sync.Mutex
+	lastFlush time.Time
+}
+
+// NewChallengeService creates a new challenge service
+func NewChallengeService(
+	challengeRepo *repository.ChallengeRepository,
+	userRepo *repository.UserRepository,
+	transactionRepo *repository.TransactionRepository,
+	badgeService *BadgeService,
+	notificationService *NotificationService,
+	bus *events.Bus,
+	interval time.Duration,
+) *ChallengeService {
+	return &ChallengeService{
+		challengeRepo:       challengeRepo,
+		userRepo:            userRepo,
+		transactionRepo:     transactionRepo,
+		badgeService:        badgeService,
+		notificationService: notificationService,
+		ruleEngine:          badgeService.ruleEngine,
+		bus:                 bus,
+		interval:            interval,
+		pending:             make(map[uint]map[events.Type]bool),
+		lastFlush:           time.Now(),
+	}
+}
+
+// ===== ADMIN OPERATIONS =====
+
+// CreateChallenge creates a challenge (admin only)
+//
+// Returns:
+//   - *ChallengeResponse: The created challenge
+//   - error: utils.ErrInvalidChallenge, utils.ErrInvalidBadgeRule, utils.ErrBadgeNotFound, or a database error
+func (s *ChallengeService) CreateChallenge(req *dto.CreateChallengeRequest) (*dto.ChallengeResponse, error) {
+	goal, err := normalizeChallengeGoal(req.Goal)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &models.Challenge{
+		Title:           strings.TrimSpace(req.Title),
+		Description:     req.Description,
+		Icon:            req.Icon,
+		Season:          strings.TrimSpace(req.Season),
+		Goal:            goal,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+		RewardCredits:   req.RewardCredits,
+		RewardBadgeID:   req.RewardBadgeID,
+		MaxParticipants: req.MaxParticipants,
+		IsActive:        true,
+	}
+	if req.IsActive != nil {
+		challenge.IsActive = *req.IsActive
+	}
+	if err := s.validateChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	if err := s.challengeRepo.Create(challenge); err != nil {
+		return nil, fmt.Errorf("failed to create challenge: %w", err)
+	}
+	// GORM skips zero values that have a column default, so store an explicit inactive flag
+	if !challenge.IsActive {
+		if err := s.challengeRepo.Update(challenge); err != nil {
+			return nil, fmt.Errorf("failed to create challenge: %w", err)
+		}
+	}
+
+	return s.reload(challenge.ID)
+}
+
+// UpdateChallenge updates a challenge (admin only); omitted fields are left unchanged
+// The goal and start date are frozen once the challenge has started, so progress stays fair.
+//
+// Returns:
+//   - *ChallengeResponse: The updated challenge
+//   - error: utils.ErrChallengeNotFound, utils.ErrChallengeStarted, utils.ErrInvalidChallenge, or a database error
+func (s *ChallengeService) UpdateChallenge(challengeID uint, req *dto.UpdateChallengeRequest) (*dto.ChallengeResponse, error) {
+	challenge, err := s.getChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	started := !time.Now().Before(challenge.StartsAt)
+
+	if len(req.Goal) > 0 {
+		if started {
+			return nil, utils.ErrChallengeStarted
+		}
+		goal, err := normalizeChallengeGoal(req.Goal)
+		if err != nil {
+			return nil, err
+		}
+		challenge.Goal = goal
+	}
+	if req.StartsAt != nil && !req.StartsAt.Equal(challenge.StartsAt) {
+		if started {
+			return nil, utils.ErrChallengeStarted
+		}
+		challenge.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		challenge.EndsAt = *req.EndsAt
+	}
+	if req.Title != nil {
+		challenge.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		challenge.Description = *req.Description
+	}
+	if req.Icon != nil {
+		challenge.Icon = *req.Icon
+	}
+	if req.Season != nil {
+		challenge.Season = strings.TrimSpace(*req.Season)
+	}
+	if req.RewardCredits != nil {
+		challenge.RewardCredits = *req.RewardCredits
+	}
+	if req.RewardBadgeID != nil {
+		challenge.RewardBadgeID = req.RewardBadgeID
+		if *req.RewardBadgeID == 0 {
+			challenge.RewardBadgeID = nil
+		}
+		challenge.RewardBadge = nil
+	}
+	if req.MaxParticipants != nil {
+		challenge.MaxParticipants = *req.MaxParticipants
+	}
+	if req.IsActive != nil {
+		challenge.IsActive = *req.IsActive
+	}
+	if err := s.validateChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	if err := s.challengeRepo.Update(challenge); err != nil {
+		return nil, fmt.Errorf("failed to update challenge: %w", err)
+	}
+	return s.reload(challenge.ID)
+}
+
+// DeleteChallenge deletes a challenge (admin only)
+// Rewards already granted are kept.
+func (s *ChallengeService) DeleteChallenge(challengeID uint) error {
+	if _, err := s.getChallenge(challengeID); err != nil {
+		return err
+	}
+	return s.challengeRepo.Delete(challengeID)
+}
+
+// GetAllChallenges lists every challenge, including unpublished ones (admin only)
+func (s *ChallengeService) GetAllChallenges(status string) ([]dto.ChallengeResponse, error) {
+	challenges, err := s.challengeRepo.GetAll(false, models.ChallengeStatus(status), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch challenges: %w", err)
+	}
+
+	now := time.Now()
+	responses := make([]dto.ChallengeResponse, 0, len(challenges))
+	for i := range challenges {
+		responses = append(responses, *dto.MapChallengeToResponse(&challenges[i], now))
+	}
+	return responses, nil
+}
+
+// ===== USER OPERATIONS =====
+
+// GetChallenges lists published challenges with the user's enrollment
+// status filters by phase ("upcoming", "active", "ended"); "" lists upcoming and active ones.
+func (s *ChallengeService) GetChallenges(userID uint, status string) ([]dto.ChallengeResponse, error) {
+	now := time.Now()
+	challenges, err := s.challengeRepo.GetAll(true, models.ChallengeStatus(status), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch challenges: %w", err)
+	}
+	participations, err := s.challengeRepo.GetUserParticipations(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch enrollments: %w", err)
+	}
+
+	responses := make([]dto.ChallengeResponse, 0, len(challenges))
+	for i := range challenges {
+		challenge := &challenges[i]
+		if status == "" && challenge.StatusAt(now) == models.ChallengeEnded {
+			continue
+		}
+
+		response := dto.MapChallengeToResponse(challenge, now)
+		if participant := participations[challenge.ID]; participant != nil {
+			response.Enrolled = true
+			response.Progress = &dto.ChallengeProgress{
+				Percent:     participant.Percent,
+				Completed:   participant.CompletedAt != nil,
+				CompletedAt: participant.CompletedAt,
+				Conditions:  []dto.BadgeConditionProgress{},
+			}
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// GetChallenge gets a published challenge with the user's live progress if enrolled
+//
+// Returns:
+//   - *ChallengeResponse: The challenge; Progress lists every goal condition with current values
+//   - error: utils.ErrChallengeNotFound or a database error
+func (s *ChallengeService) GetChallenge(userID, challengeID uint) (*dto.ChallengeResponse, error) {
+	challenge, err := s.getPublishedChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.MapChallengeToResponse(challenge, time.Now())
+	participant, err := s.challengeRepo.GetParticipant(challengeID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response, nil
+		}
+		return nil, err
+	}
+	response.Enrolled = true
+
+	progress, err := s.liveProgress(challenge, participant)
+	if err != nil {
+		return nil, err
+	}
+	response.Progress = progress
+	return response, nil
+}
+
+// JoinChallenge enrolls the user in an upcoming or running challenge
+// Activity since the challenge start counts, so progress is evaluated right away.
+//
+// Returns:
+//   - *ChallengeResponse: The challenge with the user's progress
+//   - error: utils.ErrChallengeNotFound, utils.ErrChallengeClosed, utils.ErrChallengeFull,
+//     utils.ErrAlreadyEnrolled, or a database error
+func (s *ChallengeService) JoinChallenge(userID, challengeID uint) (*dto.ChallengeResponse, error) {
+	challenge, err := s.getPublishedChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.StatusAt(time.Now()) == models.ChallengeEnded {
+		return nil, utils.ErrChallengeClosed
+	}
+
+	participant := &models.ChallengeParticipant{ChallengeID: challengeID, UserID: userID}
+	if err := s.challengeRepo.Enroll(participant); err != nil {
+		return nil, err
+	}
+
+	if challenge.StatusAt(time.Now()) == models.ChallengeRunning {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		rule, err := ParseBadgeRule(challenge.Goal)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.evaluate(user, challenge, rule, participant); err != nil {
+			log.Printf("ERROR: Failed to evaluate challenge %d for user %d: %v", challengeID, userID, err)
+		}
+	}
+
+	return s.GetChallenge(userID, challengeID)
+}
+
+// LeaveChallenge withdraws the user from a challenge they have not completed
+func (s *ChallengeService) LeaveChallenge(userID, challengeID uint) error {
+	participant, err := s.challengeRepo.GetParticipant(challengeID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrNotEnrolled
+		}
+		return err
+	}
+	if participant.CompletedAt != nil {
+		return utils.ErrChallengeCompleted
+	}
+	return s.challengeRepo.Withdraw(challengeID, userID)
+}
+
+// GetLeaderboard ranks a challenge's participants: completed first by completion time,
+// then by progress
+func (s *ChallengeService) GetLeaderboard(challengeID uint, limit int) ([]dto.ChallengeLeaderboardEntry, error) {
+	if limit <= 0 || limit > 100 {
+		limit = defaultChallengeLeaderboardSize
+	}
+	if _, err := s.getPublishedChallenge(challengeID); err != nil {
+		return nil, err
+	}
+
+	participants, err := s.challengeRepo.GetLeaderboard(challengeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch leaderboard: %w", err)
+	}
+
+	entries := make([]dto.ChallengeLeaderboardEntry, 0, len(participants))
+	for i, participant := range participants {
+		entries = append(entries, dto.ChallengeLeaderboardEntry{
+			Rank:        i + 1,
+			UserID:      participant.UserID,
+			Username:    participant.User.Username,
+			FullName:    participant.User.FullName,
+			Avatar:      participant.User.Avatar,
+			Percent:     participant.Percent,
+			CompletedAt: participant.CompletedAt,
+		})
+	}
+	return entries, nil
+}
+
+// ===== EVENT PROCESSING =====
+
+// StartScheduler subscribes to the bus and starts a background goroutine that
+// periodically evaluates the enrollments of users with recorded events
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler (pending events are flushed first)
+func (s *ChallengeService) StartScheduler() chan struct{} {
+	s.bus.Subscribe(s.handle)
+
+	stop := make(chan struct{})
+	interval := s.interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.flushAndLog()
+			case <-stop:
+				ticker.Stop()
+				s.flushAndLog()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// handle records an event for the next flush
+func (s *ChallengeService) handle(event events.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, userID := range event.UserIDs {
+		if userID == 0 {
+			continue
+		}
+		if s.pending[userID] == nil {
+			s.pending[userID] = make(map[events.Type]bool)
+		}
+		s.pending[userID][event.Type] = true
+	}
+}
+
+// Flush evaluates the open enrollments of users with events recorded since the last flush
+// Challenges that ended since the previous flush are still evaluated once, so activity
+// just before the end counts.
+//
+// Returns:
+//   - int: Number of challenges completed
+//   - error: If the challenges could not be loaded (the events are kept for the next flush)
+func (s *ChallengeService) Flush() (int, error) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[uint]map[events.Type]bool)
+	s.mu.Unlock()
+
+	now := time.Now()
+	if len(pending) == 0 {
+		s.lastFlush = now
+		return 0, nil
+	}
+	challenges, err := s.challengeRepo.GetEvaluable(now, s.lastFlush)
+	if err != nil {
+		s.requeue(pending)
+		return 0, err
+	}
+	s.lastFlush = now
+	if len(challenges) == 0 {
+		return 0, nil
+	}
+
+	// Parse each goal once
+	byID := make(map[uint]*models.Challenge, len(challenges))
+	rules := make(map[uint]*BadgeRule, len(challenges))
+	ids := make([]uint, 0, len(challenges))
+	for i := range challenges {
+		challenge := &challenges[i]
+		rule, err := ParseBadgeRule(challenge.Goal)
+		if err != nil {
+			log.Printf("WARNING: Skipping challenge %d (%s): %v", challenge.ID, challenge.Title, err)
+			continue
+		}
+		byID[challenge.ID] = challenge
+		rules[challenge.ID] = rule
+		ids = append(ids, challenge.ID)
+	}
+
+	completed := 0
+	for userID, types := range pending {
+		participants, err := s.challengeRepo.GetOpenParticipations(userID, ids)
+		if err != nil {
+			log.Printf("ERROR: Failed to fetch challenge enrollments for user %d: %v", userID, err)
+			continue
+		}
+		if len(participants) == 0 {
+			continue
+		}
+
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil || !user.IsActive {
+			continue
+		}
+
+		for i := range participants {
+			participant := &participants[i]
+			rule := rules[participant.ChallengeID]
+			if !dependsOn(rule, types) {
+				continue
+			}
+			done, err := s.evaluate(user, byID[participant.ChallengeID], rule, participant)
+			if err != nil {
+				// Completion and reward roll back together; retry on the next flush
+				log.Printf("ERROR: Failed to evaluate challenge %d for user %d: %v", participant.ChallengeID, userID, err)
+				s.requeue(map[uint]map[events.Type]bool{userID: types})
+				continue
+			}
+			if done {
+				completed++
+			}
+		}
+	}
+	return completed, nil
+}
+
+// flushAndLog runs Flush from the scheduler
+func (s *ChallengeService) flushAndLog() {
+	completed, err := s.Flush()
+	if err != nil {
+		log.Printf("⚠️  Challenge event processing error: %v", err)
+	}
+	if completed > 0 {
+		log.Printf("🏁 %d challenges completed from events", completed)
+	}
+}
+
+// requeue puts events back after a failed flush
+func (s *ChallengeService) requeue(pending map[uint]map[events.Type]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for userID, types := range pending {
+		if s.pending[userID] == nil {
+			s.pending[userID] = make(map[events.Type]bool)
+		}
+		for t := range types {
+			s.pending[userID][t] = true
+		}
+	}
+}
+
+// dependsOn reports whether a goal can change with any of the event types
+func dependsOn(rule *BadgeRule, types map[events.Type]bool) bool {
+	ruleTypes := rule.Events()
+	if ruleTypes == nil {
+		return true
+	}
+	for _, t := range ruleTypes {
+		if types[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate updates a participant's progress and completes the challenge when the goal is met
+// Returns true if the participant completed the challenge in this evaluation.
+func (s *ChallengeService) evaluate(user *models.User, challenge *models.Challenge, rule *BadgeRule, participant *models.ChallengeParticipant) (bool, error) {
+	result, err := s.ruleEngine.NewPeriodEvaluation(user, challenge.StartsAt, challenge.EndsAt).Evaluate(rule)
+	if err != nil {
+		return false, err
+	}
+
+	if !result.Met {
+		percent := rulePercent(result)
+		if percent == participant.Percent {
+			return false, nil
+		}
+		participant.Percent = percent
+		return false, s.challengeRepo.UpdateProgress(participant.ID, percent)
+	}
+
+	var reward *models.Transaction
+	if challenge.RewardCredits > 0 {
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"challenge_id": challenge.ID,
+			"expirable":    true,
+		})
+		reward = &models.Transaction{
+			UserID:      user.ID,
+			Type:        models.TransactionBonus,
+			Amount:      challenge.RewardCredits,
+			Description: "Challenge reward: " + challenge.Title,
+			Metadata:    string(metadata),
+		}
+	}
+
+	completedAt := time.Now()
+	completed, err := s.challengeRepo.MarkCompleted(participant, completedAt, reward)
+	if err != nil || !completed {
+		return false, err
+	}
+	participant.Percent = 100
+	participant.CompletedAt = &completedAt
+	if reward != nil {
+		participant.RewardTransactionID = &reward.ID
+	}
+
+	s.grantRewards(user.ID, challenge)
+	return true, nil
+}
+
+// grantRewards grants a completed challenge's badge and notifies the user
+// Reward credits are posted with the completion itself (see ChallengeRepository.MarkCompleted);
+// they are an expirable bonus, like badge bonuses.
+func (s *ChallengeService) grantRewards(userID uint, challenge *models.Challenge) {
+	if challenge.RewardBadge != nil {
+		hasIt, err := s.badgeService.badgeRepo.HasUserBadge(userID, challenge.RewardBadge.ID)
+		if err != nil {
+			log.Printf("ERROR: Failed to check badge %d for user %d: %v", challenge.RewardBadge.ID, userID, err)
+		} else if !hasIt {
+			if _, err := s.badgeService.awardBadge(userID, challenge.RewardBadge); err != nil && !errors.Is(err, utils.ErrBadgeAlreadyHeld) {
+				log.Printf("ERROR: Failed to award challenge badge to user %d: %v", userID, err)
+			}
+		}
+	}
+
+	message := fmt.Sprintf("You completed the %s challenge!", challenge.Title)
+	if challenge.RewardCredits > 0 {
+		message += fmt.Sprintf(" %.1f credits have been added to your balance.", challenge.RewardCredits)
+	}
+	_, _ = s.notificationService.CreateNotification(
+		userID,
+		models.NotificationTypeAchievement,
+		"Challenge Complete! 🏁",
+		message,
+		map[string]interface{}{
+			"challengeID":   challenge.ID,
+			"challengeName": challenge.Title,
+			"reward":        challenge.RewardCredits,
+		},
+	)
+}
+
+// liveProgress evaluates a participant's goal now
+// Completed and ended challenges report the stored result instead.
+func (s *ChallengeService) liveProgress(challenge *models.Challenge, participant *models.ChallengeParticipant) (*dto.ChallengeProgress, error) {
+	progress := &dto.ChallengeProgress{
+		Percent:     participant.Percent,
+		Completed:   participant.CompletedAt != nil,
+		CompletedAt: participant.CompletedAt,
+		Conditions:  []dto.BadgeConditionProgress{},
+	}
+
+	if participant.CompletedAt == nil && challenge.StatusAt(time.Now()) == models.ChallengeRunning {
+		user, err := s.userRepo.GetByID(participant.UserID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		rule, err := ParseBadgeRule(challenge.Goal)
+		if err != nil {
+			return nil, err
+		}
+		result, err := s.ruleEngine.NewPeriodEvaluation(user, challenge.StartsAt, challenge.EndsAt).Evaluate(rule)
+		if err != nil {
+			return nil, err
+		}
+		progress.Percent = rulePercent(result)
+		progress.Conditions = mapConditionProgress(result)
+	}
+
+	rank, err := s.challengeRepo.GetRank(participant)
+	if err != nil {
+		return nil, err
+	}
+	progress.Rank = rank
+	return progress, nil
+}
+
+// validateChallenge checks dates, the badge reward and the title
+func (s *ChallengeService) validateChallenge(challenge *models.Challenge) error {
+	if challenge.Title == "" {
+		return fmt.Errorf("%w: title is required", utils.ErrInvalidChallenge)
+	}
+	if !challenge.EndsAt.After(challenge.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", utils.ErrInvalidChallenge)
+	}
+	if challenge.RewardBadgeID != nil {
+		badge, err := s.badgeService.getBadge(*challenge.RewardBadgeID)
+		if err != nil {
+			return err
+		}
+		challenge.RewardBadge = badge
+	}
+	return nil
+}
+
+// getChallenge loads a challenge or returns utils.ErrChallengeNotFound
+func (s *ChallengeService) getChallenge(challengeID uint) (*models.Challenge, error) {
+	challenge, err := s.challengeRepo.GetByID(challengeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrChallengeNotFound
+		}
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// getPublishedChallenge loads a challenge visible to users
+func (s *ChallengeService) getPublishedChallenge(challengeID uint) (*models.Challenge, error) {
+	challenge, err := s.getChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.IsActive {
+		return nil, utils.ErrChallengeNotFound
+	}
+	return challenge, nil
+}
+
+// reload maps a saved challenge, reloading its reward badge
+func (s *ChallengeService) reload(challengeID uint) (*dto.ChallengeResponse, error) {
+	challenge, err := s.getChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.MapChallengeToResponse(challenge, time.Now()), nil
+}
+
+// normalizeChallengeGoal validates a challenge goal and returns it in normalized form
+// Goals count from the challenge start, so every metric must support a period and
+// window_days is not allowed.
+func normalizeChallengeGoal(raw json.RawMessage) (string, error) {
+	rule, err := ParseBadgeRule(string(raw))
+	if err != nil {
+		return "", err
+	}
+
+	var check func(r *BadgeRule) error
+	check = func(r *BadgeRule) error {
+		for i := range r.All {
+			if err := check(&r.All[i]); err != nil {
+				return err
+			}
+		}
+		for i := range r.Any {
+			if err := check(&r.Any[i]); err != nil {
+				return err
+			}
+		}
+		if r.Metric == "" {
+			return nil
+		}
+		if r.WindowDays > 0 {
+			return fmt.Errorf("%w: window_days is not allowed in challenge goals", utils.ErrInvalidChallenge)
+		}
+		if !badgeMetrics[r.Metric].windowed {
+			return fmt.Errorf("%w: metric %q cannot be counted over the challenge period", utils.ErrInvalidChallenge, r.Metric)
+		}
+		return nil
+	}
+	if err := check(rule); err != nil {
+		return "", err
+	}
+
+	normalized, err := json.Marshal(rule)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode challenge goal: %w", err)
+	}
+	return string(normalized), nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// CommunityPoolService handles grants from the community pool and its public statistics
+//
+// The pool is funded by donations (EndorsementService) and by the credit policy
+// (balance cap overflow, inactivity decay, expired bonuses). Credits leave the pool as:
+//   - Admin grants to a specific user, with a reason
+//   - Automatic grants to students below AutoGrantThreshold who have an active learning wish
+type CommunityPoolService struct {
+	cfg                 config.CommunityPoolConfig
+	poolRepo            *repository.CommunityPoolRepository
+	userRepo            *repository.UserRepository
+	notificationService *NotificationService
+}
+
+// NewCommunityPoolService creates a new community pool service
+func NewCommunityPoolService(
+	cfg config.CommunityPoolConfig,
+	poolRepo *repository.CommunityPoolRepository,
+	userRepo *repository.UserRepository,
+	notificationService *NotificationService,
+) *CommunityPoolService {
+	return &CommunityPoolService{
+		cfg:                 cfg,
+		poolRepo:            poolRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// GetStats returns the public community pool statistics
+func (s *CommunityPoolService) GetStats() (*dto.CommunityPoolStatsResponse, error) {
+	pool, err := s.poolRepo.GetPool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool: %w", err)
+	}
+
+	totals, err := s.poolRepo.GetTotalsByType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool totals: %w", err)
+	}
+
+	donors, err := s.poolRepo.CountDistinctUsers(models.PoolEntryDonation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count donors: %w", err)
+	}
+
+	recipients, err := s.poolRepo.CountDistinctUsers(models.PoolEntryGrant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recipients: %w", err)
+	}
+
+	return &dto.CommunityPoolStatsResponse{
+		Balance:            pool.Balance,
+		TotalDonated:       totals[models.PoolEntryDonation],
+		TotalDistributed:   totals[models.PoolEntryGrant],
+		TotalRedistributed: totals[models.PoolEntryOverflow] + totals[models.PoolEntryDecay] + totals[models.PoolEntryExpiry],
+		DonorCount:         donors,
+		RecipientCount:     recipients,
+	}, nil
+}
+
+// GrantByAdmin grants credits from the pool to a user on behalf of an admin
+//
+// Returns:
+//   - *Transaction: The user's grant ledger entry
+//   - error: utils.ErrUserNotFound, utils.ErrUserInactive, utils.ErrPoolInsufficient, or a database error
+func (s *CommunityPoolService) GrantByAdmin(adminID uint, req *dto.CommunityPoolGrantRequest) (*models.Transaction, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("grant amount must be positive")
+	}
+
+	user, err := s.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if !user.IsActive {
+		return nil, utils.ErrUserInactive
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"source":     "admin",
+		"granted_by": adminID,
+	})
+	transaction, err := s.poolRepo.GrantToUser(
+		user.ID,
+		req.Amount,
+		"Community pool grant: "+req.Reason,
+		string(metadata),
+		&adminID,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyGrant(user.ID, req.Amount, req.Reason)
+	return transaction, nil
+}
+
+// RunAutoGrants grants AutoGrantAmount to eligible students
+//
+// Eligible: active account, available balance below AutoGrantThreshold, at least one
+// learning wish, and no grant within AutoGrantCooldownDays (re-checked when granting, so
+// replicas running the job at the same time grant a user once). Stops early when the pool
+// runs dry.
+//
+// Returns:
+//   - int: Number of grants made
+//   - error: If candidates could not be loaded
+func (s *CommunityPoolService) RunAutoGrants() (int, error) {
+	if s.cfg.AutoGrantAmount <= 0 {
+		return 0, nil
+	}
+
+	batch := s.cfg.AutoGrantBatchSize
+	if batch <= 0 {
+		batch = 50
+	}
+	since := time.Now().AddDate(0, 0, -s.cfg.AutoGrantCooldownDays)
+
+	candidates, err := s.poolRepo.FindAutoGrantCandidates(s.cfg.AutoGrantThreshold, since, batch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find grant candidates: %w", err)
+	}
+
+	granted := 0
+	for _, user := range candidates {
+		metadata, _ := json.Marshal(map[string]interface{}{
+			"source":    "auto",
+			"rule":      "low_balance_learning_wish",
+			"threshold": s.cfg.AutoGrantThreshold,
+		})
+		_, err := s.poolRepo.GrantToUser(
+			user.ID,
+			s.cfg.AutoGrantAmount,
+			"Community pool grant: starter credits for learning",
+			string(metadata),
+			nil,
+			&since,
+		)
+		if errors.Is(err, utils.ErrPoolInsufficient) {
+			break
+		}
+		if errors.Is(err, utils.ErrRecentGrant) {
+			continue // Granted by a concurrent run
+		}
+		if err != nil {
+			log.Printf("ERROR: community pool: auto grant to user %d failed: %v", user.ID, err)
+			continue
+		}
+
+		s.notifyGrant(user.ID, s.cfg.AutoGrantAmount, "starter credits for your learning wishlist")
+		granted++
+	}
+
+	return granted, nil
+}
+
+// StartScheduler starts a background goroutine that periodically runs RunAutoGrants
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler
+func (s *CommunityPoolService) StartScheduler() chan struct{} {
+	stop := make(chan struct{})
+	interval := s.cfg.AutoGrantInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if granted, err := s.RunAutoGrants(); err != nil {
+					log.Printf("⚠️  Community pool auto grant error: %v", err)
+				} else if granted > 0 {
+					log.Printf("✅ Community pool granted credits to %d users", granted)
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// notifyGrant tells a user they received credits from the pool
+func (s *CommunityPoolService) notifyGrant(userID uint, amount float64, reason string) {
+	if s.notificationService == nil {
+		return
+	}
+	_, _ = s.notificationService.CreateNotification(
+		userID,
+		models.NotificationTypeCredit,
+		"Community Grant Received! 🤝",
+		fmt.Sprintf("The community pool granted you %.1f credits: %s", amount, reason),
+		map[string]interface{}{
+			"amount": amount,
+			"reason": reason,
+		},
+	)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// MaxCreditAdjustmentAmount limits a single adjustment in either direction
+const MaxCreditAdjustmentAmount = 500.0
+
+// CreditAdjustmentService handles admin balance corrections with maker-checker approval
+//
+// Flow: admin A proposes (pending) → admin B with manage_finance approves or rejects.
+// Only approval posts an "adjustment" ledger transaction and notifies the user,
+// so every correction appears in GET /admin/transactions with both admins in its metadata.
+type CreditAdjustmentService struct {
+	adjustmentRepo      *repository.CreditAdjustmentRepository
+	adminRepo           *repository.AdminRepository
+	userRepo            *repository.UserRepository
+	notificationService *NotificationService
+}
+
+// NewCreditAdjustmentService creates a new credit adjustment service
+func NewCreditAdjustmentService(
+	adjustmentRepo *repository.CreditAdjustmentRepository,
+	adminRepo *repository.AdminRepository,
+	userRepo *repository.UserRepository,
+	notificationService *NotificationService,
+) *CreditAdjustmentService {
+	return &CreditAdjustmentService{
+		adjustmentRepo:      adjustmentRepo,
+		adminRepo:           adminRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// ProposeAdjustment records a pending adjustment proposed by an admin
+//
+// Returns:
+//   - *CreditAdjustment: The pending adjustment
+//   - error: utils.ErrNotAuthorized, utils.ErrUserNotFound, or utils.ErrInvalidAdjustmentAmount
+func (s *CreditAdjustmentService) ProposeAdjustment(adminID uint, email string, req *dto.CreateCreditAdjustmentRequest) (*models.CreditAdjustment, error) {
+	if _, err := s.getAdmin(adminID, email); err != nil {
+		return nil, err
+	}
+
+	if req.Amount == 0 {
+		return nil, fmt.Errorf("%w: it cannot be zero", utils.ErrInvalidAdjustmentAmount)
+	}
+	if math.Abs(req.Amount) > MaxCreditAdjustmentAmount {
+		return nil, fmt.Errorf("%w: it cannot exceed %.0f credits", utils.ErrInvalidAdjustmentAmount, MaxCreditAdjustmentAmount)
+	}
+
+	if _, err := s.userRepo.GetByID(req.UserID); err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	adjustment := &models.CreditAdjustment{
+		UserID:      req.UserID,
+		Amount:      req.Amount,
+		Reason:      req.Reason,
+		Status:      models.CreditAdjustmentPending,
+		RequestedBy: adminID,
+	}
+	if err := s.adjustmentRepo.Create(adjustment); err != nil {
+		return nil, fmt.Errorf("failed to create credit adjustment: %w", err)
+	}
+
+	return s.adjustmentRepo.GetByID(adjustment.ID)
+}
+
+// ApproveAdjustment approves a pending adjustment and posts it to the ledger
+// The approver must be a different admin with manage_finance.
+//
+// Returns:
+//   - *CreditAdjustment: The approved adjustment with its TransactionID
+//   - error: utils.ErrSelfApproval, utils.ErrNotAuthorized, utils.ErrAdjustmentNotFound,
+//     utils.ErrAdjustmentNotPending, utils.ErrInsufficientCredits, or a database error
+func (s *CreditAdjustmentService) ApproveAdjustment(adminID uint, email string, adjustmentID uint, note string) (*models.CreditAdjustment, error) {
+	reviewer, adjustment, err := s.getForReview(adminID, email, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Balance adjustment: %s", adjustment.Reason)
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"adjustment_id": adjustment.ID,
+		"requested_by":  adjustment.RequestedBy,
+		"approved_by":   reviewer.ID,
+		"reason":        adjustment.Reason,
+		"note":          note,
+	})
+
+	if _, err := s.adjustmentRepo.Approve(adjustment.ID, reviewer.ID, note, description, string(metadata)); err != nil {
+		return nil, err
+	}
+
+	s.notifyUser(adjustment)
+	return s.adjustmentRepo.GetByID(adjustment.ID)
+}
+
+// RejectAdjustment rejects a pending adjustment without touching the ledger
+// The reviewer must be a different admin with manage_finance.
+func (s *CreditAdjustmentService) RejectAdjustment(adminID uint, email string, adjustmentID uint, note string) (*models.CreditAdjustment, error) {
+	reviewer, adjustment, err := s.getForReview(adminID, email, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.adjustmentRepo.Reject(adjustment.ID, reviewer.ID, note); err != nil {
+		return nil, err
+	}
+	return s.adjustmentRepo.GetByID(adjustment.ID)
+}
+
+// GetAdjustments returns paginated adjustments, optionally filtered by status and user
+// Only active admins may list adjustments (utils.ErrNotAuthorized otherwise).
+func (s *CreditAdjustmentService) GetAdjustments(adminID uint, email string, status string, userID uint, page, limit int) ([]models.CreditAdjustment, int64, error) {
+	if _, err := s.getAdmin(adminID, email); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	return s.adjustmentRepo.GetAll(status, userID, limit, offset)
+}
+
+// getAdmin loads the active admin behind the token
+// The email must match so a regular user token with the same numeric ID is rejected.
+func (s *CreditAdjustmentService) getAdmin(adminID uint, email string) (*models.Admin, error) {
+	admin, err := s.adminRepo.GetByID(adminID)
+	if err != nil || !admin.IsActive || admin.Email != email {
+		return nil, utils.ErrNotAuthorized
+	}
+	return admin, nil
+}
+
+// getForReview checks the reviewer may review the adjustment and loads it
+func (s *CreditAdjustmentService) getForReview(adminID uint, email string, adjustmentID uint) (*models.Admin, *models.CreditAdjustment, error) {
+	reviewer, err := s.getAdmin(adminID, email)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Finance admins review adjustments; super admins may review them too
+	if reviewer.Role != "super_admin" && !reviewer.HasPermission("manage_finance") {
+		return nil, nil, utils.ErrNotAuthorized
+	}
+
+	adjustment, err := s.adjustmentRepo.GetByID(adjustmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, utils.ErrAdjustmentNotFound
+		}
+		return nil, nil, err
+	}
+	if !adjustment.IsPending() {
+		return nil, nil, utils.ErrAdjustmentNotPending
+	}
+	if adjustment.RequestedBy == reviewer.ID {
+		return nil, nil, utils.ErrSelfApproval
+	}
+
+	return reviewer, adjustment, nil
+}
+
+// notifyUser tells the user their balance was corrected
+func (s *CreditAdjustmentService) notifyUser(adjustment *models.CreditAdjustment) {
+	if s.notificationService == nil {
+		return
+	}
+
+	message := fmt.Sprintf("An administrator added %.1f credits to your balance: %s", adjustment.Amount, adjustment.Reason)
+	if adjustment.Amount < 0 {
+		message = fmt.Sprintf("An administrator deducted %.1f credits from your balance: %s", -adjustment.Amount, adjustment.Reason)
+	}
+
+	_, _ = s.notificationService.CreateNotification(
+		adjustment.UserID,
+		models.NotificationTypeCredit,
+		"Balance Adjusted",
+		message,
+		map[string]interface{}{
+			"adjustment_id": adjustment.ID,
+			"amount":        adjustment.Amount,
+			"reason":        adjustment.Reason,
+		},
+	)
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// CreditPolicyService enforces the anti-hoarding rules of the time bank
+//
+// Rules (all disabled when the policy is disabled, each when its config value is zero):
+//   - Balance cap: earnings that push a balance above MaxBalance are redirected to the community pool
+//   - Inactivity decay: accounts without activity for InactivityMonths lose DecayRate of the
+//     credits above DecayFloor, at most once a month
+//   - Bonus expiry: the unspent part of bonus credits marked expirable expires BonusExpiryDays
+//     after they were granted (bonus credits count as spent first, see CreditPolicyRepository.GetUnusedBonuses)
+//
+// Every adjustment:
+//   - Is announced to the user (decay and expiry NoticeDays ahead through NotificationService,
+//     the cap in the same database transaction that moves the overflow)
+//   - Is recorded as a CreditPolicyNotice (announcement) and a ledger Transaction (adjustment)
+//   - Moves the credits into the community pool with a matching pool ledger entry
+type CreditPolicyService struct {
+	policy              config.CreditPolicyConfig
+	policyRepo          *repository.CreditPolicyRepository
+	poolRepo            *repository.CommunityPoolRepository
+	userRepo            *repository.UserRepository
+	notificationService *NotificationService
+}
+
+// NewCreditPolicyService creates a new credit policy service
+func NewCreditPolicyService(
+	policy config.CreditPolicyConfig,
+	policyRepo *repository.CreditPolicyRepository,
+	poolRepo *repository.CommunityPoolRepository,
+	userRepo *repository.UserRepository,
+	notificationService *NotificationService,
+) *CreditPolicyService {
+	return &CreditPolicyService{
+		policy:              policy,
+		policyRepo:          policyRepo,
+		poolRepo:            poolRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// GetPolicy returns the active policy configuration
+func (s *CreditPolicyService) GetPolicy() config.CreditPolicyConfig {
+	return s.policy
+}
+
+// GetPool returns the community pool account
+func (s *CreditPolicyService) GetPool() (*models.CommunityPool, error) {
+	return s.poolRepo.GetPool()
+}
+
+// GetPoolEntries returns the community pool ledger
+func (s *CreditPolicyService) GetPoolEntries(limit, offset int, entryType string) ([]models.CommunityPoolEntry, int64, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.poolRepo.GetEntries(limit, offset, entryType)
+}
+
+// GetUserNotices returns the announced adjustments for a user (pending and past)
+func (s *CreditPolicyService) GetUserNotices(userID uint) ([]models.CreditPolicyNotice, error) {
+	return s.policyRepo.GetUserNotices(userID, 50)
+}
+
+// EnforceBalanceCap redirects credits above the balance cap to the community pool
+// Called right after a user earns credits outside the shared ledger posting
+// (session completion); every other credit is capped as it is posted (see repository.SetBalanceCap).
+//
+// The overflow is computed under row lock and moved, recorded as an applied notice and
+// announced in one database transaction.
+//
+// Returns:
+//   - *Transaction: Overflow ledger entry, or nil when the balance is within the cap
+//   - error: If the adjustment could not be posted
+func (s *CreditPolicyService) EnforceBalanceCap(userID uint) (*models.Transaction, error) {
+	if !s.policy.Enabled || s.policy.MaxBalance <= 0 {
+		return nil, nil
+	}
+	return s.poolRepo.EnforceBalanceCap(userID)
+}
+
+// RunPolicies announces new decay and bonus expiry adjustments and applies the ones that are due
+// Safe to run repeatedly; each step is idempotent
+func (s *CreditPolicyService) RunPolicies() error {
+	now := time.Now()
+
+	if err := s.announceDecay(now); err != nil {
+		return fmt.Errorf("announce decay: %w", err)
+	}
+	if err := s.announceBonusExpiry(now); err != nil {
+		return fmt.Errorf("announce bonus expiry: %w", err)
+	}
+
+	notices, err := s.policyRepo.GetDueNotices(now)
+	if err != nil {
+		return fmt.Errorf("get due notices: %w", err)
+	}
+	for i := range notices {
+		if _, err := s.applyNotice(&notices[i]); err != nil {
+			log.Printf("ERROR: credit policy: failed to apply notice %d: %v", notices[i].ID, err)
+		}
+	}
+
+	return nil
+}
+
+// StartScheduler starts a background goroutine that periodically runs RunPolicies
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler
+func (s *CreditPolicyService) StartScheduler() chan struct{} {
+	stop := make(chan struct{})
+	interval := s.policy.RunInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.RunPolicies(); err != nil {
+					log.Printf("⚠️  Credit policy run error: %v", err)
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// announceDecay creates decay notices for inactive accounts
+func (s *CreditPolicyService) announceDecay(now time.Time) error {
+	if s.policy.InactivityMonths <= 0 || s.policy.DecayRate <= 0 {
+		return nil
+	}
+
+	cutoff := now.AddDate(0, -s.policy.InactivityMonths, 0)
+	users, err := s.policyRepo.FindInactiveUsers(cutoff, s.policy.DecayFloor)
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		user := &users[i]
+
+		// Decay at most once a month, and never stack pending notices
+		recent, err := s.policyRepo.HasRecentNotice(user.ID, models.PolicyDecay, now.AddDate(0, -1, 0))
+		if err != nil || recent {
+			continue
+		}
+
+		amount := s.decayAmount(user)
+		if amount <= 0 {
+			continue
+		}
+
+		notice := &models.CreditPolicyNotice{
+			UserID: user.ID,
+			Kind:   models.PolicyDecay,
+			Status: models.NoticePending,
+			Amount: amount,
+			DueAt:  now.AddDate(0, 0, s.policy.NoticeDays),
+		}
+		if err := s.policyRepo.CreateNotice(notice); err != nil {
+			log.Printf("ERROR: credit policy: failed to create decay notice for user %d: %v", user.ID, err)
+			continue
+		}
+
+		s.announce(notice,
+			"Your Credits Will Decay",
+			fmt.Sprintf("You have been inactive for %d months. %.1f credits will move to the community pool on %s unless you book or teach a session.",
+				s.policy.InactivityMonths, amount, notice.DueAt.Format("2 Jan 2006")),
+		)
+	}
+
+	return nil
+}
+
+// announceBonusExpiry creates expiry notices for bonus credits reaching their expiry date
+func (s *CreditPolicyService) announceBonusExpiry(now time.Time) error {
+	if s.policy.BonusExpiryDays <= 0 {
+		return nil
+	}
+
+	// Announce NoticeDays before the bonus actually expires
+	cutoff := now.AddDate(0, 0, s.policy.NoticeDays-s.policy.BonusExpiryDays)
+	bonuses, err := s.policyRepo.FindExpiringBonuses(cutoff)
+	if err != nil {
+		return err
+	}
+
+	// Unspent bonus per user, replayed from the ledger once per run
+	unusedByUser := make(map[uint]map[uint]float64)
+
+	for i := range bonuses {
+		bonus := &bonuses[i]
+		bonusID := bonus.ID
+
+		dueAt := bonus.CreatedAt.AddDate(0, 0, s.policy.BonusExpiryDays)
+		if dueAt.Before(now) {
+			dueAt = now.AddDate(0, 0, s.policy.NoticeDays)
+		}
+
+		notice := &models.CreditPolicyNotice{
+			UserID:      bonus.UserID,
+			Kind:        models.PolicyBonusExpiry,
+			Status:      models.NoticePending,
+			ReferenceID: &bonusID,
+			DueAt:       dueAt,
+		}
+
+		unused, ok := unusedByUser[bonus.UserID]
+		if !ok {
+			unused, err = s.policyRepo.GetUnusedBonuses(bonus.UserID)
+			if err != nil {
+				log.Printf("ERROR: credit policy: failed to replay bonuses of user %d: %v", bonus.UserID, err)
+				continue
+			}
+			unusedByUser[bonus.UserID] = unused
+		}
+
+		user, err := s.userRepo.GetByID(bonus.UserID)
+		if err == nil {
+			notice.Amount = s.bonusExpiryAmount(user, unused[bonus.ID])
+		}
+
+		// Bonus already spent: record it as handled so it is not scanned again
+		if notice.Amount <= 0 {
+			notice.Status = models.NoticeCancelled
+			_ = s.policyRepo.CreateNotice(notice)
+			continue
+		}
+
+		if err := s.policyRepo.CreateNotice(notice); err != nil {
+			log.Printf("ERROR: credit policy: failed to create expiry notice for transaction %d: %v", bonus.ID, err)
+			continue
+		}
+
+		s.announce(notice,
+			"Bonus Credits Expiring Soon",
+			fmt.Sprintf("%.1f unused bonus credits (%s) expire on %s. Use them before then!",
+				notice.Amount, bonus.Description, dueAt.Format("2 Jan 2006")),
+		)
+	}
+
+	return nil
+}
+
+// applyNotice posts the announced adjustment to the ledger and the community pool
+// The amount is re-evaluated against the locked user row and never exceeds what was announced
+func (s *CreditPolicyService) applyNotice(notice *models.CreditPolicyNotice) (*models.Transaction, error) {
+	var (
+		txType      models.TransactionType
+		entryType   models.PoolEntryType
+		description string
+		amountFn    func(user *models.User) float64
+	)
+
+	switch notice.Kind {
+	case models.PolicyBalanceCap:
+		txType, entryType = models.TransactionOverflow, models.PoolEntryOverflow
+		description = fmt.Sprintf("Balance above %.1f credit cap shared with the community pool", s.policy.MaxBalance)
+		amountFn = func(user *models.User) float64 {
+			return math.Min(notice.Amount, s.capOverflow(user))
+		}
+	case models.PolicyDecay:
+		// Any activity since the announcement cancels the decay
+		active, err := s.policyRepo.HasActivitySince(notice.UserID, notice.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if active {
+			return nil, s.cancelNotice(notice)
+		}
+		txType, entryType = models.TransactionDecay, models.PoolEntryDecay
+		description = fmt.Sprintf("Inactivity decay after %d months without activity", s.policy.InactivityMonths)
+		amountFn = func(user *models.User) float64 {
+			return math.Min(notice.Amount, user.AvailableCredits()-s.policy.DecayFloor)
+		}
+	case models.PolicyBonusExpiry:
+		txType, entryType = models.TransactionExpiry, models.PoolEntryExpiry
+		description = fmt.Sprintf("Unused bonus credits expired after %d days", s.policy.BonusExpiryDays)
+		amountFn = func(user *models.User) float64 {
+			return math.Min(notice.Amount, user.AvailableCredits())
+		}
+	default:
+		return nil, fmt.Errorf("unknown credit policy kind: %s", notice.Kind)
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"policy":       notice.Kind,
+		"notice_id":    notice.ID,
+		"reference_id": notice.ReferenceID,
+	})
+
+	transaction, err := s.poolRepo.ApplyPolicyNotice(
+		notice,
+		txType,
+		entryType,
+		description,
+		string(metadata),
+		func(user *models.User) float64 { return roundCredits(amountFn(user)) },
+	)
+	if errors.Is(err, utils.ErrNoticeNotPending) {
+		// Already applied or cancelled by a concurrent run
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// transaction is nil when nothing was left to move (credits were spent or held
+	// in the meantime); the notice has been cancelled in that case
+	return transaction, nil
+}
+
+// cancelNotice marks a notice as no longer applicable
+func (s *CreditPolicyService) cancelNotice(notice *models.CreditPolicyNotice) error {
+	if err := s.policyRepo.CancelNotice(notice.ID); err != nil {
+		return err
+	}
+	notice.Status = models.NoticeCancelled
+	return nil
+}
+
+// announce sends the credit notification for a notice
+func (s *CreditPolicyService) announce(notice *models.CreditPolicyNotice, title, message string) {
+	if s.notificationService == nil {
+		return
+	}
+	_, _ = s.notificationService.CreateNotification(
+		notice.UserID,
+		models.NotificationTypeCredit,
+		title,
+		message,
+		map[string]interface{}{
+			"notice_id": notice.ID,
+			"policy":    notice.Kind,
+			"amount":    notice.Amount,
+			"due_at":    notice.DueAt,
+		},
+	)
+}
+
+// capOverflow returns how many available credits are above the balance cap
+func (s *CreditPolicyService) capOverflow(user *models.User) float64 {
+	return repository.BalanceCapOverflow(user, s.policy.MaxBalance)
+}
+
+// decayAmount returns the decay for one run: DecayRate of the available credits above DecayFloor
+func (s *CreditPolicyService) decayAmount(user *models.User) float64 {
+	return roundCredits((user.AvailableCredits() - s.policy.DecayFloor) * s.policy.DecayRate)
+}
+
+// bonusExpiryAmount returns how much of a bonus expires: its unspent part, as far as
+// the credits are not held in escrow or frozen
+func (s *CreditPolicyService) bonusExpiryAmount(user *models.User, unused float64) float64 {
+	return roundCredits(math.Min(unused, user.AvailableCredits()))
+}
+
+// roundCredits rounds to 2 decimals and clamps negatives to zero
+func roundCredits(amount float64) float64 {
+	if amount <= 0 {
+		return 0
+	}
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+func TestCreditPolicyCapOverflow(t *testing.T) {
+	policy := NewCreditPolicyService(config.CreditPolicyConfig{MaxBalance: 100}, nil, nil, nil, nil)
+
+	tests := []struct {
+		name     string
+		balance  float64
+		held     float64
+		overflow float64
+	}{
+		{"under the cap", 80, 0, 0},
+		{"at the cap", 100, 0, 0},
+		{"above the cap", 112.5, 0, 12.5},
+		{"escrow counts towards the cap", 120, 15, 20},
+		{"escrow is never redirected", 120, 110, 10},
+		{"everything in escrow", 120, 120, 0},
+		{"rounded to cents", 100.004, 0, 0},
+		{"fractional overflow", 103.336, 0, 3.34},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{CreditBalance: tt.balance, CreditHeld: tt.held}
+			assert.Equal(t, tt.overflow, policy.capOverflow(user))
+		})
+	}
+}
+
+func TestEnforceBalanceCapDisabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy config.CreditPolicyConfig
+	}{
+		{"no cap", config.CreditPolicyConfig{Enabled: true}},
+		{"policy disabled", config.CreditPolicyConfig{Enabled: false, MaxBalance: 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The user is not even loaded
+			policy := NewCreditPolicyService(tt.policy, nil, nil, nil, nil)
+
+			txn, err := policy.EnforceBalanceCap(1)
+			require.NoError(t, err)
+			assert.Nil(t, txn)
+		})
+	}
+}
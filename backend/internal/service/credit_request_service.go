@@ -0,0 +1,342 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// Credit request limits
+// Asking for credits costs nothing, so these keep requests from turning into spam
+const (
+	MaxCreditRequestAmount          = 20.0           // Largest amount a single request may ask for
+	MaxPendingCreditRequests        = 5              // Open requests a user may have at once
+	MaxCreditRequestsPerDay         = 10             // Requests a user may create per 24h
+	MaxCreditRequestsPerPayerPerDay = 2              // Requests to the same payer per 24h
+	CreditRequestDeclineCooldown    = 24 * time.Hour // Wait after a payer declines before asking them again
+	DefaultCreditRequestExpiry      = 72 * time.Hour // Used when ExpiresInHours is not set
+)
+
+// CreditRequestService handles credit requests between users
+//
+// Flow: requester asks → payer accepts (credits transferred) or declines.
+// The requester may cancel while the request is pending; unanswered requests expire.
+type CreditRequestService struct {
+	requestRepo         *repository.CreditRequestRepository
+	userRepo            *repository.UserRepository
+	notificationService *NotificationService
+}
+
+// NewCreditRequestService creates a new credit request service
+func NewCreditRequestService(
+	requestRepo *repository.CreditRequestRepository,
+	userRepo *repository.UserRepository,
+	notificationService *NotificationService,
+) *CreditRequestService {
+	return &CreditRequestService{
+		requestRepo:         requestRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// CreateRequest asks another user to send credits
+//
+// Returns:
+//   - *CreditRequestResponse: The created request
+//   - error: utils.ErrCreditRequestLimit when an anti-spam limit is hit, or a validation error
+func (s *CreditRequestService) CreateRequest(requesterID uint, req *dto.CreateCreditRequestRequest) (*dto.CreditRequestResponse, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("request amount must be positive")
+	}
+	if req.Amount > MaxCreditRequestAmount {
+		return nil, fmt.Errorf("request amount cannot exceed %.1f credits", MaxCreditRequestAmount)
+	}
+	if requesterID == req.PayerID {
+		return nil, errors.New("cannot request credits from yourself")
+	}
+
+	payer, err := s.userRepo.GetByID(req.PayerID)
+	if err != nil {
+		return nil, errors.New("payer not found")
+	}
+	if !payer.IsActive {
+		return nil, errors.New("payer account is not active")
+	}
+
+	requester, err := s.userRepo.GetByID(requesterID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	s.expireOverdue()
+	if err := s.checkLimits(requesterID, req.PayerID); err != nil {
+		return nil, err
+	}
+
+	expiry := DefaultCreditRequestExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	request := &models.CreditRequest{
+		RequesterID: requesterID,
+		PayerID:     req.PayerID,
+		Amount:      req.Amount,
+		Message:     req.Message,
+		Status:      models.CreditRequestPending,
+		ExpiresAt:   time.Now().Add(expiry),
+	}
+	if err := s.requestRepo.Create(request); err != nil {
+		return nil, fmt.Errorf("failed to create credit request: %w", err)
+	}
+	// Parties are attached only after the insert so GORM does not upsert the user rows
+	request.Requester = *requester
+	request.Payer = *payer
+
+	s.notify(payer.ID, "Credit Request 🙋",
+		fmt.Sprintf("%s asked you for %.1f credits", requester.FullName, request.Amount), request)
+
+	return s.mapToResponse(request), nil
+}
+
+// AcceptRequest pays a pending request addressed to the payer
+// Credits move from payer to requester in the same database transaction that marks the request accepted
+func (s *CreditRequestService) AcceptRequest(payerID, requestID uint) (*dto.CreditRequestResponse, error) {
+	request, err := s.getForPayer(payerID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	payerDescription := fmt.Sprintf("Paid credit request from %s", request.Requester.FullName)
+	requesterDescription := fmt.Sprintf("Credit request paid by %s", request.Payer.FullName)
+	if request.Message != "" {
+		payerDescription += ": " + request.Message
+		requesterDescription += ": " + request.Message
+	}
+
+	if _, err := s.requestRepo.Accept(request.ID, payerDescription, requesterDescription); err != nil {
+		if errors.Is(err, utils.ErrInsufficientCredits) {
+			return nil, fmt.Errorf("insufficient credits: you need %.1f available credits", request.Amount)
+		}
+		return nil, err
+	}
+	events.PublishWithData(events.TransferMade, map[string]interface{}{"amount": request.Amount, "credit_request_id": request.ID},
+		request.PayerID, request.RequesterID)
+
+	request, err = s.requestRepo.GetByID(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload credit request: %w", err)
+	}
+
+	s.notify(request.RequesterID, "Credit Request Accepted! 🎉",
+		fmt.Sprintf("%s sent you %.1f credits", request.Payer.FullName, request.Amount), request)
+
+	return s.mapToResponse(request), nil
+}
+
+// DeclineRequest declines a pending request addressed to the payer
+func (s *CreditRequestService) DeclineRequest(payerID, requestID uint, reason string) (*dto.CreditRequestResponse, error) {
+	request, err := s.getForPayer(payerID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requestRepo.Resolve(request.ID, models.CreditRequestDeclined, reason); err != nil {
+		return nil, err
+	}
+
+	request, err = s.requestRepo.GetByID(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload credit request: %w", err)
+	}
+
+	message := fmt.Sprintf("%s declined your request for %.1f credits", request.Payer.FullName, request.Amount)
+	if reason != "" {
+		message += ": " + reason
+	}
+	s.notify(request.RequesterID, "Credit Request Declined", message, request)
+
+	return s.mapToResponse(request), nil
+}
+
+// CancelRequest withdraws a pending request created by the requester
+func (s *CreditRequestService) CancelRequest(requesterID, requestID uint) (*dto.CreditRequestResponse, error) {
+	request, err := s.requestRepo.GetByID(requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrCreditRequestNotFound
+		}
+		return nil, err
+	}
+	if request.RequesterID != requesterID {
+		return nil, utils.ErrNotAuthorized
+	}
+
+	if err := s.requestRepo.Resolve(request.ID, models.CreditRequestCancelled, ""); err != nil {
+		return nil, err
+	}
+
+	request, err = s.requestRepo.GetByID(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload credit request: %w", err)
+	}
+	return s.mapToResponse(request), nil
+}
+
+// GetIncoming returns requests the user has been asked to pay
+func (s *CreditRequestService) GetIncoming(userID uint, status string, limit, offset int) ([]dto.CreditRequestResponse, int64, error) {
+	s.expireOverdue()
+	requests, total, err := s.requestRepo.GetIncoming(userID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.mapToResponses(requests), total, nil
+}
+
+// GetOutgoing returns requests the user has created
+func (s *CreditRequestService) GetOutgoing(userID uint, status string, limit, offset int) ([]dto.CreditRequestResponse, int64, error) {
+	s.expireOverdue()
+	requests, total, err := s.requestRepo.GetOutgoing(userID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.mapToResponses(requests), total, nil
+}
+
+// checkLimits enforces the anti-spam limits for a new request
+func (s *CreditRequestService) checkLimits(requesterID, payerID uint) error {
+	pending, err := s.requestRepo.CountPendingOutgoing(requesterID)
+	if err != nil {
+		return err
+	}
+	if pending >= MaxPendingCreditRequests {
+		return utils.ErrCreditRequestLimit
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	today, err := s.requestRepo.CountCreatedSince(requesterID, 0, since)
+	if err != nil {
+		return err
+	}
+	if today >= MaxCreditRequestsPerDay {
+		return utils.ErrCreditRequestLimit
+	}
+
+	toPayer, err := s.requestRepo.CountCreatedSince(requesterID, payerID, since)
+	if err != nil {
+		return err
+	}
+	if toPayer >= MaxCreditRequestsPerPayerPerDay {
+		return utils.ErrCreditRequestLimit
+	}
+
+	declined, err := s.requestRepo.HasDeclinedSince(requesterID, payerID, time.Now().Add(-CreditRequestDeclineCooldown))
+	if err != nil {
+		return err
+	}
+	if declined {
+		return utils.ErrCreditRequestLimit
+	}
+
+	return nil
+}
+
+// getForPayer loads a pending request and checks it is addressed to the payer
+func (s *CreditRequestService) getForPayer(payerID, requestID uint) (*models.CreditRequest, error) {
+	s.expireOverdue()
+
+	request, err := s.requestRepo.GetByID(requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrCreditRequestNotFound
+		}
+		return nil, err
+	}
+	if request.PayerID != payerID {
+		return nil, utils.ErrNotAuthorized
+	}
+	if request.Status != models.CreditRequestPending {
+		return nil, utils.ErrCreditRequestNotPending
+	}
+	return request, nil
+}
+
+// expireOverdue expires requests past their deadline and tells requesters
+// Runs lazily before reads and writes, so no scheduler is needed
+func (s *CreditRequestService) expireOverdue() {
+	expired, err := s.requestRepo.ExpireOverdue(time.Now())
+	if err != nil {
+		log.Printf("ERROR: credit requests: failed to expire overdue requests: %v", err)
+		return
+	}
+
+	for i := range expired {
+		s.notify(expired[i].RequesterID, "Credit Request Expired",
+			fmt.Sprintf("Your request for %.1f credits expired without an answer", expired[i].Amount), &expired[i])
+	}
+}
+
+// notify sends a credit notification about a request
+func (s *CreditRequestService) notify(userID uint, title, message string, request *models.CreditRequest) {
+	if s.notificationService == nil {
+		return
+	}
+	_, _ = s.notificationService.CreateNotification(
+		userID,
+		models.NotificationTypeCredit,
+		title,
+		message,
+		map[string]interface{}{
+			"credit_request_id": request.ID,
+			"amount":            request.Amount,
+			"status":            request.Status,
+		},
+	)
+}
+
+// mapToResponses maps a list of credit requests to responses
+func (s *CreditRequestService) mapToResponses(requests []models.CreditRequest) []dto.CreditRequestResponse {
+	responses := make([]dto.CreditRequestResponse, len(requests))
+	for i := range requests {
+		responses[i] = *s.mapToResponse(&requests[i])
+	}
+	return responses
+}
+
+// mapToResponse maps a credit request to its response
+func (s *CreditRequestService) mapToResponse(request *models.CreditRequest) *dto.CreditRequestResponse {
+	return &dto.CreditRequestResponse{
+		ID:            request.ID,
+		Requester:     s.mapToProfile(&request.Requester),
+		Payer:         s.mapToProfile(&request.Payer),
+		Amount:        request.Amount,
+		Message:       request.Message,
+		Status:        string(request.Status),
+		DeclineReason: request.DeclineReason,
+		TransactionID: request.TransactionID,
+		ExpiresAt:     request.ExpiresAt,
+		RespondedAt:   request.RespondedAt,
+		CreatedAt:     request.CreatedAt,
+	}
+}
+
+// mapToProfile maps a user to the public profile embedded in responses
+func (s *CreditRequestService) mapToProfile(user *models.User) *dto.UserPublicProfile {
+	return &dto.UserPublicProfile{
+		ID:       user.ID,
+		FullName: user.FullName,
+		Username: user.Username,
+		Avatar:   user.Avatar,
+		School:   user.School,
+		Grade:    user.Grade,
+	}
+}
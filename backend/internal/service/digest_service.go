@@ -0,0 +1,310 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// digestPeriod is the period a weekly digest looks back and ahead
+	digestPeriod = 7 * 24 * time.Hour
+
+	// digestRetryDelay is how long a user whose digest failed waits before it is retried
+	// Recipients are picked in ID order, so without it addresses that keep failing would
+	// fill every batch and hold back the users after them.
+	digestRetryDelay = 4 * time.Hour
+
+	// digestMinInterval is the least time between two digests of a user
+	// Shorter than a week, so a digest sent a little later one week does not push the next one
+	// to the week after.
+	digestMinInterval = 6 * 24 * time.Hour
+
+	// digestListLimit is the most entries shown per digest section
+	digestListLimit = 5
+
+	// digestBadgeMinPercent is the progress from which a badge is shown as close to completion
+	digestBadgeMinPercent = 50
+)
+
+// DigestService builds and sends the weekly digest email
+// Each user gets the digest on their chosen weekday (NotificationPreference.DigestWeekday),
+// from DigestConfig.SendHour in the email time zone.
+type DigestService struct {
+	digestRepo repository.DigestRepositoryInterface
+	cfg        config.DigestConfig
+	location   *time.Location
+}
+
+// NewDigestService creates a new digest service
+// location is the time zone weekdays and the send hour are read in.
+func NewDigestService(digestRepo repository.DigestRepositoryInterface, cfg config.DigestConfig, location *time.Location) *DigestService {
+	if location == nil {
+		location = time.UTC
+	}
+	return &DigestService{
+		digestRepo: digestRepo,
+		cfg:        cfg,
+		location:   location,
+	}
+}
+
+// DigestRunResult counts the outcomes of a digest run
+type DigestRunResult struct {
+	Sent    int `json:"sent"`
+	Skipped int `json:"skipped"` // Nothing to report this week
+	Failed  int `json:"failed"`  // Retried after digestRetryDelay
+}
+
+// GetDigest builds the digest the user would receive now
+func (s *DigestService) GetDigest(userID uint) (*dto.WeeklyDigestResponse, error) {
+	recipient, err := s.digestRepo.GetDigestRecipient(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return s.buildDigest(recipient, time.Now())
+}
+
+// CheckUnsubscribeToken verifies a digest unsubscribe token without unsubscribing
+func (s *DigestService) CheckUnsubscribeToken(token string) error {
+	if _, err := utils.VerifyDigestUnsubscribeToken(token); err != nil {
+		return utils.ErrInvalidToken
+	}
+	return nil
+}
+
+// Unsubscribe turns off the weekly digest of the user an unsubscribe token was issued to
+func (s *DigestService) Unsubscribe(token string) error {
+	userID, err := utils.VerifyDigestUnsubscribeToken(token)
+	if err != nil {
+		return utils.ErrInvalidToken
+	}
+	if err := s.digestRepo.SetWeeklyDigest(userID, false); err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	return nil
+}
+
+// SendDueDigests sends the digests due now
+func (s *DigestService) SendDueDigests() (*DigestRunResult, error) {
+	return s.sendDue(time.Now())
+}
+
+// StartScheduler starts a background goroutine that periodically sends due weekly digests
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler
+func (s *DigestService) StartScheduler() chan struct{} {
+	stop := make(chan struct{})
+	interval := s.cfg.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if result, err := s.SendDueDigests(); err != nil {
+					log.Printf("⚠️  Weekly digest error: %v", err)
+				} else if result.Sent+result.Failed > 0 {
+					log.Printf("📰 Weekly digests: %d sent, %d skipped, %d failed", result.Sent, result.Skipped, result.Failed)
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// sendDue sends the digests due at now
+// Each digest is claimed before it is sent, and released again if sending fails so a
+// later run retries it, once digestRetryDelay has passed.
+func (s *DigestService) sendDue(now time.Time) (*DigestRunResult, error) {
+	result := &DigestRunResult{}
+	local := now.In(s.location)
+	if local.Hour() < s.cfg.SendHour {
+		return result, nil
+	}
+
+	batchSize := s.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	sentBefore := now.Add(-digestMinInterval)
+	recipients, err := s.digestRepo.GetDigestRecipients(local.Weekday(), sentBefore, now.Add(-digestRetryDelay), batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest recipients: %w", err)
+	}
+
+	for i := range recipients {
+		recipient := &recipients[i]
+		claimed, err := s.digestRepo.ClaimDigest(recipient.UserID, now, sentBefore)
+		if err != nil {
+			log.Printf("⚠️  Failed to claim digest of user %d: %v", recipient.UserID, err)
+			result.Failed++
+			continue
+		}
+		if !claimed {
+			continue // Sent by another instance
+		}
+
+		if err := s.send(recipient, now, result); err != nil {
+			log.Printf("⚠️  Failed to send digest to user %d: %v", recipient.UserID, err)
+			result.Failed++
+			if err := s.digestRepo.ReleaseDigest(recipient.UserID, recipient.LastDigestSentAt, now); err != nil {
+				log.Printf("⚠️  Failed to release digest of user %d: %v", recipient.UserID, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// send builds a recipient's digest and emails it, unless there is nothing to report
+func (s *DigestService) send(recipient *repository.DigestRecipient, now time.Time, result *DigestRunResult) error {
+	digest, err := s.buildDigest(recipient, now)
+	if err != nil {
+		return err
+	}
+	if digest.IsEmpty() {
+		result.Skipped++
+		return nil
+	}
+
+	token, err := utils.GenerateDigestUnsubscribeToken(recipient.UserID)
+	if err != nil {
+		return err
+	}
+	if err := utils.SendWeeklyDigestEmail(recipient.Email, recipient.FullName, digest, utils.DigestUnsubscribeURL(token)); err != nil {
+		return err
+	}
+	result.Sent++
+	return nil
+}
+
+// buildDigest gathers the digest of a user at now: the past week's activity and the
+// coming week's sessions, with times in the digest time zone
+func (s *DigestService) buildDigest(recipient *repository.DigestRecipient, now time.Time) (*dto.WeeklyDigestResponse, error) {
+	userID := recipient.UserID
+	since := now.Add(-digestPeriod)
+	digest := &dto.WeeklyDigestResponse{
+		PeriodStart:        since.In(s.location),
+		PeriodEnd:          now.In(s.location),
+		UpcomingSessions:   []dto.DigestSession{},
+		PendingRequests:    []dto.DigestRequest{},
+		NewTeachers:        []dto.DigestTeacher{},
+		ThreadReplies:      []dto.DigestThreadReplies{},
+		NearlyEarnedBadges: []dto.DigestBadge{},
+	}
+
+	sessions, err := s.digestRepo.GetUpcomingSessions(userID, now, now.Add(digestPeriod), digestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming sessions: %w", err)
+	}
+	for _, session := range sessions {
+		digest.UpcomingSessions = append(digest.UpcomingSessions, dto.DigestSession{
+			ID:          session.ID,
+			Title:       session.Title,
+			ScheduledAt: session.ScheduledAt.In(s.location),
+			Duration:    session.Duration,
+			Mode:        string(session.Mode),
+			Role:        session.Role,
+			PartnerName: session.PartnerName,
+		})
+	}
+
+	requests, err := s.digestRepo.GetPendingRequests(userID, digestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending requests: %w", err)
+	}
+	for _, request := range requests {
+		var scheduledAt *time.Time
+		if request.ScheduledAt != nil {
+			local := request.ScheduledAt.In(s.location)
+			scheduledAt = &local
+		}
+		digest.PendingRequests = append(digest.PendingRequests, dto.DigestRequest{
+			ID:          request.ID,
+			Title:       request.Title,
+			StudentName: request.StudentName,
+			ScheduledAt: scheduledAt,
+			RequestedAt: request.CreatedAt.In(s.location),
+		})
+	}
+
+	teachers, err := s.digestRepo.GetNewWishlistTeachers(userID, since, digestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get new teachers: %w", err)
+	}
+	for _, teacher := range teachers {
+		digest.NewTeachers = append(digest.NewTeachers, dto.DigestTeacher{
+			UserSkillID: teacher.UserSkillID,
+			SkillName:   teacher.SkillName,
+			TeacherID:   teacher.TeacherID,
+			TeacherName: teacher.TeacherName,
+			Level:       string(teacher.Level),
+		})
+	}
+
+	threads, err := s.digestRepo.GetThreadReplies(userID, since, digestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread replies: %w", err)
+	}
+	for _, thread := range threads {
+		digest.ThreadReplies = append(digest.ThreadReplies, dto.DigestThreadReplies{
+			ThreadID:    thread.ThreadID,
+			Title:       thread.Title,
+			NewReplies:  thread.NewReplies,
+			LastReplyAt: thread.LastReplyAt.In(s.location),
+		})
+	}
+
+	transactions, err := s.digestRepo.GetBalanceChanges(userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit summary: %w", err)
+	}
+	digest.Credits = digestCredits(recipient.CreditBalance, transactions)
+
+	badges, err := s.digestRepo.GetNearlyEarnedBadges(userID, digestBadgeMinPercent, digestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get badge progress: %w", err)
+	}
+	for _, badge := range badges {
+		digest.NearlyEarnedBadges = append(digest.NearlyEarnedBadges, dto.DigestBadge{
+			BadgeID: badge.BadgeID,
+			Name:    badge.Name,
+			Icon:    badge.Icon,
+			Percent: badge.Percent,
+		})
+	}
+
+	return digest, nil
+}
+
+// digestCredits summarizes the credits gained and lost over the digest period
+// Like statement totals, only lines that changed the balance count (no escrow holds or releases).
+func digestCredits(balance float64, transactions []models.Transaction) dto.DigestCredits {
+	earned, spent, changes := balanceTotals(transactions)
+	return dto.DigestCredits{
+		Balance:      balance,
+		Earned:       earned,
+		Spent:        spent,
+		Net:          earned - spent,
+		Transactions: changes,
+	}
+}
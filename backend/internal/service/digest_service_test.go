@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+func TestDigestCredits(t *testing.T) {
+	tests := []struct {
+		name         string
+		lines        []models.Transaction
+		earned       float64
+		spent        float64
+		transactions int
+	}{
+		{
+			name: "quiet week",
+		},
+		{
+			name: "taught and donated",
+			lines: []models.Transaction{
+				ledgerLine(models.TransactionEarned, 2, 10, 12),
+				ledgerLine(models.TransactionDonation, -1, 12, 11),
+			},
+			earned:       2,
+			spent:        1,
+			transactions: 2,
+		},
+		{
+			name: "booking still in escrow is not spent",
+			lines: []models.Transaction{
+				ledgerLine(models.TransactionHold, 1.5, 10, 10),
+			},
+		},
+		{
+			name: "cancelled booking",
+			lines: []models.Transaction{
+				ledgerLine(models.TransactionHold, 1.5, 10, 10),
+				ledgerLine(models.TransactionRefund, -1.5, 10, 10),
+			},
+		},
+		{
+			name: "completed booking counts once",
+			lines: []models.Transaction{
+				ledgerLine(models.TransactionHold, 1.5, 10, 10),
+				ledgerLine(models.TransactionSpent, -1.5, 10, 8.5),
+				ledgerLine(models.TransactionEarned, 1, 8.5, 9.5),
+			},
+			earned:       1,
+			spent:        1.5,
+			transactions: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credits := digestCredits(9.5, tt.lines)
+
+			assert.Equal(t, 9.5, credits.Balance)
+			assert.InDelta(t, tt.earned, credits.Earned, 1e-9)
+			assert.InDelta(t, tt.spent, credits.Spent, 1e-9)
+			assert.InDelta(t, tt.earned-tt.spent, credits.Net, 1e-9)
+			assert.Equal(t, tt.transactions, credits.Transactions)
+		})
+	}
+}
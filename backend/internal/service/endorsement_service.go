@@ -5,15 +5,19 @@ import (
 	"fmt"
 
 	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
 	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
 )
 
 // EndorsementService handles donation business logic
+// Donations are real credit movements: the donor's available credits go into the community pool
 type EndorsementService struct {
 	endorsementRepo     *repository.EndorsementRepository
 	userRepo            *repository.UserRepository
 	skillRepo           *repository.SkillRepository
+	poolRepo            *repository.CommunityPoolRepository
 	notificationService *NotificationService
 }
 
@@ -22,11 +26,13 @@ func NewEndorsementService(
 	endorsementRepo *repository.EndorsementRepository,
 	userRepo *repository.UserRepository,
 	skillRepo *repository.SkillRepository,
+	poolRepo *repository.CommunityPoolRepository,
 ) *EndorsementService {
 	return &EndorsementService{
 		endorsementRepo:     endorsementRepo,
 		userRepo:            userRepo,
 		skillRepo:           skillRepo,
+		poolRepo:            poolRepo,
 		notificationService: nil,
 	}
 }
@@ -36,17 +42,21 @@ func NewEndorsementServiceWithNotification(
 	endorsementRepo *repository.EndorsementRepository,
 	userRepo *repository.UserRepository,
 	skillRepo *repository.SkillRepository,
+	poolRepo *repository.CommunityPoolRepository,
 	notificationService *NotificationService,
 ) *EndorsementService {
 	return &EndorsementService{
 		endorsementRepo:     endorsementRepo,
 		userRepo:            userRepo,
 		skillRepo:           skillRepo,
+		poolRepo:            poolRepo,
 		notificationService: notificationService,
 	}
 }
 
 // CreateDonation creates a new donation from a user
+// The amount is debited from the donor's available credits (balance minus escrow)
+// and credited to the community pool in the same database transaction
 func (s *EndorsementService) CreateEndorsement(donorID uint, req *dto.CreateEndorsementRequest) (*models.Endorsement, error) {
 	// Validate donor exists
 	_, err := s.userRepo.GetByID(donorID)
@@ -66,9 +76,26 @@ func (s *EndorsementService) CreateEndorsement(donorID uint, req *dto.CreateEndo
 		IsAnonymous: req.IsAnonymous,
 	}
 
-	if err := s.endorsementRepo.CreateEndorsement(donation); err != nil {
+	if err := s.poolRepo.Donate(donation); err != nil {
+		if errors.Is(err, utils.ErrInsufficientCredits) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create donation: %w", err)
 	}
+	events.PublishWithData(events.DonationMade, map[string]interface{}{"amount": donation.Amount}, donorID)
+
+	if s.notificationService != nil {
+		_, _ = s.notificationService.CreateNotification(
+			donorID,
+			models.NotificationTypeCredit,
+			"Thank You for Donating! 💚",
+			fmt.Sprintf("You donated %.1f credits to the community pool", donation.Amount),
+			map[string]interface{}{
+				"amount":      donation.Amount,
+				"donation_id": donation.ID,
+			},
+		)
+	}
 
 	return s.endorsementRepo.GetEndorsementByID(donation.ID)
 }
@@ -111,6 +138,11 @@ func (s *EndorsementService) DeleteEndorsement(endorsementID, donorID uint) erro
 		return errors.New("unauthorized to delete this donation")
 	}
 
+	// Credits already moved to the pool stay there; only legacy records can be removed
+	if donation.TransactionID != nil {
+		return errors.New("donations credited to the community pool cannot be deleted")
+	}
+
 	return s.endorsementRepo.DeleteEndorsement(endorsementID)
 }
 
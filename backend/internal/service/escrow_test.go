@@ -84,7 +84,7 @@ func (m *MockTransactionRepo) FindByUserID(u uint, l int) ([]models.Transaction,
 func (m *MockTransactionRepo) GetUserTransactionHistory(u uint, l, o int) ([]models.Transaction, int64, error) { return nil, 0, nil }
 func (m *MockTransactionRepo) CountTotal() (int64, error) { return 0, nil }
 func (m *MockTransactionRepo) GetTotalVolume() (float64, error) { return 0, nil }
-func (m *MockTransactionRepo) GetAllWithFilters(l, o int, t, s string) ([]models.Transaction, int64, error) { return nil, 0, nil }
+func (m *MockTransactionRepo) GetAllWithFilters(l, o int, t, s string, u uint) ([]models.Transaction, int64, error) { return nil, 0, nil }
 func (m *MockTransactionRepo) GetCreditVolumeTrend(d int) ([]models.DailyStat, error) { return nil, nil }
 
 type MockSkillRepo struct{ mock.Mock }
@@ -105,7 +105,7 @@ func (m *MockSkillRepo) GetUserSkillByID(id uint) (*models.UserSkill, error) {
 func (m *MockSkillRepo) Create(s *models.Skill) error { return nil }
 func (m *MockSkillRepo) Update(s *models.Skill) error { return nil }
 func (m *MockSkillRepo) Delete(id uint) error { return nil }
-func (m *MockSkillRepo) GetAllWithFilters(l, o int, c, s string, d *int, r *float64, loc, sb string) ([]models.Skill, int64, error) { return nil, 0, nil }
+func (m *MockSkillRepo) GetAllWithFilters(l, o int, c, s string, d *int, r *float64, loc, t, sb string) ([]models.Skill, int64, error) { return nil, 0, nil }
 func (m *MockSkillRepo) GetRecommendations(l int) ([]models.Skill, error) { return nil, nil }
 func (m *MockSkillRepo) GetUserSkills(u uint) ([]models.UserSkill, error) { return nil, nil }
 func (m *MockSkillRepo) GetUserSkill(u, s uint) (*models.UserSkill, error) { return nil, nil }
@@ -116,7 +116,7 @@ func (m *MockSkillRepo) GetLearningSkills(u uint) ([]models.LearningSkill, error
 func (m *MockSkillRepo) GetLearningSkill(u, s uint) (*models.LearningSkill, error) { return nil, nil }
 func (m *MockSkillRepo) CreateLearningSkill(l *models.LearningSkill) error { return nil }
 func (m *MockSkillRepo) DeleteLearningSkill(u, s uint) error { return nil }
-func (m *MockSkillRepo) GetTeachersBySkillID(s uint) ([]models.UserSkill, error) { return nil, nil }
+func (m *MockSkillRepo) GetTeachersBySkillID(s uint, t string) ([]models.UserSkill, error) { return nil, nil }
 func (m *MockSkillRepo) CountTotal() (int64, error) { return 0, nil }
 
 type MockNotificationService struct{ mock.Mock }
@@ -145,6 +145,7 @@ func TestBookSessionEscrow(t *testing.T) {
 		userRepo,
 		txRepo,
 		skillRepo,
+		nil,
 		badgeService,
 		notifService,
 	)
@@ -227,6 +228,7 @@ func TestCompleteSessionEscrow(t *testing.T) {
 		userRepo,
 		txRepo,
 		skillRepo,
+		nil,
 		badgeService,
 		notifService,
 	)
@@ -299,3 +301,54 @@ func TestCompleteSessionEscrow(t *testing.T) {
 	sessionRepo.AssertExpectations(t)
 	txRepo.AssertExpectations(t)
 }
+
+func TestAdminCompleteSessionReleasesEscrow(t *testing.T) {
+	userRepo := new(MockUserRepo)
+	sessionRepo := new(MockSessionRepo)
+	txRepo := new(MockTransactionRepo)
+	notifService := new(MockNotificationService)
+
+	s := NewSessionService(nil, sessionRepo, userRepo, txRepo, new(MockSkillRepo), nil, new(MockBadgeService), notifService)
+
+	student := &models.User{ID: 1, CreditBalance: 7.0, CreditHeld: 3.0}
+	teacher := &models.User{ID: 2, CreditBalance: 5.0, PayoutsFrozen: true}
+	session := &models.Session{
+		ID:           1,
+		TeacherID:    2,
+		StudentID:    1,
+		Status:       models.StatusInProgress,
+		CreditAmount: 3.0,
+		CreditHeld:   true,
+	}
+
+	sessionRepo.On("GetByID", uint(1)).Return(session, nil)
+	userRepo.On("GetByID", uint(1)).Return(student, nil)
+	userRepo.On("GetByID", uint(2)).Return(teacher, nil)
+	userRepo.On("Update", mock.Anything).Return(nil)
+	sessionRepo.On("Update", mock.Anything).Return(nil)
+	txRepo.On("Create", mock.Anything).Return(nil)
+	notifService.On("CreateNotification", uint(2), models.NotificationTypeSession, mock.Anything, mock.Anything, mock.Anything).Return(&models.Notification{}, nil)
+
+	assert.NoError(t, s.AdminCompleteSession(1))
+	assert.Equal(t, 0.0, student.CreditHeld)
+	assert.Equal(t, 4.0, student.CreditBalance)
+	assert.Equal(t, 8.0, teacher.CreditBalance)
+	assert.Equal(t, 3.0, teacher.FrozenCredits, "the payout freeze applies to admin completions too")
+	assert.True(t, session.CreditReleased)
+	txRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestAdminCompleteSessionRefusesRefundedSession(t *testing.T) {
+	sessionRepo := new(MockSessionRepo)
+	s := NewSessionService(nil, sessionRepo, new(MockUserRepo), new(MockTransactionRepo), new(MockSkillRepo), nil, new(MockBadgeService), new(MockNotificationService))
+
+	sessionRepo.On("GetByID", uint(1)).Return(&models.Session{
+		ID:           1,
+		Status:       models.StatusCancelled,
+		CreditAmount: 3.0,
+		CreditHeld:   true,
+	}, nil)
+
+	assert.Error(t, s.AdminCompleteSession(1))
+	sessionRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
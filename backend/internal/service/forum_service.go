@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
 	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/repository"
 )
@@ -75,6 +76,7 @@ func (s *ForumService) CreateThread(userID uint, req *dto.CreateThreadRequest) (
 	if err := s.forumRepo.CreateThread(thread); err != nil {
 		return nil, fmt.Errorf("failed to create thread: %w", err)
 	}
+	events.Publish(events.ForumThreadCreated, userID)
 
 	return s.forumRepo.GetThreadByID(thread.ID)
 }
@@ -169,6 +171,7 @@ func (s *ForumService) CreateReply(userID uint, req *dto.CreateReplyRequest) (*m
 	if err := s.forumRepo.CreateReply(reply); err != nil {
 		return nil, fmt.Errorf("failed to create reply: %w", err)
 	}
+	events.Publish(events.ForumReplyCreated, userID)
 
 	return reply, nil
 }
@@ -185,13 +188,20 @@ func (s *ForumService) GetRepliesByThread(threadID uint, limit, offset int) ([]m
 	return s.forumRepo.GetRepliesByThread(threadID, limit, offset)
 }
 
-// DeleteReply deletes a reply
+// DeleteReply deletes a reply (author only)
 func (s *ForumService) DeleteReply(replyID, userID uint) error {
-	// Note: We need to add GetReplyByID to repository for authorization check
-	// For now, just delete
+	reply, err := s.forumRepo.GetReplyByID(replyID)
+	if err != nil {
+		return errors.New("reply not found")
+	}
+	if reply.AuthorID != userID {
+		return errors.New("you can only delete your own replies")
+	}
+
 	if err := s.forumRepo.DeleteReply(replyID); err != nil {
 		return fmt.Errorf("failed to delete reply: %w", err)
 	}
+	events.Publish(events.ForumReplyDeleted, reply.AuthorID)
 
 	return nil
 }
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+)
+
+// maxTransferCyclesPerRun bounds the cycle search on dense transfer graphs
+const maxTransferCyclesPerRun = 100
+
+// FraudDetectionService scans sessions and transfers for farming patterns
+//
+// Each detected pattern raises a "fraud" Report for admin review. Detection never
+// moves credits by itself; with FreezePayouts enabled, the involved users' future
+// teaching earnings are held (see SessionService.completeSession) until an admin
+// dismisses the report or releases the freeze.
+//
+// Signals:
+//   - Reciprocal pairs: two users teaching each other again and again
+//   - Instant sessions: completed within minutes of check-in
+//   - Transfer cycles: credits sent around a loop back to the first sender
+//   - New account rings: young accounts trading sessions only among themselves
+type FraudDetectionService struct {
+	cfg       config.FraudConfig
+	fraudRepo *repository.FraudRepository
+}
+
+// NewFraudDetectionService creates a new fraud detection service
+func NewFraudDetectionService(cfg config.FraudConfig, fraudRepo *repository.FraudRepository) *FraudDetectionService {
+	return &FraudDetectionService{
+		cfg:       cfg,
+		fraudRepo: fraudRepo,
+	}
+}
+
+// RunDetection runs every detector once and raises reports for new findings
+//
+// Returns:
+//   - *FraudScanResult: Number of new reports per signal
+//   - error: If a detector query failed (reports raised before the failure are kept)
+func (s *FraudDetectionService) RunDetection() (*dto.FraudScanResult, error) {
+	since := time.Now().AddDate(0, 0, -s.cfg.LookbackDays)
+	result := &dto.FraudScanResult{FreezePayouts: s.cfg.FreezePayouts}
+
+	var err error
+	if result.ReciprocalPairs, err = s.detectReciprocalPairs(since); err != nil {
+		return result, fmt.Errorf("reciprocal pair detection failed: %w", err)
+	}
+	if result.InstantSessions, err = s.detectInstantSessions(since); err != nil {
+		return result, fmt.Errorf("instant session detection failed: %w", err)
+	}
+	if result.TransferCycles, err = s.detectTransferCycles(since); err != nil {
+		return result, fmt.Errorf("transfer cycle detection failed: %w", err)
+	}
+	if result.NewAccountRings, err = s.detectNewAccountRings(since); err != nil {
+		return result, fmt.Errorf("new account ring detection failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// StartScheduler starts a background goroutine that periodically runs RunDetection
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler
+func (s *FraudDetectionService) StartScheduler() chan struct{} {
+	stop := make(chan struct{})
+	interval := s.cfg.RunInterval
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				result, err := s.RunDetection()
+				if err != nil {
+					log.Printf("⚠️  Fraud detection error: %v", err)
+				}
+				if result != nil && result.Total() > 0 {
+					log.Printf("🚩 Fraud detection raised %d reports", result.Total())
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// detectReciprocalPairs reports pairs of users who keep teaching each other
+func (s *FraudDetectionService) detectReciprocalPairs(since time.Time) (int, error) {
+	pairs, err := s.fraudRepo.FindReciprocalPairs(since, s.cfg.ReciprocalMinSessions)
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, pair := range pairs {
+		ok, err := s.raise(
+			models.FraudSignalReciprocalPair,
+			[]uint{pair.UserA, pair.UserB},
+			fmt.Sprintf("Users %d and %d completed %d sessions teaching each other in the last %d days (%d and %d)",
+				pair.UserA, pair.UserB, pair.ATaught+pair.BTaught, s.cfg.LookbackDays, pair.ATaught, pair.BTaught),
+			map[string]interface{}{
+				"a_taught":    pair.ATaught,
+				"b_taught":    pair.BTaught,
+				"session_ids": splitIDs(pair.SessionIDs),
+			},
+			since,
+		)
+		if err != nil {
+			return raised, err
+		}
+		if ok {
+			raised++
+		}
+	}
+	return raised, nil
+}
+
+// detectInstantSessions reports teachers whose sessions complete right after check-in
+func (s *FraudDetectionService) detectInstantSessions(since time.Time) (int, error) {
+	stats, err := s.fraudRepo.FindInstantSessions(since, s.cfg.MinSessionMinutes, s.cfg.InstantMinSessions)
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, stat := range stats {
+		// Only the teacher is paid, so only the teacher's payouts are frozen
+		ok, err := s.raise(
+			models.FraudSignalInstantSessions,
+			[]uint{stat.TeacherID},
+			fmt.Sprintf("Teacher %d completed %d sessions less than %d minutes after check-in in the last %d days",
+				stat.TeacherID, stat.SessionCount, s.cfg.MinSessionMinutes, s.cfg.LookbackDays),
+			map[string]interface{}{
+				"session_count": stat.SessionCount,
+				"session_ids":   splitIDs(stat.SessionIDs),
+				"student_ids":   splitIDs(stat.StudentIDs),
+			},
+			since,
+		)
+		if err != nil {
+			return raised, err
+		}
+		if ok {
+			raised++
+		}
+	}
+	return raised, nil
+}
+
+// detectTransferCycles reports credits that travel through transfers back to where they started
+func (s *FraudDetectionService) detectTransferCycles(since time.Time) (int, error) {
+	edges, err := s.fraudRepo.GetTransferEdges(since)
+	if err != nil {
+		return 0, err
+	}
+
+	amounts := make(map[[2]uint]float64)
+	graph := make(map[uint][]uint)
+	for _, edge := range edges {
+		if edge.Amount < s.cfg.CycleMinAmount || edge.FromID == edge.ToID {
+			continue
+		}
+		graph[edge.FromID] = append(graph[edge.FromID], edge.ToID)
+		amounts[[2]uint{edge.FromID, edge.ToID}] = edge.Amount
+	}
+
+	raised := 0
+	for _, cycle := range findCycles(graph, s.cfg.CycleMaxLength, maxTransferCyclesPerRun) {
+		path := make([]string, 0, len(cycle)+1)
+		legs := make([]map[string]interface{}, 0, len(cycle))
+		for i, from := range cycle {
+			to := cycle[(i+1)%len(cycle)]
+			path = append(path, strconv.FormatUint(uint64(from), 10))
+			legs = append(legs, map[string]interface{}{
+				"from":   from,
+				"to":     to,
+				"amount": amounts[[2]uint{from, to}],
+			})
+		}
+		path = append(path, path[0])
+
+		ok, err := s.raise(
+			models.FraudSignalTransferCycle,
+			cycle,
+			fmt.Sprintf("Credits were transferred in a cycle %s in the last %d days",
+				strings.Join(path, " → "), s.cfg.LookbackDays),
+			map[string]interface{}{"legs": legs},
+			since,
+		)
+		if err != nil {
+			return raised, err
+		}
+		if ok {
+			raised++
+		}
+	}
+	return raised, nil
+}
+
+// detectNewAccountRings reports groups of new accounts that trade only among themselves
+func (s *FraudDetectionService) detectNewAccountRings(since time.Time) (int, error) {
+	createdAfter := time.Now().AddDate(0, 0, -s.cfg.NewAccountDays)
+	edges, err := s.fraudRepo.GetNewAccountSessionEdges(since, createdAfter)
+	if err != nil {
+		return 0, err
+	}
+	trades, err := s.fraudRepo.GetNewAccountTradeEdges(since, createdAfter)
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, members := range closedClusters(edges, trades, s.cfg.ClusterMinSize) {
+		total := 0
+		inCluster := make(map[uint]bool, len(members))
+		for _, id := range members {
+			inCluster[id] = true
+		}
+		for _, edge := range edges {
+			if inCluster[edge.FromID] {
+				total += edge.Count
+			}
+		}
+
+		ok, err := s.raise(
+			models.FraudSignalNewAccountRing,
+			members,
+			fmt.Sprintf("%d accounts created in the last %d days completed %d sessions only with each other",
+				len(members), s.cfg.NewAccountDays, total),
+			map[string]interface{}{"session_count": total},
+			since,
+		)
+		if err != nil {
+			return raised, err
+		}
+		if ok {
+			raised++
+		}
+	}
+	return raised, nil
+}
+
+// closedClusters groups the new accounts connected by session edges into clusters of at
+// least minSize members that trade with nobody else
+//
+// An account with a session or transfer (trades) with anyone outside the remaining
+// candidates is dropped, repeatedly, so every member of a returned cluster traded only
+// with other members. Members are sorted by ID.
+func closedClusters(edges, trades []models.UserEdgeStat, minSize int) [][]uint {
+	partners := make(map[uint]map[uint]bool)
+	link := func(a, b uint) {
+		if partners[a] == nil {
+			partners[a] = make(map[uint]bool)
+		}
+		partners[a][b] = true
+	}
+	for _, trade := range trades {
+		link(trade.FromID, trade.ToID)
+		link(trade.ToID, trade.FromID)
+	}
+
+	candidates := make(map[uint]bool)
+	for _, edge := range edges {
+		candidates[edge.FromID] = true
+		candidates[edge.ToID] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for id := range candidates {
+			for partner := range partners[id] {
+				if !candidates[partner] {
+					delete(candidates, id)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	// Union-find over the session graph of the remaining accounts
+	parent := make(map[uint]uint)
+	var find func(uint) uint
+	find = func(id uint) uint {
+		if _, ok := parent[id]; !ok {
+			parent[id] = id
+		}
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	for _, edge := range edges {
+		if candidates[edge.FromID] && candidates[edge.ToID] {
+			parent[find(edge.FromID)] = find(edge.ToID)
+		}
+	}
+
+	groups := make(map[uint][]uint)
+	for id := range parent {
+		root := find(id)
+		groups[root] = append(groups[root], id)
+	}
+
+	var clusters [][]uint
+	for _, members := range groups {
+		if len(members) < minSize {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+		clusters = append(clusters, members)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i][0] < clusters[j][0] })
+	return clusters
+}
+
+// raise creates a fraud report unless the same pattern is already under review
+// The fingerprint is the signal plus the involved user IDs, so a pair or cycle is reported once per lookback window.
+func (s *FraudDetectionService) raise(signal models.FraudSignal, userIDs []uint, reason string, evidence map[string]interface{}, since time.Time) (bool, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	fingerprint := string(signal) + ":" + strings.Join(ids, "-")
+
+	exists, err := s.fraudRepo.HasReport(fingerprint, since)
+	if err != nil || exists {
+		return false, err
+	}
+
+	evidence["signal"] = signal
+	evidence["user_ids"] = userIDs
+	evidence["payouts_frozen"] = s.cfg.FreezePayouts
+	metadata, _ := json.Marshal(evidence)
+
+	report := &models.Report{
+		Type:        models.ReportTypeFraud,
+		TargetID:    userIDs[0],
+		Reason:      reason,
+		Status:      models.ReportStatusPending,
+		Metadata:    string(metadata),
+		Fingerprint: fingerprint,
+	}
+	if err := s.fraudRepo.CreateReport(report, userIDs, s.cfg.FreezePayouts); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findCycles returns simple directed cycles of length 2..maxLength, at most limit of them
+// Each cycle starts at its smallest user ID, so rotations of the same cycle are found once.
+func findCycles(graph map[uint][]uint, maxLength, limit int) [][]uint {
+	starts := make([]uint, 0, len(graph))
+	for id := range graph {
+		starts = append(starts, id)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	var cycles [][]uint
+	var path []uint
+	onPath := make(map[uint]bool)
+
+	var visit func(start, node uint)
+	visit = func(start, node uint) {
+		if len(cycles) >= limit {
+			return
+		}
+		for _, next := range graph[node] {
+			if next == start && len(path) >= 2 {
+				cycle := make([]uint, len(path))
+				copy(cycle, path)
+				cycles = append(cycles, cycle)
+				continue
+			}
+			if next <= start || onPath[next] || len(path) >= maxLength {
+				continue
+			}
+			path = append(path, next)
+			onPath[next] = true
+			visit(start, next)
+			onPath[next] = false
+			path = path[:len(path)-1]
+		}
+	}
+
+	for _, start := range starts {
+		path = []uint{start}
+		onPath[start] = true
+		visit(start, start)
+		onPath[start] = false
+	}
+	return cycles
+}
+
+// splitIDs parses a comma-separated ID list from an aggregate query
+func splitIDs(list string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(list, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+func TestFindCycles(t *testing.T) {
+	tests := []struct {
+		name      string
+		graph     map[uint][]uint
+		maxLength int
+		limit     int
+		cycles    [][]uint
+	}{
+		{
+			name:      "chain without cycle",
+			graph:     map[uint][]uint{1: {2}, 2: {3}},
+			maxLength: 4,
+			limit:     10,
+		},
+		{
+			name:      "self transfer is not a cycle",
+			graph:     map[uint][]uint{1: {1}},
+			maxLength: 4,
+			limit:     10,
+		},
+		{
+			name:      "transfer back and forth",
+			graph:     map[uint][]uint{1: {2}, 2: {1}},
+			maxLength: 4,
+			limit:     10,
+			cycles:    [][]uint{{1, 2}},
+		},
+		{
+			name:      "rotations are reported once from the smallest ID",
+			graph:     map[uint][]uint{3: {1}, 1: {2}, 2: {3}},
+			maxLength: 4,
+			limit:     10,
+			cycles:    [][]uint{{1, 2, 3}},
+		},
+		{
+			name:      "overlapping cycles",
+			graph:     map[uint][]uint{1: {2}, 2: {1, 3}, 3: {1}},
+			maxLength: 4,
+			limit:     10,
+			cycles:    [][]uint{{1, 2}, {1, 2, 3}},
+		},
+		{
+			name:      "transfers into a cycle",
+			graph:     map[uint][]uint{1: {5}, 5: {6}, 6: {5}},
+			maxLength: 4,
+			limit:     10,
+			cycles:    [][]uint{{5, 6}},
+		},
+		{
+			name:      "longer than maxLength",
+			graph:     map[uint][]uint{1: {2}, 2: {3}, 3: {4}, 4: {1}},
+			maxLength: 3,
+			limit:     10,
+		},
+		{
+			name:      "exactly maxLength",
+			graph:     map[uint][]uint{1: {2}, 2: {3}, 3: {4}, 4: {1}},
+			maxLength: 4,
+			limit:     10,
+			cycles:    [][]uint{{1, 2, 3, 4}},
+		},
+		{
+			name:      "stops at the limit",
+			graph:     map[uint][]uint{1: {2}, 2: {1}, 3: {4}, 4: {3}},
+			maxLength: 4,
+			limit:     1,
+			cycles:    [][]uint{{1, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.cycles, findCycles(tt.graph, tt.maxLength, tt.limit))
+		})
+	}
+}
+
+func TestClosedClusters(t *testing.T) {
+	edge := func(from, to uint) models.UserEdgeStat {
+		return models.UserEdgeStat{FromID: from, ToID: to, Count: 1}
+	}
+	ring := []models.UserEdgeStat{edge(1, 2), edge(2, 3), edge(3, 1)}
+
+	tests := []struct {
+		name     string
+		edges    []models.UserEdgeStat
+		trades   []models.UserEdgeStat
+		minSize  int
+		clusters [][]uint
+	}{
+		{
+			name:     "closed ring",
+			edges:    ring,
+			trades:   ring,
+			minSize:  3,
+			clusters: [][]uint{{1, 2, 3}},
+		},
+		{
+			name:    "smaller than minSize",
+			edges:   ring,
+			trades:  ring,
+			minSize: 4,
+		},
+		{
+			name:    "member with a session outside the cluster",
+			edges:   ring,
+			trades:  append([]models.UserEdgeStat{edge(9, 3)}, ring...),
+			minSize: 3,
+		},
+		{
+			name:    "member with a transfer outside the cluster",
+			edges:   ring,
+			trades:  append([]models.UserEdgeStat{edge(2, 9)}, ring...),
+			minSize: 3,
+		},
+		{
+			name:    "dropping an open member opens its partners",
+			edges:   []models.UserEdgeStat{edge(1, 2), edge(2, 3), edge(3, 4)},
+			trades:  []models.UserEdgeStat{edge(1, 2), edge(2, 3), edge(3, 4), edge(4, 9)},
+			minSize: 1,
+		},
+		{
+			name:     "separate clusters",
+			edges:    []models.UserEdgeStat{edge(1, 2), edge(5, 6)},
+			trades:   []models.UserEdgeStat{edge(1, 2), edge(5, 6)},
+			minSize:  2,
+			clusters: [][]uint{{1, 2}, {5, 6}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.clusters, closedClusters(tt.edges, tt.trades, tt.minSize))
+		})
+	}
+}
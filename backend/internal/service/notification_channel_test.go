@@ -0,0 +1,181 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+// defaultPreferences are the preferences of a user who changed nothing
+func defaultPreferences() models.NotificationPreference {
+	return models.NotificationPreference{
+		SessionNotifications:     true,
+		CreditNotifications:      true,
+		AchievementNotifications: true,
+		ReviewNotifications:      true,
+		EmailNotifications:       true,
+		QuietHoursStart:          "22:00",
+		QuietHoursEnd:            "07:00",
+	}
+}
+
+// noonUTC is outside the default quiet hours
+var noonUTC = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// channelOutcome is what a dispatcher run did with one queued notification
+type channelOutcome struct {
+	sent, deferred, skipped int
+	delivered               int       // Messages that reached the stand-in server
+	sendAfter               time.Time // SendAfter of the delivery after the run
+}
+
+// notificationChannel is a delivery channel that honours notification preferences
+type notificationChannel struct {
+	name   string
+	enable func(pref *models.NotificationPreference)
+	// dispatch queues one notification due at sendAfter for a user with pref and runs
+	// the channel's dispatcher at now
+	dispatch func(t *testing.T, pref models.NotificationPreference, typ models.NotificationType, sendAfter, now time.Time) channelOutcome
+}
+
+// notificationChannels are the channels checked against the user's preferences when due
+var notificationChannels = []notificationChannel{
+	{
+		name:   "email",
+		enable: func(p *models.NotificationPreference) { p.EmailNotifications = true },
+		dispatch: func(t *testing.T, pref models.NotificationPreference, typ models.NotificationType, sendAfter, now time.Time) channelOutcome {
+			srv := startSMTP(t)
+			repo := &fakeEmailDeliveryRepo{
+				pref: pref,
+				due:  []models.EmailDelivery{dueEmail(1, typ, "Title", "Message", sendAfter)},
+			}
+			result, err := newTestDispatcher(repo).dispatchDue(now)
+			require.NoError(t, err)
+			require.Len(t, repo.updated, 1)
+			return channelOutcome{
+				sent:      result.Sent,
+				deferred:  result.Deferred,
+				skipped:   result.Skipped,
+				delivered: len(srv.Messages()),
+				sendAfter: repo.updated[0].SendAfter,
+			}
+		},
+	},
+	{
+		name:   "push",
+		enable: func(p *models.NotificationPreference) { p.PushNotifications = true },
+		dispatch: func(t *testing.T, pref models.NotificationPreference, typ models.NotificationType, sendAfter, now time.Time) channelOutcome {
+			dispatcher, repo, srv := newPushTest(t, 1)
+			repo.pref = pref
+			repo.due = []models.PushDelivery{duePush(1, typ, sendAfter)}
+			result, err := dispatcher.dispatchDue(now)
+			require.NoError(t, err)
+			require.Len(t, repo.updated, 1)
+			return channelOutcome{
+				sent:      result.Sent,
+				deferred:  result.Deferred,
+				skipped:   result.Skipped,
+				delivered: len(srv.Messages()),
+				sendAfter: repo.updated[0].SendAfter,
+			}
+		},
+	},
+}
+
+func TestNotificationChannelsHonourPreferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		disable func(*models.NotificationPreference)
+		typ     models.NotificationType
+	}{
+		{"channel off", func(p *models.NotificationPreference) { p.EmailNotifications, p.PushNotifications = false, false }, models.NotificationTypeSocial},
+		{"sessions off", func(p *models.NotificationPreference) { p.SessionNotifications = false }, models.NotificationTypeSession},
+		{"credits off", func(p *models.NotificationPreference) { p.CreditNotifications = false }, models.NotificationTypeCredit},
+		{"achievements off", func(p *models.NotificationPreference) { p.AchievementNotifications = false }, models.NotificationTypeAchievement},
+		{"reviews off", func(p *models.NotificationPreference) { p.ReviewNotifications = false }, models.NotificationTypeReview},
+	}
+	for _, ch := range notificationChannels {
+		for _, tt := range tests {
+			t.Run(ch.name+"/"+tt.name, func(t *testing.T) {
+				pref := defaultPreferences()
+				ch.enable(&pref)
+				tt.disable(&pref)
+
+				out := ch.dispatch(t, pref, tt.typ, noonUTC.Add(-time.Minute), noonUTC)
+				assert.Equal(t, 1, out.skipped)
+				assert.Zero(t, out.delivered)
+			})
+		}
+	}
+}
+
+func TestNotificationChannelsDeferDuringQuietHours(t *testing.T) {
+	lateNight := time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC)
+	quietEnd := time.Date(2026, 3, 11, 7, 0, 0, 0, time.UTC)
+
+	for _, ch := range notificationChannels {
+		t.Run(ch.name, func(t *testing.T) {
+			pref := defaultPreferences()
+			ch.enable(&pref)
+			pref.QuietHours = true
+
+			// Non-urgent notifications wait until quiet hours end the next morning
+			out := ch.dispatch(t, pref, models.NotificationTypeReview, lateNight.Add(-time.Minute), lateNight)
+			assert.Equal(t, 1, out.deferred)
+			assert.Zero(t, out.delivered)
+			assert.Equal(t, quietEnd, out.sendAfter)
+
+			// and go out once they are over
+			out = ch.dispatch(t, pref, models.NotificationTypeReview, out.sendAfter, out.sendAfter.Add(time.Minute))
+			assert.Equal(t, 1, out.sent)
+			assert.Equal(t, 1, out.delivered)
+
+			// Urgent session notifications are not held back
+			out = ch.dispatch(t, pref, models.NotificationTypeSession, lateNight.Add(-time.Minute), lateNight)
+			assert.Equal(t, 1, out.sent)
+			assert.Equal(t, 1, out.delivered)
+		})
+	}
+}
+
+func TestQuietHoursEnd(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 10, hour, minute, 0, 0, jakarta)
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		now        time.Time
+		quiet      bool
+		endsAt     time.Time
+	}{
+		{"overnight, before midnight", "22:00", "07:00", at(23, 15), true, at(7, 0).AddDate(0, 0, 1)},
+		{"overnight, after midnight", "22:00", "07:00", at(3, 0), true, at(7, 0)},
+		{"overnight, at end", "22:00", "07:00", at(7, 0), false, time.Time{}},
+		{"overnight, daytime", "22:00", "07:00", at(12, 0), false, time.Time{}},
+		{"same day", "13:00", "15:30", at(14, 0), true, at(15, 30)},
+		{"same day, outside", "13:00", "15:30", at(16, 0), false, time.Time{}},
+		{"equal times", "22:00", "22:00", at(22, 0), false, time.Time{}},
+		{"malformed", "late", "07:00", at(23, 0), false, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pref := &models.NotificationPreference{QuietHours: true, QuietHoursStart: tt.start, QuietHoursEnd: tt.end}
+			// Quiet hours are read in the configured zone, whatever zone now is in
+			endsAt, quiet := quietHoursEnd(pref, tt.now.UTC(), jakarta)
+			assert.Equal(t, tt.quiet, quiet)
+			if tt.quiet {
+				assert.True(t, tt.endsAt.Equal(endsAt), "ends at %v, want %v", endsAt, tt.endsAt)
+			}
+		})
+	}
+
+	pref := &models.NotificationPreference{QuietHours: false, QuietHoursStart: "00:00", QuietHoursEnd: "23:59"}
+	_, quiet := quietHoursEnd(pref, at(12, 0), jakarta)
+	assert.False(t, quiet, "quiet hours off")
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// emailDeliveryLease is how long a claimed email is reserved for the dispatcher sending it
+const emailDeliveryLease = 10 * time.Minute
+
+// urgentNotificationTypes are emailed even during quiet hours
+// Session notifications are time-sensitive (requests, reminders, cancellations).
+var urgentNotificationTypes = map[models.NotificationType]bool{
+	models.NotificationTypeSession: true,
+}
+
+// NotificationEmailDispatcher sends queued notification emails
+// Each email is checked against the user's current preferences when it is due;
+// non-urgent emails due during the user's quiet hours wait until the quiet hours end.
+type NotificationEmailDispatcher struct {
+	deliveryRepo repository.EmailDeliveryRepositoryInterface
+	cfg          config.EmailConfig
+}
+
+// NewNotificationEmailDispatcher creates a new notification email dispatcher
+func NewNotificationEmailDispatcher(deliveryRepo repository.EmailDeliveryRepositoryInterface, cfg config.EmailConfig) *NotificationEmailDispatcher {
+	return &NotificationEmailDispatcher{
+		deliveryRepo: deliveryRepo,
+		cfg:          cfg,
+	}
+}
+
+// EmailDispatchResult counts the outcomes of a dispatch run
+type EmailDispatchResult struct {
+	Sent     int `json:"sent"`
+	Deferred int `json:"deferred"` // Moved to the end of quiet hours
+	Skipped  int `json:"skipped"`  // Disabled by preferences or expired
+	Failed   int `json:"failed"`   // SMTP errors, retried later unless out of attempts
+}
+
+// DispatchDue sends the notification emails that are due
+func (d *NotificationEmailDispatcher) DispatchDue() (*EmailDispatchResult, error) {
+	return d.dispatchDue(time.Now())
+}
+
+// StartScheduler starts a background goroutine that periodically sends due notification emails
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler
+func (d *NotificationEmailDispatcher) StartScheduler() chan struct{} {
+	stop := make(chan struct{})
+	interval := d.cfg.DispatchInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if result, err := d.DispatchDue(); err != nil {
+					log.Printf("⚠️  Email dispatch error: %v", err)
+				} else if result.Sent+result.Failed > 0 {
+					log.Printf("📧 Notification emails: %d sent, %d deferred, %d skipped, %d failed",
+						result.Sent, result.Deferred, result.Skipped, result.Failed)
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// dispatchDue sends the emails due at now
+func (d *NotificationEmailDispatcher) dispatchDue(now time.Time) (*EmailDispatchResult, error) {
+	batchSize := d.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	deliveries, err := d.deliveryRepo.ClaimDueEmailDeliveries(now, emailDeliveryLease, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim emails: %w", err)
+	}
+
+	result := &EmailDispatchResult{}
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		d.deliver(delivery, now, result)
+		if err := d.deliveryRepo.UpdateEmailDelivery(delivery); err != nil {
+			log.Printf("⚠️  Failed to save email delivery %d: %v", delivery.ID, err)
+		}
+	}
+	return result, nil
+}
+
+// deliver applies the user's preferences to a due email and sends it, recording the
+// outcome on the delivery
+func (d *NotificationEmailDispatcher) deliver(delivery *models.EmailDelivery, now time.Time, result *EmailDispatchResult) {
+	notification := delivery.Notification
+
+	pref, err := d.deliveryRepo.GetPreferences(delivery.UserID)
+	if err != nil {
+		d.retry(delivery, now, fmt.Errorf("failed to get preferences: %w", err), result)
+		return
+	}
+	if !emailNotificationAllowed(pref, notification.Type) {
+		delivery.Status = models.EmailDeliverySkipped
+		delivery.LastError = "disabled by notification preferences"
+		result.Skipped++
+		return
+	}
+
+	maxAge := d.cfg.MaxAge
+	if maxAge <= 0 {
+		maxAge = 24 * time.Hour
+	}
+	// Retries and quiet hours move SendAfter, so the age counts from the notification itself
+	if now.Sub(notification.CreatedAt) > maxAge {
+		delivery.Status = models.EmailDeliverySkipped
+		delivery.LastError = "expired before it could be sent"
+		result.Skipped++
+		return
+	}
+
+	if !urgentNotificationTypes[notification.Type] {
+		if end, quiet := quietHoursEnd(pref, now, d.location()); quiet {
+			delivery.SendAfter = end
+			result.Deferred++
+			return
+		}
+	}
+
+	err = utils.SendNotificationEmail(delivery.User.Email, utils.NotificationEmail{
+		Type:          string(notification.Type),
+		RecipientName: delivery.User.FullName,
+		Title:         notification.Title,
+		Message:       notification.Message,
+	})
+	if err != nil {
+		d.retry(delivery, now, err, result)
+		return
+	}
+
+	delivery.Status = models.EmailDeliverySent
+	delivery.SentAt = &now
+	delivery.LastError = ""
+	result.Sent++
+}
+
+// retry records a failed attempt and schedules the next one, or gives up after MaxAttempts
+func (d *NotificationEmailDispatcher) retry(delivery *models.EmailDelivery, now time.Time, err error, result *EmailDispatchResult) {
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	result.Failed++
+
+	maxAttempts := d.cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = models.EmailDeliveryFailed
+		return
+	}
+	retryDelay := d.cfg.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = 5 * time.Minute
+	}
+	delivery.SendAfter = now.Add(time.Duration(delivery.Attempts) * retryDelay)
+}
+
+// location returns the time zone quiet hours are read in
+func (d *NotificationEmailDispatcher) location() *time.Location {
+	if d.cfg.Location == nil {
+		return time.UTC
+	}
+	return d.cfg.Location
+}
+
+// emailNotificationAllowed reports whether the preferences allow emailing a notification type
+// Email must be on, as well as the toggle of the type's category.
+func emailNotificationAllowed(pref *models.NotificationPreference, notificationType models.NotificationType) bool {
+	return pref.EmailNotifications && categoryNotificationAllowed(pref, notificationType)
+}
+
+// categoryNotificationAllowed reports whether the toggle of a notification type's category is on
+// Social notifications have no toggle of their own.
+func categoryNotificationAllowed(pref *models.NotificationPreference, notificationType models.NotificationType) bool {
+	switch notificationType {
+	case models.NotificationTypeSession:
+		return pref.SessionNotifications
+	case models.NotificationTypeCredit:
+		return pref.CreditNotifications
+	case models.NotificationTypeAchievement:
+		return pref.AchievementNotifications
+	case models.NotificationTypeReview:
+		return pref.ReviewNotifications
+	}
+	return true
+}
+
+// quietHoursEnd reports whether now falls within the user's quiet hours, read in loc,
+// and when they end
+// Quiet hours may span midnight (22:00-07:00). Equal or malformed start and end times
+// mean no quiet hours.
+func quietHoursEnd(pref *models.NotificationPreference, now time.Time, loc *time.Location) (time.Time, bool) {
+	if !pref.QuietHours {
+		return time.Time{}, false
+	}
+	start, err := time.Parse("15:04", pref.QuietHoursStart)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse("15:04", pref.QuietHoursEnd)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+	if startMinute == endMinute {
+		return time.Time{}, false
+	}
+
+	var quiet bool
+	if startMinute < endMinute {
+		quiet = minute >= startMinute && minute < endMinute
+	} else {
+		quiet = minute >= startMinute || minute < endMinute
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	endsAt := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+	if !endsAt.After(local) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt, true
+}
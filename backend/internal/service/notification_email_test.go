@@ -0,0 +1,209 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/smtptest"
+)
+
+// fakeEmailDeliveryRepo is an in-memory email outbox
+type fakeEmailDeliveryRepo struct {
+	due     []models.EmailDelivery
+	pref    models.NotificationPreference
+	updated []models.EmailDelivery
+}
+
+func (r *fakeEmailDeliveryRepo) ClaimDueEmailDeliveries(now time.Time, lease time.Duration, limit int) ([]models.EmailDelivery, error) {
+	due := r.due
+	r.due = nil
+	return due, nil
+}
+
+func (r *fakeEmailDeliveryRepo) UpdateEmailDelivery(delivery *models.EmailDelivery) error {
+	r.updated = append(r.updated, *delivery)
+	return nil
+}
+
+func (r *fakeEmailDeliveryRepo) GetPreferences(userID uint) (*models.NotificationPreference, error) {
+	pref := r.pref
+	return &pref, nil
+}
+
+// dueEmail is a pending email for a notification of the given type
+func dueEmail(id uint, notificationType models.NotificationType, title, message string, sendAfter time.Time) models.EmailDelivery {
+	return models.EmailDelivery{
+		ID:             id,
+		NotificationID: id,
+		UserID:         7,
+		Status:         models.EmailDeliveryPending,
+		SendAfter:      sendAfter,
+		Notification: models.Notification{
+			ID:        id,
+			UserID:    7,
+			Type:      notificationType,
+			Title:     title,
+			Message:   message,
+			CreatedAt: sendAfter,
+		},
+		User: models.User{Email: "siti@example.com", FullName: "Siti Rahma"},
+	}
+}
+
+// startSMTP starts the SMTP stand-in and points the SMTP helper at it
+func startSMTP(t *testing.T) *smtptest.Server {
+	t.Helper()
+	srv, err := smtptest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+
+	t.Setenv("SMTP_HOST", srv.Host)
+	t.Setenv("SMTP_PORT", srv.Port)
+	t.Setenv("SMTP_USER", "wibi")
+	t.Setenv("SMTP_PASS", "secret")
+	t.Setenv("EMAIL_FROM", "noreply@wibi.test")
+	t.Setenv("FRONTEND_URL", "https://wibi.test")
+	return srv
+}
+
+func newTestDispatcher(repo *fakeEmailDeliveryRepo) *NotificationEmailDispatcher {
+	return NewNotificationEmailDispatcher(repo, config.EmailConfig{
+		BatchSize:   10,
+		MaxAttempts: 3,
+		RetryDelay:  5 * time.Minute,
+		MaxAge:      24 * time.Hour,
+		Location:    time.UTC,
+	})
+}
+
+func TestNotificationEmailSentWithTypeTemplate(t *testing.T) {
+	srv := startSMTP(t)
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	repo := &fakeEmailDeliveryRepo{
+		pref: defaultPreferences(),
+		due:  []models.EmailDelivery{dueEmail(1, models.NotificationTypeReview, "New Review Received! ⭐", "Budi gave you a 5-star review", now)},
+	}
+
+	result, err := newTestDispatcher(repo).dispatchDue(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+
+	messages := srv.Messages()
+	require.Len(t, messages, 1)
+	msg := messages[0]
+	assert.Equal(t, "noreply@wibi.test", msg.From)
+	assert.Equal(t, []string{"siti@example.com"}, msg.To)
+	assert.Equal(t, "New Review Received! ⭐ - Wibi", msg.Subject())
+	assert.Contains(t, msg.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, msg.Body, "Review Update")
+	assert.Contains(t, msg.Body, "Hello Siti Rahma!")
+	assert.Contains(t, msg.Body, "Budi gave you a 5-star review")
+	assert.Contains(t, msg.Body, `href="https://wibi.test/profile"`)
+
+	require.Len(t, repo.updated, 1)
+	assert.Equal(t, models.EmailDeliverySent, repo.updated[0].Status)
+	assert.Equal(t, &now, repo.updated[0].SentAt)
+}
+
+func TestNotificationEmailTemplatesPerType(t *testing.T) {
+	srv := startSMTP(t)
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	repo := &fakeEmailDeliveryRepo{
+		pref: defaultPreferences(),
+		due: []models.EmailDelivery{
+			dueEmail(1, models.NotificationTypeCredit, "Credits Received", "You earned 2 credits", now),
+			dueEmail(2, models.NotificationTypeAchievement, "Badge Unlocked", "You earned Early Bird", now),
+		},
+	}
+
+	_, err := newTestDispatcher(repo).dispatchDue(now)
+	require.NoError(t, err)
+
+	messages := srv.Messages()
+	require.Len(t, messages, 2)
+	assert.Contains(t, messages[0].Body, "Credit Update")
+	assert.Contains(t, messages[0].Body, "/wallet")
+	assert.Contains(t, messages[1].Body, "New Achievement")
+	assert.Contains(t, messages[1].Body, "/badges")
+}
+
+func TestNotificationEmailEscapesContent(t *testing.T) {
+	srv := startSMTP(t)
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	repo := &fakeEmailDeliveryRepo{
+		pref: defaultPreferences(),
+		due:  []models.EmailDelivery{dueEmail(1, models.NotificationTypeSocial, "New Reply", `<script>alert("x")</script>`, now)},
+	}
+
+	_, err := newTestDispatcher(repo).dispatchDue(now)
+	require.NoError(t, err)
+
+	messages := srv.Messages()
+	require.Len(t, messages, 1)
+	assert.NotContains(t, messages[0].Body, "<script>")
+	assert.Contains(t, messages[0].Body, "&lt;script&gt;")
+}
+
+func TestNotificationEmailRetriedOnSMTPFailure(t *testing.T) {
+	srv := startSMTP(t)
+	srv.SetReject(true)
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	repo := &fakeEmailDeliveryRepo{
+		pref: defaultPreferences(),
+		due:  []models.EmailDelivery{dueEmail(1, models.NotificationTypeCredit, "Credits Received", "You earned 2 credits", now)},
+	}
+
+	result, err := newTestDispatcher(repo).dispatchDue(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+
+	require.Len(t, repo.updated, 1)
+	failed := repo.updated[0]
+	assert.Equal(t, models.EmailDeliveryPending, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, now.Add(5*time.Minute), failed.SendAfter)
+	assert.True(t, strings.Contains(failed.LastError, "451"), failed.LastError)
+
+	// The last attempt gives up
+	failed.Attempts = 2
+	repo.due = []models.EmailDelivery{failed}
+	_, err = newTestDispatcher(repo).dispatchDue(failed.SendAfter)
+	require.NoError(t, err)
+	assert.Equal(t, models.EmailDeliveryFailed, repo.updated[1].Status)
+	assert.Empty(t, srv.Messages())
+}
+
+func TestNotificationEmailExpires(t *testing.T) {
+	srv := startSMTP(t)
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	repo := &fakeEmailDeliveryRepo{
+		pref: defaultPreferences(),
+		due:  []models.EmailDelivery{dueEmail(1, models.NotificationTypeCredit, "Credits Received", "Old news", now.Add(-25*time.Hour))},
+	}
+
+	result, err := newTestDispatcher(repo).dispatchDue(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Empty(t, srv.Messages())
+}
+
+func TestNotificationEmailExpiresFromCreation(t *testing.T) {
+	srv := startSMTP(t)
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	// Retried until recently, but the notification is from yesterday
+	delivery := dueEmail(1, models.NotificationTypeCredit, "Credits Received", "Old news", now.Add(-10*time.Minute))
+	delivery.Attempts = 1
+	delivery.Notification.CreatedAt = now.Add(-25 * time.Hour)
+	repo := &fakeEmailDeliveryRepo{pref: defaultPreferences(), due: []models.EmailDelivery{delivery}}
+
+	result, err := newTestDispatcher(repo).dispatchDue(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, "expired before it could be sent", repo.updated[0].LastError)
+	assert.Empty(t, srv.Messages())
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/broadcast"
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/webpush"
+)
+
+// pushDeliveryLease is how long a claimed push is reserved for the dispatcher sending it
+const pushDeliveryLease = 5 * time.Minute
+
+// pushSendWorkers is the number of pushes of a batch sent at the same time
+// Push service requests time out after 10 seconds, so a default batch of 100 takes about
+// 100 seconds at worst, well within the lease.
+const pushSendWorkers = 10
+
+// pushPayload is the JSON a service worker receives in its push event
+type pushPayload struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// NotificationPushDispatcher pushes queued notifications to the devices of users who
+// were not connected
+// A notification is not pushed when a live WebSocket acknowledged it within the grace
+// period, or when the user still has an open notification stream (WebSocket or SSE) on any
+// instance once the grace period is over: the stream delivered it, or replays it on
+// reconnect. Otherwise it is sent to every push subscription of the user, subject to the user's
+// preferences and quiet hours like emails; subscriptions the push service reports gone
+// are deleted.
+type NotificationPushDispatcher struct {
+	deliveryRepo repository.PushDeliveryRepositoryInterface
+	client       *webpush.Client
+	cfg          config.PushConfig
+	location     *time.Location
+}
+
+// NewNotificationPushDispatcher creates a new notification push dispatcher
+func NewNotificationPushDispatcher(deliveryRepo repository.PushDeliveryRepositoryInterface, client *webpush.Client, cfg config.PushConfig, location *time.Location) *NotificationPushDispatcher {
+	if location == nil {
+		location = time.UTC
+	}
+	return &NotificationPushDispatcher{
+		deliveryRepo: deliveryRepo,
+		client:       client,
+		cfg:          cfg,
+		location:     location,
+	}
+}
+
+// PushDispatchResult counts the outcomes of a dispatch run
+type PushDispatchResult struct {
+	Sent     int `json:"sent"`
+	Waiting  int `json:"waiting"`  // Still within the grace period for a live connection
+	Deferred int `json:"deferred"` // Moved to the end of quiet hours
+	Skipped  int `json:"skipped"`  // Delivered live, disabled by preferences, expired or no devices
+	Failed   int `json:"failed"`   // Push service errors, retried later unless out of attempts
+	Pruned   int `json:"pruned"`   // Subscriptions deleted because the push service no longer knows them
+}
+
+// DispatchDue sends the notification pushes that are due
+func (d *NotificationPushDispatcher) DispatchDue() (*PushDispatchResult, error) {
+	return d.dispatchDue(time.Now())
+}
+
+// StartScheduler starts a background goroutine that periodically sends due notification pushes
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler
+func (d *NotificationPushDispatcher) StartScheduler() chan struct{} {
+	stop := make(chan struct{})
+	interval := d.cfg.DispatchInterval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if result, err := d.DispatchDue(); err != nil {
+					log.Printf("⚠️  Push dispatch error: %v", err)
+				} else if result.Sent+result.Failed+result.Pruned > 0 {
+					log.Printf("📲 Notification pushes: %d sent, %d deferred, %d skipped, %d failed, %d subscriptions pruned",
+						result.Sent, result.Deferred, result.Skipped, result.Failed, result.Pruned)
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// dispatchDue sends the pushes due at now, pushSendWorkers at a time
+func (d *NotificationPushDispatcher) dispatchDue(now time.Time) (*PushDispatchResult, error) {
+	batchSize := d.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	deliveries, err := d.deliveryRepo.ClaimDuePushDeliveries(now, pushDeliveryLease, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pushes: %w", err)
+	}
+
+	result := &PushDispatchResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan *models.PushDelivery)
+	for w := 0; w < pushSendWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range queue {
+				var outcome PushDispatchResult
+				d.deliver(delivery, now, &outcome)
+				if err := d.deliveryRepo.UpdatePushDelivery(delivery); err != nil {
+					log.Printf("⚠️  Failed to save push delivery %d: %v", delivery.ID, err)
+				}
+				mu.Lock()
+				result.add(&outcome)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range deliveries {
+		queue <- &deliveries[i]
+	}
+	close(queue)
+	wg.Wait()
+	return result, nil
+}
+
+// add adds the counts of another result
+func (r *PushDispatchResult) add(other *PushDispatchResult) {
+	r.Sent += other.Sent
+	r.Waiting += other.Waiting
+	r.Deferred += other.Deferred
+	r.Skipped += other.Skipped
+	r.Failed += other.Failed
+	r.Pruned += other.Pruned
+}
+
+// deliver pushes a due notification to the user's devices unless it was delivered live
+// or the user's preferences prevent it, recording the outcome on the delivery
+func (d *NotificationPushDispatcher) deliver(delivery *models.PushDelivery, now time.Time, result *PushDispatchResult) {
+	notification := delivery.Notification
+	if notification.ID == 0 {
+		d.skip(delivery, "notification was deleted", result)
+		return
+	}
+	if notification.DeliveredAt != nil {
+		d.skip(delivery, "delivered to a live connection", result)
+		return
+	}
+	if liveBy := notification.CreatedAt.Add(d.cfg.LiveGrace); now.Before(liveBy) {
+		delivery.SendAfter = liveBy
+		result.Waiting++
+		return
+	}
+	if broadcast.Default().Connected(delivery.UserID) {
+		d.skip(delivery, "user is connected", result)
+		return
+	}
+
+	pref, err := d.deliveryRepo.GetPreferences(delivery.UserID)
+	if err != nil {
+		d.retry(delivery, now, fmt.Errorf("failed to get preferences: %w", err), result)
+		return
+	}
+	if !pushNotificationAllowed(pref, notification.Type) {
+		d.skip(delivery, "disabled by notification preferences", result)
+		return
+	}
+
+	maxAge := d.cfg.MaxAge
+	if maxAge <= 0 {
+		maxAge = 6 * time.Hour
+	}
+	if now.Sub(delivery.SendAfter) > maxAge {
+		d.skip(delivery, "expired before it could be sent", result)
+		return
+	}
+
+	if !urgentNotificationTypes[notification.Type] {
+		if end, quiet := quietHoursEnd(pref, now, d.location); quiet {
+			delivery.SendAfter = end
+			result.Deferred++
+			return
+		}
+	}
+
+	subs, err := d.deliveryRepo.GetPushSubscriptions(delivery.UserID)
+	if err != nil {
+		d.retry(delivery, now, fmt.Errorf("failed to get push subscriptions: %w", err), result)
+		return
+	}
+	payload, err := buildPushPayload(&notification)
+	if err != nil {
+		d.skip(delivery, err.Error(), result)
+		return
+	}
+	msg := webpush.Message{Payload: payload, TTL: d.cfg.TTL, Urgency: webpush.UrgencyNormal}
+	if urgentNotificationTypes[notification.Type] {
+		msg.Urgency = webpush.UrgencyHigh
+	}
+
+	sent := 0
+	var lastErr error
+	for _, sub := range subs {
+		err := d.client.Send(context.Background(), webpush.Subscription{
+			Endpoint: sub.Endpoint,
+			P256dh:   sub.P256dh,
+			Auth:     sub.Auth,
+		}, msg)
+		switch {
+		case errors.Is(err, webpush.ErrSubscriptionGone):
+			if err := d.deliveryRepo.DeletePushSubscription(sub.ID); err != nil {
+				log.Printf("⚠️  Failed to delete expired push subscription %d: %v", sub.ID, err)
+			} else {
+				result.Pruned++
+			}
+		case err != nil:
+			lastErr = err
+		default:
+			sent++
+		}
+	}
+
+	switch {
+	case sent > 0:
+		delivery.Status = models.PushDeliverySent
+		delivery.SentAt = &now
+		delivery.LastError = ""
+		result.Sent++
+	case lastErr != nil:
+		d.retry(delivery, now, lastErr, result)
+	default:
+		d.skip(delivery, "no push subscriptions left", result)
+	}
+}
+
+// skip records that a push will not be sent
+func (d *NotificationPushDispatcher) skip(delivery *models.PushDelivery, reason string, result *PushDispatchResult) {
+	delivery.Status = models.PushDeliverySkipped
+	delivery.LastError = reason
+	result.Skipped++
+}
+
+// retry records a failed attempt and schedules the next one, or gives up after MaxAttempts
+func (d *NotificationPushDispatcher) retry(delivery *models.PushDelivery, now time.Time, err error, result *PushDispatchResult) {
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	result.Failed++
+
+	maxAttempts := d.cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = models.PushDeliveryFailed
+		return
+	}
+	retryDelay := d.cfg.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = time.Minute
+	}
+	delivery.SendAfter = now.Add(time.Duration(delivery.Attempts) * retryDelay)
+}
+
+// pushNotificationAllowed reports whether the preferences allow pushing a notification type
+// Push must be on, as well as the toggle of the type's category.
+func pushNotificationAllowed(pref *models.NotificationPreference, notificationType models.NotificationType) bool {
+	return pref.PushNotifications && categoryNotificationAllowed(pref, notificationType)
+}
+
+// buildPushPayload encodes a notification for a service worker
+// Push messages are limited to about 4KB: the data is dropped, then the message shortened,
+// until the payload fits.
+func buildPushPayload(notification *models.Notification) ([]byte, error) {
+	p := pushPayload{
+		ID:        notification.ID,
+		Type:      string(notification.Type),
+		Title:     notification.Title,
+		Body:      notification.Message,
+		Data:      notification.Data,
+		CreatedAt: notification.CreatedAt.Format(time.RFC3339),
+	}
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode push payload: %w", err)
+	}
+	if len(payload) > webpush.MaxPayloadSize && p.Data != nil {
+		p.Data = nil
+		payload, _ = json.Marshal(p)
+	}
+	for len(payload) > webpush.MaxPayloadSize {
+		body := []rune(p.Body)
+		if len(body) <= 1 {
+			return nil, errors.New("notification too large to push")
+		}
+		keep := len(body) - (len(payload) - webpush.MaxPayloadSize) - 1
+		if keep < 0 {
+			keep = 0
+		}
+		p.Body = string(body[:keep]) + "…"
+		payload, _ = json.Marshal(p)
+	}
+	return payload, nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/broadcast"
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/webpush"
+	"github.com/timebankingskill/backend/internal/webpushtest"
+)
+
+// fakePushDeliveryRepo is an in-memory push outbox with a user's subscriptions
+type fakePushDeliveryRepo struct {
+	mu      sync.Mutex
+	due     []models.PushDelivery
+	pref    models.NotificationPreference
+	subs    []models.PushSubscription
+	updated []models.PushDelivery
+	deleted []uint
+}
+
+func (r *fakePushDeliveryRepo) ClaimDuePushDeliveries(now time.Time, lease time.Duration, limit int) ([]models.PushDelivery, error) {
+	due := r.due
+	r.due = nil
+	return due, nil
+}
+
+func (r *fakePushDeliveryRepo) UpdatePushDelivery(delivery *models.PushDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updated = append(r.updated, *delivery)
+	return nil
+}
+
+func (r *fakePushDeliveryRepo) GetPreferences(userID uint) (*models.NotificationPreference, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pref := r.pref
+	return &pref, nil
+}
+
+func (r *fakePushDeliveryRepo) GetPushSubscriptions(userID uint) ([]models.PushSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.PushSubscription(nil), r.subs...), nil
+}
+
+func (r *fakePushDeliveryRepo) DeletePushSubscription(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = append(r.deleted, id)
+	for i, sub := range r.subs {
+		if sub.ID == id {
+			r.subs = append(r.subs[:i], r.subs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// pushTestConfig is the push configuration used by the tests
+var pushTestConfig = config.PushConfig{
+	LiveGrace:   30 * time.Second,
+	MaxAttempts: 3,
+	RetryDelay:  time.Minute,
+	MaxAge:      6 * time.Hour,
+	TTL:         24 * time.Hour,
+}
+
+// pushPreferences are the defaults with push turned on
+func pushPreferences() models.NotificationPreference {
+	pref := defaultPreferences()
+	pref.PushNotifications = true
+	return pref
+}
+
+// duePush is a pending push for a notification created at createdAt
+func duePush(id uint, notificationType models.NotificationType, createdAt time.Time) models.PushDelivery {
+	return models.PushDelivery{
+		ID:             id,
+		NotificationID: id,
+		UserID:         7,
+		Status:         models.PushDeliveryPending,
+		SendAfter:      createdAt,
+		Notification: models.Notification{
+			ID:        id,
+			UserID:    7,
+			Type:      notificationType,
+			Title:     "New Session Request",
+			Message:   "Budi wants to learn React.js",
+			Data:      json.RawMessage(`{"sessionID":123}`),
+			CreatedAt: createdAt,
+		},
+	}
+}
+
+// newPushTest starts the fake push service with the given number of subscribed devices
+// and returns a dispatcher pushing through it
+func newPushTest(t *testing.T, devices int) (*NotificationPushDispatcher, *fakePushDeliveryRepo, *webpushtest.Server) {
+	t.Helper()
+	srv := webpushtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	repo := &fakePushDeliveryRepo{pref: pushPreferences()}
+	for i := 0; i < devices; i++ {
+		sub, err := srv.NewSubscription()
+		require.NoError(t, err)
+		repo.subs = append(repo.subs, models.PushSubscription{
+			ID: uint(i + 1), UserID: 7, Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth,
+		})
+	}
+
+	publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+	require.NoError(t, err)
+	client, err := webpush.NewClient(publicKey, privateKey, "mailto:admin@wibi.id")
+	require.NoError(t, err)
+	return NewNotificationPushDispatcher(repo, client, pushTestConfig, time.UTC), repo, srv
+}
+
+func TestPushDispatcherPushesUnacknowledgedNotification(t *testing.T) {
+	dispatcher, repo, srv := newPushTest(t, 2)
+	repo.due = []models.PushDelivery{duePush(1, models.NotificationTypeSession, noonUTC.Add(-time.Minute))}
+
+	result, err := dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+
+	messages := srv.Messages()
+	require.Len(t, messages, 2, "one message per device")
+	var payload pushPayload
+	require.NoError(t, json.Unmarshal(messages[0].Payload, &payload))
+	assert.Equal(t, uint(1), payload.ID)
+	assert.Equal(t, "session", payload.Type)
+	assert.Equal(t, "New Session Request", payload.Title)
+	assert.Equal(t, "Budi wants to learn React.js", payload.Body)
+	assert.JSONEq(t, `{"sessionID":123}`, string(payload.Data))
+	assert.Equal(t, "high", messages[0].Urgency, "session notifications are urgent")
+	assert.Equal(t, 86400, messages[0].TTL)
+
+	require.Len(t, repo.updated, 1)
+	assert.Equal(t, models.PushDeliverySent, repo.updated[0].Status)
+	assert.Equal(t, noonUTC, *repo.updated[0].SentAt)
+}
+
+func TestPushDispatcherSkipsNotificationsDeliveredLive(t *testing.T) {
+	dispatcher, repo, srv := newPushTest(t, 1)
+	delivery := duePush(1, models.NotificationTypeCredit, noonUTC.Add(-time.Minute))
+	acked := noonUTC.Add(-time.Minute + time.Second)
+	delivery.Notification.DeliveredAt = &acked
+	repo.due = []models.PushDelivery{delivery}
+
+	result, err := dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Empty(t, srv.Messages())
+	assert.Equal(t, models.PushDeliverySkipped, repo.updated[0].Status)
+	assert.Equal(t, "delivered to a live connection", repo.updated[0].LastError)
+}
+
+func TestPushDispatcherSkipsConnectedUsers(t *testing.T) {
+	// An SSE stream never acknowledges, so presence decides
+	previous := broadcast.Default()
+	b := broadcast.NewMemory()
+	broadcast.SetDefault(b)
+	t.Cleanup(func() { broadcast.SetDefault(previous) })
+
+	stream := make(chan *models.Notification, 1)
+	b.Subscribe(7, stream)
+
+	dispatcher, repo, srv := newPushTest(t, 1)
+	repo.due = []models.PushDelivery{duePush(1, models.NotificationTypeCredit, noonUTC.Add(-time.Minute))}
+
+	result, err := dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Empty(t, srv.Messages())
+	assert.Equal(t, "user is connected", repo.updated[0].LastError)
+
+	// Once the stream closes the user's next notification is pushed
+	b.Unsubscribe(7, stream)
+	repo.due = []models.PushDelivery{duePush(2, models.NotificationTypeCredit, noonUTC.Add(-time.Minute))}
+	result, err = dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+}
+
+func TestPushDispatcherWaitsForLiveAcknowledgement(t *testing.T) {
+	dispatcher, repo, srv := newPushTest(t, 1)
+	createdAt := noonUTC.Add(-10 * time.Second)
+	repo.due = []models.PushDelivery{duePush(1, models.NotificationTypeCredit, createdAt)}
+
+	result, err := dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Waiting)
+	assert.Empty(t, srv.Messages())
+	assert.Equal(t, models.PushDeliveryPending, repo.updated[0].Status)
+	assert.Equal(t, createdAt.Add(30*time.Second), repo.updated[0].SendAfter)
+}
+
+func TestPushDispatcherSendsBatchConcurrently(t *testing.T) {
+	dispatcher, repo, srv := newPushTest(t, 1)
+	for id := uint(1); id <= 3*pushSendWorkers; id++ {
+		repo.due = append(repo.due, duePush(id, models.NotificationTypeCredit, noonUTC.Add(-time.Minute)))
+	}
+
+	result, err := dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 3*pushSendWorkers, result.Sent)
+	assert.Len(t, repo.updated, 3*pushSendWorkers)
+	assert.Len(t, srv.Messages(), 3*pushSendWorkers)
+}
+
+func TestPushDispatcherPrunesGoneSubscriptions(t *testing.T) {
+	dispatcher, repo, srv := newPushTest(t, 3)
+	srv.SetStatus(repo.subs[0].Endpoint, http.StatusGone)
+	srv.SetStatus(repo.subs[1].Endpoint, http.StatusNotFound)
+	repo.due = []models.PushDelivery{duePush(1, models.NotificationTypeCredit, noonUTC.Add(-time.Minute))}
+
+	result, err := dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Sent)
+	assert.Equal(t, 2, result.Pruned)
+	assert.Equal(t, []uint{1, 2}, repo.deleted)
+	require.Len(t, srv.Messages(), 1)
+	assert.Equal(t, repo.subs[0].Endpoint, srv.Messages()[0].Endpoint)
+
+	// With every device gone there is nothing left to push to
+	srv.SetStatus(repo.subs[0].Endpoint, http.StatusGone)
+	repo.due = []models.PushDelivery{duePush(2, models.NotificationTypeCredit, noonUTC.Add(-time.Minute))}
+	result, err = dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Empty(t, repo.subs)
+	assert.Equal(t, "no push subscriptions left", repo.updated[1].LastError)
+}
+
+func TestPushDispatcherRetriesServiceErrors(t *testing.T) {
+	dispatcher, repo, srv := newPushTest(t, 1)
+	srv.SetStatus(repo.subs[0].Endpoint, http.StatusServiceUnavailable)
+	repo.due = []models.PushDelivery{duePush(1, models.NotificationTypeCredit, noonUTC.Add(-time.Minute))}
+
+	result, err := dispatcher.dispatchDue(noonUTC)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	delivery := repo.updated[0]
+	assert.Equal(t, models.PushDeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, noonUTC.Add(time.Minute), delivery.SendAfter)
+	assert.Contains(t, delivery.LastError, "503")
+	assert.Empty(t, repo.deleted, "a failing push service does not drop the device")
+
+	// Out of attempts
+	delivery.Attempts = 2
+	repo.due = []models.PushDelivery{delivery}
+	_, err = dispatcher.dispatchDue(delivery.SendAfter)
+	require.NoError(t, err)
+	assert.Equal(t, models.PushDeliveryFailed, repo.updated[1].Status)
+}
+
+func TestBuildPushPayloadFitsLimit(t *testing.T) {
+	message := strings.Repeat("Siti membalas thread kalkulus kamu — ", 150)
+	notification := &models.Notification{
+		ID:        9,
+		Type:      models.NotificationTypeSocial,
+		Title:     "New reply",
+		Message:   message,
+		Data:      json.RawMessage(`{"threadID":4}`),
+		CreatedAt: noonUTC,
+	}
+
+	payload, err := buildPushPayload(notification)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(payload), webpush.MaxPayloadSize)
+
+	var decoded pushPayload
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.Nil(t, decoded.Data, "data is dropped first")
+	assert.Equal(t, "New reply", decoded.Title)
+	assert.True(t, strings.HasSuffix(decoded.Body, "…"))
+	assert.True(t, strings.HasPrefix(message, strings.TrimSuffix(decoded.Body, "…")))
+	assert.Greater(t, len(decoded.Body), 3000)
+
+	// Small notifications keep their data
+	notification.Message = "Siti replied to your thread"
+	payload, err = buildPushPayload(notification)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.JSONEq(t, `{"threadID":4}`, string(decoded.Data))
+	assert.Equal(t, "Siti replied to your thread", decoded.Body)
+}
@@ -4,9 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sync"
+	"log"
 	"time"
 
+	"github.com/timebankingskill/backend/internal/broadcast"
 	"github.com/timebankingskill/backend/internal/dto"
 	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/repository"
@@ -14,16 +15,11 @@ import (
 
 // NotificationService handles all notification business logic
 // Manages notification creation, retrieval, and broadcasting to users
-// Uses WebSocket connections to send real-time notifications
+// Real-time delivery goes through the process-wide broadcaster (broadcast.Default), so
+// every NotificationService instance, and with Redis every replica, reaches the same clients.
 type NotificationService struct {
 	notificationRepo *repository.NotificationRepository
 	userRepo         *repository.UserRepository
-
-	// WebSocket connection management
-	// clients maps userID to their WebSocket connections
-	// mutex protects concurrent access to clients map
-	clients map[uint][]chan *models.Notification
-	mutex   sync.RWMutex
 }
 
 // NewNotificationService creates a new notification service instance
@@ -34,7 +30,6 @@ func NewNotificationService(
 	return &NotificationService{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
-		clients:          make(map[uint][]chan *models.Notification),
 	}
 }
 
@@ -56,6 +51,7 @@ func NewNotificationService(
 //   - Saves notification to database
 //   - Broadcasts to user via WebSocket if online
 //   - Triggers toast notification on frontend
+//   - Queues an email if the user's preferences allow it (see NotificationEmailDispatcher)
 //
 // Example:
 //   notification, err := notificationService.CreateNotification(
@@ -114,9 +110,57 @@ func (s *NotificationService) CreateNotification(
 	// Broadcast to user if they're online (WebSocket connected)
 	s.BroadcastToUser(userID, notification)
 
+	// Queue for the email and push channels
+	s.queueDeliveries(notification)
+
 	return notification, nil
 }
 
+// queueDeliveries queues a notification for the email and push channels the user's
+// preferences allow
+// Failing to queue does not fail the notification, which was already delivered in-app.
+func (s *NotificationService) queueDeliveries(notification *models.Notification) {
+	pref, err := s.notificationRepo.GetPreferences(notification.UserID)
+	if err != nil {
+		log.Printf("⚠️  Failed to get preferences for notification %d deliveries: %v", notification.ID, err)
+		return
+	}
+
+	if emailNotificationAllowed(pref, notification.Type) {
+		delivery := &models.EmailDelivery{
+			NotificationID: notification.ID,
+			UserID:         notification.UserID,
+			Status:         models.EmailDeliveryPending,
+			SendAfter:      notification.CreatedAt,
+		}
+		if err := s.notificationRepo.CreateEmailDelivery(delivery); err != nil {
+			log.Printf("⚠️  Failed to queue email for notification %d: %v", notification.ID, err)
+		}
+	}
+
+	// Pushes are only queued for users with a subscribed device; the dispatcher then
+	// skips the ones a live connection acknowledged
+	if pushNotificationAllowed(pref, notification.Type) {
+		count, err := s.notificationRepo.CountPushSubscriptions(notification.UserID)
+		if err != nil {
+			log.Printf("⚠️  Failed to count push subscriptions for notification %d: %v", notification.ID, err)
+			return
+		}
+		if count == 0 {
+			return
+		}
+		delivery := &models.PushDelivery{
+			NotificationID: notification.ID,
+			UserID:         notification.UserID,
+			Status:         models.PushDeliveryPending,
+			SendAfter:      notification.CreatedAt,
+		}
+		if err := s.notificationRepo.CreatePushDelivery(delivery); err != nil {
+			log.Printf("⚠️  Failed to queue push for notification %d: %v", notification.ID, err)
+		}
+	}
+}
+
 // GetNotifications retrieves paginated notifications for a user
 // Parameters:
 //   - userID: User ID
@@ -225,56 +269,26 @@ func (s *NotificationService) DeleteNotification(notificationID uint, userID uin
 //   - userID: User ID
 //   - ch: Channel to send notifications to
 func (s *NotificationService) RegisterClient(userID uint, ch chan *models.Notification) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	s.clients[userID] = append(s.clients[userID], ch)
+	broadcast.Default().Subscribe(userID, ch)
 }
 
-// UnregisterClient unregisters a WebSocket client for a user
+// UnregisterClient unregisters a WebSocket client for a user and closes its channel
 // Called when user disconnects from WebSocket
 // Parameters:
 //   - userID: User ID
 //   - ch: Channel to remove
 func (s *NotificationService) UnregisterClient(userID uint, ch chan *models.Notification) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	clients := s.clients[userID]
-	for i, client := range clients {
-		if client == ch {
-			// Remove client from slice
-			s.clients[userID] = append(clients[:i], clients[i+1:]...)
-			close(ch)
-			break
-		}
-	}
-
-	// Clean up empty user entry
-	if len(s.clients[userID]) == 0 {
-		delete(s.clients, userID)
-	}
+	broadcast.Default().Unsubscribe(userID, ch)
 }
 
-// BroadcastToUser sends a notification to all WebSocket connections of a user
+// BroadcastToUser sends a notification to all WebSocket connections of a user,
+// on whichever instance they are connected to
 // If user is offline, notification is already saved in database
 // Parameters:
 //   - userID: User ID
 //   - notification: Notification to broadcast
 func (s *NotificationService) BroadcastToUser(userID uint, notification *models.Notification) {
-	s.mutex.RLock()
-	clients := s.clients[userID]
-	s.mutex.RUnlock()
-
-	// Send to all connected clients for this user
-	for _, ch := range clients {
-		select {
-		case ch <- notification:
-		case <-time.After(1 * time.Second):
-			// Timeout: client might be slow or disconnected
-			// Don't block, just skip
-		}
-	}
+	broadcast.Default().Publish(userID, notification)
 }
 
 // BroadcastToUsers sends a notification to multiple users
@@ -288,6 +302,48 @@ func (s *NotificationService) BroadcastToUsers(userIDs []uint, notification *mod
 	}
 }
 
+// NotificationReplayPageSize is how many missed notifications are loaded per replay query
+const NotificationReplayPageSize = 200
+
+// ReplayMissed retrieves the notifications a reconnecting client missed, oldest first
+// Parameters:
+//   - userID: User ID
+//   - sinceID: ID of the last notification the client received (its cursor)
+// Returns:
+//   - []Notification: Notifications after the cursor, in ID order (at most NotificationReplayPageSize;
+//     a full page means more may follow after its last ID)
+//   - error: If database error
+func (s *NotificationService) ReplayMissed(userID uint, sinceID uint) ([]models.Notification, error) {
+	return s.notificationRepo.GetSince(userID, sinceID, NotificationReplayPageSize)
+}
+
+// AcknowledgeDelivery records that a client received one of the user's notifications
+// Only the first acknowledgement of a notification counts, so delivered_at - created_at
+// is its delivery latency.
+// Parameters:
+//   - userID: User ID
+//   - notificationID: ID of the notification the client received
+func (s *NotificationService) AcknowledgeDelivery(userID uint, notificationID uint) error {
+	if notificationID == 0 {
+		return nil
+	}
+	_, err := s.notificationRepo.MarkDelivered(userID, notificationID, time.Now())
+	return err
+}
+
+// GetDeliveryStats returns the real-time delivery latency of recent notifications
+// Parameters:
+//   - hours: Size of the window, ending now
+// Returns:
+//   - *NotificationDeliveryStats: Delivered count and latency percentiles
+//   - error: If database error
+func (s *NotificationService) GetDeliveryStats(hours int) (*repository.NotificationDeliveryStats, error) {
+	if hours <= 0 {
+		hours = 24
+	}
+	return s.notificationRepo.GetDeliveryStats(time.Now().Add(-time.Duration(hours) * time.Hour))
+}
+
 // GetNotificationsByType retrieves notifications of a specific type for a user
 // Parameters:
 //   - userID: User ID
@@ -351,6 +407,12 @@ func (s *NotificationService) UpdatePreferences(userID uint, req dto.UpdateNotif
 	pref.QuietHours = req.QuietHours
 	pref.QuietHoursStart = req.QuietHoursStart
 	pref.QuietHoursEnd = req.QuietHoursEnd
+	if req.WeeklyDigest != nil {
+		pref.WeeklyDigest = *req.WeeklyDigest
+	}
+	if req.DigestWeekday != nil {
+		pref.DigestWeekday = *req.DigestWeekday
+	}
 
 	// Validate quiet hours format (HH:MM)
 	if pref.QuietHoursStart == "" {
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/timebankingskill/backend/internal/config"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
+	"github.com/timebankingskill/backend/internal/webpush"
+)
+
+// maxUserAgentLength is the length of the stored User-Agent of a subscription
+const maxUserAgentLength = 255
+
+// PushService manages the browser push subscriptions of users
+type PushService struct {
+	notificationRepo *repository.NotificationRepository
+	cfg              config.PushConfig
+}
+
+// NewPushService creates a new push subscription service
+func NewPushService(notificationRepo *repository.NotificationRepository, cfg config.PushConfig) *PushService {
+	return &PushService{
+		notificationRepo: notificationRepo,
+		cfg:              cfg,
+	}
+}
+
+// GetPublicKey returns the VAPID public key browsers subscribe with (applicationServerKey)
+func (s *PushService) GetPublicKey() (string, error) {
+	if !s.cfg.Enabled || s.cfg.VAPIDPublicKey == "" {
+		return "", utils.ErrPushNotConfigured
+	}
+	return s.cfg.VAPIDPublicKey, nil
+}
+
+// Subscribe registers a device's push subscription for a user
+// The server posts to the endpoint later, so only HTTPS endpoints on a known push service
+// (PushConfig.AllowedHosts) are accepted; anything else could point it at internal hosts.
+// Parameters:
+//   - userID: User ID
+//   - req: Subscription JSON from the browser
+//   - userAgent: User-Agent of the subscribing browser
+//
+// Returns:
+//   - *PushSubscription: Saved subscription
+//   - error: ErrPushNotConfigured, ErrInvalidPushSubscription or database error
+func (s *PushService) Subscribe(userID uint, req dto.PushSubscriptionRequest, userAgent string) (*models.PushSubscription, error) {
+	if _, err := s.GetPublicKey(); err != nil {
+		return nil, err
+	}
+
+	sub := webpush.Subscription{Endpoint: req.Endpoint, P256dh: req.Keys.P256dh, Auth: req.Keys.Auth}
+	if err := sub.Validate(); err != nil {
+		return nil, utils.ErrInvalidPushSubscription
+	}
+	if !pushEndpointAllowed(req.Endpoint, s.cfg.AllowedHosts) {
+		return nil, utils.ErrInvalidPushSubscription
+	}
+
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	subscription := &models.PushSubscription{
+		UserID:    userID,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+		UserAgent: userAgent,
+	}
+	if err := s.notificationRepo.SavePushSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// pushEndpointAllowed reports whether an endpoint is an HTTPS URL on the default port of
+// one of the allowed push service hosts
+// A host starting with a dot matches its subdomains (".push.apple.com" matches
+// "web.push.apple.com").
+func pushEndpointAllowed(endpoint string, allowedHosts []string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.User != nil || (u.Port() != "" && u.Port() != "443") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range allowedHosts {
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unsubscribe removes a device's push subscription
+// Parameters:
+//   - userID: User ID
+//   - endpoint: Endpoint of the subscription
+//
+// Returns:
+//   - error: ErrPushSubscriptionNotFound if the user has no subscription with that endpoint
+func (s *PushService) Unsubscribe(userID uint, endpoint string) error {
+	deleted, err := s.notificationRepo.DeleteUserPushSubscription(userID, endpoint)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return utils.ErrPushSubscriptionNotFound
+	}
+	return nil
+}
+
+// GetSubscriptions lists the devices a user receives push notifications on
+func (s *PushService) GetSubscriptions(userID uint) ([]models.PushSubscription, error) {
+	return s.notificationRepo.GetPushSubscriptions(userID)
+}
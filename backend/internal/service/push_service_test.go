@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushEndpointAllowed(t *testing.T) {
+	hosts := []string{"fcm.googleapis.com", ".push.apple.com"}
+
+	tests := []struct {
+		endpoint string
+		allowed  bool
+	}{
+		{"https://fcm.googleapis.com/fcm/send/abc", true},
+		{"https://web.push.apple.com/QGd", true},
+		{"https://FCM.googleapis.com:443/fcm/send/abc", true},
+		{"https://push.apple.com.attacker.test/x", false},
+		{"https://evilpush.apple.com/x", false},
+		{"http://fcm.googleapis.com/fcm/send/abc", false},
+		{"https://fcm.googleapis.com:8443/fcm/send/abc", false},
+		{"https://user@fcm.googleapis.com/fcm/send/abc", false},
+		{"https://127.0.0.1/push", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://localhost/push", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, pushEndpointAllowed(tt.endpoint, hosts), tt.endpoint)
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// ReportReview reports a review for moderation
+// Any user except its author may report a review, once while their report is pending.
+// The review stays visible until an admin hides it.
+func (s *ReviewService) ReportReview(reviewID, reporterID uint, req *dto.ReportReviewRequest) error {
+	if !models.IsValidReviewReportReason(req.Reason) {
+		return fmt.Errorf("%w: expected one of %v", utils.ErrInvalidReportReason, models.ReviewReportReasons)
+	}
+
+	review, err := s.getReview(reviewID)
+	if err != nil {
+		return err
+	}
+	if review.ReviewerID == reporterID {
+		return utils.ErrCannotReportOwnReview
+	}
+	if review.IsSealed {
+		// Only its reviewer can see it yet
+		return utils.ErrReviewNotFound
+	}
+
+	reported, err := s.reportRepo.HasPendingReport(models.ReportTypeReview, reviewID, reporterID)
+	if err != nil {
+		return err
+	}
+	if reported {
+		return utils.ErrAlreadyReported
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"details":     req.Details,
+		"reviewer_id": review.ReviewerID,
+		"reviewee_id": review.RevieweeID,
+	})
+	return s.reviewRepo.ReportReview(&models.Report{
+		Type:       models.ReportTypeReview,
+		TargetID:   reviewID,
+		ReportedBy: &reporterID,
+		Reason:     req.Reason,
+		Status:     models.ReportStatusPending,
+		Metadata:   string(metadata),
+	})
+}
+
+// GetModerationQueue returns reported reviews awaiting a decision, most reported first,
+// or with hidden the reviews currently hidden (admin only)
+func (s *ReviewService) GetModerationQueue(hidden bool, page, limit int) ([]dto.ReviewModerationItem, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	reviews, total, err := s.reviewRepo.GetModerationQueue(hidden, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch moderation queue: %w", err)
+	}
+
+	reviewIDs := make([]uint, len(reviews))
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+	}
+	reports, err := s.reportRepo.GetPendingByTargets(models.ReportTypeReview, reviewIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch review reports: %w", err)
+	}
+	reportsByReview := make(map[uint][]models.Report)
+	for _, report := range reports {
+		reportsByReview[report.TargetID] = append(reportsByReview[report.TargetID], report)
+	}
+
+	items := make([]dto.ReviewModerationItem, 0, len(reviews))
+	for i := range reviews {
+		item := dto.ReviewModerationItem{
+			Review:  *dto.MapReviewToResponse(&reviews[i]),
+			Reasons: make(map[string]int),
+			Reports: []dto.ReportDetail{},
+		}
+		for _, report := range reportsByReview[reviews[i].ID] {
+			var metadata struct {
+				Details string `json:"details"`
+			}
+			_ = json.Unmarshal([]byte(report.Metadata), &metadata)
+
+			item.ReportCount++
+			item.Reasons[report.Reason]++
+			item.Reports = append(item.Reports, dto.ReportDetail{
+				ID:         report.ID,
+				ReportedBy: report.ReportedBy,
+				Reason:     report.Reason,
+				Details:    metadata.Details,
+				CreatedAt:  report.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			})
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// HideReview hides a review from profiles and rating aggregates (admin only)
+// Pending reports are resolved, the reviewee's ratings are recalculated and the
+// reviewer is notified.
+func (s *ReviewService) HideReview(adminID, reviewID uint, reason string) (*models.ReviewModerationLog, error) {
+	review, err := s.getReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.IsHidden {
+		return nil, utils.ErrReviewAlreadyHidden
+	}
+
+	entry, err := s.moderate(adminID, review, models.ModerationHide, reason, true, models.ReportStatusResolved)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = s.notificationService.CreateNotification(
+		review.ReviewerID,
+		models.NotificationTypeReview,
+		"Review Hidden",
+		fmt.Sprintf("Your review of %s was hidden by a moderator: %s", review.Reviewee.FullName, reason),
+		map[string]interface{}{"reviewID": review.ID},
+	)
+
+	// Badges earned with this review are re-checked
+	events.Publish(events.ReviewDeleted, review.ReviewerID, review.RevieweeID)
+	return entry, nil
+}
+
+// UnhideReview makes a hidden review visible again (admin only)
+// Reports raised while it was hidden are dismissed.
+func (s *ReviewService) UnhideReview(adminID, reviewID uint, reason string) (*models.ReviewModerationLog, error) {
+	review, err := s.getReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsHidden {
+		return nil, utils.ErrReviewNotHidden
+	}
+
+	entry, err := s.moderate(adminID, review, models.ModerationUnhide, reason, false, models.ReportStatusDismissed)
+	if err != nil {
+		return nil, err
+	}
+
+	events.Publish(events.ReviewCreated, review.ReviewerID, review.RevieweeID)
+	return entry, nil
+}
+
+// DismissReviewReports dismisses the pending reports on a review, leaving it visible (admin only)
+func (s *ReviewService) DismissReviewReports(adminID, reviewID uint, reason string) (*models.ReviewModerationLog, error) {
+	review, err := s.getReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsReported {
+		return nil, utils.ErrNoPendingReports
+	}
+
+	return s.moderate(adminID, review, models.ModerationDismiss, reason, review.IsHidden, models.ReportStatusDismissed)
+}
+
+// GetModerationLog returns the audit trail of a review (admin only)
+func (s *ReviewService) GetModerationLog(reviewID uint) ([]models.ReviewModerationLog, error) {
+	if _, err := s.getReview(reviewID); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.GetModerationLog(reviewID)
+}
+
+// moderate applies a moderation action and writes its audit entry
+func (s *ReviewService) moderate(adminID uint, review *models.Review, action models.ReviewModerationAction, reason string, hidden bool, reportStatus models.ReportStatus) (*models.ReviewModerationLog, error) {
+	entry := &models.ReviewModerationLog{
+		ReviewID: review.ID,
+		AdminID:  adminID,
+		Action:   action,
+		Reason:   reason,
+	}
+	if err := s.reviewRepo.ApplyModeration(review, entry, hidden, reportStatus); err != nil {
+		return nil, fmt.Errorf("failed to %s review: %w", action, err)
+	}
+	return entry, nil
+}
+
+// getReview gets a review, mapping a missing record to utils.ErrReviewNotFound
+func (s *ReviewService) getReview(reviewID uint) (*models.Review, error) {
+	review, err := s.reviewRepo.GetByID(reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return review, nil
+}
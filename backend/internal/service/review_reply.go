@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// ReplyToReview adds the reviewee's public reply to a review and notifies the reviewer
+// Each review takes a single reply; use UpdateReply to change it.
+func (s *ReviewService) ReplyToReview(reviewID, userID uint, reply string) (*dto.ReviewResponse, error) {
+	review, err := s.getRepliableReview(reviewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Reply != "" {
+		return nil, utils.ErrReplyExists
+	}
+
+	now := time.Now()
+	if err := s.reviewRepo.AddReply(reviewID, reply, now); err != nil {
+		if errors.Is(err, utils.ErrReplyExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save reply: %w", err)
+	}
+	review.Reply = reply
+	review.RepliedAt = &now
+
+	_, _ = s.notificationService.CreateNotification(
+		review.ReviewerID,
+		models.NotificationTypeReview,
+		"Reply to Your Review 💬",
+		fmt.Sprintf("%s replied to your review", review.Reviewee.FullName),
+		map[string]interface{}{"reviewID": review.ID},
+	)
+
+	return dto.MapReviewToResponse(review), nil
+}
+
+// UpdateReply changes the reviewee's reply to a review
+func (s *ReviewService) UpdateReply(reviewID, userID uint, reply string) (*dto.ReviewResponse, error) {
+	review, err := s.getRepliableReview(reviewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Reply == "" {
+		return nil, utils.ErrNoReply
+	}
+
+	now := time.Now()
+	if err := s.reviewRepo.SetReply(reviewID, reply, &now); err != nil {
+		return nil, fmt.Errorf("failed to save reply: %w", err)
+	}
+	review.Reply = reply
+	review.RepliedAt = &now
+
+	return dto.MapReviewToResponse(review), nil
+}
+
+// DeleteReply removes the reviewee's reply to a review
+func (s *ReviewService) DeleteReply(reviewID, userID uint) error {
+	review, err := s.getRepliableReview(reviewID, userID)
+	if err != nil {
+		return err
+	}
+	if review.Reply == "" {
+		return utils.ErrNoReply
+	}
+
+	return s.reviewRepo.SetReply(reviewID, "", nil)
+}
+
+// getRepliableReview gets a review the user may reply to, i.e. one about them
+func (s *ReviewService) getRepliableReview(reviewID, userID uint) (*models.Review, error) {
+	review, err := s.getReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.IsSealed {
+		// The reviewee cannot see it yet
+		return nil, utils.ErrReviewNotFound
+	}
+	if review.RevieweeID != userID {
+		return nil, utils.ErrNotReviewee
+	}
+	return review, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/events"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+// RevealExpiredReviews reveals the sealed reviews of sessions whose review window has
+// closed, notifies their reviewees and re-checks badges
+//
+// Returns:
+//   - int: Number of reviews revealed
+//   - error: If the reviews could not be revealed
+func (s *ReviewService) RevealExpiredReviews() (int, error) {
+	revealed, err := s.reviewRepo.RevealExpired(time.Now().Add(-s.revealWindow()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to reveal reviews: %w", err)
+	}
+	s.notifyRevealed(revealed)
+	return len(revealed), nil
+}
+
+// StartScheduler starts a background goroutine that periodically reveals sealed
+// reviews whose review window has closed
+//
+// Returns:
+//   - chan struct{}: Close this channel to stop the scheduler
+func (s *ReviewService) StartScheduler() chan struct{} {
+	stop := make(chan struct{})
+	interval := s.cfg.RevealInterval
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if count, err := s.RevealExpiredReviews(); err != nil {
+					log.Printf("⚠️  Review reveal run error: %v", err)
+				} else if count > 0 {
+					log.Printf("🔓 Revealed %d reviews whose review window closed", count)
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return stop
+}
+
+// revealWindow returns how long after completion a session can be reviewed
+func (s *ReviewService) revealWindow() time.Duration {
+	if s.cfg.RevealWindow <= 0 {
+		return 14 * 24 * time.Hour
+	}
+	return s.cfg.RevealWindow
+}
+
+// revealDeadline returns when the review window of a session closes
+// Sessions completed before completion times were recorded count from their last update.
+func (s *ReviewService) revealDeadline(session *models.Session) time.Time {
+	completedAt := session.UpdatedAt
+	if session.CompletedAt != nil {
+		completedAt = *session.CompletedAt
+	}
+	return completedAt.Add(s.revealWindow())
+}
+
+// notifyRevealed tells reviewees about their newly revealed reviews and re-checks
+// the badges of both parties, as the reviews now count towards ratings
+func (s *ReviewService) notifyRevealed(reviews []models.Review) {
+	for _, review := range reviews {
+		reviewer, err := s.userRepo.GetByID(review.ReviewerID)
+		if err != nil {
+			continue
+		}
+		_, _ = s.notificationService.CreateNotification(
+			review.RevieweeID,
+			models.NotificationTypeReview,
+			"New Review Received! ⭐",
+			fmt.Sprintf("%s gave you a %d-star review", reviewer.FullName, review.Rating),
+			map[string]interface{}{
+				"reviewID":     review.ID,
+				"rating":       review.Rating,
+				"reviewerName": reviewer.FullName,
+			},
+		)
+
+		events.Publish(events.ReviewCreated, review.ReviewerID, review.RevieweeID)
+	}
+}
@@ -3,10 +3,14 @@ package service
 import (
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/timebankingskill/backend/internal/config"
 	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
 	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
 )
 
 // ReviewService handles review business logic
@@ -14,8 +18,10 @@ type ReviewService struct {
 	reviewRepo          *repository.ReviewRepository
 	sessionRepo         *repository.SessionRepository
 	userRepo            *repository.UserRepository
+	reportRepo          *repository.ReportRepository
+	tagRepo             *repository.ReviewTagRepository
 	notificationService *NotificationService
-	badgeService        *BadgeService
+	cfg                 config.ReviewConfig
 }
 
 // NewReviewService creates a new review service
@@ -23,15 +29,19 @@ func NewReviewService(
 	reviewRepo *repository.ReviewRepository,
 	sessionRepo *repository.SessionRepository,
 	userRepo *repository.UserRepository,
+	reportRepo *repository.ReportRepository,
+	tagRepo *repository.ReviewTagRepository,
 	notificationService *NotificationService,
-	badgeService *BadgeService,
+	cfg config.ReviewConfig,
 ) *ReviewService {
 	return &ReviewService{
 		reviewRepo:          reviewRepo,
 		sessionRepo:         sessionRepo,
 		userRepo:            userRepo,
+		reportRepo:          reportRepo,
+		tagRepo:             tagRepo,
 		notificationService: notificationService,
-		badgeService:        badgeService,
+		cfg:                 cfg,
 	}
 }
 
@@ -56,7 +66,7 @@ func NewReviewService(
 //   - Communication rating (optional)
 //   - Punctuality rating (optional)
 //   - Knowledge rating (optional)
-//   - Tags (e.g., "Patient", "Clear Explanation", "Well Prepared")
+//   - Tags from the ReviewTag vocabulary of the review type (e.g., "patient", "explains-clearly")
 //
 // Parameters:
 //   - reviewerID: ID of user creating the review
@@ -71,7 +81,7 @@ func NewReviewService(
 //     SessionID: 123,
 //     Rating: 5,
 //     Comment: "Great teacher!",
-//     Tags: []string{"Patient", "Knowledgeable"},
+//     Tags: "patient,knowledgeable",
 //   })
 func (s *ReviewService) CreateReview(reviewerID uint, req *dto.CreateReviewRequest) (*dto.ReviewResponse, error) {
 	// VALIDATION: Ensure rating is within valid range (1-5 stars)
@@ -91,6 +101,11 @@ func (s *ReviewService) CreateReview(reviewerID uint, req *dto.CreateReviewReque
 		return nil, errors.New("can only review completed sessions")
 	}
 
+	// WINDOW CHECK: Reviews can only be written until the review window closes
+	if time.Now().After(s.revealDeadline(session)) {
+		return nil, utils.ErrReviewWindowClosed
+	}
+
 	// ROLE DETERMINATION: Identify reviewer role and who is being reviewed
 	// Teacher reviewing student vs Student reviewing teacher
 	var reviewType models.ReviewType
@@ -115,6 +130,12 @@ func (s *ReviewService) CreateReview(reviewerID uint, req *dto.CreateReviewReque
 		return nil, errors.New("you have already reviewed this session")
 	}
 
+	// TAG CHECK: Tags must come from the vocabulary of the review type
+	tags, err := s.normalizeReviewTags(req.Tags, reviewType)
+	if err != nil {
+		return nil, err
+	}
+
 	// CREATE REVIEW: Build review object with all provided data
 	review := &models.Review{
 		SessionID:           req.SessionID,
@@ -123,14 +144,16 @@ func (s *ReviewService) CreateReview(reviewerID uint, req *dto.CreateReviewReque
 		Type:                reviewType,
 		Rating:              req.Rating,
 		Comment:             req.Comment,
-		Tags:                req.Tags,
+		Tags:                tags,
 		CommunicationRating: req.CommunicationRating,
 		PunctualityRating:   req.PunctualityRating,
 		KnowledgeRating:     req.KnowledgeRating,
 	}
 
-	// PERSIST: Save review to database
-	if err := s.reviewRepo.Create(review); err != nil {
+	// PERSIST: Save review sealed; it is revealed with the counterpart review if
+	// that was already submitted (double-blind)
+	revealed, err := s.reviewRepo.CreateSealed(review)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create review: %w", err)
 	}
 
@@ -140,35 +163,39 @@ func (s *ReviewService) CreateReview(reviewerID uint, req *dto.CreateReviewReque
 		return nil, err
 	}
 
-	// Send review notification to reviewee
-	reviewer, _ := s.userRepo.GetByID(reviewerID)
-	notificationData := map[string]interface{}{
-		"reviewID":   review.ID,
-		"rating":     req.Rating,
-		"reviewerName": reviewer.FullName,
+	// NOTIFY: Reveal both reviews, or nudge the reviewee to write theirs without
+	// telling them anything about this one
+	if len(revealed) > 0 {
+		s.notifyRevealed(revealed)
+	} else {
+		_, _ = s.notificationService.CreateNotification(
+			revieweeID,
+			models.NotificationTypeReview,
+			"You Have a Review Waiting ✍️",
+			fmt.Sprintf("%s reviewed your session \"%s\". Leave your review to see theirs.", review.Reviewer.FullName, session.Title),
+			map[string]interface{}{"sessionID": session.ID},
+		)
 	}
-	_, _ = s.notificationService.CreateNotification(
-		revieweeID,
-		models.NotificationTypeReview,
-		"New Review Received! ⭐",
-		fmt.Sprintf("%s gave you a %d-star review", reviewer.FullName, req.Rating),
-		notificationData,
-	)
 
 	return dto.MapReviewToResponse(review), nil
 }
 
 // GetReview gets a specific review by ID
-func (s *ReviewService) GetReview(id uint) (*dto.ReviewResponse, error) {
+// A sealed review is only found by its reviewer.
+func (s *ReviewService) GetReview(id, viewerID uint) (*dto.ReviewResponse, error) {
 	review, err := s.reviewRepo.GetByID(id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get review: %w", err)
 	}
+	if review.IsSealed && review.ReviewerID != viewerID {
+		return nil, utils.ErrReviewNotFound
+	}
 	return dto.MapReviewToResponse(review), nil
 }
 
 // GetReviewsForUser gets all reviews for a user
-func (s *ReviewService) GetReviewsForUser(userID uint, limit, offset int) ([]dto.ReviewResponse, int64, error) {
+// sort is recent (default), helpful, highest or lowest.
+func (s *ReviewService) GetReviewsForUser(userID uint, sort string, limit, offset int) ([]dto.ReviewResponse, int64, error) {
 	// Validate pagination
 	if limit <= 0 || limit > 100 {
 		limit = 10
@@ -176,8 +203,12 @@ func (s *ReviewService) GetReviewsForUser(userID uint, limit, offset int) ([]dto
 	if offset < 0 {
 		offset = 0
 	}
+	reviewSort, err := parseReviewSort(sort)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	reviews, total, err := s.reviewRepo.GetReviewsForUser(userID, limit, offset)
+	reviews, total, err := s.reviewRepo.GetReviewsForUser(userID, reviewSort, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
 	}
@@ -186,7 +217,7 @@ func (s *ReviewService) GetReviewsForUser(userID uint, limit, offset int) ([]dto
 }
 
 // GetReviewsForUserByType gets reviews for a user filtered by type
-func (s *ReviewService) GetReviewsForUserByType(userID uint, reviewType string, limit, offset int) ([]dto.ReviewResponse, int64, error) {
+func (s *ReviewService) GetReviewsForUserByType(userID uint, reviewType, sort string, limit, offset int) ([]dto.ReviewResponse, int64, error) {
 	// Validate pagination
 	if limit <= 0 || limit > 100 {
 		limit = 10
@@ -194,6 +225,10 @@ func (s *ReviewService) GetReviewsForUserByType(userID uint, reviewType string,
 	if offset < 0 {
 		offset = 0
 	}
+	reviewSort, err := parseReviewSort(sort)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// Validate review type
 	var rType models.ReviewType
@@ -205,7 +240,7 @@ func (s *ReviewService) GetReviewsForUserByType(userID uint, reviewType string,
 		return nil, 0, errors.New("invalid review type")
 	}
 
-	reviews, total, err := s.reviewRepo.GetReviewsForUserByType(userID, rType, limit, offset)
+	reviews, total, err := s.reviewRepo.GetReviewsForUserByType(userID, rType, reviewSort, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
 	}
@@ -230,14 +265,14 @@ func (s *ReviewService) GetUserRatingSummary(userID uint) (map[string]interface{
 
 	// FETCH TEACHER REVIEWS: Get all reviews where user was teaching
 	// These are reviews from students about the user's teaching ability
-	teacherReviews, _, err := s.reviewRepo.GetReviewsForUserByType(userID, models.ReviewTypeTeacher, 1000, 0)
+	teacherReviews, _, err := s.reviewRepo.GetReviewsForUserByType(userID, models.ReviewTypeTeacher, models.ReviewSortRecent, 1000, 0)
 	if err != nil {
 		return nil, err
 	}
 
 	// FETCH STUDENT REVIEWS: Get all reviews where user was learning
 	// These are reviews from teachers about the user's learning behavior
-	studentReviews, _, err := s.reviewRepo.GetReviewsForUserByType(userID, models.ReviewTypeStudent, 1000, 0)
+	studentReviews, _, err := s.reviewRepo.GetReviewsForUserByType(userID, models.ReviewTypeStudent, models.ReviewSortRecent, 1000, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -247,6 +282,21 @@ func (s *ReviewService) GetUserRatingSummary(userID uint) (map[string]interface{
 	avgTeacherRating := calculateAverageRating(teacherReviews)
 	avgStudentRating := calculateAverageRating(studentReviews)
 
+	// SCORE RATINGS: Raw and adjusted (Bayesian, recency-weighted) ratings with the
+	// averages of the detailed ratings, overall and per role
+	scores := make(map[string]*models.RatingScore)
+	for key, reviewType := range map[string]models.ReviewType{
+		"rating":         "",
+		"teacher_rating": models.ReviewTypeTeacher,
+		"student_rating": models.ReviewTypeStudent,
+	} {
+		score, err := s.reviewRepo.GetRatingScore(userID, reviewType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to score ratings: %w", err)
+		}
+		scores[key] = score
+	}
+
 	// BUILD RESPONSE: Return comprehensive rating breakdown
 	return map[string]interface{}{
 		"average_rating":          avgRating,          // Overall average rating
@@ -255,6 +305,9 @@ func (s *ReviewService) GetUserRatingSummary(userID uint) (map[string]interface{
 		"teacher_review_count":    len(teacherReviews), // Number of teaching reviews
 		"average_student_rating":  avgStudentRating,   // Average rating as student
 		"student_review_count":    len(studentReviews), // Number of student reviews
+		"rating":                  scores["rating"],         // Overall raw/adjusted score and detailed averages
+		"teacher_rating":          scores["teacher_rating"], // Same, as teacher
+		"student_rating":          scores["student_rating"], // Same, as student
 	}, nil
 }
 
@@ -271,6 +324,12 @@ func (s *ReviewService) UpdateReview(reviewID, userID uint, req *dto.UpdateRevie
 		return nil, errors.New("you can only edit your own reviews")
 	}
 
+	// Revealed reviews are final, and so are sealed ones once the review window has
+	// closed (they are revealed on the next run)
+	if !review.IsSealed || time.Now().After(s.revealDeadline(&review.Session)) {
+		return nil, utils.ErrReviewLocked
+	}
+
 	// Update fields
 	if req.Rating > 0 {
 		if req.Rating < 1 || req.Rating > 5 {
@@ -284,7 +343,11 @@ func (s *ReviewService) UpdateReview(reviewID, userID uint, req *dto.UpdateRevie
 	}
 
 	if req.Tags != "" {
-		review.Tags = req.Tags
+		tags, err := s.normalizeReviewTags(req.Tags, review.Type)
+		if err != nil {
+			return nil, err
+		}
+		review.Tags = tags
 	}
 
 	if req.CommunicationRating != nil {
@@ -305,17 +368,12 @@ func (s *ReviewService) UpdateReview(reviewID, userID uint, req *dto.UpdateRevie
 	}
 
 	// Reload
+	// A sealed review counts towards no rating yet, so badges are checked on reveal.
 	review, err = s.reviewRepo.GetByID(reviewID)
 	if err != nil {
 		return nil, err
 	}
 
-	// TRIGGER BADGE CHECK:
-	go func() {
-		_, _ = s.badgeService.CheckAndAwardBadges(review.ReviewerID)
-		_, _ = s.badgeService.CheckAndAwardBadges(review.RevieweeID)
-	}()
-
 	return dto.MapReviewToResponse(review), nil
 }
 
@@ -333,7 +391,27 @@ func (s *ReviewService) DeleteReview(reviewID, userID uint) error {
 	}
 
 	// Delete
-	return s.reviewRepo.Delete(reviewID)
+	if err := s.reviewRepo.Delete(reviewID); err != nil {
+		return err
+	}
+
+	// Badges earned with a revealed review are re-checked
+	// (a sealed review counts towards no rating or badge yet)
+	if !review.IsSealed {
+		events.Publish(events.ReviewDeleted, review.ReviewerID, review.RevieweeID)
+	}
+	return nil
+}
+
+// parseReviewSort validates a review sort option, defaulting to most recent
+func parseReviewSort(sort string) (models.ReviewSort, error) {
+	switch models.ReviewSort(sort) {
+	case "":
+		return models.ReviewSortRecent, nil
+	case models.ReviewSortRecent, models.ReviewSortHelpful, models.ReviewSortHighest, models.ReviewSortLowest:
+		return models.ReviewSort(sort), nil
+	}
+	return "", utils.ErrInvalidReviewSort
 }
 
 // Helper function to calculate average rating from reviews
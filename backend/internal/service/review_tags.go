@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// reviewTagSlugPattern matches valid review tag slugs, e.g. "explains-clearly"
+var reviewTagSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// GetReviewTags gets the active review tags reviewers can choose from
+// reviewType is teacher, student or empty for both.
+func (s *ReviewService) GetReviewTags(reviewType string) ([]models.ReviewTag, error) {
+	return s.tagRepo.GetAll(models.ReviewType(reviewType), false)
+}
+
+// GetAllReviewTags gets all review tags, inactive ones included (admin only)
+func (s *ReviewService) GetAllReviewTags(reviewType string) ([]models.ReviewTag, error) {
+	return s.tagRepo.GetAll(models.ReviewType(reviewType), true)
+}
+
+// CreateReviewTag adds a tag to the review vocabulary (admin only)
+//
+// Returns:
+//   - *ReviewTag: The created tag
+//   - error: utils.ErrInvalidReviewTag, utils.ErrReviewTagTaken, or a database error
+func (s *ReviewService) CreateReviewTag(req *dto.CreateReviewTagRequest) (*models.ReviewTag, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !reviewTagSlugPattern.MatchString(slug) {
+		return nil, fmt.Errorf("%w: slug must be lower-case words joined by hyphens", utils.ErrInvalidReviewTag)
+	}
+	if _, err := s.tagRepo.GetBySlug(slug); err == nil {
+		return nil, utils.ErrReviewTagTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	tag := &models.ReviewTag{
+		Slug:      slug,
+		Label:     strings.TrimSpace(req.Label),
+		Type:      models.ReviewType(req.Type),
+		IsActive:  true,
+		SortOrder: req.SortOrder,
+	}
+	if req.IsActive != nil {
+		tag.IsActive = *req.IsActive
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, fmt.Errorf("failed to create review tag: %w", err)
+	}
+	// GORM skips zero values that have a column default, so store an explicit inactive flag
+	if !tag.IsActive {
+		if err := s.tagRepo.Update(tag); err != nil {
+			return nil, fmt.Errorf("failed to create review tag: %w", err)
+		}
+	}
+	return tag, nil
+}
+
+// UpdateReviewTag updates a review tag (admin only); omitted fields are left unchanged
+// The slug and type cannot change, as reviews store the slug.
+func (s *ReviewService) UpdateReviewTag(tagID uint, req *dto.UpdateReviewTagRequest) (*models.ReviewTag, error) {
+	tag, err := s.getReviewTag(tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != nil {
+		tag.Label = strings.TrimSpace(*req.Label)
+	}
+	if req.IsActive != nil {
+		tag.IsActive = *req.IsActive
+	}
+	if req.SortOrder != nil {
+		tag.SortOrder = *req.SortOrder
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, fmt.Errorf("failed to update review tag: %w", err)
+	}
+	return tag, nil
+}
+
+// DeleteReviewTag removes a tag from the vocabulary (admin only)
+// Reviews keep the tag, but it can no longer be given and leaves tag clouds.
+func (s *ReviewService) DeleteReviewTag(tagID uint) error {
+	if _, err := s.getReviewTag(tagID); err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(tagID)
+}
+
+// getReviewTag gets a tag, mapping a missing one to utils.ErrReviewTagNotFound
+func (s *ReviewService) getReviewTag(tagID uint) (*models.ReviewTag, error) {
+	tag, err := s.tagRepo.GetByID(tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrReviewTagNotFound
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+// normalizeReviewTags validates comma-separated review tags against the active tags of
+// the review type and returns them as comma-separated slugs
+// Tags may be given by slug or label, in any case.
+//
+// Returns:
+//   - string: The tags as slugs, in the order given
+//   - error: utils.ErrInvalidReviewTag naming unknown tags, utils.ErrTooManyReviewTags, or a database error
+func (s *ReviewService) normalizeReviewTags(tags string, reviewType models.ReviewType) (string, error) {
+	given := models.ParseReviewTags(tags)
+	if len(given) == 0 {
+		return "", nil
+	}
+	if len(given) > models.MaxReviewTags {
+		return "", fmt.Errorf("%w: at most %d allowed", utils.ErrTooManyReviewTags, models.MaxReviewTags)
+	}
+
+	active, err := s.tagRepo.GetAll(reviewType, false)
+	if err != nil {
+		return "", err
+	}
+	bySlugOrLabel := make(map[string]string, 2*len(active))
+	for _, tag := range active {
+		bySlugOrLabel[tag.Slug] = tag.Slug
+		bySlugOrLabel[strings.ToLower(tag.Label)] = tag.Slug
+	}
+
+	slugs := make([]string, 0, len(given))
+	seen := make(map[string]bool)
+	var unknown []string
+	for _, tag := range given {
+		slug, ok := bySlugOrLabel[tag]
+		if !ok {
+			unknown = append(unknown, tag)
+			continue
+		}
+		if !seen[slug] {
+			seen[slug] = true
+			slugs = append(slugs, slug)
+		}
+	}
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("%w for a %s review: %s", utils.ErrInvalidReviewTag, reviewType, strings.Join(unknown, ", "))
+	}
+	return strings.Join(slugs, ","), nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/events"
 	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/repository"
 	"github.com/timebankingskill/backend/internal/utils"
@@ -22,14 +23,20 @@ type BadgeServiceInterface interface {
 	CheckAndAwardBadges(userID uint) ([]dto.UserBadgeResponse, error)
 }
 
+type CreditPolicyInterface interface {
+	EnforceBalanceCap(userID uint) (*models.Transaction, error)
+}
+
 type SessionService struct {
 	db                  *gorm.DB
 	sessionRepo         repository.SessionRepositoryInterface
 	userRepo            repository.UserRepositoryInterface
 	transactionRepo     repository.TransactionRepositoryInterface
 	skillRepo           repository.SkillRepositoryInterface
+	statsRepo           repository.StatsRepositoryInterface
 	badgeService        BadgeServiceInterface
 	notificationService NotificationServiceInterface
+	creditPolicy        CreditPolicyInterface
 }
 
 func NewSessionService(
@@ -38,6 +45,7 @@ func NewSessionService(
 	userRepo repository.UserRepositoryInterface,
 	transactionRepo repository.TransactionRepositoryInterface,
 	skillRepo repository.SkillRepositoryInterface,
+	statsRepo repository.StatsRepositoryInterface,
 	badgeService BadgeServiceInterface,
 	notificationService NotificationServiceInterface,
 ) *SessionService {
@@ -47,10 +55,38 @@ func NewSessionService(
 		userRepo:            userRepo,
 		transactionRepo:     transactionRepo,
 		skillRepo:           skillRepo,
+		statsRepo:           statsRepo,
 		badgeService:        badgeService,
 		notificationService: notificationService,
 	}
 }
+
+// NewSessionServiceWithCreditPolicy creates a session service that enforces the
+// credit balance cap whenever a teacher earns credits
+func NewSessionServiceWithCreditPolicy(
+	db *gorm.DB,
+	sessionRepo repository.SessionRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	transactionRepo repository.TransactionRepositoryInterface,
+	skillRepo repository.SkillRepositoryInterface,
+	statsRepo repository.StatsRepositoryInterface,
+	badgeService BadgeServiceInterface,
+	notificationService NotificationServiceInterface,
+	creditPolicy CreditPolicyInterface,
+) *SessionService {
+	return &SessionService{
+		db:                  db,
+		sessionRepo:         sessionRepo,
+		userRepo:            userRepo,
+		transactionRepo:     transactionRepo,
+		skillRepo:           skillRepo,
+		statsRepo:           statsRepo,
+		badgeService:        badgeService,
+		notificationService: notificationService,
+		creditPolicy:        creditPolicy,
+	}
+}
+
 // This is the entry point for students to request learning sessions with tutors
 //
 // Flow:
@@ -145,7 +181,7 @@ func (s *SessionService) BookSession(studentID uint, req *dto.CreateSessionReque
 
 		// Step 2: Check if student has enough credits (with locked row data)
 		log.Printf("[BookSession] Tx Step 2: Checking credits (need: %.2f)", creditAmount)
-		availableBalance := student.CreditBalance - student.CreditHeld
+		availableBalance := student.AvailableCredits()
 		if availableBalance < creditAmount {
 			log.Printf("[BookSession] Tx ERROR: Insufficient credits - Available: %.2f, Need: %.2f", availableBalance, creditAmount)
 			return utils.ErrInsufficientCredits
@@ -689,6 +725,11 @@ func (s *SessionService) completeSession(session *models.Session) error {
 	student.CreditBalance -= session.CreditAmount
 	teacher.CreditBalance += session.CreditAmount
 
+	// Payouts frozen by fraud detection: credit the teacher but hold the earnings until an admin reviews
+	if teacher.PayoutsFrozen {
+		teacher.FrozenCredits += session.CreditAmount
+	}
+
 	if err := s.userRepo.Update(student); err != nil {
 		return utils.ErrInternal
 	}
@@ -706,10 +747,20 @@ func (s *SessionService) completeSession(session *models.Session) error {
 		Description:   "Earned from teaching session: " + session.Title,
 		SessionID:     &session.ID,
 	}
+	if teacher.PayoutsFrozen {
+		earnedTransaction.Description += " (held pending review)"
+	}
 	if err := s.transactionRepo.Create(earnedTransaction); err != nil {
 		log.Printf("ERROR: Failed to create earned transaction for teacher %d: %v", session.TeacherID, err)
 	}
 
+	// Redirect earnings above the balance cap to the community pool
+	if s.creditPolicy != nil {
+		if _, err := s.creditPolicy.EnforceBalanceCap(session.TeacherID); err != nil {
+			log.Printf("ERROR: Failed to enforce balance cap for teacher %d: %v", session.TeacherID, err)
+		}
+	}
+
 	// Record the spent transaction for student (finalized)
 	spentTransaction := &models.Transaction{
 		UserID:        session.StudentID,
@@ -724,16 +775,25 @@ func (s *SessionService) completeSession(session *models.Session) error {
 		log.Printf("ERROR: Failed to create spent transaction for student %d: %v", session.StudentID, err)
 	}
 
-	// Update skill statistics
-	// Increment session count for this teaching skill
-	userSkill, _ := s.skillRepo.GetUserSkillByID(session.UserSkillID)
-	if userSkill != nil {
-		userSkill.TotalSessions++
-		_ = s.skillRepo.UpdateUserSkill(userSkill)
+	// Persist all session changes together with the session counts and credit totals
+	// of both parties and the skill
+	if err := s.saveCompletedSession(session); err != nil {
+		return err
 	}
 
-	// Persist all session changes to database
-	return s.sessionRepo.Update(session)
+	events.PublishWithData(events.SessionCompleted, map[string]interface{}{"session_id": session.ID},
+		session.TeacherID, session.StudentID)
+	return nil
+}
+
+// saveCompletedSession saves a completed session and the stats it touches in one
+// transaction, so the session is never completed with stale counters
+// Without a stats repository only the session is saved.
+func (s *SessionService) saveCompletedSession(session *models.Session) error {
+	if s.statsRepo == nil {
+		return s.sessionRepo.Update(session)
+	}
+	return s.statsRepo.SaveCompletedSession(session)
 }
 
 // CancelSession allows either party to cancel a session
@@ -2,8 +2,6 @@ package service
 
 import (
 	"errors"
-	"log"
-	"time"
 
 	"github.com/timebankingskill/backend/internal/models"
 )
@@ -61,6 +59,8 @@ func (s *SessionService) AdminRejectSession(sessionID uint) error {
 }
 
 // AdminCompleteSession completes a session by admin override (releases funds)
+// The credits in escrow are released through the same path as a confirmed completion,
+// so payout freezes, the balance cap and the SessionCompleted event all apply.
 func (s *SessionService) AdminCompleteSession(sessionID uint) error {
 	session, err := s.sessionRepo.GetByID(sessionID)
 	if err != nil {
@@ -71,52 +71,18 @@ func (s *SessionService) AdminCompleteSession(sessionID uint) error {
 		return errors.New("session is already completed")
 	}
 
-	// 1. Update status
-	session.Status = models.StatusCompleted
-	now := time.Now()
-	session.CompletedAt = &now
-	session.CreditReleased = true
-	
-	if err := s.sessionRepo.Update(session); err != nil {
-		return err
+	// Only credits still in escrow can be released (rejected and cancelled sessions were refunded)
+	active := session.Status == models.StatusPending ||
+		session.Status == models.StatusApproved ||
+		session.Status == models.StatusInProgress
+	if !active || !session.CreditHeld || session.CreditReleased {
+		return errors.New("session credits are no longer held")
 	}
 
-	// 2. Transfer credits using session.CreditAmount
-	amount := session.CreditAmount
-
-	// Get teacher balance
-	teacherBalance, _ := s.transactionRepo.GetUserBalance(session.TeacherID)
-	// Get student balance
-	studentBalance, _ := s.transactionRepo.GetUserBalance(session.StudentID)
-
-	// Create earned transaction for teacher
-	earnedTransaction := &models.Transaction{
-		UserID:        session.TeacherID,
-		Type:          models.TransactionEarned,
-		Amount:        amount,
-		BalanceBefore: teacherBalance,
-		BalanceAfter:  teacherBalance + amount,
-		Description:   "Earned from session (Admin completed)",
-		SessionID:     &session.ID,
-	}
-	if err := s.transactionRepo.Create(earnedTransaction); err != nil {
-		log.Printf("ERROR: AdminCompleteSession: failed to create earned transaction: %v", err)
-	}
-	
-	// Create spent transaction for student
-	spentTransaction := &models.Transaction{
-		UserID:        session.StudentID,
-		Type:          models.TransactionSpent,
-		Amount:        -amount,
-		BalanceBefore: studentBalance,
-		BalanceAfter:  studentBalance - amount,
-		Description:   "Spent on session (Admin completed)",
-		SessionID:     &session.ID,
-	}
-	if err := s.transactionRepo.Create(spentTransaction); err != nil {
-		log.Printf("ERROR: AdminCompleteSession: failed to create spent transaction: %v", err)
+	if err := s.completeSession(session); err != nil {
+		return err
 	}
-	
+
 	s.notificationService.CreateNotification(
 		session.TeacherID,
 		models.NotificationTypeSession,
@@ -124,6 +90,6 @@ func (s *SessionService) AdminCompleteSession(sessionID uint) error {
 		"Session marked completed by admin.",
 		map[string]interface{}{"session_id": session.ID},
 	)
-	
+
 	return nil
 }
@@ -22,8 +22,9 @@ func NewSkillService(skillRepo repository.SkillRepositoryInterface, userRepo rep
 }
 
 // GetAllSkills retrieves all skills with pagination and filters
-func (s *SkillService) GetAllSkills(limit, offset int, category, search string, dayOfWeek *int, minRating *float64, location, sortBy string) ([]models.Skill, int64, error) {
-	return s.skillRepo.GetAllWithFilters(limit, offset, category, search, dayOfWeek, minRating, location, sortBy)
+// tag is a review tag slug; it keeps skills with a teacher given that tag.
+func (s *SkillService) GetAllSkills(limit, offset int, category, search string, dayOfWeek *int, minRating *float64, location, tag, sortBy string) ([]models.Skill, int64, error) {
+	return s.skillRepo.GetAllWithFilters(limit, offset, category, search, dayOfWeek, minRating, location, tag, sortBy)
 }
 
 // GetSkillByID retrieves a skill by ID
@@ -40,10 +41,11 @@ func (s *SkillService) GetRecommendedSkills(limit int) ([]models.Skill, error) {
 }
 
 // GetSkillTeachers retrieves all teachers for a specific skill
-func (s *SkillService) GetSkillTeachers(skillID uint) ([]models.UserSkill, error) {
+// A non-empty tag (review tag slug) keeps only teachers given that tag for the skill.
+func (s *SkillService) GetSkillTeachers(skillID uint, tag string) ([]models.UserSkill, error) {
 	// Use type assertion to access the concrete method
 	if repo, ok := s.skillRepo.(*repository.SkillRepository); ok {
-		return repo.GetTeachersBySkillID(skillID)
+		return repo.GetTeachersBySkillID(skillID, tag)
 	}
 	return nil, utils.ErrInternal
 }
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/timebankingskill/backend/internal/events"
 	"github.com/timebankingskill/backend/internal/models"
 	"github.com/timebankingskill/backend/internal/repository"
+	"github.com/timebankingskill/backend/internal/utils"
 )
 
 // TransactionService handles transaction business logic
@@ -373,11 +375,10 @@ func (s *TransactionService) CalculateUserStats(userID uint) (map[string]interfa
 // This is different from session-based transfers - used for gifting, helping friends, etc.
 // 
 // Transaction Flow:
-//   1. Validate sender has sufficient balance
-//   2. Validate recipient exists and is active
-//   3. Debit credits from sender
-//   4. Credit credits to recipient
-//   5. Send notification to recipient
+//   1. Validate recipient exists and is active
+//   2. Validate sender has sufficient available balance (balance minus escrow)
+//   3. Debit sender and credit recipient in one database transaction
+//   4. Send notification to recipient
 //
 // Error Handling:
 //   - Returns specific error for insufficient credits (for UI alert)
@@ -418,24 +419,19 @@ func (s *TransactionService) DirectTransfer(
 		return errors.New("recipient account is not active")
 	}
 
-	// Check sender balance
-	senderBalance, err := s.transactionRepo.GetUserBalance(senderID)
-	if err != nil {
-		return fmt.Errorf("failed to get sender balance: %w", err)
-	}
-
-	// CRITICAL: Check for insufficient credits
-	if senderBalance < amount {
-		return fmt.Errorf("insufficient credits: you have %.1f credits, need %.1f credits", 
-			senderBalance, amount)
-	}
-
-	// Get sender info for notification
+	// Get sender info for notification and the early balance check
 	sender, err := s.userRepo.GetByID(senderID)
 	if err != nil {
 		return fmt.Errorf("failed to get sender info: %w", err)
 	}
 
+	// CRITICAL: Check for insufficient credits (re-checked under row lock in Transfer)
+	senderAvailable := sender.AvailableCredits()
+	if senderAvailable < amount {
+		return fmt.Errorf("insufficient credits: you have %.1f credits, need %.1f credits",
+			senderAvailable, amount)
+	}
+
 	// Prepare descriptions
 	senderDescription := fmt.Sprintf("Transfer to %s", recipient.FullName)
 	if message != "" {
@@ -447,29 +443,15 @@ func (s *TransactionService) DirectTransfer(
 		recipientDescription = fmt.Sprintf("Transfer from %s: %s", sender.FullName, message)
 	}
 
-	// Debit from sender (negative amount)
-	_, err = s.CreateTransaction(
-		senderID,
-		models.TransactionSpent,
-		-amount,
-		senderDescription,
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to debit sender: %w", err)
+	// Debit sender and credit recipient atomically (balances and ledger)
+	_, _, err = s.transactionRepo.Transfer(senderID, recipientID, amount, senderDescription, recipientDescription, "")
+	if errors.Is(err, utils.ErrInsufficientCredits) {
+		return fmt.Errorf("insufficient credits: you need %.1f available credits", amount)
 	}
-
-	// Credit to recipient (positive amount)
-	_, err = s.CreateTransaction(
-		recipientID,
-		models.TransactionBonus, // Use bonus type for peer transfers
-		amount,
-		recipientDescription,
-		nil,
-	)
 	if err != nil {
-		return fmt.Errorf("failed to credit recipient: %w", err)
+		return fmt.Errorf("failed to transfer credits: %w", err)
 	}
+	events.PublishWithData(events.TransferMade, map[string]interface{}{"amount": amount}, senderID, recipientID)
 
 	// Send notification to recipient
 	notificationData := map[string]interface{}{
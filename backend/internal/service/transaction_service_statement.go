@@ -0,0 +1,274 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// StatementMonthLayout is the month format used in statement URLs (yyyy-mm)
+const StatementMonthLayout = "2006-01"
+
+// GetMonthlyStatement builds a user's account statement for one calendar month
+//
+// Opening and closing balances come from the ledger balance snapshots, so the
+// statement matches the transaction history the user sees in the app.
+// Hours taught and learned are the durations of sessions paid in the month.
+//
+// Returns:
+//   - *MonthlyStatement: The statement
+//   - error: If the month is malformed or in the future, or a database error
+func (s *TransactionService) GetMonthlyStatement(userID uint, month string) (*dto.MonthlyStatement, error) {
+	start, err := time.ParseInLocation(StatementMonthLayout, month, time.Local)
+	if err != nil {
+		return nil, utils.ErrInvalidStatementMonth
+	}
+	if start.After(time.Now()) {
+		return nil, utils.ErrFutureStatementMonth
+	}
+	end := start.AddDate(0, 1, 0)
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+
+	transactions, err := s.transactionRepo.GetUserTransactionsBetween(userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	statement := &dto.MonthlyStatement{
+		UserID:      user.ID,
+		FullName:    user.FullName,
+		Email:       user.Email,
+		Month:       month,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Lines:       make([]dto.StatementLine, 0, len(transactions)),
+	}
+
+	if len(transactions) > 0 {
+		statement.OpeningBalance = transactions[0].BalanceBefore
+	} else {
+		previous, err := s.transactionRepo.GetLastBefore(userID, start)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get opening balance: %w", err)
+		}
+		if previous != nil {
+			statement.OpeningBalance = previous.BalanceAfter
+		}
+	}
+	statement.ClosingBalance = statement.OpeningBalance
+	addStatementLines(statement, transactions)
+
+	return statement, nil
+}
+
+// addStatementLines appends transactions, oldest first, to a statement and totals them
+// Totals are balance changes, so escrow lines (balance before == after) count for neither.
+func addStatementLines(statement *dto.MonthlyStatement, transactions []models.Transaction) {
+	taught := make(map[uint]bool)
+	learned := make(map[uint]bool)
+	for _, t := range transactions {
+		line := dto.StatementLine{
+			Date:         t.CreatedAt,
+			Type:         string(t.Type),
+			Description:  t.Description,
+			Amount:       t.Amount,
+			BalanceAfter: t.BalanceAfter,
+		}
+		if t.Session != nil {
+			line.SessionTitle = t.Session.Title
+
+			// Count each session once, even if it has several lines (hold, spent, refund)
+			switch {
+			case t.Type == models.TransactionEarned && !taught[t.Session.ID]:
+				taught[t.Session.ID] = true
+				statement.HoursTaught += t.Session.Duration
+			case t.Type == models.TransactionSpent && !learned[t.Session.ID]:
+				learned[t.Session.ID] = true
+				statement.HoursLearned += t.Session.Duration
+			}
+		}
+
+		statement.ClosingBalance = t.BalanceAfter
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.SessionsTaught = len(taught)
+	statement.SessionsLearned = len(learned)
+	statement.TotalCredited, statement.TotalDebited, _ = balanceTotals(transactions)
+}
+
+// balanceTotals sums the balance increases and decreases of ledger lines
+// Escrow holds and releases move credits between the available and held parts of a
+// balance without changing it, so they count for neither total whatever the sign of
+// their amount. changes is the number of lines that changed the balance.
+func balanceTotals(transactions []models.Transaction) (credited, debited float64, changes int) {
+	for _, t := range transactions {
+		change := t.BalanceAfter - t.BalanceBefore
+		switch {
+		case change > 0:
+			credited += change
+		case change < 0:
+			debited += -change
+		default:
+			continue
+		}
+		changes++
+	}
+	return credited, debited, changes
+}
+
+// ExportMonthlyStatement renders a monthly statement as "pdf" or "csv"
+//
+// Returns:
+//   - []byte: File content
+//   - string: Download filename
+//   - error: If the statement could not be built or the format is unsupported
+func (s *TransactionService) ExportMonthlyStatement(userID uint, month, format string) ([]byte, string, error) {
+	statement, err := s.GetMonthlyStatement(userID, month)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case "csv":
+		filename := fmt.Sprintf("statement_%s.csv", month)
+		content, err := s.generateStatementCSV(statement)
+		return content, filename, err
+	case "pdf":
+		filename := fmt.Sprintf("statement_%s.pdf", month)
+		content, err := s.generateStatementPDF(statement)
+		return content, filename, err
+	}
+
+	return nil, "", fmt.Errorf("unsupported format: %s", format)
+}
+
+func (s *TransactionService) generateStatementCSV(statement *dto.MonthlyStatement) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+
+	// Summary
+	w.Write([]string{"Statement", statement.Month})
+	w.Write([]string{"Name", statement.FullName})
+	w.Write([]string{"Email", statement.Email})
+	w.Write([]string{"Opening Balance", fmt.Sprintf("%.2f", statement.OpeningBalance)})
+	w.Write([]string{"Closing Balance", fmt.Sprintf("%.2f", statement.ClosingBalance)})
+	w.Write([]string{"Total Credited", fmt.Sprintf("%.2f", statement.TotalCredited)})
+	w.Write([]string{"Total Debited", fmt.Sprintf("%.2f", statement.TotalDebited)})
+	w.Write([]string{"Hours Taught", fmt.Sprintf("%.2f", statement.HoursTaught)})
+	w.Write([]string{"Hours Learned", fmt.Sprintf("%.2f", statement.HoursLearned)})
+
+	// Lines
+	w.Write([]string{""})
+	w.Write([]string{"Date", "Type", "Description", "Session", "Amount", "Balance After"})
+	for _, line := range statement.Lines {
+		w.Write([]string{
+			line.Date.Format("2006-01-02 15:04"),
+			line.Type,
+			line.Description,
+			line.SessionTitle,
+			fmt.Sprintf("%.2f", line.Amount),
+			fmt.Sprintf("%.2f", line.BalanceAfter),
+		})
+	}
+
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+func (s *TransactionService) generateStatementPDF(statement *dto.MonthlyStatement) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 16)
+
+	// Title
+	pdf.Cell(40, 10, "Wibi Time Banking - Monthly Statement")
+	pdf.Ln(12)
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.Cell(40, 6, tr(fmt.Sprintf("%s (%s)", statement.FullName, statement.Email)))
+	pdf.Ln(6)
+	pdf.Cell(40, 6, fmt.Sprintf("Period: %s to %s",
+		statement.PeriodStart.Format("2 January 2006"),
+		statement.PeriodEnd.AddDate(0, 0, -1).Format("2 January 2006")))
+	pdf.Ln(6)
+	pdf.Cell(40, 6, fmt.Sprintf("Generated on: %s", time.Now().Format("2006-01-02 15:04:05")))
+	pdf.Ln(12)
+
+	// Summary
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(0, 10, "Summary")
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "", 11)
+	summary := [][]string{
+		{"Opening Balance", fmt.Sprintf("%.2f", statement.OpeningBalance)},
+		{"Total Credited", fmt.Sprintf("%.2f", statement.TotalCredited)},
+		{"Total Debited", fmt.Sprintf("%.2f", statement.TotalDebited)},
+		{"Closing Balance", fmt.Sprintf("%.2f", statement.ClosingBalance)},
+		{"Hours Taught", fmt.Sprintf("%.1f (%d sessions)", statement.HoursTaught, statement.SessionsTaught)},
+		{"Hours Learned", fmt.Sprintf("%.1f (%d sessions)", statement.HoursLearned, statement.SessionsLearned)},
+	}
+	for _, row := range summary {
+		pdf.Cell(50, 8, row[0])
+		pdf.Cell(50, 8, row[1])
+		pdf.Ln(8)
+	}
+	pdf.Ln(6)
+
+	// Transactions
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(0, 10, "Transactions")
+	pdf.Ln(10)
+
+	if len(statement.Lines) == 0 {
+		pdf.SetFont("Arial", "", 10)
+		pdf.Cell(0, 8, "No transactions in this period.")
+	} else {
+		// Table Header
+		pdf.SetFillColor(240, 240, 240)
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(22, 7, "Date", "1", 0, "", true, 0, "")
+		pdf.CellFormat(18, 7, "Type", "1", 0, "", true, 0, "")
+		pdf.CellFormat(70, 7, "Description", "1", 0, "", true, 0, "")
+		pdf.CellFormat(40, 7, "Session", "1", 0, "", true, 0, "")
+		pdf.CellFormat(20, 7, "Amount", "1", 0, "R", true, 0, "")
+		pdf.CellFormat(20, 7, "Balance", "1", 0, "R", true, 0, "")
+		pdf.Ln(7)
+
+		// Table Body
+		pdf.SetFont("Arial", "", 8)
+		for _, line := range statement.Lines {
+			pdf.CellFormat(22, 7, line.Date.Format("2006-01-02"), "1", 0, "", false, 0, "")
+			pdf.CellFormat(18, 7, line.Type, "1", 0, "", false, 0, "")
+			pdf.CellFormat(70, 7, tr(truncateText(line.Description, 48)), "1", 0, "", false, 0, "")
+			pdf.CellFormat(40, 7, tr(truncateText(line.SessionTitle, 26)), "1", 0, "", false, 0, "")
+			pdf.CellFormat(20, 7, fmt.Sprintf("%+.2f", line.Amount), "1", 0, "R", false, 0, "")
+			pdf.CellFormat(20, 7, fmt.Sprintf("%.2f", line.BalanceAfter), "1", 0, "R", false, 0, "")
+			pdf.Ln(7)
+		}
+	}
+
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	return buf.Bytes(), err
+}
+
+// truncateText shortens text to at most max runes, marking the cut with "..."
+func truncateText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-3]) + "..."
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+)
+
+// ledgerLine is a transaction moving a balance from before to after
+func ledgerLine(typ models.TransactionType, amount, before, after float64) models.Transaction {
+	return models.Transaction{Type: typ, Amount: amount, BalanceBefore: before, BalanceAfter: after}
+}
+
+func TestStatementTotals(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []models.Transaction
+		credited float64
+		debited  float64
+		closing  float64
+	}{
+		{
+			name:    "no transactions",
+			closing: 20,
+		},
+		{
+			name: "earnings and donations",
+			lines: []models.Transaction{
+				ledgerLine(models.TransactionEarned, 3, 20, 23),
+				ledgerLine(models.TransactionDonation, -5, 23, 18),
+			},
+			credited: 3,
+			debited:  5,
+			closing:  18,
+		},
+		{
+			name: "hold then spent counts the payment once",
+			lines: []models.Transaction{
+				ledgerLine(models.TransactionHold, 2, 20, 20),
+				ledgerLine(models.TransactionSpent, -2, 20, 18),
+			},
+			debited: 2,
+			closing: 18,
+		},
+		{
+			name: "hold released by a cancellation",
+			lines: []models.Transaction{
+				ledgerLine(models.TransactionHold, 2, 20, 20),
+				ledgerLine(models.TransactionRefund, -2, 20, 20),
+			},
+			closing: 20,
+		},
+		{
+			name: "escrow between balance changes",
+			lines: []models.Transaction{
+				ledgerLine(models.TransactionGrant, 4, 20, 24),
+				ledgerLine(models.TransactionHold, 1.5, 24, 24),
+				ledgerLine(models.TransactionBonus, 2.5, 24, 26.5),
+				ledgerLine(models.TransactionRefund, -1.5, 26.5, 26.5),
+				ledgerLine(models.TransactionOverflow, -1.5, 26.5, 25),
+			},
+			credited: 6.5,
+			debited:  1.5,
+			closing:  25,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement := &dto.MonthlyStatement{OpeningBalance: 20, ClosingBalance: 20}
+			addStatementLines(statement, tt.lines)
+
+			assert.InDelta(t, tt.credited, statement.TotalCredited, 1e-9)
+			assert.InDelta(t, tt.debited, statement.TotalDebited, 1e-9)
+			assert.Equal(t, tt.closing, statement.ClosingBalance)
+			assert.InDelta(t, statement.ClosingBalance-statement.OpeningBalance,
+				statement.TotalCredited-statement.TotalDebited, 1e-9, "totals reconcile with the balances")
+			assert.Len(t, statement.Lines, len(tt.lines), "escrow lines are still listed")
+		})
+	}
+}
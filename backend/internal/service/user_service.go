@@ -13,6 +13,7 @@ import (
 type UserService struct {
 	userRepo    repository.UserRepositoryInterface
 	sessionRepo *repository.SessionRepository
+	tagRepo     *repository.ReviewTagRepository
 }
 
 func NewUserService(userRepo repository.UserRepositoryInterface) *UserService {
@@ -21,12 +22,13 @@ func NewUserService(userRepo repository.UserRepositoryInterface) *UserService {
 	}
 }
 
-// NewUserServiceWithSession creates a new user service with session repository
-// Used for calculating teaching/learning hours from completed sessions
-func NewUserServiceWithSession(userRepo repository.UserRepositoryInterface, sessionRepo *repository.SessionRepository) *UserService {
+// NewUserServiceWithSession creates a new user service with session and review tag repositories
+// Used for calculating teaching/learning hours from completed sessions and profile tag clouds
+func NewUserServiceWithSession(userRepo repository.UserRepositoryInterface, sessionRepo *repository.SessionRepository, tagRepo *repository.ReviewTagRepository) *UserService {
 	return &UserService{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
+		tagRepo:     tagRepo,
 	}
 }
 
@@ -292,6 +294,16 @@ func (s *UserService) GetPublicProfile(userID uint) (*PublicProfile, error) {
 		CreatedAt:             user.CreatedAt,
 	}
 
+	// Tag clouds from the visible reviews received
+	if s.tagRepo != nil {
+		if profile.TeacherTags, err = s.tagRepo.GetTagCountsForUser(userID, models.ReviewTypeTeacher); err != nil {
+			return nil, err
+		}
+		if profile.StudentTags, err = s.tagRepo.GetTagCountsForUser(userID, models.ReviewTypeStudent); err != nil {
+			return nil, err
+		}
+	}
+
 	return profile, nil
 }
 
@@ -322,5 +334,7 @@ type PublicProfile struct {
 	TotalSessionsAsTeacher int     `json:"total_sessions_as_teacher"`
 	AverageRatingAsTeacher float64 `json:"average_rating_as_teacher"`
 	TotalTeachingHours   float64 `json:"total_teaching_hours"`
+	TeacherTags          []models.ReviewTagCount `json:"teacher_tags"` // Review tags received as teacher, most given first
+	StudentTags          []models.ReviewTagCount `json:"student_tags"` // Review tags received as student, most given first
 	CreatedAt            time.Time `json:"created_at"`
 }
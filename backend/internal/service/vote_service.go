@@ -6,12 +6,13 @@ import (
 	"github.com/timebankingskill/backend/internal/repository"
 )
 
-// VoteService encapsulates all toggle-vote business logic for both forum
-// threads (upvote) and success stories (like).
+// VoteService encapsulates all toggle-vote business logic for forum
+// threads (upvote), success stories (like) and reviews (helpful).
 type VoteService struct {
-	voteRepo  *repository.VoteRepository
-	forumRepo *repository.ForumRepository
-	storyRepo *repository.StoryRepository
+	voteRepo   *repository.VoteRepository
+	forumRepo  *repository.ForumRepository
+	storyRepo  *repository.StoryRepository
+	reviewRepo *repository.ReviewRepository
 }
 
 // NewVoteService creates a new VoteService.
@@ -19,11 +20,13 @@ func NewVoteService(
 	voteRepo *repository.VoteRepository,
 	forumRepo *repository.ForumRepository,
 	storyRepo *repository.StoryRepository,
+	reviewRepo *repository.ReviewRepository,
 ) *VoteService {
 	return &VoteService{
-		voteRepo:  voteRepo,
-		forumRepo: forumRepo,
-		storyRepo: storyRepo,
+		voteRepo:   voteRepo,
+		forumRepo:  forumRepo,
+		storyRepo:  storyRepo,
+		reviewRepo: reviewRepo,
 	}
 }
 
@@ -112,3 +115,42 @@ func (s *VoteService) GetThreadUpvoteCountOnly(threadID uint) (int64, error) {
 func (s *VoteService) GetStoryLikeCountOnly(storyID uint) (int64, error) {
 	return s.voteRepo.GetStoryLikeCount(storyID)
 }
+
+// ─────────────────────────────────────────────
+// REVIEW HELPFUL
+// ─────────────────────────────────────────────
+
+// ToggleReviewHelpful toggles a user's helpful vote on a review.
+// The reviewer and the reviewee cannot vote on their own review, and hidden
+// or sealed reviews cannot be voted on.
+func (s *VoteService) ToggleReviewHelpful(userID, reviewID uint) (*VoteResult, error) {
+	review, err := s.reviewRepo.GetByID(reviewID)
+	if err != nil || review.IsHidden || review.IsSealed {
+		return nil, errors.New("review not found")
+	}
+	if review.ReviewerID == userID || review.RevieweeID == userID {
+		return nil, errors.New("you cannot vote on a review you wrote or received")
+	}
+
+	helpful, count, err := s.voteRepo.ToggleReviewHelpful(userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VoteResult{Voted: helpful, Count: count}, nil
+}
+
+// GetReviewHelpfulStatus returns whether the user has marked a review helpful and its total count.
+func (s *VoteService) GetReviewHelpfulStatus(userID, reviewID uint) (*VoteResult, error) {
+	helpful, err := s.voteRepo.HasMarkedReviewHelpful(userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.voteRepo.GetReviewHelpfulCount(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VoteResult{Voted: helpful, Count: count}, nil
+}
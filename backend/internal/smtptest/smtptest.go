@@ -0,0 +1,192 @@
+// Package smtptest provides a local SMTP stand-in for tests
+//
+// The server accepts any AUTH PLAIN credentials over a plain connection on 127.0.0.1,
+// which net/smtp allows for local servers, and records every message it receives:
+//
+//	srv, err := smtptest.NewServer()
+//	defer srv.Close()
+//	os.Setenv("SMTP_HOST", srv.Host)
+//	os.Setenv("SMTP_PORT", srv.Port)
+//	... send mail ...
+//	messages := srv.Messages()
+package smtptest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is an email received by the server
+type Message struct {
+	From   string
+	To     []string
+	Header mail.Header
+	Body   string
+}
+
+// Subject returns the decoded Subject header
+func (m Message) Subject() string {
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		return m.Header.Get("Subject")
+	}
+	return subject
+}
+
+// Server is a local SMTP server recording the messages it receives
+type Server struct {
+	Host string // Always 127.0.0.1
+	Port string
+
+	listener net.Listener
+	mutex    sync.Mutex
+	messages []Message
+	reject   bool
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server on a free local port
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("smtptest: failed to listen: %w", err)
+	}
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	s := &Server{Host: host, Port: port, listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Messages returns the messages received so far
+func (s *Server) Messages() []Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// SetReject makes the server reject (true) or accept (false) new messages with a
+// temporary error, to test retries
+func (s *Server) SetReject(reject bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.reject = reject
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// serve accepts connections until the listener is closed
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(textproto.NewConn(conn))
+		}()
+	}
+}
+
+// handle runs an SMTP session
+func (s *Server) handle(tp *textproto.Conn) {
+	var msg Message
+	_ = tp.PrintfLine("220 smtptest ESMTP ready")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			_ = tp.PrintfLine("250-smtptest greets %s", arg)
+			_ = tp.PrintfLine("250-8BITMIME")
+			_ = tp.PrintfLine("250 AUTH PLAIN")
+		case "HELO":
+			_ = tp.PrintfLine("250 smtptest")
+		case "AUTH":
+			if mechanism, _, _ := strings.Cut(arg, " "); !strings.EqualFold(mechanism, "PLAIN") {
+				_ = tp.PrintfLine("504 unrecognized authentication type")
+				continue
+			}
+			if !strings.Contains(arg, " ") {
+				// Credentials follow on their own line
+				_ = tp.PrintfLine("334 ")
+				if _, err := tp.ReadLine(); err != nil {
+					return
+				}
+			}
+			_ = tp.PrintfLine("235 authentication successful")
+		case "MAIL":
+			s.mutex.Lock()
+			reject := s.reject
+			s.mutex.Unlock()
+			if reject {
+				_ = tp.PrintfLine("451 temporary failure, try again later")
+				continue
+			}
+			msg = Message{From: trimAddress(arg, "FROM:")}
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			msg.To = append(msg.To, trimAddress(arg, "TO:"))
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			parsed, err := mail.ReadMessage(bytes.NewReader(data))
+			if err != nil {
+				_ = tp.PrintfLine("554 malformed message: %v", err)
+				continue
+			}
+			body, _ := io.ReadAll(bufio.NewReader(parsed.Body))
+			msg.Header = parsed.Header
+			msg.Body = string(body)
+
+			s.mutex.Lock()
+			s.messages = append(s.messages, msg)
+			s.mutex.Unlock()
+			_ = tp.PrintfLine("250 ok: queued")
+		case "RSET", "NOOP":
+			msg = Message{}
+			_ = tp.PrintfLine("250 ok")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+// trimAddress extracts the address from a MAIL FROM:<a> or RCPT TO:<a> argument
+func trimAddress(arg, prefix string) string {
+	if len(arg) >= len(prefix) && strings.EqualFold(arg[:len(prefix)], prefix) {
+		arg = arg[len(prefix):]
+	}
+	address, _, _ := strings.Cut(strings.TrimSpace(arg), " ")
+	return strings.Trim(address, "<>")
+}
@@ -0,0 +1,233 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/timebankingskill/backend/internal/dto"
+)
+
+// digestEmailFuncs are the helpers of the weekly digest template
+var digestEmailFuncs = template.FuncMap{
+	"datetime": func(t time.Time) string { return t.Format("Mon 2 Jan, 15:04") },
+	"date":     func(t time.Time) string { return t.Format("2 Jan") },
+	"credits":  func(amount float64) string { return fmt.Sprintf("%.1f", amount) },
+	"signed": func(amount float64) string {
+		if amount > 0 {
+			return fmt.Sprintf("+%.1f", amount)
+		}
+		return fmt.Sprintf("%.1f", amount)
+	},
+}
+
+// weeklyDigestLayout is the HTML layout of the weekly digest email
+// Empty sections are left out.
+var weeklyDigestLayout = template.Must(template.New("digest").Funcs(digestEmailFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>Your week on Wibi</title>
+	{{.Styles}}
+</head>
+<body>
+	<div class="wrapper">
+		<div class="card">
+			<div class="header">
+				<h1>Your Week on Wibi</h1>
+			</div>
+
+			<div class="content">
+				<p class="greeting">Hello {{.RecipientName}}!</p>
+				<p class="text">Here is what happened from {{date .Digest.PeriodStart}} to {{date .Digest.PeriodEnd}}, and what's coming up.</p>
+
+				{{with .Digest.UpcomingSessions}}
+				<h2 style="color: #ffffff; font-size: 17px;">📅 Upcoming sessions</h2>
+				<ul class="text">
+					{{range .}}<li><strong>{{.Title}}</strong> with {{.PartnerName}} ({{if eq .Role "teacher"}}teaching{{else}}learning{{end}}), {{datetime .ScheduledAt}}</li>
+					{{end}}
+				</ul>
+				{{end}}
+
+				{{with .Digest.PendingRequests}}
+				<h2 style="color: #ffffff; font-size: 17px;">⏳ Requests awaiting your approval</h2>
+				<ul class="text">
+					{{range .}}<li><strong>{{.Title}}</strong> from {{.StudentName}}{{with .ScheduledAt}}, for {{datetime .}}{{end}}</li>
+					{{end}}
+				</ul>
+				{{end}}
+
+				{{with .Digest.NewTeachers}}
+				<h2 style="color: #ffffff; font-size: 17px;">🎓 New teachers for skills you want to learn</h2>
+				<ul class="text">
+					{{range .}}<li>{{.TeacherName}} now teaches <strong>{{.SkillName}}</strong> ({{.Level}})</li>
+					{{end}}
+				</ul>
+				{{end}}
+
+				{{with .Digest.ThreadReplies}}
+				<h2 style="color: #ffffff; font-size: 17px;">💬 New replies to your threads</h2>
+				<ul class="text">
+					{{range .}}<li><strong>{{.Title}}</strong>: {{.NewReplies}} new {{if eq .NewReplies 1}}reply{{else}}replies{{end}}</li>
+					{{end}}
+				</ul>
+				{{end}}
+
+				{{if .Digest.Credits.Transactions}}
+				<h2 style="color: #ffffff; font-size: 17px;">💰 Credits</h2>
+				<div class="info-box">
+					<p>Balance: <strong>{{credits .Digest.Credits.Balance}}</strong> ({{signed .Digest.Credits.Net}} this week)<br>
+					Earned {{credits .Digest.Credits.Earned}}, spent {{credits .Digest.Credits.Spent}} in {{.Digest.Credits.Transactions}} transactions</p>
+				</div>
+				{{end}}
+
+				{{with .Digest.NearlyEarnedBadges}}
+				<h2 style="color: #ffffff; font-size: 17px;">🏅 Badges within reach</h2>
+				<ul class="text">
+					{{range .}}<li>{{.Icon}} <strong>{{.Name}}</strong>: {{.Percent}}% complete</li>
+					{{end}}
+				</ul>
+				{{end}}
+
+				<div class="button-wrapper">
+					<a href="{{.DashboardURL}}" class="button">Open Wibi</a>
+				</div>
+
+				<p class="text" style="font-size: 13px; color: #737373;">
+					You receive this digest every week.
+					<a href="{{.UnsubscribeURL}}" style="color: #f97316;">Unsubscribe from the weekly digest</a> or
+					<a href="{{.SettingsURL}}" style="color: #f97316;">pick another day</a>.
+				</p>
+			</div>
+
+			<div class="footer">
+				<p class="footer-logo">Wibi</p>
+				<p class="footer-text">Waktu Indonesia Berbagi Ilmu<br>Time Banking Skill Platform</p>
+			</div>
+		</div>
+	</div>
+</body>
+</html>
+`))
+
+// DigestUnsubscribeURL returns the one-click unsubscribe link of a weekly digest
+// The link points at the API (API_URL, default http://localhost:8080).
+func DigestUnsubscribeURL(token string) string {
+	apiURL := strings.TrimRight(os.Getenv("API_URL"), "/")
+	if apiURL == "" {
+		apiURL = "http://localhost:8080"
+	}
+	return apiURL + "/api/v1/digest/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+// digestUnsubscribePage is the confirmation page opened from the unsubscribe link of a digest
+// Opening the link must not unsubscribe (link scanners and prefetchers follow it too), so
+// the page posts the token back to the same URL.
+var digestUnsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<meta name="robots" content="noindex">
+	<title>Unsubscribe from the weekly digest</title>
+	{{.Styles}}
+</head>
+<body>
+	<div class="wrapper">
+		<div class="card">
+			<div class="header">
+				<h1>Weekly Digest</h1>
+			</div>
+
+			<div class="content">
+				<p class="text">Unsubscribe from the weekly digest? You will no longer receive the weekly summary
+					of your sessions, credits and badges. Other notifications are not affected.</p>
+				<form method="post" action="{{.ActionURL}}" class="button-wrapper">
+					<button type="submit" class="button" style="border: none; cursor: pointer;">Unsubscribe</button>
+				</form>
+				<p class="text"><a href="{{.SettingsURL}}" style="color: #f97316;">Manage notification settings instead</a></p>
+			</div>
+		</div>
+	</div>
+</body>
+</html>
+`))
+
+// RenderDigestUnsubscribePage renders the confirmation page of the digest unsubscribe link
+func RenderDigestUnsubscribePage(token string) (string, error) {
+	var body bytes.Buffer
+	err := digestUnsubscribePage.Execute(&body, map[string]interface{}{
+		"Styles":      template.HTML(emailBaseStyles),
+		"ActionURL":   DigestUnsubscribeURL(token),
+		"SettingsURL": FrontendURL() + "/settings/notifications",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render unsubscribe page: %w", err)
+	}
+	return body.String(), nil
+}
+
+// RenderWeeklyDigestEmail renders a user's weekly digest
+//
+// Returns:
+//   - string: Subject
+//   - string: HTML body
+//   - error: If rendering fails
+func RenderWeeklyDigestEmail(recipientName string, digest *dto.WeeklyDigestResponse, unsubscribeURL string) (string, string, error) {
+	frontendURL := FrontendURL()
+
+	var body bytes.Buffer
+	err := weeklyDigestLayout.Execute(&body, map[string]interface{}{
+		"Styles":         template.HTML(emailBaseStyles),
+		"RecipientName":  recipientName,
+		"Digest":         digest,
+		"DashboardURL":   frontendURL + "/dashboard",
+		"SettingsURL":    frontendURL + "/settings/notifications",
+		"UnsubscribeURL": unsubscribeURL,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render weekly digest: %w", err)
+	}
+
+	return "Your week on Wibi", body.String(), nil
+}
+
+// SendWeeklyDigestEmail renders a user's weekly digest and sends it via SMTP
+// The email carries List-Unsubscribe headers, so mail clients can offer one-click
+// unsubscribe (RFC 8058).
+func SendWeeklyDigestEmail(recipientEmail, recipientName string, digest *dto.WeeklyDigestResponse, unsubscribeURL string) error {
+	smtpHost := os.Getenv("SMTP_HOST")
+	smtpPort := os.Getenv("SMTP_PORT")
+	smtpUser := os.Getenv("SMTP_USER")
+	smtpPass := os.Getenv("SMTP_PASS")
+	fromEmail := os.Getenv("EMAIL_FROM")
+
+	subject, htmlContent, err := RenderWeeklyDigestEmail(recipientName, digest, unsubscribeURL)
+	if err != nil {
+		return err
+	}
+
+	if smtpHost == "" || smtpUser == "" || smtpPass == "" {
+		log.Println("⚠️  SMTP credentials not set, skipping email sending")
+		log.Printf("📧 [DEV] Weekly digest for %s, unsubscribe: %s", recipientEmail, unsubscribeURL)
+		return nil
+	}
+
+	if fromEmail == "" {
+		fromEmail = "noreply@wibi.local"
+	}
+	if smtpPort == "" {
+		smtpPort = "587"
+	}
+
+	return sendSMTPEmailWithHeaders(smtpHost, smtpPort, smtpUser, smtpPass, fromEmail, recipientEmail, subject, htmlContent, map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	})
+}
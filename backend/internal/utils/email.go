@@ -3,8 +3,10 @@ package utils
 import (
 	"fmt"
 	"log"
+	"mime"
 	"net/smtp"
 	"os"
+	"strings"
 )
 
 // Email template base styles matching Wibi website design
@@ -297,8 +299,23 @@ func SendPasswordResetEmail(recipientEmail string, recipientName string, resetLi
 	return sendSMTPEmail(smtpHost, smtpPort, smtpUser, smtpPass, fromEmail, recipientEmail, subject, htmlContent)
 }
 
+// FrontendURL returns the base URL of the frontend, for links in emails
+// Read from FRONTEND_URL, default http://localhost:3000.
+func FrontendURL() string {
+	frontendURL := strings.TrimRight(os.Getenv("FRONTEND_URL"), "/")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+	return frontendURL
+}
+
 // sendSMTPEmail is a helper function to send emails via SMTP (Mailtrap)
 func sendSMTPEmail(host, port, username, password, from, to, subject, htmlBody string) error {
+	return sendSMTPEmailWithHeaders(host, port, username, password, from, to, subject, htmlBody, nil)
+}
+
+// sendSMTPEmailWithHeaders sends an HTML email with additional headers (e.g. List-Unsubscribe)
+func sendSMTPEmailWithHeaders(host, port, username, password, from, to, subject, htmlBody string, extraHeaders map[string]string) error {
 	// SMTP authentication
 	auth := smtp.PlainAuth("", username, password, host)
 
@@ -306,9 +323,12 @@ func sendSMTPEmail(host, port, username, password, from, to, subject, htmlBody s
 	headers := make(map[string]string)
 	headers["From"] = from
 	headers["To"] = to
-	headers["Subject"] = subject
+	headers["Subject"] = mime.QEncoding.Encode("UTF-8", subject) // Titles may contain emoji
 	headers["MIME-Version"] = "1.0"
 	headers["Content-Type"] = "text/html; charset=UTF-8"
+	for k, v := range extraHeaders {
+		headers[k] = v
+	}
 
 	message := ""
 	for k, v := range headers {
@@ -9,7 +9,71 @@ var (
 
 	// User Errors
 	ErrUserNotFound = errors.New("user not found")
+	ErrUserInactive        = errors.New("user account is not active")
+	ErrRecentGrant         = errors.New("user received a community pool grant recently")
 	ErrInsufficientCredits = errors.New("insufficient available credit balance")
+	ErrPoolInsufficient    = errors.New("community pool has insufficient credits")
+	ErrNoticeNotPending    = errors.New("credit policy notice is no longer pending")
+
+	// Credit Request Errors
+	ErrCreditRequestNotFound   = errors.New("credit request not found")
+	ErrCreditRequestNotPending = errors.New("credit request is no longer pending")
+	ErrCreditRequestLimit      = errors.New("too many credit requests, please try again later")
+
+	// Statement Errors
+	ErrInvalidStatementMonth = errors.New("invalid statement month, expected yyyy-mm")
+	ErrFutureStatementMonth  = errors.New("statement month is in the future")
+
+	// Push Notification Errors
+	ErrPushNotConfigured        = errors.New("push notifications are not configured")
+	ErrInvalidPushSubscription  = errors.New("invalid push subscription")
+	ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+
+	// Credit Adjustment Errors
+	ErrAdjustmentNotFound      = errors.New("credit adjustment not found")
+	ErrAdjustmentNotPending    = errors.New("credit adjustment has already been reviewed")
+	ErrSelfApproval            = errors.New("an adjustment must be approved by a different admin")
+	ErrInvalidAdjustmentAmount = errors.New("invalid adjustment amount")
+
+	// Badge Errors
+	ErrInvalidBadgeRule   = errors.New("invalid badge requirements")
+	ErrInvalidBadge       = errors.New("invalid badge")
+	ErrInvalidRevocation  = errors.New("invalid badge revocation")
+	ErrBadgeNotFound      = errors.New("badge not found")
+	ErrBadgeNameTaken     = errors.New("a badge with this name already exists")
+	ErrBadgeNotHeld       = errors.New("user does not hold this badge")
+	ErrBadgeAlreadyHeld   = errors.New("user already holds this badge")
+	ErrBackfillNotFound   = errors.New("badge backfill not found")
+	ErrInvalidLeaderboard = errors.New("invalid leaderboard query")
+
+	// Review Errors
+	ErrReviewNotFound        = errors.New("review not found")
+	ErrInvalidReportReason   = errors.New("invalid report reason")
+	ErrCannotReportOwnReview = errors.New("you cannot report your own review")
+	ErrAlreadyReported       = errors.New("you have already reported this review")
+	ErrReviewAlreadyHidden   = errors.New("review is already hidden")
+	ErrReviewNotHidden       = errors.New("review is not hidden")
+	ErrNoPendingReports      = errors.New("review has no pending reports")
+	ErrNotReviewee           = errors.New("only the person reviewed can reply to this review")
+	ErrReplyExists           = errors.New("this review already has a reply")
+	ErrNoReply               = errors.New("this review has no reply")
+	ErrInvalidReviewSort     = errors.New("invalid sort, expected recent, helpful, highest or lowest")
+	ErrReviewWindowClosed    = errors.New("the review window for this session has closed")
+	ErrReviewLocked          = errors.New("revealed reviews can no longer be changed")
+	ErrReviewTagNotFound     = errors.New("review tag not found")
+	ErrReviewTagTaken        = errors.New("a review tag with this slug already exists")
+	ErrInvalidReviewTag      = errors.New("invalid review tag")
+	ErrTooManyReviewTags     = errors.New("too many review tags")
+
+	// Challenge Errors
+	ErrChallengeNotFound  = errors.New("challenge not found")
+	ErrInvalidChallenge   = errors.New("invalid challenge")
+	ErrChallengeClosed    = errors.New("challenge is not open for enrollment")
+	ErrChallengeFull      = errors.New("challenge has no places left")
+	ErrChallengeStarted   = errors.New("the goal and start date cannot change once the challenge has started")
+	ErrAlreadyEnrolled    = errors.New("already enrolled in this challenge")
+	ErrNotEnrolled        = errors.New("not enrolled in this challenge")
+	ErrChallengeCompleted = errors.New("cannot leave a completed challenge")
 
 	// Skill Errors
 	ErrSkillNotFound = errors.New("skill not found")
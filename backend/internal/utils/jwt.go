@@ -19,9 +19,15 @@ type JWTClaims struct {
 	UserID       uint   `json:"user_id"`
 	Email        string `json:"email"`
 	TokenVersion int    `json:"token_version"` // For session invalidation
+	TokenType    string `json:"token_type"`    // Always "access", see ValidateToken
 	jwt.RegisteredClaims
 }
 
+// accessTokenType marks login tokens
+// Other tokens signed with JWT_SECRET (email verification, password reset, digest
+// unsubscribe) lack it, so they are never accepted as a login.
+const accessTokenType = "access"
+
 // getJWTSecret retrieves and validates the JWT secret from environment
 func getJWTSecret() (string, error) {
 	secret := os.Getenv("JWT_SECRET")
@@ -53,6 +59,7 @@ func GenerateTokenWithVersion(userID uint, email string, tokenVersion int) (stri
 		UserID:       userID,
 		Email:        email,
 		TokenVersion: tokenVersion,
+		TokenType:    accessTokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -73,6 +80,7 @@ func GenerateTokenWithVersion(userID uint, email string, tokenVersion int) (stri
 }
 
 // ValidateToken validates a JWT token and returns claims
+// Only access tokens are accepted.
 func ValidateToken(tokenString string) (*JWTClaims, error) {
 	secret, err := getJWTSecret()
 	if err != nil {
@@ -93,7 +101,7 @@ func ValidateToken(tokenString string) (*JWTClaims, error) {
   }
 
   // Extract claims
-  if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+  if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid && claims.TokenType == accessTokenType {
     return claims, nil
   }
 
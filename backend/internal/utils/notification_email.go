@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"os"
+)
+
+// NotificationEmail is an in-app notification rendered for the email channel
+type NotificationEmail struct {
+	Type          string // Notification type (session, credit, achievement, review, social)
+	RecipientName string
+	Title         string
+	Message       string
+}
+
+// notificationEmailTemplate is the per-type look and call to action of notification emails
+type notificationEmailTemplate struct {
+	Heading     string
+	Gradient    string // Header background
+	ActionLabel string
+	ActionPath  string // Frontend path the button links to
+}
+
+// notificationEmailTemplates are the templates per notification type
+var notificationEmailTemplates = map[string]notificationEmailTemplate{
+	"session": {
+		Heading:     "Session Update",
+		Gradient:    "linear-gradient(135deg, #3b82f6 0%, #2563eb 100%)",
+		ActionLabel: "View Sessions",
+		ActionPath:  "/sessions",
+	},
+	"credit": {
+		Heading:     "Credit Update",
+		Gradient:    "linear-gradient(135deg, #22c55e 0%, #16a34a 100%)",
+		ActionLabel: "Open Wallet",
+		ActionPath:  "/wallet",
+	},
+	"achievement": {
+		Heading:     "New Achievement",
+		Gradient:    "linear-gradient(135deg, #eab308 0%, #ca8a04 100%)",
+		ActionLabel: "See Your Badges",
+		ActionPath:  "/badges",
+	},
+	"review": {
+		Heading:     "Review Update",
+		Gradient:    "linear-gradient(135deg, #f97316 0%, #ea580c 100%)",
+		ActionLabel: "See Reviews",
+		ActionPath:  "/profile",
+	},
+	"social": {
+		Heading:     "Community Update",
+		Gradient:    "linear-gradient(135deg, #a855f7 0%, #9333ea 100%)",
+		ActionLabel: "Open Wibi",
+		ActionPath:  "/notifications",
+	},
+}
+
+// defaultNotificationEmailTemplate is used for notification types without a template
+var defaultNotificationEmailTemplate = notificationEmailTemplate{
+	Heading:     "Notification",
+	Gradient:    "linear-gradient(135deg, #f97316 0%, #ea580c 100%)",
+	ActionLabel: "Open Wibi",
+	ActionPath:  "/notifications",
+}
+
+// notificationEmailLayout is the HTML layout shared by all notification emails
+var notificationEmailLayout = template.Must(template.New("notification").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>{{.Title}} - Wibi</title>
+	{{.Styles}}
+</head>
+<body>
+	<div class="wrapper">
+		<div class="card">
+			<div class="header" style="background: {{.Gradient}};">
+				<h1>{{.Heading}}</h1>
+			</div>
+
+			<div class="content">
+				<p class="greeting">Hello {{.RecipientName}}!</p>
+				<p class="text"><strong>{{.Title}}</strong></p>
+				<p class="text">{{.Message}}</p>
+
+				<div class="button-wrapper">
+					<a href="{{.ActionURL}}" class="button" style="background: {{.Gradient}};">{{.ActionLabel}}</a>
+				</div>
+
+				<p class="text" style="font-size: 13px; color: #737373;">
+					You receive these emails because email notifications are on.
+					<a href="{{.SettingsURL}}" style="color: #f97316;">Change your notification settings</a>.
+				</p>
+			</div>
+
+			<div class="footer">
+				<p class="footer-logo">Wibi</p>
+				<p class="footer-text">Waktu Indonesia Berbagi Ilmu<br>Time Banking Skill Platform</p>
+			</div>
+		</div>
+	</div>
+</body>
+</html>
+`))
+
+// RenderNotificationEmail renders a notification email with its type's template
+//
+// Returns:
+//   - string: Subject
+//   - string: HTML body
+//   - error: If rendering fails
+func RenderNotificationEmail(email NotificationEmail) (string, string, error) {
+	tmpl, ok := notificationEmailTemplates[email.Type]
+	if !ok {
+		tmpl = defaultNotificationEmailTemplate
+	}
+	frontendURL := FrontendURL()
+
+	var body bytes.Buffer
+	err := notificationEmailLayout.Execute(&body, map[string]interface{}{
+		"Styles":        template.HTML(emailBaseStyles),
+		"Gradient":      template.CSS(tmpl.Gradient),
+		"Heading":       tmpl.Heading,
+		"RecipientName": email.RecipientName,
+		"Title":         email.Title,
+		"Message":       email.Message,
+		"ActionLabel":   tmpl.ActionLabel,
+		"ActionURL":     frontendURL + tmpl.ActionPath,
+		"SettingsURL":   frontendURL + "/settings/notifications",
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render %s email: %w", email.Type, err)
+	}
+
+	return email.Title + " - Wibi", body.String(), nil
+}
+
+// SendNotificationEmail renders a notification with its type's template and sends it via SMTP
+func SendNotificationEmail(recipientEmail string, email NotificationEmail) error {
+	smtpHost := os.Getenv("SMTP_HOST")
+	smtpPort := os.Getenv("SMTP_PORT")
+	smtpUser := os.Getenv("SMTP_USER")
+	smtpPass := os.Getenv("SMTP_PASS")
+	fromEmail := os.Getenv("EMAIL_FROM")
+
+	subject, htmlContent, err := RenderNotificationEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if smtpHost == "" || smtpUser == "" || smtpPass == "" {
+		log.Println("⚠️  SMTP credentials not set, skipping email sending")
+		log.Printf("📧 [DEV] Notification email for %s: %s", recipientEmail, subject)
+		return nil
+	}
+
+	if fromEmail == "" {
+		fromEmail = "noreply@wibi.local"
+	}
+	if smtpPort == "" {
+		smtpPort = "587"
+	}
+
+	return sendSMTPEmail(smtpHost, smtpPort, smtpUser, smtpPass, fromEmail, recipientEmail, subject, htmlContent)
+}
@@ -162,3 +162,67 @@ func VerifyPasswordResetToken(tokenString string) (string, error) {
 
 	return claims.Email, nil
 }
+
+// DigestUnsubscribeClaims contains claims for the one-click weekly digest unsubscribe link
+type DigestUnsubscribeClaims struct {
+	UserID    uint   `json:"user_id"`
+	TokenType string `json:"token_type"` // Always "digest_unsubscribe", so other tokens are not accepted
+	jwt.RegisteredClaims
+}
+
+// digestUnsubscribeTokenType marks digest unsubscribe tokens
+// It differs from the access token type, so ValidateToken rejects the link as a login.
+const digestUnsubscribeTokenType = "digest_unsubscribe"
+
+// GenerateDigestUnsubscribeToken generates the unsubscribe token of a weekly digest (60 day expiry)
+// The link keeps working for several weeks of digests, as users often unsubscribe from an older email.
+func GenerateDigestUnsubscribeToken(userID uint) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", fmt.Errorf("JWT_SECRET environment variable not set")
+	}
+
+	expirationTime := time.Now().Add(60 * 24 * time.Hour)
+	claims := &DigestUnsubscribeClaims{
+		UserID:    userID,
+		TokenType: digestUnsubscribeTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// VerifyDigestUnsubscribeToken validates a weekly digest unsubscribe token and returns the user ID
+func VerifyDigestUnsubscribeToken(tokenString string) (uint, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return 0, fmt.Errorf("JWT_SECRET environment variable not set")
+	}
+
+	claims := &DigestUnsubscribeClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if !token.Valid || claims.TokenType != digestUnsubscribeTokenType || claims.UserID == 0 {
+		return 0, fmt.Errorf("invalid token")
+	}
+
+	return claims.UserID, nil
+}
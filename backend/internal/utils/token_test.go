@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "test-secret-that-is-at-least-32-characters"
+
+func TestValidateTokenAcceptsAccessTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+
+	token, err := GenerateTokenWithVersion(7, "user@example.com", 2)
+	require.NoError(t, err)
+
+	claims, err := ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), claims.UserID)
+	assert.Equal(t, 2, claims.TokenVersion)
+}
+
+func TestValidateTokenRejectsDigestUnsubscribeTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+
+	token, err := GenerateDigestUnsubscribeToken(7)
+	require.NoError(t, err)
+
+	_, err = ValidateToken(token)
+	assert.Error(t, err)
+
+	userID, err := VerifyDigestUnsubscribeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), userID)
+}
+
+func TestVerifyDigestUnsubscribeTokenRejectsAccessTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+
+	token, err := GenerateTokenWithVersion(7, "user@example.com", 0)
+	require.NoError(t, err)
+
+	_, err = VerifyDigestUnsubscribeToken(token)
+	assert.Error(t, err)
+}
@@ -31,6 +31,10 @@ type WebSocketToken struct {
 	CreatedAt time.Time
 }
 
+// NotificationStreamTokenScope is the session ID of tokens for the notification stream
+// (GET /api/v1/notifications/stream); session IDs start at 1, so it never matches a session.
+const NotificationStreamTokenScope uint = 0
+
 // WebSocketTokenStore manages short-lived WebSocket tokens
 // Uses in-memory storage with automatic cleanup
 type WebSocketTokenStore struct {
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWSTokenIsSingleUse(t *testing.T) {
+	store := NewWebSocketTokenStore(time.Minute)
+	defer store.Stop()
+
+	token, err := store.GenerateWSToken(7, 3)
+	require.NoError(t, err)
+
+	userID, err := store.ValidateWSToken(token, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), userID)
+
+	_, err = store.ValidateWSToken(token, 3)
+	assert.Error(t, err)
+}
+
+func TestStreamTokenIsScopedToTheStream(t *testing.T) {
+	store := NewWebSocketTokenStore(time.Minute)
+	defer store.Stop()
+
+	token, err := store.GenerateWSToken(7, NotificationStreamTokenScope)
+	require.NoError(t, err)
+
+	_, err = store.ValidateWSToken(token, 3)
+	assert.Error(t, err, "only valid for its scope")
+
+	// An EventSource reconnect with the same URL is refused
+	token, err = store.GenerateWSToken(7, NotificationStreamTokenScope)
+	require.NoError(t, err)
+	userID, err := store.ValidateWSToken(token, NotificationStreamTokenScope)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), userID)
+	_, err = store.ValidateWSToken(token, NotificationStreamTokenScope)
+	assert.Error(t, err)
+}
+
+func TestWSTokenExpires(t *testing.T) {
+	store := NewWebSocketTokenStore(-time.Second)
+	defer store.Stop()
+
+	token, err := store.GenerateWSToken(7, NotificationStreamTokenScope)
+	require.NoError(t, err)
+
+	_, err = store.ValidateWSToken(token, NotificationStreamTokenScope)
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// recordSize is the aes128gcm record size advertised in the header; messages are a single record
+	recordSize = 4096
+
+	// saltSize, keySize and nonceSize are set by RFC 8291
+	saltSize  = 16
+	keySize   = 16
+	nonceSize = 12
+
+	// headerSize is the aes128gcm header: salt, record size, key ID length and the
+	// application server's uncompressed P-256 public key
+	headerSize = saltSize + 4 + 1 + 65
+
+	// MaxPayloadSize is the largest payload that fits the 4096 bytes push services accept,
+	// after the header, the padding delimiter and the AES-GCM tag
+	MaxPayloadSize = recordSize - headerSize - 1 - 16
+)
+
+// ErrPayloadTooLarge is returned for payloads over MaxPayloadSize
+var ErrPayloadTooLarge = errors.New("webpush: payload too large")
+
+// Encrypt encrypts a payload for a subscription (RFC 8291, aes128gcm content coding)
+// Each call uses a fresh application server key pair and salt.
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	uaPublic, authSecret, err := sub.keys()
+	if err != nil {
+		return nil, err
+	}
+	asKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encrypt(payload, uaPublic, authSecret, asKey, salt)
+}
+
+// encrypt encrypts a payload with the given application server key and salt
+func encrypt(payload []byte, uaPublic *ecdh.PublicKey, authSecret []byte, asKey *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	sharedSecret, err := asKey.ECDH(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: key agreement failed: %w", err)
+	}
+	asPublic := asKey.PublicKey().Bytes()
+
+	gcm, nonce, err := contentKeys(sharedSecret, authSecret, salt, uaPublic.Bytes(), asPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	// A single record: the payload and the last-record padding delimiter
+	plaintext := make([]byte, 0, len(payload)+1)
+	plaintext = append(plaintext, payload...)
+	plaintext = append(plaintext, 0x02)
+
+	body := make([]byte, 0, headerSize+len(plaintext)+gcm.Overhead())
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, recordSize)
+	body = append(body, byte(len(asPublic)))
+	body = append(body, asPublic...)
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts a message encrypted for a user agent's key pair and auth secret
+// This is the browser's side of RFC 8291; the server only needs it to test messages.
+func Decrypt(body []byte, uaKey *ecdh.PrivateKey, authSecret []byte) ([]byte, error) {
+	if len(body) < headerSize {
+		return nil, errors.New("webpush: message too short")
+	}
+	salt := body[:saltSize]
+	keyIDLen := int(body[saltSize+4])
+	if keyIDLen != 65 || len(body) < saltSize+5+keyIDLen {
+		return nil, errors.New("webpush: unexpected key ID")
+	}
+	asPublicBytes := body[saltSize+5 : saltSize+5+keyIDLen]
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid sender key: %w", err)
+	}
+
+	sharedSecret, err := uaKey.ECDH(asPublic)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: key agreement failed: %w", err)
+	}
+	gcm, nonce, err := contentKeys(sharedSecret, authSecret, salt, uaKey.PublicKey().Bytes(), asPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, body[saltSize+5+keyIDLen:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: decryption failed: %w", err)
+	}
+
+	// Strip the padding: zeros after the delimiter
+	i := len(plaintext) - 1
+	for i >= 0 && plaintext[i] == 0x00 {
+		i--
+	}
+	if i < 0 || plaintext[i] != 0x02 {
+		return nil, errors.New("webpush: invalid padding")
+	}
+	return plaintext[:i], nil
+}
+
+// contentKeys derives the content encryption key and nonce of a message (RFC 8291 section 3.4)
+func contentKeys(sharedSecret, authSecret, salt, uaPublic, asPublic []byte) (cipher.AEAD, []byte, error) {
+	prkKey, err := hkdf.Extract(sha256.New, sharedSecret, authSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", keySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", nonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gcm, nonce, nil
+}
@@ -0,0 +1,59 @@
+package webpush
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// vapidTokenLifetime is how long a VAPID token is valid (push services accept up to 24h)
+const vapidTokenLifetime = 12 * time.Hour
+
+// vapidAuthorization builds the Authorization header for a push endpoint (RFC 8292)
+// The token's audience is the origin of the push service.
+func (c *Client) vapidAuthorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("webpush: invalid endpoint %q", endpoint)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(vapidTokenLifetime).Unix(),
+		"sub": c.subject,
+	})
+	signed, err := token.SignedString(c.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("webpush: failed to sign VAPID token: %w", err)
+	}
+	return "vapid t=" + signed + ", k=" + c.publicKey, nil
+}
+
+// GenerateVAPIDKeys generates a VAPID key pair, base64url encoded
+// Generate it once and keep it: browser subscriptions are bound to the public key.
+//
+// Returns:
+//   - publicKey: Uncompressed P-256 public key (VAPID_PUBLIC_KEY)
+//   - privateKey: P-256 private scalar (VAPID_PRIVATE_KEY)
+//   - err: If key generation fails
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	public, err := key.PublicKey.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	private, err := key.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(public), base64.RawURLEncoding.EncodeToString(private), nil
+}
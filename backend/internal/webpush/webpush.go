@@ -0,0 +1,196 @@
+// Package webpush sends Web Push messages (RFC 8030) to browser push subscriptions
+//
+// Payloads are encrypted for the subscription (RFC 8291) and requests are signed with the
+// application server's VAPID key (RFC 8292), so push services accept them without a
+// vendor-specific API key:
+//
+//	client, err := webpush.NewClient(publicKey, privateKey, "mailto:admin@wibi.id")
+//	err = client.Send(ctx, sub, webpush.Message{Payload: payload, TTL: time.Hour})
+//	if errors.Is(err, webpush.ErrSubscriptionGone) {
+//	    // Forget the subscription
+//	}
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds a request to a push service
+const requestTimeout = 10 * time.Second
+
+// ErrSubscriptionGone is returned when the push service no longer knows the subscription
+// (404 or 410): the user unsubscribed or it expired, and it should be deleted.
+var ErrSubscriptionGone = errors.New("webpush: subscription expired or unsubscribed")
+
+// Subscription is a browser push subscription, as returned by PushSubscription.toJSON()
+type Subscription struct {
+	Endpoint string // Push service URL of the subscription
+	P256dh   string // User agent's P-256 public key, base64url
+	Auth     string // User agent's authentication secret, base64url
+}
+
+// Validate checks the endpoint is an absolute URL and the keys are well-formed
+func (s Subscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.New("webpush: invalid endpoint")
+	}
+	_, _, err = s.keys()
+	return err
+}
+
+// keys decodes the subscription's public key and authentication secret
+func (s Subscription) keys() (*ecdh.PublicKey, []byte, error) {
+	p256dh, err := decodeKey(s.P256dh)
+	if err != nil {
+		return nil, nil, errors.New("webpush: invalid p256dh key")
+	}
+	publicKey, err := ecdh.P256().NewPublicKey(p256dh)
+	if err != nil {
+		return nil, nil, errors.New("webpush: invalid p256dh key")
+	}
+	auth, err := decodeKey(s.Auth)
+	if err != nil || len(auth) != 16 {
+		return nil, nil, errors.New("webpush: invalid auth secret")
+	}
+	return publicKey, auth, nil
+}
+
+// Urgency tells the push service how soon to wake the device (RFC 8030 section 5.3)
+type Urgency string
+
+const (
+	UrgencyVeryLow Urgency = "very-low"
+	UrgencyLow     Urgency = "low"
+	UrgencyNormal  Urgency = "normal"
+	UrgencyHigh    Urgency = "high"
+)
+
+// Message is a push message
+type Message struct {
+	Payload []byte        // Encrypted before sending; at most MaxPayloadSize bytes
+	TTL     time.Duration // How long the push service keeps the message for an offline device
+	Urgency Urgency       // Optional
+	Topic   string        // Optional: replaces a pending message with the same topic
+}
+
+// Client sends push messages signed with a VAPID key pair
+type Client struct {
+	publicKey  string // Uncompressed P-256 public key, base64url
+	privateKey *ecdsa.PrivateKey
+	subject    string
+	httpClient *http.Client
+}
+
+// NewClient creates a push client from a VAPID key pair
+//
+// Parameters:
+//   - publicKey: Uncompressed P-256 public key, base64url (the applicationServerKey given to browsers)
+//   - privateKey: P-256 private scalar, base64url
+//   - subject: Contact for push service operators, a mailto: or https: URL
+//
+// Returns:
+//   - *Client: Push client
+//   - error: If the keys are missing, malformed or do not match
+func NewClient(publicKey, privateKey, subject string) (*Client, error) {
+	if publicKey == "" || privateKey == "" {
+		return nil, errors.New("webpush: VAPID keys are not configured")
+	}
+	if !strings.HasPrefix(subject, "mailto:") && !strings.HasPrefix(subject, "https:") {
+		return nil, errors.New("webpush: VAPID subject must be a mailto: or https: URL")
+	}
+
+	d, err := decodeKey(privateKey)
+	if err != nil {
+		return nil, errors.New("webpush: invalid VAPID private key")
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), d)
+	if err != nil {
+		return nil, errors.New("webpush: invalid VAPID private key")
+	}
+	public, err := decodeKey(publicKey)
+	if err != nil {
+		return nil, errors.New("webpush: invalid VAPID public key")
+	}
+	derived, err := key.PublicKey.Bytes()
+	if err != nil || !bytes.Equal(derived, public) {
+		return nil, errors.New("webpush: VAPID public key does not match the private key")
+	}
+
+	return &Client{
+		publicKey:  base64.RawURLEncoding.EncodeToString(public),
+		privateKey: key,
+		subject:    subject,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// PublicKey returns the VAPID public key browsers subscribe with (applicationServerKey)
+func (c *Client) PublicKey() string {
+	return c.publicKey
+}
+
+// Send encrypts a message for a subscription and delivers it to its push service
+//
+// Returns:
+//   - error: ErrSubscriptionGone if the subscription no longer exists, another error if
+//     the message could not be sent (worth retrying on 429 and 5xx)
+func (c *Client) Send(ctx context.Context, sub Subscription, msg Message) error {
+	body, err := Encrypt(sub, msg.Payload)
+	if err != nil {
+		return err
+	}
+	authorization, err := c.vapidAuthorization(sub.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webpush: invalid endpoint: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(msg.TTL/time.Second)))
+	if msg.Urgency != "" {
+		req.Header.Set("Urgency", string(msg.Urgency))
+	}
+	if msg.Topic != "" {
+		req.Header.Set("Topic", msg.Topic)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webpush: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	default:
+		return fmt.Errorf("webpush: push service responded %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+}
+
+// decodeKey decodes a base64url key, with or without padding
+func decodeKey(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,174 @@
+package webpush_test
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/timebankingskill/backend/internal/webpush"
+	"github.com/timebankingskill/backend/internal/webpushtest"
+)
+
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// newClient creates a client with a fresh VAPID key pair
+func newClient(t *testing.T) *webpush.Client {
+	t.Helper()
+	publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+	require.NoError(t, err)
+	client, err := webpush.NewClient(publicKey, privateKey, "mailto:admin@wibi.id")
+	require.NoError(t, err)
+	return client
+}
+
+// TestDecryptRFC8291Example decrypts the example message of RFC 8291 section 5
+func TestDecryptRFC8291Example(t *testing.T) {
+	uaKey, err := ecdh.P256().NewPrivateKey(decode(t, "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94"))
+	require.NoError(t, err)
+	assert.Equal(t, "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		base64.RawURLEncoding.EncodeToString(uaKey.PublicKey().Bytes()))
+
+	body := decode(t, "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN")
+	plaintext, err := webpush.Decrypt(body, uaKey, decode(t, "BTBZMqHH6r4Tts7J_aSIgg"))
+	require.NoError(t, err)
+	assert.Equal(t, "When I grow up, I want to be a watermelon", string(plaintext))
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	uaKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	auth := []byte("0123456789abcdef")
+	sub := webpush.Subscription{
+		Endpoint: "https://push.example.com/send/1",
+		P256dh:   base64.RawURLEncoding.EncodeToString(uaKey.PublicKey().Bytes()),
+		Auth:     base64.URLEncoding.EncodeToString(auth), // Padded keys are accepted too
+	}
+
+	body, err := webpush.Encrypt(sub, []byte(`{"title":"New Session Request"}`))
+	require.NoError(t, err)
+	plaintext, err := webpush.Decrypt(body, uaKey, auth)
+	require.NoError(t, err)
+	assert.Equal(t, `{"title":"New Session Request"}`, string(plaintext))
+
+	// Each message uses a fresh key and salt
+	again, err := webpush.Encrypt(sub, []byte(`{"title":"New Session Request"}`))
+	require.NoError(t, err)
+	assert.NotEqual(t, body, again)
+
+	_, err = webpush.Encrypt(sub, make([]byte, webpush.MaxPayloadSize+1))
+	assert.ErrorIs(t, err, webpush.ErrPayloadTooLarge)
+	_, err = webpush.Encrypt(sub, make([]byte, webpush.MaxPayloadSize))
+	assert.NoError(t, err)
+}
+
+func TestSubscriptionValidate(t *testing.T) {
+	srv := webpushtest.NewServer()
+	defer srv.Close()
+	sub, err := srv.NewSubscription()
+	require.NoError(t, err)
+	assert.NoError(t, sub.Validate())
+
+	badEndpoint := sub
+	badEndpoint.Endpoint = "not a url"
+	assert.Error(t, badEndpoint.Validate())
+
+	badKey := sub
+	badKey.P256dh = base64.RawURLEncoding.EncodeToString(make([]byte, 65))
+	assert.Error(t, badKey.Validate())
+
+	badAuth := sub
+	badAuth.Auth = base64.RawURLEncoding.EncodeToString([]byte("short"))
+	assert.Error(t, badAuth.Validate())
+}
+
+func TestNewClientRejectsBadKeys(t *testing.T) {
+	publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+	require.NoError(t, err)
+	otherPublic, _, err := webpush.GenerateVAPIDKeys()
+	require.NoError(t, err)
+
+	_, err = webpush.NewClient("", "", "mailto:admin@wibi.id")
+	assert.Error(t, err)
+	_, err = webpush.NewClient(otherPublic, privateKey, "mailto:admin@wibi.id")
+	assert.Error(t, err)
+	_, err = webpush.NewClient(publicKey, privateKey, "admin@wibi.id")
+	assert.Error(t, err)
+
+	client, err := webpush.NewClient(publicKey, privateKey, "https://wibi.id")
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, client.PublicKey())
+}
+
+func TestSendDeliversSignedEncryptedMessage(t *testing.T) {
+	srv := webpushtest.NewServer()
+	defer srv.Close()
+	sub, err := srv.NewSubscription()
+	require.NoError(t, err)
+	client := newClient(t)
+
+	err = client.Send(context.Background(), sub, webpush.Message{
+		Payload: []byte(`{"title":"Session reminder"}`),
+		TTL:     2 * time.Hour,
+		Urgency: webpush.UrgencyHigh,
+		Topic:   "session-12",
+	})
+	require.NoError(t, err)
+
+	messages := srv.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, sub.Endpoint, messages[0].Endpoint)
+	assert.Equal(t, `{"title":"Session reminder"}`, string(messages[0].Payload))
+	assert.Equal(t, 7200, messages[0].TTL)
+	assert.Equal(t, "high", messages[0].Urgency)
+	assert.Equal(t, "session-12", messages[0].Topic)
+	assert.Equal(t, client.PublicKey(), messages[0].VAPIDKey)
+	assert.Equal(t, "mailto:admin@wibi.id", messages[0].VAPIDSubject)
+}
+
+func TestSendReportsGoneSubscriptions(t *testing.T) {
+	srv := webpushtest.NewServer()
+	defer srv.Close()
+	client := newClient(t)
+
+	for _, status := range []int{http.StatusNotFound, http.StatusGone} {
+		sub, err := srv.NewSubscription()
+		require.NoError(t, err)
+		srv.SetStatus(sub.Endpoint, status)
+
+		err = client.Send(context.Background(), sub, webpush.Message{Payload: []byte("{}"), TTL: time.Hour})
+		assert.ErrorIs(t, err, webpush.ErrSubscriptionGone, "status %d", status)
+	}
+
+	// Unknown to the push service
+	unknown, err := srv.NewSubscription()
+	require.NoError(t, err)
+	unknown.Endpoint = srv.URL + "/push/999"
+	err = client.Send(context.Background(), unknown, webpush.Message{Payload: []byte("{}"), TTL: time.Hour})
+	assert.ErrorIs(t, err, webpush.ErrSubscriptionGone)
+	assert.Empty(t, srv.Messages())
+}
+
+func TestSendReportsServiceErrors(t *testing.T) {
+	srv := webpushtest.NewServer()
+	defer srv.Close()
+	sub, err := srv.NewSubscription()
+	require.NoError(t, err)
+	srv.SetStatus(sub.Endpoint, http.StatusServiceUnavailable)
+
+	err = newClient(t).Send(context.Background(), sub, webpush.Message{Payload: []byte("{}"), TTL: time.Hour})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, webpush.ErrSubscriptionGone)
+	assert.True(t, strings.Contains(err.Error(), "503"), err.Error())
+}
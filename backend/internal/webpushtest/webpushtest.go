@@ -0,0 +1,220 @@
+// Package webpushtest provides a local push service stand-in for tests
+//
+// The server plays both the push service and the browsers subscribed to it: it creates
+// subscriptions with their own key pairs, verifies the VAPID signature of each request and
+// decrypts the payload, so tests see what a service worker would receive:
+//
+//	srv := webpushtest.NewServer()
+//	defer srv.Close()
+//	sub, err := srv.NewSubscription()
+//	// send to sub with a webpush.Client, then inspect srv.Messages()
+package webpushtest
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/timebankingskill/backend/internal/webpush"
+)
+
+// Message is a push message accepted by the server
+type Message struct {
+	Endpoint     string
+	Payload      []byte // Decrypted
+	TTL          int
+	Urgency      string
+	Topic        string
+	VAPIDKey     string // Public key the request was signed with
+	VAPIDSubject string
+}
+
+// Server is an in-memory push service
+type Server struct {
+	URL string // Base URL of the subscription endpoints
+
+	srv           *httptest.Server
+	mutex         sync.Mutex
+	nextID        int
+	subscriptions map[string]*subscription // Endpoint path -> subscription
+	messages      []Message
+}
+
+// subscription is a subscribed browser
+type subscription struct {
+	key    *ecdh.PrivateKey
+	auth   []byte
+	status int // Response status forced by SetStatus, 0 to accept messages
+}
+
+// NewServer starts a server on a free local port
+func NewServer() *Server {
+	s := &Server{subscriptions: make(map[string]*subscription)}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// NewSubscription subscribes a new browser and returns its subscription
+func (s *Server) NewSubscription() (webpush.Subscription, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return webpush.Subscription{}, err
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		return webpush.Subscription{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nextID++
+	path := "/push/" + strconv.Itoa(s.nextID)
+	s.subscriptions[path] = &subscription{key: key, auth: auth}
+
+	return webpush.Subscription{
+		Endpoint: s.URL + path,
+		P256dh:   base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}, nil
+}
+
+// SetStatus makes the server answer messages to an endpoint with a status code,
+// such as 410 for a subscription the browser dropped or 500 for an outage
+func (s *Server) SetStatus(endpoint string, status int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if sub, ok := s.subscriptions[strings.TrimPrefix(endpoint, s.URL)]; ok {
+		sub.status = status
+	}
+}
+
+// Messages returns the messages accepted so far, in order
+func (s *Server) Messages() []Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// handle accepts a push message like a push service would
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mutex.Lock()
+	sub, ok := s.subscriptions[r.URL.Path]
+	var status int
+	if ok {
+		status = sub.status
+	}
+	s.mutex.Unlock()
+	if !ok {
+		http.Error(w, "no such subscription", http.StatusNotFound)
+		return
+	}
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	vapidKey, subject, err := s.verifyVAPID(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	ttl, err := strconv.Atoi(r.Header.Get("TTL"))
+	if err != nil {
+		http.Error(w, "missing TTL", http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Content-Encoding") != "aes128gcm" {
+		http.Error(w, "unsupported content encoding", http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > 4096 {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	payload, err := webpush.Decrypt(body, sub.key, sub.auth)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mutex.Lock()
+	s.messages = append(s.messages, Message{
+		Endpoint:     s.URL + r.URL.Path,
+		Payload:      payload,
+		TTL:          ttl,
+		Urgency:      r.Header.Get("Urgency"),
+		Topic:        r.Header.Get("Topic"),
+		VAPIDKey:     vapidKey,
+		VAPIDSubject: subject,
+	})
+	s.mutex.Unlock()
+	w.WriteHeader(http.StatusCreated)
+}
+
+// verifyVAPID checks a "vapid t=<jwt>, k=<key>" header is signed by the key it names,
+// for this server, and returns the key and subject
+func (s *Server) verifyVAPID(header string) (string, string, error) {
+	params, ok := strings.CutPrefix(header, "vapid ")
+	if !ok {
+		return "", "", errors.New("missing VAPID authorization")
+	}
+	var token, key string
+	for _, param := range strings.Split(params, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch name {
+		case "t":
+			token = value
+		case "k":
+			key = value
+		}
+	}
+
+	keyBytes, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil {
+		return "", "", errors.New("invalid VAPID key")
+	}
+	publicKey, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), keyBytes)
+	if err != nil {
+		return "", "", errors.New("invalid VAPID key")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}),
+		jwt.WithAudience(s.URL),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid VAPID token: %w", err)
+	}
+	subject, _ := claims["sub"].(string)
+	return key, subject, nil
+}
@@ -0,0 +1,140 @@
+package websocket
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/timebankingskill/backend/internal/dto"
+	"github.com/timebankingskill/backend/internal/models"
+	"github.com/timebankingskill/backend/internal/service"
+	"github.com/timebankingskill/backend/internal/utils"
+)
+
+// sseHeartbeatInterval is how often an idle stream sends a heartbeat, keeping proxies
+// from closing the connection and letting the client detect a dead one
+const sseHeartbeatInterval = 25 * time.Second
+
+// sseRetry is the reconnection delay suggested to EventSource clients, in milliseconds
+const sseRetry = 3000
+
+// NotificationStream serves real-time notifications as Server-Sent Events
+// A fallback for networks that block WebSocket upgrades: it subscribes through the
+// same NotificationService mechanism as NotificationWebSocket.
+type NotificationStream struct {
+	notificationService *service.NotificationService
+	tokenStore          *utils.WebSocketTokenStore
+}
+
+// NewNotificationStream creates a new SSE handler for notifications
+func NewNotificationStream(notificationService *service.NotificationService, tokenStore *utils.WebSocketTokenStore) *NotificationStream {
+	return &NotificationStream{
+		notificationService: notificationService,
+		tokenStore:          tokenStore,
+	}
+}
+
+// HandleStream streams a user's notifications as Server-Sent Events
+// GET /api/v1/notifications/stream?token=...&since=123
+// EventSource cannot send an Authorization header, so the client authenticates with a
+// short-lived, single-use token from POST /api/v1/notifications/stream-token.
+//
+// Events:
+//
+//	id: 123
+//	event: notification
+//	data: {"id":123,"type":"session","title":"New Session Request",...}
+//
+//	event: heartbeat
+//	data: 2025-01-15T10:30:00Z
+//
+// The event ID is the notification cursor. The token is spent by the first connection,
+// so EventSource's own reconnect (same URL) is refused: on error the client closes the
+// EventSource and reconnects with a new token, passing the last ID it received as
+// ?since=<id>. It then first receives the notifications it missed, in order, then live ones.
+func (s *NotificationStream) HandleStream(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		utils.SendError(c, http.StatusUnauthorized, "Missing stream token", nil)
+		return
+	}
+	userID, err := s.tokenStore.ValidateWSToken(token, utils.NotificationStreamTokenScope)
+	if err != nil {
+		utils.SendError(c, http.StatusUnauthorized, "Invalid stream token", nil)
+		return
+	}
+
+	since, resume := replayCursor(c)
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+	c.Status(http.StatusOK)
+
+	// Create channel for this client
+	notificationChan := make(chan *models.Notification, 10)
+
+	// Register before replaying, so notifications created meanwhile are not lost
+	// (unregistering closes the channel)
+	s.notificationService.RegisterClient(userID, notificationChan)
+	defer s.notificationService.UnregisterClient(userID, notificationChan)
+
+	log.Printf("User %d connected to notification stream", userID)
+
+	send := func(event sse.Event) error {
+		if err := sse.Encode(c.Writer, event); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	sendNotification := func(notification *models.Notification) error {
+		return send(sse.Event{
+			Id:    strconv.FormatUint(uint64(notification.ID), 10),
+			Event: "notification",
+			Data:  dto.MapNotificationToResponse(notification),
+		})
+	}
+
+	if err := send(sse.Event{Event: "connected", Retry: sseRetry, Data: gin.H{"user_id": userID}}); err != nil {
+		return
+	}
+
+	// Live notifications already sent by the replay are skipped
+	var replayed map[uint]bool
+	if resume {
+		replayed, err = replayMissed(s.notificationService, userID, since, sendNotification)
+		if err != nil {
+			log.Printf("SSE replay error for user %d: %v", userID, err)
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case notification, ok := <-notificationChan:
+			if !ok {
+				return
+			}
+			if replayed[notification.ID] {
+				continue
+			}
+			if err := sendNotification(notification); err != nil {
+				return
+			}
+		case now := <-heartbeat.C:
+			if err := send(sse.Event{Event: "heartbeat", Data: now.UTC().Format(time.RFC3339)}); err != nil {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
@@ -2,8 +2,10 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -33,7 +35,7 @@ func NewNotificationWebSocket(notificationService *service.NotificationService)
 }
 
 // HandleConnection handles a new WebSocket connection for notifications
-// GET /api/v1/ws/notifications
+// GET /api/v1/ws/notifications?since=123
 // This endpoint establishes a WebSocket connection that receives real-time notifications
 // The connection stays open and receives notifications as they're created
 //
@@ -48,13 +50,22 @@ func NewNotificationWebSocket(notificationService *service.NotificationService)
 //   "created_at": "2025-01-15T10:30:00Z"
 // }
 //
+// Message Format (sent by client, acknowledges the notification with the ID):
+// {"type": "ack", "id": 123}
+//
+// Notification IDs are the cursor: a client reconnecting with the last ID it received,
+// as ?since=<id> or a Last-Event-ID header, first receives every notification it missed,
+// in order, then live ones. Live notifications arrive in creation order, which is not
+// always ID order, so clients ack each one rather than the highest ID seen.
+//
 // Connection Lifecycle:
 // 1. Client connects via WebSocket
 // 2. Server registers client in NotificationService
-// 3. Server sends existing unread notifications (optional)
+// 3. Server replays notifications after the client's cursor, if given
 // 4. Server waits for new notifications
 // 5. When notification created, server broadcasts to all connected clients
-// 6. Client disconnects, server unregisters client
+// 6. Client acks received notifications, recording their delivery time
+// 7. Client disconnects, server unregisters client
 func (ws *NotificationWebSocket) HandleConnection(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID := c.GetUint("user_id")
@@ -76,6 +87,8 @@ func (ws *NotificationWebSocket) HandleConnection(c *gin.Context) {
 		return
 	}
 
+	since, resume := replayCursor(c)
+
 	// Upgrade HTTP connection to WebSocket
 	conn, err := ws.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -86,24 +99,128 @@ func (ws *NotificationWebSocket) HandleConnection(c *gin.Context) {
 
 	// Create channel for this client
 	notificationChan := make(chan *models.Notification, 10)
-	defer close(notificationChan)
 
-	// Register client with notification service
+	// Register client with notification service (unregistering closes the channel)
+	// before replaying, so notifications created meanwhile are not lost
 	ws.notificationService.RegisterClient(userID, notificationChan)
 	defer ws.notificationService.UnregisterClient(userID, notificationChan)
 
 	log.Printf("User %d connected to notification WebSocket", userID)
 
+	// Read acks until the client disconnects
+	disconnected := make(chan struct{})
+	go ws.readAcks(conn, userID, disconnected)
+
+	// Live notifications already sent by the replay are skipped
+	var replayed map[uint]bool
+	if resume {
+		replayed, err = replayMissed(ws.notificationService, userID, since, func(notification *models.Notification) error {
+			return conn.WriteJSON(dto.MapNotificationToResponse(notification))
+		})
+		if err != nil {
+			log.Printf("WebSocket replay error for user %d: %v", userID, err)
+			return
+		}
+	}
+
 	// Listen for notifications and send to client
-	for notification := range notificationChan {
-		// Convert notification to response DTO
-		notifResponse := dto.MapNotificationToResponse(notification)
+	for {
+		select {
+		case notification, ok := <-notificationChan:
+			if !ok {
+				return
+			}
+			if replayed[notification.ID] {
+				continue
+			}
+
+			// Convert notification to response DTO and send to client
+			if err := conn.WriteJSON(dto.MapNotificationToResponse(notification)); err != nil {
+				log.Printf("WebSocket write error for user %d: %v", userID, err)
+				return
+			}
+		case <-disconnected:
+			return
+		}
+	}
+}
+
+// clientMessage is a message sent by a client over the notification WebSocket
+type clientMessage struct {
+	Type string `json:"type"`
+	ID   uint   `json:"id"`
+}
 
-		// Send to client
-		if err := conn.WriteJSON(notifResponse); err != nil {
-			log.Printf("WebSocket write error for user %d: %v", userID, err)
+// maxClientMessageSize bounds the messages read from clients (acks are tiny)
+const maxClientMessageSize = 512
+
+// replayCursor reads the ID of the last notification a reconnecting client received,
+// from the Last-Event-ID header (sent by EventSource on reconnect) or the since query parameter
+// The header wins: an automatic reconnect reuses the original URL, so its since is stale.
+// Returns false when the client sent no cursor (a fresh connection, nothing to replay).
+func replayCursor(c *gin.Context) (uint, bool) {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("since")
+	}
+	if raw == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// replayMissed sends the notifications created after the cursor, in order
+// Shared by the WebSocket and the SSE stream.
+// Returns the IDs sent, so the live loop skips the ones also broadcast during the replay.
+// Only those are skipped: a live notification with a lower ID than the replayed ones
+// may still be in flight and must not be dropped.
+func replayMissed(notificationService *service.NotificationService, userID uint, since uint, send func(*models.Notification) error) (map[uint]bool, error) {
+	sent := make(map[uint]bool)
+	cursor := since
+	for {
+		missed, err := notificationService.ReplayMissed(userID, cursor)
+		if err != nil {
+			return sent, err
+		}
+		for i := range missed {
+			if err := send(&missed[i]); err != nil {
+				return sent, err
+			}
+			sent[missed[i].ID] = true
+			cursor = missed[i].ID
+		}
+		if len(missed) < service.NotificationReplayPageSize {
+			return sent, nil
+		}
+	}
+}
+
+// readAcks records the client's acks until the connection closes, then closes disconnected
+func (ws *NotificationWebSocket) readAcks(conn *websocket.Conn, userID uint, disconnected chan struct{}) {
+	defer close(disconnected)
+	conn.SetReadLimit(maxClientMessageSize)
+
+	for {
+		var msg clientMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				// Malformed message: ignore it and keep the connection
+				continue
+			}
 			return
 		}
+		if msg.Type != "ack" {
+			continue
+		}
+		if err := ws.notificationService.AcknowledgeDelivery(userID, msg.ID); err != nil {
+			log.Printf("Failed to record notification ack for user %d: %v", userID, err)
+		}
 	}
 }
 
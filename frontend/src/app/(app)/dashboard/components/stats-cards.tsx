@@ -18,8 +18,9 @@ const cardVariants = {
 };
 
 export function StatsCards({ user, stats, isLoading }: StatsCardsProps) {
-    const availableCredits = ((user?.credit_balance || 0) - (user?.credit_held || 0)).toFixed(1);
-    const heldCredits = (user?.credit_held || 0).toFixed(1);
+    const lockedCredits = (user?.credit_held || 0) + (user?.frozen_credits || 0);
+    const availableCredits = ((user?.credit_balance || 0) - lockedCredits).toFixed(1);
+    const heldCredits = lockedCredits.toFixed(1);
 
     return (
         <m.div
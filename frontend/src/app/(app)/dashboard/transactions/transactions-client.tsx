@@ -134,7 +134,7 @@ export function TransactionsClient() {
                 <div>
                   <p className="text-xs uppercase tracking-[0.2em] font-bold text-zinc-500 mb-2">Available Credits</p>
                   <p className="text-5xl font-black text-primary tracking-tighter tabular-nums mb-1">
-                    {((user?.credit_balance || 0) - (user?.credit_held || 0)).toFixed(1)}
+                    {((user?.credit_balance || 0) - (user?.credit_held || 0) - (user?.frozen_credits || 0)).toFixed(1)}
                   </p>
                   <p className="text-xs text-muted-foreground font-medium">Currently on hand</p>
                 </div>
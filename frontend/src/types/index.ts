@@ -44,6 +44,7 @@ export interface UserProfile {
     location: string;
     credit_balance: number;
     credit_held: number;
+    frozen_credits?: number;
     is_active: boolean;
     is_verified: boolean;
     total_earned: number;